@@ -0,0 +1,134 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/metrics"
+	"sort"
+	"sync"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/fxevent"
+)
+
+// ReportConstructorAllocations causes Fx to measure the heap memory
+// allocated by each constructor while it runs, and to report it in an
+// [fxevent.StartSummary] event emitted after the application starts.
+//
+// Allocations are read from [runtime/metrics] before and after each
+// constructor, so they include allocations made concurrently by other
+// goroutines. Use them to find the constructors that are worth profiling,
+// not as exact figures.
+func ReportConstructorAllocations() Option {
+	return reportConstructorAllocationsOption{}
+}
+
+type reportConstructorAllocationsOption struct{}
+
+func (o reportConstructorAllocationsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.ReportConstructorAllocations Option should be passed to top-level " +
+			"App, not to fx.Module")
+	} else {
+		m.app.allocs = &allocRecorder{}
+	}
+}
+
+func (o reportConstructorAllocationsOption) String() string {
+	return "fx.ReportConstructorAllocations()"
+}
+
+// Metrics read around each constructor by allocContainer.
+var _allocMetrics = []string{
+	"/gc/heap/allocs:bytes",
+	"/gc/heap/allocs:objects",
+}
+
+// allocRecorder collects the allocations of constructors.
+type allocRecorder struct {
+	mu     sync.Mutex
+	allocs []fxevent.ConstructorAllocations
+}
+
+func (r *allocRecorder) record(a fxevent.ConstructorAllocations) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.allocs = append(r.allocs, a)
+}
+
+// summary returns the allocations recorded so far,
+// from the constructor that allocated the most bytes to the least.
+func (r *allocRecorder) summary() []fxevent.ConstructorAllocations {
+	r.mu.Lock()
+	allocs := append([]fxevent.ConstructorAllocations(nil), r.allocs...)
+	r.mu.Unlock()
+
+	sort.SliceStable(allocs, func(i, j int) bool {
+		return allocs[i].Bytes > allocs[j].Bytes
+	})
+	return allocs
+}
+
+// readAllocs returns the number of bytes and objects
+// allocated on the heap since the program started.
+func readAllocs() (bytes, objects uint64) {
+	samples := make([]metrics.Sample, len(_allocMetrics))
+	for i, name := range _allocMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		bytes = samples[0].Value.Uint64()
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		objects = samples[1].Value.Uint64()
+	}
+	return bytes, objects
+}
+
+// allocContainer is a container that records the heap allocations
+// of constructors provided to it.
+type allocContainer struct {
+	container
+
+	recorder *allocRecorder
+	info     fxevent.ConstructorAllocations
+}
+
+var _ container = allocContainer{}
+
+func (c allocContainer) Provide(constructor interface{}, opts ...dig.ProvideOption) error {
+	return provideWrapped(c.container, constructor, opts, false, func(_ reflect.Type, call constructorFunc) constructorFunc {
+		return func(args []reflect.Value) []reflect.Value {
+			bytes, objects := readAllocs()
+			results := call(args)
+			afterBytes, afterObjects := readAllocs()
+
+			info := c.info
+			info.Bytes = afterBytes - bytes
+			info.Objects = afterObjects - objects
+			c.recorder.record(info)
+			return results
+		}
+	})
+}
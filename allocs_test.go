@@ -0,0 +1,100 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+func TestReportConstructorAllocations(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ buf []byte }
+	type B struct{}
+
+	t.Run("allocations are reported", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.ReportConstructorAllocations(),
+			fx.Module("child",
+				fx.Provide(func() *A { return &A{buf: make([]byte, 1<<20)} }),
+			),
+			fx.Provide(func(*A, ...string) *B { return &B{} }),
+			fx.Invoke(func(*B) {}),
+		)
+		require.NoError(t, app.Start(context.Background()))
+		defer func() { require.NoError(t, app.Stop(context.Background())) }()
+
+		summaries := spy.Events().SelectByTypeName("StartSummary")
+		require.Len(t, summaries, 1)
+		allocs := summaries[0].(*fxevent.StartSummary).Constructors
+		require.Len(t, allocs, 2)
+
+		assert.Contains(t, allocs[0].Name, "TestReportConstructorAllocations")
+		assert.Equal(t, "child", allocs[0].ModuleName)
+		assert.GreaterOrEqual(t, allocs[0].Bytes, uint64(1<<20))
+		assert.NotZero(t, allocs[0].Objects)
+		assert.Empty(t, allocs[1].ModuleName)
+	})
+
+	t.Run("not reported by default", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.Provide(func() *B { return &B{} }),
+			fx.Invoke(func(*B) {}),
+		)
+		require.NoError(t, app.Start(context.Background()))
+		defer func() { require.NoError(t, app.Stop(context.Background())) }()
+
+		assert.Empty(t, spy.Events().SelectByTypeName("StartSummary"))
+	})
+
+	t.Run("not allowed in modules", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Module("child", fx.ReportConstructorAllocations()),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.ReportConstructorAllocations Option should be passed to top-level App")
+	})
+
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "fx.ReportConstructorAllocations()", fx.ReportConstructorAllocations().String())
+	})
+}
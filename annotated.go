@@ -24,11 +24,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"reflect"
+	"runtime"
+	"slices"
 	"strings"
 
 	"go.uber.org/dig"
 	"go.uber.org/fx/internal/fxreflect"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
 )
 
 // Annotated annotates a constructor provided to Fx with additional options.
@@ -56,6 +61,15 @@ import (
 //	})
 //
 // Annotated cannot be used with constructors which produce fx.Out objects.
+// Target may be the result of [Annotate], in which case Name or Group
+// is applied to the results of the annotated function,
+// which must not already be named or grouped by fx.Annotate:
+//
+//	fx.Provide(fx.Annotated{
+//	  Name: "ro",
+//	  Target: fx.Annotate(NewReadOnlyConnection, fx.ParamTags(`name:"replica"`)),
+//	})
+//
 // When used with [Supply], Target is a value instead of a constructor.
 //
 // This type represents a less powerful version of the [Annotate] construct;
@@ -96,6 +110,88 @@ func (a Annotated) String() string {
 	return fmt.Sprintf("fx.Annotated{%v}", strings.Join(fields, ", "))
 }
 
+// tagResults returns a function equivalent to fn, a constructor built by
+// [Annotate], whose non-error results are all tagged with the Name or Group
+// of a. This is how a takes effect when its Target was built by fx.Annotate,
+// since fn may already return fx.Out structs that dig won't name.
+func (a Annotated) tagResults(fn interface{}) (interface{}, error) {
+	key, value := "name", a.Name
+	if len(a.Group) > 0 {
+		key, value = _groupTag, a.Group
+	}
+	if len(value) == 0 {
+		return fn, nil
+	}
+	tag := fmt.Sprintf("%v:%q", key, value)
+
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	outTypes := make([]reflect.Type, ft.NumOut())
+	for i := range outTypes {
+		rt := ft.Out(i)
+		switch {
+		case rt == _typeOfError:
+			outTypes[i] = rt
+		case isOut(rt):
+			if rt.Name() != "" {
+				return nil, fmt.Errorf("cannot apply %v to %v: "+
+					"fx.Annotated cannot be used with constructors which produce fx.Out objects", tag, rt)
+			}
+			fields := make([]reflect.StructField, rt.NumField())
+			for j := range fields {
+				f := rt.Field(j)
+				fields[j] = f
+				if f.Type == _outAnnotationField.Type {
+					continue
+				}
+				if prev, ok := f.Tag.Lookup("name"); ok {
+					return nil, fmt.Errorf("cannot apply %v to %v: already named %q by fx.Annotate", tag, f.Type, prev)
+				}
+				if prev, ok := f.Tag.Lookup(_groupTag); ok {
+					return nil, fmt.Errorf("cannot apply %v to %v: already in group %q by fx.Annotate", tag, f.Type, prev)
+				}
+				fields[j].Tag = reflect.StructTag(strings.TrimSpace(string(f.Tag) + " " + tag))
+			}
+			outTypes[i] = reflect.StructOf(fields)
+		default:
+			outTypes[i] = reflect.StructOf([]reflect.StructField{
+				_outAnnotationField,
+				{Name: "Field0", Type: rt, Tag: reflect.StructTag(tag)},
+			})
+		}
+	}
+
+	inTypes := make([]reflect.Type, ft.NumIn())
+	for i := range inTypes {
+		inTypes[i] = ft.In(i)
+	}
+	newFt := reflect.FuncOf(inTypes, outTypes, ft.IsVariadic())
+	return reflect.MakeFunc(newFt, func(args []reflect.Value) []reflect.Value {
+		var results []reflect.Value
+		if ft.IsVariadic() {
+			results = fv.CallSlice(args)
+		} else {
+			results = fv.Call(args)
+		}
+		for i, r := range results {
+			switch {
+			case outTypes[i] == _typeOfError:
+			case isOut(r.Type()):
+				out := reflect.New(outTypes[i]).Elem()
+				for j := 1; j < out.NumField(); j++ {
+					out.Field(j).Set(r.Field(j))
+				}
+				results[i] = out
+			default:
+				out := reflect.New(outTypes[i]).Elem()
+				out.Field(1).Set(r)
+				results[i] = out
+			}
+		}
+		return results
+	}).Interface(), nil
+}
+
 var (
 	// field used for embedding fx.In type in generated struct.
 	_inAnnotationField = reflect.StructField{
@@ -142,6 +238,17 @@ func (e *annotationError) Unwrap() error {
 	return e.err
 }
 
+// Skip is a placeholder for [ParamTags] and [ResultTags] that leaves the
+// parameter or result at its position untagged.
+// It is equivalent to an empty tag.
+//
+//	fx.Annotate(func(lc fx.Lifecycle, log *zap.Logger, conn *sql.DB) *Handler {
+//		// ...
+//	}, fx.ParamTags(fx.Skip(), fx.Skip(), `name:"ro"`))
+func Skip() string {
+	return ""
+}
+
 type paramTagsAnnotation struct {
 	tags []string
 }
@@ -149,7 +256,7 @@ type paramTagsAnnotation struct {
 var _ Annotation = paramTagsAnnotation{}
 var (
 	errTagSyntaxSpace            = errors.New(`multiple tags are not separated by space`)
-	errTagKeySyntax              = errors.New("tag key is invalid, Use group, name or optional as tag keys")
+	errTagKeySyntax              = errors.New("tag key is invalid, Use group, name, optional, default, key or a namespaced key (e.g. acme.owner) as tag keys")
 	errTagValueSyntaxQuote       = errors.New(`tag value should start with double quote. i.e. key:"value" `)
 	errTagValueSyntaxEndingQuote = errors.New(`tag value should end in double quote. i.e. key:"value" `)
 )
@@ -186,33 +293,54 @@ func verifyValueQuote(value string) (string, error) {
 // Check whether the tag follows valid struct.
 // format and returns an error if it's invalid. (i.e. not following
 // tag:"value" space-separated list )
-// Currently dig accepts only 'name', 'group', 'optional' as valid tag keys.
-func verifyAnnotateTag(tag string) error {
+// Dig interprets only 'name', 'group', and 'optional', and Fx interprets
+// 'default' (see paramDefaults) and 'key' (see keyGroups). Other keys are accepted if they are
+// namespaced (see isCustomTagKey) and are passed through to dig untouched.
+//
+// Invalid keys are all reported, along with the first syntax error, if any,
+// after which the rest of the tag cannot be parsed.
+func verifyAnnotateTag(tag string) (errs error) {
 	tagIdx := 0
-	validKeys := map[string]struct{}{"group": {}, "optional": {}, "name": {}}
+	validKeys := map[string]struct{}{"group": {}, "optional": {}, "name": {}, _defaultTag: {}, _keyTag: {}}
 	for ; tag != ""; tagIdx++ {
 		if err := verifyTagsSpaceSeparated(tagIdx, tag); err != nil {
-			return err
+			return multierr.Append(errs, err)
 		}
 		i := 0
 		if strings.TrimSpace(tag) == "" {
-			return nil
+			return errs
 		}
 		// parsing the key i.e. till reaching colon :
 		for i < len(tag) && tag[i] != ':' {
 			i++
 		}
 		key := strings.TrimSpace(tag[:i])
-		if _, ok := validKeys[key]; !ok {
-			return errTagKeySyntax
+		if _, ok := validKeys[key]; !ok && !isCustomTagKey(key) {
+			errs = multierr.Append(errs, unknownTagKeyError(key))
+		}
+		if i == len(tag) {
+			// No value to parse after the key.
+			return multierr.Append(errs, errTagValueSyntaxQuote)
 		}
 		value, err := verifyValueQuote(tag[i+1:])
 		if err != nil {
-			return err
+			return multierr.Append(errs, err)
 		}
 		tag = value
 	}
-	return nil
+	return errs
+}
+
+// isCustomTagKey reports whether key is a user-defined tag key.
+// Custom keys must be namespaced with a dot, as in "acme.owner",
+// so that typos of the keys dig understands are still caught.
+func isCustomTagKey(key string) bool {
+	i := strings.IndexByte(key, '.')
+	if i <= 0 || i == len(key)-1 {
+		return false
+	}
+	// Go struct tag keys may not contain spaces, quotes, or colons.
+	return !strings.ContainsAny(key, " \t\"`:")
 }
 
 // Given func(T1, T2, T3, ..., TN), this generates a type roughly
@@ -237,10 +365,8 @@ func (pt paramTagsAnnotation) apply(ann *annotated) error {
 	if len(ann.ParamTags) > 0 {
 		return errors.New("cannot apply more than one line of ParamTags")
 	}
-	for _, tag := range pt.tags {
-		if err := verifyAnnotateTag(tag); err != nil {
-			return err
-		}
+	if err := checkTags(pt.tags, true /* params */); err != nil {
+		return err
 	}
 	ann.ParamTags = pt.tags
 	return nil
@@ -248,7 +374,10 @@ func (pt paramTagsAnnotation) apply(ann *annotated) error {
 
 // build builds and returns a constructor after applying a ParamTags annotation
 func (pt paramTagsAnnotation) build(ann *annotated) (interface{}, error) {
-	paramTypes, remap := pt.parameters(ann)
+	paramTypes, remap, err := pt.parameters(ann)
+	if err != nil {
+		return nil, err
+	}
 	resultTypes, _ := ann.currentResultTypes()
 
 	origFn := reflect.ValueOf(ann.Target)
@@ -266,6 +395,7 @@ func (pt paramTagsAnnotation) build(ann *annotated) (interface{}, error) {
 func (pt paramTagsAnnotation) parameters(ann *annotated) (
 	types []reflect.Type,
 	remap func([]reflect.Value) []reflect.Value,
+	err error,
 ) {
 	ft := reflect.TypeOf(ann.Target)
 	types = make([]reflect.Type, ft.NumIn())
@@ -278,7 +408,7 @@ func (pt paramTagsAnnotation) parameters(ann *annotated) (
 	if len(pt.tags) == 0 {
 		return types, func(args []reflect.Value) []reflect.Value {
 			return args
-		}
+		}, nil
 	}
 
 	// Turn parameters into an fx.In struct.
@@ -288,6 +418,15 @@ func (pt paramTagsAnnotation) parameters(ann *annotated) (
 	if len(types) > 0 && isIn(types[0]) {
 		paramType := types[0]
 
+		fieldTypes := make([]reflect.Type, paramType.NumField()-1)
+		for i := range fieldTypes {
+			fieldTypes[i] = paramType.Field(i + 1).Type
+		}
+		tags, err := pt.match(fieldTypes)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		for i := 1; i < paramType.NumField(); i++ {
 			origField := paramType.Field(i)
 			field := reflect.StructField{
@@ -295,8 +434,8 @@ func (pt paramTagsAnnotation) parameters(ann *annotated) (
 				Type: origField.Type,
 				Tag:  origField.Tag,
 			}
-			if i-1 < len(pt.tags) {
-				field.Tag = reflect.StructTag(pt.tags[i-1])
+			if tag, ok := tags[i-1]; ok {
+				field.Tag = reflect.StructTag(tag)
 			}
 
 			inFields = append(inFields, field)
@@ -310,7 +449,12 @@ func (pt paramTagsAnnotation) parameters(ann *annotated) (
 				args[0].Field(i).Set(param.Field(i))
 			}
 			return args
-		}
+		}, nil
+	}
+
+	tags, err := pt.match(types)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	for i, t := range types {
@@ -318,8 +462,8 @@ func (pt paramTagsAnnotation) parameters(ann *annotated) (
 			Name: fmt.Sprintf("Field%d", i),
 			Type: t,
 		}
-		if i < len(pt.tags) {
-			field.Tag = reflect.StructTag(pt.tags[i])
+		if tag, ok := tags[i]; ok {
+			field.Tag = reflect.StructTag(tag)
 		}
 
 		inFields = append(inFields, field)
@@ -333,7 +477,55 @@ func (pt paramTagsAnnotation) parameters(ann *annotated) (
 			args = append(args, params.Field(i+1))
 		}
 		return args
+	}, nil
+}
+
+// match maps the tags of the annotation to the positions of the given
+// parameter types that they apply to.
+//
+// Tags are matched to parameters in order, except that non-empty tags are
+// never applied to types provided by Fx itself, like [Lifecycle]:
+// these parameters are left untagged and the tag applies to the
+// next parameter instead.
+// Empty tags, like [Skip], apply to any parameter.
+func (pt paramTagsAnnotation) match(types []reflect.Type) (map[int]string, error) {
+	tags := make(map[int]string, len(pt.tags))
+	next, skipped := 0, false
+	for i, t := range types {
+		if next >= len(pt.tags) {
+			break
+		}
+
+		tag := pt.tags[next]
+		if _, ok := _frameworkTypes[t]; ok && tag != "" {
+			skipped = true
+			continue
+		}
+
+		tags[i] = tag
+		next++
+	}
+
+	// Tags beyond the last parameter have always been ignored, but
+	// don't silently drop tags that were pushed there by skipped parameters.
+	if skipped && next < len(pt.tags) {
+		return nil, fmt.Errorf(
+			"tag %q does not apply to any parameter: "+
+				"parameters provided by Fx, like fx.Lifecycle, cannot be tagged", pt.tags[next])
 	}
+	return tags, nil
+}
+
+// _frameworkTypes are the types that Fx provides to all applications.
+// They are never named or grouped.
+var _frameworkTypes = map[reflect.Type]struct{}{
+	reflect.TypeOf((*Lifecycle)(nil)).Elem():         {},
+	reflect.TypeOf((*Shutdowner)(nil)).Elem():        {},
+	reflect.TypeOf((*ShutdownScheduler)(nil)).Elem(): {},
+	reflect.TypeOf(DotGraph("")):                     {},
+	reflect.TypeOf((*AppContext)(nil)).Elem():        {},
+	reflect.TypeOf((*ErrGroup)(nil)):                 {},
+	reflect.TypeOf(AppInfo{}):                        {},
 }
 
 // ParamTags is an Annotation that annotates the parameter(s) of a function.
@@ -347,6 +539,22 @@ func (pt paramTagsAnnotation) parameters(ann *annotated) (
 //		// ...
 //	}, fx.ParamTags("", `name:"ro"`))
 //
+// Parameters of types provided by Fx, like [Lifecycle] and [Shutdowner],
+// can't be tagged. Positional matching skips them for non-empty tags, so
+// the following refers to the "ro" connection without a placeholder:
+//
+//	fx.Annotate(func(lc fx.Lifecycle, conn *sql.DB) *Handler {
+//		// ...
+//	}, fx.ParamTags(`name:"ro"`))
+//
+// Use [Skip] to leave a parameter untagged explicitly,
+// and [Tag] or [GroupTag] to build tags instead of writing them by hand.
+//
+// In addition to the name, group, and optional keys, tags may carry
+// user-defined metadata under namespaced keys like `acme.owner:"payments"`.
+// Fx passes these through to the container without interpreting them;
+// use [AnnotationTags] to read them back.
+//
 // ParamTags cannot be used in a function that takes an fx.In struct as a
 // parameter.
 func ParamTags(tags ...string) Annotation {
@@ -380,10 +588,8 @@ func (rt resultTagsAnnotation) apply(ann *annotated) error {
 	if len(ann.ResultTags) > 0 {
 		return errors.New("cannot apply more than one line of ResultTags")
 	}
-	for _, tag := range rt.tags {
-		if err := verifyAnnotateTag(tag); err != nil {
-			return err
-		}
+	if err := checkTags(rt.tags, false /* params */); err != nil {
+		return err
 	}
 	ann.ResultTags = rt.tags
 	return nil
@@ -538,6 +744,10 @@ func (rt resultTagsAnnotation) results(ann *annotated) (
 //		// ...
 //	}, fx.ResultTags(`name:"ro"`))
 //
+// As with [ParamTags], namespaced keys like `acme.owner:"payments"` may be
+// used to attach metadata that Fx passes through without interpreting.
+// Tags may be built with [Tag] or [GroupTag].
+//
 // ResultTags cannot be used on a function that returns an fx.Out struct.
 func ResultTags(tags ...string) Annotation {
 	return resultTagsAnnotation{tags}
@@ -660,8 +870,140 @@ func (la *lifecycleHookAnnotation) build(ann *annotated) (interface{}, error) {
 var (
 	_typeOfLifecycle = reflect.TypeOf((*Lifecycle)(nil)).Elem()
 	_typeOfContext   = reflect.TypeOf((*context.Context)(nil)).Elem()
+	_typeOfHookInfo  = reflect.TypeOf(HookInfo{})
 )
 
+// HookInfo describes a lifecycle hook appended by an [OnStart] or [OnStop]
+// annotation. Functions passed to OnStart and OnStop may accept a HookInfo
+// parameter to find out which annotated function the hook belongs to.
+//
+// Similarly, a *zap.Logger or *slog.Logger parameter of these functions
+// receives a logger with fields identifying the hook: its kind ("hook"),
+// the hook function ("callee"), the annotated function ("caller"),
+// and its module ("module"), if any. The logger is derived from the logger
+// of that type in the application, which must be provided
+// like any other dependency of these functions.
+//
+//	fx.Annotate(
+//		NewServer,
+//		fx.ResultTags(`name:"public"`),
+//		fx.OnStart(func(info fx.HookInfo, s *Server) error {
+//			log.Printf("starting %v", info.Results) // [*Server[name = "public"]]
+//			return s.Start()
+//		}),
+//	)
+type HookInfo struct {
+	// Hook is the kind of hook: "OnStart" or "OnStop".
+	Hook string
+
+	// Target is the name of the function passed to fx.Annotate.
+	Target string
+
+	// Results are the values produced by the annotated function after
+	// applying annotations like ResultTags and As, including their name
+	// or group if any.
+	Results []string
+
+	// Module is the name of the fx.Module the annotated function
+	// was provided to, if any.
+	Module string
+
+	// Method is the method run by the hook, like "(*main.Server).Start",
+	// if the hook is a method expression on one of the Results.
+	Method string
+}
+
+// hookInfo builds the HookInfo for hooks appended by this annotation.
+func (la *lifecycleHookAnnotation) hookInfo(ann *annotated, resultTypes []reflect.Type) HookInfo {
+	var results []string
+	for _, t := range resultTypes {
+		if !isOut(t) {
+			results = append(results, t.String())
+			continue
+		}
+		for i := 1; i < t.NumField(); i++ {
+			f := t.Field(i)
+			var toks []string
+			if name := f.Tag.Get(_nameTag); name != "" {
+				toks = append(toks, fmt.Sprintf("name = %q", name))
+			}
+			if group := f.Tag.Get(_groupTag); group != "" {
+				toks = append(toks, fmt.Sprintf("group = %q", group))
+			}
+			if len(toks) == 0 {
+				results = append(results, f.Type.String())
+				continue
+			}
+			results = append(results, fmt.Sprintf("%v[%v]", f.Type, strings.Join(toks, ", ")))
+		}
+	}
+	return HookInfo{
+		Hook:    la.String(),
+		Target:  ann.targetName,
+		Results: results,
+		Method:  hookMethod(reflect.ValueOf(la.Target), resultTypes),
+	}
+}
+
+// hookMethod returns the name of the method, like "(*main.Server).Start",
+// if hook is a method expression on one of the given result types,
+// and "" otherwise.
+func hookMethod(hook reflect.Value, resultTypes []reflect.Type) string {
+	ft := hook.Type()
+	if ft.NumIn() == 0 {
+		return ""
+	}
+	recv := ft.In(0)
+	if !slices.Contains(flattenResultTypes(resultTypes), recv) {
+		return ""
+	}
+
+	f := runtime.FuncForPC(hook.Pointer())
+	if f == nil {
+		return ""
+	}
+	name := f.Name()
+	m, ok := recv.MethodByName(name[strings.LastIndexByte(name, '.')+1:])
+	if !ok {
+		return ""
+	}
+
+	// Methods of concrete types take their receiver as first parameter,
+	// while methods of interfaces don't.
+	in := make([]reflect.Type, 0, ft.NumIn())
+	if recv.Kind() == reflect.Interface {
+		in = append(in, recv)
+	}
+	for i := 0; i < m.Type.NumIn(); i++ {
+		in = append(in, m.Type.In(i))
+	}
+	out := make([]reflect.Type, m.Type.NumOut())
+	for i := range out {
+		out[i] = m.Type.Out(i)
+	}
+	if reflect.FuncOf(in, out, false) != ft {
+		// A function named like one of the methods, e.g. a closure.
+		return ""
+	}
+	return fmt.Sprintf("(%v).%v", recv, m.Name)
+}
+
+// flattenResultTypes lists the types of the given results,
+// replacing fx.Out structs by the types of their fields.
+func flattenResultTypes(resultTypes []reflect.Type) []reflect.Type {
+	var types []reflect.Type
+	for _, t := range resultTypes {
+		if !isOut(t) {
+			types = append(types, t)
+			continue
+		}
+		for i := 1; i < t.NumField(); i++ {
+			types = append(types, t.Field(i).Type)
+		}
+	}
+	return types
+}
+
 // buildHookInstaller returns a function that appends a hook to Lifecycle when called,
 // along with the new parameter types and a function that maps arguments to the annotated constructor
 func (la *lifecycleHookAnnotation) buildHookInstaller(ann *annotated) (
@@ -679,9 +1021,13 @@ func (la *lifecycleHookAnnotation) buildHookInstaller(ann *annotated) (
 
 	// look for the context.Context type from the original hook function
 	// and then exclude it from the paramTypes of invokeFn because context.Context
-	// will be injected by the lifecycle
+	// will be injected by the lifecycle. The same goes for HookInfo,
+	// which is injected by us.
 	ctxPos := -1
 	ctxStructPos := -1
+	infoPos := -1
+	zapPos := -1
+	slogPos := -1
 	origHookFn := reflect.ValueOf(la.Target)
 	origHookFnT := reflect.TypeOf(la.Target)
 	invokeParamTypes := []reflect.Type{
@@ -693,6 +1039,21 @@ func (la *lifecycleHookAnnotation) buildHookInstaller(ann *annotated) (
 			ctxPos = i
 			continue
 		}
+		if t == _typeOfHookInfo && infoPos < 0 {
+			infoPos = i
+			continue
+		}
+		// Loggers are tagged with the hook's info below.
+		if t == _typeOfZapLogger && zapPos < 0 {
+			zapPos = i
+			invokeParamTypes = append(invokeParamTypes, t)
+			continue
+		}
+		if t == _typeOfSlogLogger && slogPos < 0 {
+			slogPos = i
+			invokeParamTypes = append(invokeParamTypes, t)
+			continue
+		}
 		if !isIn(t) {
 			invokeParamTypes = append(invokeParamTypes, origHookFnT.In(i))
 			continue
@@ -710,46 +1071,52 @@ func (la *lifecycleHookAnnotation) buildHookInstaller(ann *annotated) (
 		invokeParamTypes = append(invokeParamTypes, reflect.StructOf(fields))
 
 	}
+	info := la.hookInfo(ann, resultTypes)
+	funcName := fxreflect.FuncName(la.Target)
 	invokeFnT := reflect.FuncOf(invokeParamTypes, []reflect.Type{}, false)
 	invokeFn := reflect.MakeFunc(invokeFnT, func(args []reflect.Value) (results []reflect.Value) {
 		lc := args[0].Interface().(Lifecycle)
 		args = args[1:]
 		hookArgs := make([]reflect.Value, origHookFnT.NumIn())
 
+		info := info
+		info.Module = hookModule(lc)
+
 		hookFn := func(ctx context.Context) (err error) {
-			// If the hook function has multiple parameters, and the first
-			// parameter is a context, inject the provided context.
-			if ctxStructPos < 0 {
-				offset := 0
-				for i := 0; i < len(hookArgs); i++ {
-					if i == ctxPos {
-						hookArgs[i] = reflect.ValueOf(ctx)
-						offset = 1
-						continue
-					}
-					if i-offset >= 0 && i-offset < len(args) {
-						hookArgs[i] = args[i-offset]
-					}
-				}
-			} else {
-				for i := 0; i < origHookFnT.NumIn(); i++ {
-					if i != ctxStructPos {
-						hookArgs[i] = args[i]
-						continue
-					}
+			// Inject the provided context and HookInfo into the hook
+			// function's parameters, and fill the rest from args.
+			argIdx := 0
+			for i := 0; i < len(hookArgs); i++ {
+				switch {
+				case i == infoPos:
+					hookArgs[i] = reflect.ValueOf(info)
+					continue
+				case i == zapPos:
+					log, _ := args[argIdx].Interface().(*zap.Logger)
+					hookArgs[i] = reflect.ValueOf(hookZapLogger(log, info, funcName))
+				case i == slogPos:
+					log, _ := args[argIdx].Interface().(*slog.Logger)
+					hookArgs[i] = reflect.ValueOf(hookSlogLogger(log, info, funcName))
+				case i == ctxPos && ctxStructPos < 0:
+					hookArgs[i] = reflect.ValueOf(ctx)
+					continue
+				case i == ctxStructPos:
 					t := origHookFnT.In(i)
 					v := reflect.New(t).Elem()
 					for j := 1; j < t.NumField(); j++ {
 						if j < ctxPos {
-							v.Field(j).Set(args[i].Field(j))
+							v.Field(j).Set(args[argIdx].Field(j))
 						} else if j == ctxPos {
 							v.Field(j).Set(reflect.ValueOf(ctx))
 						} else {
-							v.Field(j).Set(args[i].Field(j - 1))
+							v.Field(j).Set(args[argIdx].Field(j - 1))
 						}
 					}
 					hookArgs[i] = v
+				default:
+					hookArgs[i] = args[argIdx]
 				}
+				argIdx++
 			}
 			hookResults := origHookFn.Call(hookArgs)
 			if len(hookResults) > 0 && hookResults[0].Type() == _typeOfError {
@@ -757,7 +1124,11 @@ func (la *lifecycleHookAnnotation) buildHookInstaller(ann *annotated) (
 			}
 			return err
 		}
-		lc.Append(la.buildHook(hookFn))
+		hook := la.buildHook(hookFn, funcName)
+		if la.Type == _onStartHookType && ann.StartRetry != nil {
+			hook.OnStart = retryHook(lc, *ann.StartRetry, funcName, info.Target, hookFn)
+		}
+		lc.Append(hook)
 		return results
 	})
 
@@ -957,12 +1328,16 @@ func lifecycleExists(paramTypes []reflect.Type) bool {
 	return false
 }
 
-func (la *lifecycleHookAnnotation) buildHook(fn func(context.Context) error) (hook Hook) {
+// buildHook builds a hook named after the annotation's function,
+// so that events report it rather than the wrapper calling it.
+func (la *lifecycleHookAnnotation) buildHook(fn func(context.Context) error, name string) (hook Hook) {
 	switch la.Type {
 	case _onStartHookType:
 		hook.OnStart = fn
+		hook.onStartName = name
 	case _onStopHookType:
 		hook.OnStop = fn
+		hook.onStopName = name
 	}
 	return hook
 }
@@ -995,6 +1370,18 @@ func (la *lifecycleHookAnnotation) buildHook(fn func(context.Context) error) (ho
 //	   }
 //	 )
 //
+// The hook may also be a method expression on a value produced by the
+// annotated function, in which case the method of that value runs:
+//
+//	fx.Provide(
+//		fx.Annotate(
+//			NewServer,
+//			fx.OnStart((*Server).Listen),
+//		)
+//	)
+//
+// Events and [HookInfo] then identify the hook by its method.
+//
 // It is also possible to use OnStart annotation with other parameter and result
 // annotations, provided that the parameter of the function passed to OnStart
 // matches annotated parameters and results.
@@ -1023,7 +1410,7 @@ func (la *lifecycleHookAnnotation) buildHook(fn func(context.Context) error) (ho
 // however functions may be annotated with other types of lifecycle Hooks, such
 // as OnStop. The hook function passed into OnStart cannot take any arguments
 // outside of the annotated constructor's existing dependencies or results, except
-// a context.Context.
+// a context.Context or an [HookInfo] describing the hook.
 func OnStart(onStart interface{}) Annotation {
 	return &lifecycleHookAnnotation{
 		Type:   _onStartHookType,
@@ -1059,6 +1446,9 @@ func OnStart(onStart interface{}) Annotation {
 //	   }
 //	 )
 //
+// As with [OnStart], the hook may also be a method expression on a value
+// produced by the annotated function, as in fx.OnStop((*Server).Shutdown).
+//
 // It is also possible to use OnStop annotation with other parameter and result
 // annotations, provided that the parameter of the function passed to OnStop
 // matches annotated parameters and results.
@@ -1087,7 +1477,7 @@ func OnStart(onStart interface{}) Annotation {
 // however functions may be annotated with other types of lifecycle Hooks, such
 // as OnStart. The hook function passed into OnStop cannot take any arguments
 // outside of the annotated constructor's existing dependencies or results, except
-// a context.Context.
+// a context.Context or an [HookInfo] describing the hook.
 func OnStop(onStop interface{}) Annotation {
 	return &lifecycleHookAnnotation{
 		Type:   _onStopHookType,
@@ -1533,6 +1923,10 @@ type annotated struct {
 	From        []reflect.Type
 	FuncPtr     uintptr
 	Hooks       []*lifecycleHookAnnotation
+	// StartRetry, if set, is the policy to retry the OnStart hook with.
+	StartRetry *RetryPolicy
+	// name of the function passed to fx.Annotate, reported in HookInfo.
+	targetName string
 	// container is used to build private scopes for lifecycle hook functions
 	// added via fx.OnStart and fx.OnStop annotations.
 	container *dig.Container
@@ -1561,10 +1955,12 @@ func (ann annotated) String() string {
 // results wrapping the original constructor passed to fx.Annotate.
 func (ann *annotated) Build() (interface{}, error) {
 	ann.container = dig.New()
+	ann.targetName = fxreflect.FuncName(ann.Target)
 	ft := reflect.TypeOf(ann.Target)
 	if ft.Kind() != reflect.Func {
 		return nil, fmt.Errorf("must provide constructor function, got %v (%T)", ann.Target, ann.Target)
 	}
+	ann.Target, _ = foldErrors(ann.Target)
 
 	if err := ann.typeCheckOrigFn(); err != nil {
 		return nil, fmt.Errorf("invalid annotation function %T: %w", ann.Target, err)
@@ -1812,14 +2208,56 @@ func (ann *annotated) currentParamTypes() []reflect.Type {
 //	}
 func Annotate(t interface{}, anns ...Annotation) interface{} {
 	result := annotated{Target: t}
+	var tagErrs error
 	for _, ann := range anns {
 		if err := ann.apply(&result); err != nil {
+			var tagErr *TagError
+			if errors.As(err, &tagErr) {
+				// Keep going to report the problems
+				// of all tags at once.
+				tagErrs = multierr.Append(tagErrs, err)
+				continue
+			}
 			return annotationError{
 				target: t,
-				err:    err,
+				err:    multierr.Append(tagErrs, err),
 			}
 		}
 	}
+	if tagErrs != nil {
+		return annotationError{
+			target: t,
+			err:    tagErrs,
+		}
+	}
 	result.Annotations = anns
 	return result
 }
+
+// AnnotationTags reports the tags attached by [ParamTags] and [ResultTags]
+// to a function returned by [Annotate]. Tooling may use it to read
+// user-defined metadata from the tags:
+//
+//	params, results, _ := fx.AnnotationTags(f)
+//	owner := results[0].Get("acme.owner")
+//
+// Positions without a tag hold an empty tag.
+// ok is false if f was not produced by a successful call to Annotate.
+func AnnotationTags(f interface{}) (params, results []reflect.StructTag, ok bool) {
+	ann, ok := f.(annotated)
+	if !ok {
+		return nil, nil, false
+	}
+	return structTags(ann.ParamTags), structTags(ann.ResultTags), true
+}
+
+func structTags(tags []string) []reflect.StructTag {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make([]reflect.StructTag, len(tags))
+	for i, tag := range tags {
+		out[i] = reflect.StructTag(tag)
+	}
+	return out
+}
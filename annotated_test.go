@@ -26,6 +26,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -36,6 +37,10 @@ import (
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxevent"
 	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxlog"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestAnnotated(t *testing.T) {
@@ -69,6 +74,89 @@ func TestAnnotated(t *testing.T) {
 		assert.NotNil(t, in.A, "expected in.A to be injected")
 		assert.Equal(t, "foo", in.A.name, "expected to get a type 'a' of name 'foo'")
 	})
+
+	t.Run("Annotate target", func(t *testing.T) {
+		t.Parallel()
+
+		var got struct {
+			fx.In
+
+			A        *a           `name:"foo"`
+			Stringer fmt.Stringer `name:"bar"`
+		}
+		app := fxtest.New(t,
+			fx.Supply(fx.Annotated{Name: "src", Target: "foo"}),
+			fx.Provide(
+				fx.Annotated{
+					Name: "foo",
+					Target: fx.Annotate(
+						func(name string) (*a, error) { return &a{name: name}, nil },
+						fx.ParamTags(`name:"src"`),
+					),
+				},
+				fx.Annotated{
+					Name: "bar",
+					Target: fx.Annotate(
+						func() *asStringer { return &asStringer{name: "bar"} },
+						fx.As(new(fmt.Stringer)),
+					),
+				},
+			),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Equal(t, "foo", got.A.name)
+		assert.Equal(t, "bar", got.Stringer.String())
+	})
+
+	t.Run("Annotate target in group", func(t *testing.T) {
+		t.Parallel()
+
+		var got struct {
+			fx.In
+
+			As []*a `group:"as"`
+		}
+		app := fxtest.New(t,
+			fx.Provide(
+				fx.Annotated{Group: "as", Target: fx.Annotate(newA)},
+				fx.Annotated{Group: "as", Target: fx.Annotate(newA)},
+			),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Len(t, got.As, 2)
+	})
+
+	t.Run("Annotate target already named", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(fx.Annotated{
+				Name:   "foo",
+				Target: fx.Annotate(newA, fx.ResultTags(`name:"bar"`)),
+			}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `cannot apply name:"foo" to *fx_test.a: already named "bar" by fx.Annotate`)
+	})
+
+	t.Run("Annotate target error", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(fx.Annotated{
+				Name:   "foo",
+				Target: fx.Annotate(newA, fx.ResultTags(`name:"x"`), fx.ResultTags(`name:"y"`)),
+			}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot apply more than one line of ResultTags")
+	})
 }
 
 type fromStringer struct {
@@ -1678,6 +1766,121 @@ func TestAnnotate(t *testing.T) {
 	})
 }
 
+func TestAnnotateParamTagsFrameworkTypes(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ name string }
+
+	provideA := fx.Provide(
+		fx.Annotate(func() *A { return &A{"named"} }, fx.ResultTags(`name:"a"`)),
+		func() *A { return &A{"unnamed"} },
+	)
+
+	tests := []struct {
+		desc   string
+		invoke interface{}
+		want   string
+	}{
+		{
+			desc: "lifecycle without placeholder",
+			invoke: fx.Annotate(func(_ fx.Lifecycle, a *A) string {
+				return a.name
+			}, fx.ParamTags(`name:"a"`)),
+			want: "named",
+		},
+		{
+			desc: "lifecycle with placeholder",
+			invoke: fx.Annotate(func(_ fx.Lifecycle, a *A) string {
+				return a.name
+			}, fx.ParamTags(``, `name:"a"`)),
+			want: "named",
+		},
+		{
+			desc: "skip",
+			invoke: fx.Annotate(func(_ fx.Lifecycle, u *A, a *A) string {
+				return u.name + "," + a.name
+			}, fx.ParamTags(fx.Skip(), fx.Skip(), `name:"a"`)),
+			want: "unnamed,named",
+		},
+		{
+			desc: "many framework types",
+			invoke: fx.Annotate(func(
+				_ fx.Shutdowner, _ fx.Lifecycle, a *A, _ fx.DotGraph, u *A,
+			) string {
+				return a.name + "," + u.name
+			}, fx.ParamTags(`name:"a"`)),
+			want: "named,unnamed",
+		},
+		{
+			desc: "app context",
+			invoke: fx.Annotate(func(_ fx.AppContext, a *A) string {
+				return a.name
+			}, fx.ParamTags(`name:"a"`)),
+			want: "named",
+		},
+		{
+			desc: "error group",
+			invoke: fx.Annotate(func(_ *fx.ErrGroup, a *A) string {
+				return a.name
+			}, fx.ParamTags(`name:"a"`)),
+			want: "named",
+		},
+		{
+			desc: "app info",
+			invoke: fx.Annotate(func(_ fx.AppInfo, a *A) string {
+				return a.name
+			}, fx.ParamTags(`name:"a"`)),
+			want: "named",
+		},
+		{
+			desc: "variadic",
+			invoke: fx.Annotate(func(_ fx.Lifecycle, a *A, _ ...string) string {
+				return a.name
+			}, fx.ParamTags(`name:"a"`)),
+			want: "named",
+		},
+		{
+			desc: "with hook",
+			invoke: fx.Annotate(func(_ fx.Lifecycle, a *A) string {
+				return a.name
+			},
+				fx.ParamTags(`name:"a"`),
+				fx.OnStart(func(context.Context, fx.Lifecycle) error { return nil }),
+			),
+			want: "named",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var got string
+			app := fxtest.New(t,
+				provideA,
+				fx.Provide(tt.invoke),
+				fx.Populate(&got),
+			)
+			defer app.RequireStart().RequireStop()
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("tag left over", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			provideA,
+			fx.Invoke(fx.Annotate(func(*A, fx.Lifecycle) {}, fx.ParamTags(``, `name:"a"`))),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `tag "name:\"a\"" does not apply to any parameter`)
+	})
+}
+
 func TestAnnotateApplyFail(t *testing.T) {
 	type a struct{}
 	type b struct{ a *a }
@@ -1688,7 +1891,7 @@ func TestAnnotateApplyFail(t *testing.T) {
 
 	var (
 		errTagSyntaxSpace            = `multiple tags are not separated by space`
-		errTagKeySyntax              = "tag key is invalid, Use group, name or optional as tag keys"
+		errTagKeySyntax              = "tag key is invalid, Use group, name, optional, default, key or a namespaced key (e.g. acme.owner) as tag keys"
 		errTagValueSyntaxQuote       = `tag value should start with double quote. i.e. key:"value" `
 		errTagValueSyntaxEndingQuote = `tag value should end in double quote. i.e. key:"value" `
 	)
@@ -1722,12 +1925,42 @@ func TestAnnotateApplyFail(t *testing.T) {
 			giveAnnotationParam:  fx.ParamTags(`name1:"something"`),
 			giveAnnotationResult: fx.ResultTags(`name1:"something"`),
 		},
+		{
+			give:                 "Tags custom key not namespaced",
+			wantErr:              errTagKeySyntax,
+			giveAnnotationParam:  fx.ParamTags(`.owner:"payments"`),
+			giveAnnotationResult: fx.ResultTags(`acme.:"payments"`),
+		},
 		{
 			give:                 "Tags key empty",
 			wantErr:              errTagKeySyntax,
 			giveAnnotationParam:  fx.ParamTags(`:"something"`),
 			giveAnnotationResult: fx.ResultTags(`:"something"`),
 		},
+		{
+			give:                 "Tags key misspelled",
+			wantErr:              errTagKeySyntax + ": did you mean `name:`?",
+			giveAnnotationParam:  fx.ParamTags(`nmae:"something"`),
+			giveAnnotationResult: fx.ResultTags(`Name:"something"`),
+		},
+		{
+			give:                 "Tags group option used as key",
+			wantErr:              errTagKeySyntax + ": did you mean `group:`?",
+			giveAnnotationParam:  fx.ParamTags(`soft:"true"`),
+			giveAnnotationResult: fx.ResultTags(`flatten:"true"`),
+		},
+		{
+			give:                 "Tags key without value",
+			wantErr:              errTagValueSyntaxQuote,
+			giveAnnotationParam:  fx.ParamTags(`name`),
+			giveAnnotationResult: fx.ResultTags(`optional`),
+		},
+		{
+			give:                 "Tags name and group",
+			wantErr:              `cannot use named values with value groups: name:"foo" used with group:"bar"`,
+			giveAnnotationParam:  fx.ParamTags(`name:"foo" group:"bar"`),
+			giveAnnotationResult: fx.ResultTags(`group:"bar" name:"foo"`),
+		},
 	}
 	for _, tt := range tests {
 		t.Run("Param "+tt.give, func(t *testing.T) {
@@ -1757,6 +1990,44 @@ func TestAnnotateApplyFail(t *testing.T) {
 	}
 }
 
+func TestAnnotateTagErrors(t *testing.T) {
+	t.Parallel()
+
+	app := NewForTest(t,
+		fx.Provide(
+			fx.Annotate(
+				func(string, int) (string, int) { return "", 0 },
+				fx.ParamTags(`nmae:"foo" optinal:"true"`, `group:"bar,flatten"`),
+				fx.ResultTags(`group:"baz,soft"`, `name:"qux`),
+			),
+		),
+	)
+	err := app.Err()
+	require.Error(t, err)
+
+	var tagErrs []*fx.TagError
+	for _, err := range multierr.Errors(errors.Unwrap(err)) {
+		var tagErr *fx.TagError
+		require.ErrorAs(t, err, &tagErr)
+		tagErrs = append(tagErrs, tagErr)
+	}
+	require.Len(t, tagErrs, 5, "all tag problems should be reported: %v", err)
+
+	assert.Equal(t, "fx.ParamTags", tagErrs[0].Annotation)
+	assert.Equal(t, 0, tagErrs[0].Index)
+	assert.Equal(t, `nmae:"foo" optinal:"true"`, tagErrs[0].Tag)
+	assert.ErrorContains(t, tagErrs[0], "did you mean `name:`?")
+	assert.ErrorContains(t, tagErrs[1], "did you mean `optional:`?")
+
+	assert.Equal(t, 1, tagErrs[2].Index)
+	assert.ErrorContains(t, tagErrs[2], "cannot use flatten in parameter value groups")
+
+	assert.Equal(t, "fx.ResultTags", tagErrs[3].Annotation)
+	assert.ErrorContains(t, tagErrs[3], "cannot use soft with result value groups")
+	assert.ErrorContains(t, tagErrs[4], "tag value should end in double quote")
+	assert.Equal(t, "fx.ResultTags tag 1 (`name:\"qux`): "+tagErrs[4].Err.Error(), tagErrs[4].Error())
+}
+
 func TestAnnotateApplySuccess(t *testing.T) {
 	type a struct{}
 	type b struct{ a *a }
@@ -1785,6 +2056,11 @@ func TestAnnotateApplySuccess(t *testing.T) {
 			giveAnnotationParam:  fx.ParamTags(`name:"version\\Num"`, `  `),
 			giveAnnotationResult: fx.ResultTags(``, `group:"version\\Num"`),
 		},
+		{
+			give:                 "Tags with custom namespaced keys",
+			giveAnnotationParam:  fx.ParamTags(`acme.owner:"payments"`),
+			giveAnnotationResult: fx.ResultTags(`acme.owner:"payments" acme.tier:"1"`),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.give, func(t *testing.T) {
@@ -1803,6 +2079,55 @@ func TestAnnotateApplySuccess(t *testing.T) {
 	}
 }
 
+func TestAnnotationTags(t *testing.T) {
+	t.Parallel()
+
+	type a struct{}
+	newA := func() *a { return &a{} }
+
+	t.Run("custom keys reach the container", func(t *testing.T) {
+		t.Parallel()
+
+		var got *a
+		app := NewForTest(t,
+			fx.Provide(
+				fx.Annotate(newA, fx.ResultTags(`name:"primary" acme.owner:"payments"`)),
+			),
+			fx.Invoke(fx.Annotate(func(v *a) { got = v }, fx.ParamTags(`name:"primary" acme.sla:"gold"`))),
+		)
+		require.NoError(t, app.Err())
+		assert.NotNil(t, got)
+	})
+
+	t.Run("reports tags", func(t *testing.T) {
+		t.Parallel()
+
+		f := fx.Annotate(
+			func(string, int) *a { return nil },
+			fx.ParamTags(fx.Skip(), `acme.sla:"gold"`),
+			fx.ResultTags(`name:"primary" acme.owner:"payments"`),
+		)
+		params, results, ok := fx.AnnotationTags(f)
+		require.True(t, ok)
+		require.Len(t, params, 2)
+		assert.Empty(t, params[0])
+		assert.Equal(t, "gold", params[1].Get("acme.sla"))
+		require.Len(t, results, 1)
+		assert.Equal(t, "payments", results[0].Get("acme.owner"))
+		assert.Equal(t, "primary", results[0].Get("name"))
+	})
+
+	t.Run("not annotated", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, ok := fx.AnnotationTags(newA)
+		assert.False(t, ok)
+
+		_, _, ok = fx.AnnotationTags(fx.Annotate(newA, fx.ResultTags(`nmae:"x"`)))
+		assert.False(t, ok)
+	})
+}
+
 func assertApp(
 	t *testing.T,
 	app interface {
@@ -1833,6 +2158,23 @@ func assertApp(
 	defer app.Stop(ctx)
 }
 
+// hookServer has methods used as hooks by TestHookAnnotations.
+type hookServer struct {
+	info    fx.HookInfo
+	started bool
+	stopped bool
+}
+
+func (s *hookServer) Start(ctx context.Context, info fx.HookInfo) error {
+	s.info = info
+	s.started = true
+	return ctx.Err()
+}
+
+func (s *hookServer) Stop() { s.stopped = true }
+
+type hookStopper interface{ Stop() }
+
 func TestHookAnnotations(t *testing.T) {
 	t.Parallel()
 
@@ -2277,6 +2619,191 @@ func TestHookAnnotations(t *testing.T) {
 		require.NoError(t, app.Err())
 		defer app.RequireStart().RequireStop()
 	})
+
+	t.Run("inject hook info", func(t *testing.T) {
+		t.Parallel()
+
+		type stringer interface{ String() string }
+		type namedB struct {
+			fx.In
+			B *b `name:"b"`
+		}
+
+		var infos []fx.HookInfo
+		app := fxtest.New(t,
+			fx.Supply(&a{}),
+			fx.Provide(
+				fx.Annotate(
+					newB,
+					fx.ResultTags(`name:"b"`),
+					fx.OnStart(func(info fx.HookInfo, ctx context.Context, _ namedB) {
+						require.NotNil(t, ctx, "context not correctly injected")
+						infos = append(infos, info)
+					}),
+				),
+				fx.Annotate(
+					func() *bytes.Buffer { return bytes.NewBufferString("buf") },
+					fx.As(new(stringer)),
+					fx.OnStop(func(s stringer, info fx.HookInfo) {
+						assert.Equal(t, "buf", s.String())
+						infos = append(infos, info)
+					}),
+				),
+			),
+			fx.Invoke(fx.Annotate(func(*b, stringer) {}, fx.ParamTags(`name:"b"`))),
+		)
+		app.RequireStart().RequireStop()
+
+		require.Len(t, infos, 2)
+		assert.Equal(t, "OnStart", infos[0].Hook)
+		assert.Contains(t, infos[0].Target, "TestHookAnnotations")
+		assert.Equal(t, []string{`*fx_test.b[name = "b"]`}, infos[0].Results)
+		assert.Equal(t, "OnStop", infos[1].Hook)
+		assert.Contains(t, infos[1].Target, "TestHookAnnotations")
+		assert.Equal(t, []string{"fx_test.stringer"}, infos[1].Results)
+	})
+
+	t.Run("method expression hooks", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		var (
+			srv     *hookServer
+			stopper hookStopper
+		)
+		app := fxtest.New(t,
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.Provide(
+				fx.Annotate(
+					func() *hookServer { return &hookServer{} },
+					fx.OnStart((*hookServer).Start),
+				),
+				fx.Annotate(
+					func() *hookServer { return &hookServer{} },
+					fx.As(new(hookStopper)),
+					fx.OnStop(hookStopper.Stop),
+				),
+			),
+			fx.Populate(&srv, &stopper),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.True(t, srv.started)
+		assert.Equal(t, "(*fx_test.hookServer).Start", srv.info.Method)
+		assert.Equal(t, []string{"*fx_test.hookServer"}, srv.info.Results)
+		assert.True(t, stopper.(*hookServer).stopped)
+
+		starts := spy.Events().SelectByTypeName("OnStartExecuted")
+		require.Len(t, starts, 1)
+		assert.Equal(t, "go.uber.org/fx_test.(*hookServer).Start()", starts[0].(*fxevent.OnStartExecuted).FunctionName)
+		stops := spy.Events().SelectByTypeName("OnStopExecuted")
+		require.Len(t, stops, 1)
+		assert.Equal(t, "go.uber.org/fx_test.hookStopper.Stop()", stops[0].(*fxevent.OnStopExecuted).FunctionName)
+	})
+
+	t.Run("hooks named like methods are not methods", func(t *testing.T) {
+		t.Parallel()
+
+		var info fx.HookInfo
+		app := fxtest.New(t,
+			fx.Provide(
+				fx.Annotate(
+					func() *hookServer { return &hookServer{} },
+					fx.OnStart(func(_ *hookServer, i fx.HookInfo) { info = i }),
+				),
+			),
+			fx.Invoke(func(*hookServer) {}),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.Equal(t, "OnStart", info.Hook)
+		assert.Empty(t, info.Method)
+	})
+
+	t.Run("inject hook info into param struct hook", func(t *testing.T) {
+		t.Parallel()
+
+		type hookParam struct {
+			fx.In
+			Ctx context.Context
+			B   *b
+		}
+
+		var info fx.HookInfo
+		app := fxtest.New(t,
+			fx.Supply(&a{}),
+			fx.Provide(
+				fx.Annotate(
+					newB,
+					fx.OnStart(func(i fx.HookInfo, p hookParam) {
+						require.NotNil(t, p.Ctx, "context not correctly injected")
+						require.NotNil(t, p.B)
+						info = i
+					}),
+				),
+			),
+			fx.Invoke(newC),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.Equal(t, "OnStart", info.Hook)
+		assert.Equal(t, []string{"*fx_test.b"}, info.Results)
+	})
+
+	t.Run("inject hook loggers", func(t *testing.T) {
+		t.Parallel()
+
+		core, logs := observer.New(zap.InfoLevel)
+		var buf bytes.Buffer
+		app := fxtest.New(t,
+			fx.Supply(zap.New(core), slog.New(slog.NewTextHandler(&buf, nil))),
+			fx.Module("server",
+				fx.Provide(
+					fx.Annotate(
+						func(*zap.Logger, *slog.Logger) *a { return &a{} },
+						fx.OnStart(func(log *zap.Logger, info fx.HookInfo) {
+							assert.Equal(t, "server", info.Module)
+							log.Info("starting")
+						}),
+						fx.OnStop(func(_ context.Context, log *slog.Logger) {
+							log.Info("stopping")
+						}),
+					),
+				),
+			),
+			fx.Invoke(func(*a) {}),
+		)
+		app.RequireStart().RequireStop()
+
+		entries := logs.FilterMessage("starting").All()
+		require.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "OnStart", fields["hook"])
+		assert.Contains(t, fields["callee"], "TestHookAnnotations")
+		assert.Contains(t, fields["caller"], "TestHookAnnotations")
+		assert.Equal(t, "server", fields["module"])
+
+		out := buf.String()
+		assert.Contains(t, out, "msg=stopping hook=OnStop callee=")
+		assert.Contains(t, out, "module=server")
+	})
+
+	t.Run("hook loggers are required", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.Provide(
+				fx.Annotate(
+					func() *a { return &a{} },
+					fx.OnStart(func(*zap.Logger) {}),
+				),
+			),
+			fx.Invoke(func(*a) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *zap.Logger")
+	})
 }
 
 func TestHookAnnotationFailures(t *testing.T) {
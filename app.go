@@ -28,6 +28,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/dig"
@@ -65,18 +66,86 @@ type Option interface {
 //
 // Similar to invocations, errors are applied in order. All Provide and Invoke
 // options registered before or after an Error option will not be applied.
+//
+// Each error is wrapped in an [*OptionError] recording where Error was called,
+// and reported to the [fxevent.Logger] with an [fxevent.OptionError] event.
+// Use [errors.As] to retrieve the location of the first error,
+// or [multierr.Errors] to inspect each of them.
 func Error(errs ...error) Option {
-	return errorOption(errs)
+	return errorOption{
+		Errs:  errs,
+		Stack: fxreflect.CallerStack(1, 0),
+	}
+}
+
+type errorOption struct {
+	Errs  []error
+	Stack fxreflect.Stack
+}
+
+func (o errorOption) apply(mod *module) {
+	for _, err := range o.Errs {
+		if err == nil {
+			continue
+		}
+		optErr := &OptionError{
+			Err:         err,
+			ModuleName:  mod.name,
+			ModuleTrace: append([]string{o.Stack[0].String()}, mod.trace...),
+			StackTrace:  o.Stack.Strings(),
+		}
+		mod.app.err = multierr.Append(mod.app.err, optErr)
+		mod.app.optionErrors = append(mod.app.optionErrors, optErr)
+	}
+}
+
+func (o errorOption) String() string {
+	return fmt.Sprintf("fx.Error(%v)", multierr.Combine(o.Errs...))
 }
 
-type errorOption []error
+// OptionError is an error registered with the application by [Error],
+// along with the location that registered it.
+// It lets applications that fail because a module reported an error
+// point at that module.
+type OptionError struct {
+	// Err is the error passed to Error.
+	Err error
+
+	// ModuleName is the name of the module Error was passed to,
+	// or empty if it was passed to the top-level App.
+	ModuleName string
+
+	// ModuleTrace lists the call site of Error,
+	// followed by the modules it was passed to,
+	// from the innermost to the top-level App.
+	ModuleTrace []string
+
+	// StackTrace is the stack trace of the call to Error.
+	StackTrace []string
+}
+
+// Error returns the message of the original error.
+func (e *OptionError) Error() string {
+	return e.Err.Error()
+}
 
-func (errs errorOption) apply(mod *module) {
-	mod.app.err = multierr.Append(mod.app.err, multierr.Combine(errs...))
+// Unwrap returns the original error.
+func (e *OptionError) Unwrap() error {
+	return e.Err
 }
 
-func (errs errorOption) String() string {
-	return fmt.Sprintf("fx.Error(%v)", multierr.Combine(errs...))
+// logOptionErrors reports the errors registered with fx.Error
+// since the last call.
+func (app *App) logOptionErrors() {
+	for _, e := range app.optionErrors {
+		app.log().LogEvent(&fxevent.OptionError{
+			Err:         e.Err,
+			ModuleName:  e.ModuleName,
+			ModuleTrace: e.ModuleTrace,
+			StackTrace:  e.StackTrace,
+		})
+	}
+	app.optionErrors = nil
 }
 
 // Options bundles a group of options together into a single option.
@@ -134,6 +203,71 @@ func (t startTimeoutOption) String() string {
 	return fmt.Sprintf("fx.StartTimeout(%v)", time.Duration(t))
 }
 
+// FairStartBudget divides the start timeout among [OnStart] hooks,
+// so that a slow hook can't use up the time the hooks after it need.
+//
+// Before each OnStart hook runs, the time left before the start timeout
+// is divided equally among the hooks left to run,
+// and the hook's context expires once it has used its share.
+// A hook that runs for longer than its share fails the start of the
+// application with an error naming it and its share,
+// unless the start timeout expired first.
+//
+// Time a hook leaves unused is shared among the hooks after it.
+// Hooks only get a share if the context passed to [App.Start] has a
+// deadline, so FairStartBudget has no effect with [MonotonicTimeouts].
+func FairStartBudget() Option {
+	return fairStartBudgetOption{}
+}
+
+type fairStartBudgetOption struct{}
+
+func (fairStartBudgetOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.FairStartBudget Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.fairStartBudget = true
+	}
+}
+
+func (fairStartBudgetOption) String() string {
+	return "fx.FairStartBudget()"
+}
+
+// SlowHookThreshold makes the application report [OnStart] hooks that run
+// for longer than threshold with an [fxevent.OnStartSlow] event,
+// repeated every threshold until the hook returns.
+// This lets operators see which hook is holding up the start of an
+// application before its start timeout elapses.
+//
+//	fx.New(
+//		fx.StartTimeout(time.Minute),
+//		fx.SlowHookThreshold(10*time.Second),
+//		...
+//	)
+func SlowHookThreshold(threshold time.Duration) Option {
+	return slowHookThresholdOption(threshold)
+}
+
+type slowHookThresholdOption time.Duration
+
+func (o slowHookThresholdOption) apply(m *module) {
+	switch {
+	case m.parent != nil:
+		m.app.err = fmt.Errorf("fx.SlowHookThreshold Option should be passed to top-level App, " +
+			"not to fx.Module")
+	case o <= 0:
+		m.app.err = fmt.Errorf("fx.SlowHookThreshold must be positive, got %v", time.Duration(o))
+	default:
+		m.app.slowHookThreshold = time.Duration(o)
+	}
+}
+
+func (o slowHookThresholdOption) String() string {
+	return fmt.Sprintf("fx.SlowHookThreshold(%v)", time.Duration(o))
+}
+
 // StopTimeout changes the application's stop timeout.
 // This controls the total time that all [OnStop] hooks have to complete.
 // If the timeout is exceeded, the application will exit early.
@@ -158,9 +292,81 @@ func (t stopTimeoutOption) String() string {
 	return fmt.Sprintf("fx.StopTimeout(%v)", time.Duration(t))
 }
 
+// StopPolicy controls how [App.Stop] handles errors returned by [OnStop]
+// hooks. See [StopErrorPolicy].
+type StopPolicy int
+
+const (
+	// StopContinueAndAggregate runs every OnStop hook, even after a hook
+	// fails, and returns the errors of all failed hooks combined.
+	//
+	// This is the default policy.
+	StopContinueAndAggregate = StopPolicy(lifecycle.StopAggregate)
+
+	// StopFailFast returns the first error reported by an OnStop hook
+	// without running the hooks that would have run after it.
+	//
+	// When rolling back after a failed start, every started hook is
+	// still stopped and their errors are aggregated.
+	StopFailFast = StopPolicy(lifecycle.StopFailFast)
+
+	// StopBestEffort runs every OnStop hook and logs hook errors without
+	// returning them from [App.Stop].
+	// Errors unrelated to the hooks, like an expired context, are still
+	// returned.
+	StopBestEffort = StopPolicy(lifecycle.StopLogOnly)
+)
+
+func (p StopPolicy) String() string {
+	switch p {
+	case StopContinueAndAggregate:
+		return "fx.StopContinueAndAggregate"
+	case StopFailFast:
+		return "fx.StopFailFast"
+	case StopBestEffort:
+		return "fx.StopBestEffort"
+	default:
+		return fmt.Sprintf("fx.StopPolicy(%d)", int(p))
+	}
+}
+
+// StopErrorPolicy changes how the application handles errors returned by
+// [OnStop] hooks when it is stopped.
+//
+//	fx.New(
+//		fx.StopErrorPolicy(fx.StopFailFast),
+//		// ...
+//	)
+//
+// Defaults to [StopContinueAndAggregate].
+func StopErrorPolicy(p StopPolicy) Option {
+	return stopErrorPolicyOption(p)
+}
+
+type stopErrorPolicyOption StopPolicy
+
+func (o stopErrorPolicyOption) apply(m *module) {
+	switch p := StopPolicy(o); {
+	case m.parent != nil:
+		m.app.err = fmt.Errorf("fx.StopErrorPolicy Option should be passed to top-level App, " +
+			"not to fx.Module")
+	case p < StopContinueAndAggregate || p > StopBestEffort:
+		m.app.err = fmt.Errorf("fx.StopErrorPolicy: unknown policy %v", p)
+	default:
+		m.app.stopPolicy = p
+	}
+}
+
+func (o stopErrorPolicyOption) String() string {
+	return fmt.Sprintf("fx.StopErrorPolicy(%v)", StopPolicy(o))
+}
+
 // RecoverFromPanics causes panics that occur in functions given to [Provide],
 // [Decorate], and [Invoke] to be recovered from.
 // This error can be retrieved as any other error, by using (*App).Err().
+//
+// It's equivalent to RecoverPanicsIn(PanicsInConstructors).
+// See [RecoverPanicsIn] to also recover from panics in hooks.
 func RecoverFromPanics() Option {
 	return recoverFromPanicsOption{}
 }
@@ -180,6 +386,32 @@ func (o recoverFromPanicsOption) String() string {
 	return "fx.RecoverFromPanics()"
 }
 
+// DumpStacksOnTimeout causes Fx to capture the stacks of all goroutines
+// if the application fails to start or stop within its timeout
+// while a lifecycle hook is still executing.
+// The stacks are reported to the [fxevent.Logger] as an
+// [fxevent.HookTimedOut] event, with the stacks of goroutines running hooks
+// reported separately to ease diagnosis of hooks that deadlock
+// waiting on work scheduled by a later hook.
+func DumpStacksOnTimeout() Option {
+	return dumpStacksOnTimeoutOption{}
+}
+
+type dumpStacksOnTimeoutOption struct{}
+
+func (o dumpStacksOnTimeoutOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.DumpStacksOnTimeout Option should be passed to top-level " +
+			"App, not to fx.Module")
+	} else {
+		m.app.dumpStacksOnTimeout = true
+	}
+}
+
+func (o dumpStacksOnTimeoutOption) String() string {
+	return "fx.DumpStacksOnTimeout()"
+}
+
 // WithLogger specifies the [fxevent.Logger] used by Fx to log its own events
 // (e.g. a constructor was provided, a function was invoked, etc.).
 //
@@ -224,6 +456,45 @@ func (l withLoggerOption) String() string {
 	return fmt.Sprintf("fx.WithLogger(%s)", fxreflect.FuncName(l.constructor))
 }
 
+// DecorateLogger wraps the [fxevent.Logger] used by Fx to log the events of
+// the module it's passed to, and of the modules nested in it, once that
+// logger is initialized. This allows a module to filter the events of the
+// logger it inherits, or to add its own prefix to them, without replacing
+// the logger with [WithLogger].
+//
+//	fx.Module("db",
+//		fx.DecorateLogger(func(log fxevent.Logger) fxevent.Logger {
+//			return dropProvided{log}
+//		}),
+//	)
+//
+// The logger is decorated after all constructors and decorators of the
+// application are provided, at the same time as [WithLogger] loggers are
+// built. Events the module emitted before that are buffered and replayed
+// through the decorated logger, so the decorator sees all of the module's
+// events. Events emitted by the parent module are not affected.
+//
+// If multiple decorators are passed to a module, each wraps the logger
+// returned by the previous one. A decorator that returns nil leaves the
+// logger undecorated. Fx flushes the root logger if it implements
+// [fxevent.Flusher], so decorators of flushing loggers should implement it
+// too.
+func DecorateLogger(decorator func(fxevent.Logger) fxevent.Logger) Option {
+	return decorateLoggerOption{decorator: decorator}
+}
+
+type decorateLoggerOption struct {
+	decorator func(fxevent.Logger) fxevent.Logger
+}
+
+func (o decorateLoggerOption) apply(m *module) {
+	m.logDecorators = append(m.logDecorators, o.decorator)
+}
+
+func (o decorateLoggerOption) String() string {
+	return fmt.Sprintf("fx.DecorateLogger(%s)", fxreflect.FuncName(o.decorator))
+}
+
 // Printer is the interface required by Fx's logging backend. It's implemented
 // by most loggers, including the one bundled with the standard library.
 //
@@ -302,20 +573,124 @@ type App struct {
 	clock     fxclock.Clock
 	lifecycle *lifecycleWrapper
 
+	// Names of the auxiliary lifecycles declared with fx.DeclareLifecycle,
+	// and the lifecycles built for them.
+	lifecycleNames  []string
+	namedLifecycles map[string]*lifecycleWrapper
+
 	container *dig.Container
 	root      *module
 
 	// Timeouts used
 	startTimeout time.Duration
 	stopTimeout  time.Duration
+	stopPolicy   StopPolicy
+	tracer       Tracer
+	// Whether OnStart hooks get a fair share of the start timeout
+	fairStartBudget bool
+	// Threshold after which OnStart hooks are reported as slow, if set.
+	slowHookThreshold time.Duration
+	// traceCtx holds the span that spans for constructors,
+	// decorators, and invokes are children of.
+	traceCtx context.Context
 	// Decides how we react to errors when building the graph.
 	errorHooks []ErrorHandler
 	validate   bool
 	// Whether to recover from panics in Dig container
 	recoverFromPanics bool
+	recoverHookPanics bool
+	panicHandlers     []func(PanicInfo)
+	// Options passed to the Dig container with fx.DigContainerOptions
+	digOptions []dig.Option
+	// Whether to dump goroutine stacks if a hook times out
+	dumpStacksOnTimeout bool
+	// Whether timeouts expire with a timer instead of a deadline
+	monotonicTimeouts bool
+	// Whether constructors should run with pprof labels
+	profileLabels bool
+	// Allocations of constructors, if reported with
+	// fx.ReportConstructorAllocations
+	allocs *allocRecorder
+	// Types of the values consumed by fx.LazyGroup parameters;
+	// only values of these types get loaders.
+	lazyGroupTypes map[reflect.Type]struct{}
+	// Whether any module specified an fx.OnDuplicate policy
+	// or provided a default constructor
+	hasDuplicatePolicy bool
+	// Modules added by App.Extend, whose constructors were provided
+	extensions []*module
+	// How modules with the same name are handled, with fx.OnModuleConflict
+	moduleConflictPolicy ModuleConflictPolicy
+	// Modules declared with fx.Module, in the order they were included,
+	// and how many of them were checked for conflicts.
+	namedModules   []*module
+	checkedModules int
+	// Checks enabled by fx.Lint, if any
+	linter *linter
+	// Whether constructors are checked by fx.Strict
+	strict bool
+	// Whether constructors fail when they return interfaces
+	// holding nil pointers, with fx.DetectNilResults
+	detectNilResults bool
+	// Middleware wrapping the calls to constructors,
+	// with fx.WithConstructorMiddleware
+	constructorMiddleware []ConstructorMiddleware
+	// Describes the application; provided as AppInfo
+	info AppInfo
+	// Name included in events, if the application was named.
+	name string
+	// Sequence number of the last event emitted.
+	eventSeq atomic.Uint64
+	// How long New took, reported in the Started event.
+	initRuntime time.Duration
+	// Number of constructors run, reported in the Started event.
+	constructorsRun atomic.Int64
+	// First constructor of each type provided, by type name,
+	// to report types provided more than once.
+	providers map[string]ProviderInfo
+	// Constructors provided with fx.Meta,
+	// reported in the errors that involve them.
+	metaProviders []metaProvider
+	// Manifest of the graph recorded with fx.RecordGraphManifest, if any.
+	manifest *graphManifest
+	// Value groups passed to fx.RequireGroupConsumed.
+	requiredGroups []*requiredGroup
+	// Value groups passed to fx.RequireGroupMin and fx.RequireGroupNonEmpty.
+	groupMins []*groupMin
+	// Cleanup functions of the Wire providers that ran in New.
+	wireCleanups []func()
+	// Functions passed to fx.InvokeAtStart,
+	// and how many of them succeeded so far.
+	startInvokes []startInvoke
+	startInvoked int
+	// Whether the application started once, letting constructors
+	// passed to fx.ProvideAtStart run.
+	startReached atomic.Bool
+	// Tracks why constructors run, with fx.RecordDemandPaths.
+	demand *demandGraph
+	// Errors registered with fx.Error that were not reported yet.
+	optionErrors []*OptionError
+	// Called around the wait in Run, with fx.BeforeRun and fx.AfterRun.
+	beforeRun []beforeRunOption
+	afterRun  []afterRunOption
 
 	// Used to signal shutdowns.
 	receivers signalReceivers
+	// Shutdowns scheduled with ShutdownScheduler that have not fired yet.
+	scheduledShutdowns scheduledShutdowns
+	// Goroutines started through the ErrGroup.
+	errGroup *ErrGroup
+	// Whether the application has modules, whose hooks are tracked
+	// to report when each module started and stopped.
+	hasModules bool
+	// Modules whose functions are running, to attribute hooks to them.
+	runningModules runningModules
+	// Canceled when the application stops; provided as AppContext.
+	appCtx *appContext
+	// Whether Start was called; Extend is rejected afterwards.
+	startCalled atomic.Bool
+	// Current phase of the application's lifecycle.
+	state appStateBroadcaster
 
 	osExit func(code int) // os.Exit override; used for testing only
 }
@@ -334,6 +709,36 @@ type provide struct {
 
 	// Set if the type should be provided at private scope.
 	Private bool
+
+	// Metadata attached with fx.Meta, if any.
+	Meta map[string]string
+
+	// Set if the constructor is ignored when another constructor
+	// provides the same type, as with fx.ProvideDefault and fx.Default.
+	IsDefault bool
+
+	// Set if the constructor may only run once the application starts,
+	// as with fx.ProvideAtStart.
+	AtStart bool
+
+	// Set if the constructor is retried when it fails,
+	// as with fx.ProvideWithRetry.
+	Retry *RetryPolicy
+
+	// If set, the name of the constructor reported in events
+	// and the location reported by dig,
+	// for constructors that Fx wraps in another function.
+	Name    string
+	FuncPtr uintptr
+
+	// Set for constructors passed to fx.ProvideAll or fx.ProvideMap,
+	// along with the key the constructor is reported under.
+	Bulk    *bulkProvide
+	BulkKey string
+
+	// Set for the constructors of the types that Fx provides itself,
+	// which constructor middleware doesn't wrap.
+	Builtin bool
 }
 
 // invoke is a single invocation request to Fx.
@@ -343,6 +748,18 @@ type invoke struct {
 
 	// Stack trace of where this invoke was made.
 	Stack fxreflect.Stack
+
+	// If set, the name of the function reported in events,
+	// for functions that Fx generates.
+	Name string
+
+	// If set, the module whose scope resolves the function's parameters,
+	// for functions passed to fx.InvokeWith.
+	Module *module
+
+	// Whether the function's errors are reported without stopping
+	// the application, for functions passed to fx.TryInvoke.
+	Try bool
 }
 
 // ErrorHandler handles Fx application startup errors.
@@ -355,7 +772,9 @@ type ErrorHandler interface {
 }
 
 // ErrorHook registers error handlers that implement error handling functions.
-// They are executed on invoke failures. Passing multiple ErrorHandlers appends
+// They are executed on invoke failures, including failures of functions
+// passed to [TryInvoke], which don't stop the application.
+// Passing multiple ErrorHandlers appends
 // the new handlers to the application's existing list.
 func ErrorHook(funcs ...ErrorHandler) Option {
 	return errorHookOption(funcs)
@@ -449,6 +868,22 @@ func New(opts ...Option) *App {
 	for _, opt := range opts {
 		opt.apply(app.root)
 	}
+	// Name events after the application if it was named explicitly,
+	// before AppInfo defaults the name.
+	app.name = app.info.Name
+	app.root.log = app.stampLogger(app.root.log)
+
+	clock := "system"
+	if app.clock != fxclock.System {
+		clock = fmt.Sprintf("%T", app.clock)
+	}
+	if app.monotonicTimeouts {
+		clock += " with monotonic timeouts"
+		app.clock = fxclock.Monotonic(app.clock)
+	}
+
+	begin := app.clock.Now()
+	defer func() { app.initRuntime = app.clock.Since(begin) }()
 
 	// There are a few levels of wrapping on the lifecycle here. To quickly
 	// cover them:
@@ -460,7 +895,23 @@ func New(opts ...Option) *App {
 	// - appLogger ensures that the lifecycle always logs events to the
 	//   "current" logger associated with the fx.App.
 	app.lifecycle = &lifecycleWrapper{
-		lifecycle.New(appLogger{app}, app.clock),
+		Lifecycle: lifecycle.New(appLogger{app}, app.clock),
+		logger:    appLogger{app},
+		clock:     app.clock,
+	}
+	app.lifecycle.SetStopPolicy(lifecycle.StopPolicy(app.stopPolicy))
+	app.lifecycle.SetFairStartBudget(app.fairStartBudget)
+	app.lifecycle.SetSlowThreshold(app.slowHookThreshold)
+	if app.linter != nil {
+		app.lifecycle.onAppend = func(h Hook) {
+			app.linter.checkHook(app.log(), h)
+		}
+	}
+	if app.tracer != nil || app.recoverHookPanics {
+		app.lifecycle.wrap = app.wrapHook
+	}
+	if app.hasModules = len(app.root.modules) > 0; app.hasModules {
+		app.lifecycle.modules = &app.runningModules
 	}
 
 	containerOptions := []dig.Option{
@@ -471,25 +922,57 @@ func New(opts ...Option) *App {
 	if app.recoverFromPanics {
 		containerOptions = append(containerOptions, dig.RecoverFromPanics())
 	}
+	containerOptions = append(containerOptions, app.digOptions...)
 
 	app.container = dig.New(containerOptions...)
 	app.root.build(app, app.container)
+	app.initAppInfo()
+
+	var endSpan func(error)
+	app.traceCtx, endSpan = app.startSpan(context.Background(), "fx.New")
+	defer func() { endSpan(app.err) }()
+
+	app.log().LogEvent(&fxevent.Configured{
+		StartTimeout:      app.startTimeout,
+		StopTimeout:       app.stopTimeout,
+		Clock:             clock,
+		RecoverFromPanics: app.recoverFromPanics,
+	})
+	app.logOptionErrors()
+	app.checkModuleConflicts()
 
 	// Provide Fx types first to increase the chance a custom logger
 	// can be successfully built in the face of unrelated DI failure.
 	// E.g., for a custom logger that relies on the Lifecycle type.
 	frames := fxreflect.CallerStack(0, 0) // include New in the stack for default Provides
+	app.errGroup = newErrGroup(&shutdowner{app: app})
+	app.appCtx = newAppContext()
 	app.root.provide(provide{
-		Target: func() Lifecycle { return app.lifecycle },
-		Stack:  frames,
+		Target: func() (Lifecycle, *ErrGroup, AppInfo, AppContext) {
+			return app.lifecycle, app.errGroup, app.info, app.appCtx
+		},
+		Stack:   frames,
+		Builtin: true,
 	})
-	app.root.provide(provide{Target: app.shutdowner, Stack: frames})
-	app.root.provide(provide{Target: app.dotGraph, Stack: frames})
+	app.root.provide(provide{Target: app.shutdowner, Stack: frames, Builtin: true})
+	app.root.provide(provide{Target: app.dotGraph, Stack: frames, Builtin: true})
+	app.provideNamedLifecycles(frames)
+	app.collectLazyGroupTypes(app.root)
+	if app.hasDuplicatePolicy {
+		app.root.resolveDuplicates()
+	}
 	app.root.provideAll()
+	if app.linter != nil {
+		app.linter.checkOptionals(app.root.log)
+	}
 
 	// Run decorators before executing any Invokes
 	// (including the ones inside installAllEventLoggers).
 	app.err = multierr.Append(app.err, app.root.decorateAll())
+	if app.err == nil {
+		app.root.logDecoratorChains()
+	}
+	app.root.provideConditionals()
 
 	// If you are thinking about returning here after provides: do not (just yet)!
 	// If a custom logger was being used, we're still buffering messages.
@@ -501,10 +984,33 @@ func New(opts ...Option) *App {
 	// This error might have come from the provide loop above. We've
 	// already flushed to the custom logger, so we can return.
 	if app.err != nil {
+		app.runWireCleanups()
 		return app
 	}
 
-	if err := app.root.invokeAll(); err != nil {
+	app.invokeAll(app.root)
+	if app.err == nil && len(app.startInvokes) == 0 {
+		app.err = app.checkGroupsConsumed()
+	}
+
+	if app.linter != nil {
+		app.linter.built.Store(true)
+	}
+	if app.manifest != nil && app.err == nil {
+		app.manifest.write()
+	}
+	if app.err != nil {
+		app.runWireCleanups()
+	}
+	app.wireCleanups = nil
+	return app
+}
+
+// invokeAll runs the invokes of m and its submodules,
+// recording the first failure on the App.
+func (app *App) invokeAll(m *module) {
+	if err := m.invokeAll(); err != nil {
+		err = app.withMeta(err)
 		app.err = err
 
 		if dig.CanVisualizeError(err) {
@@ -517,20 +1023,132 @@ func New(opts ...Option) *App {
 		}
 		errorHandlerList(app.errorHooks).HandleError(err)
 	}
+}
 
-	return app
+// Extend applies additional options to an application
+// that was built with [New] but has not been started yet.
+// It's intended for tools that assemble applications incrementally,
+// such as interactive notebooks or plugin loaders,
+// which cannot pass all their options to New at once.
+//
+//	app := fx.New(fx.Provide(NewConfig))
+//	if err := app.Extend(fx.Provide(NewServer), fx.Invoke(Register)); err != nil {
+//		return err
+//	}
+//
+// Constructors and values are added to the top-level scope of the application
+// as if they were passed to New, and invocations run immediately.
+// Options that configure the application itself,
+// such as [StartTimeout] or [RecoverFromPanics], are rejected.
+//
+// Defaults and [OnDuplicate] policies take the constructors already in the
+// application into account: a default is ignored if the application already
+// provides its types. Constructors that were already provided are kept,
+// so a new constructor for the same type is either ignored or fails.
+//
+// As with New, failures are recorded on the application:
+// Extend returns the error, and [App.Err] and [App.Start] report it afterwards.
+// Extend fails if the application was already started,
+// or if an earlier error was recorded.
+func (app *App) Extend(opts ...Option) error {
+	if app.err != nil {
+		return app.err
+	}
+	if app.startCalled.Load() {
+		return errors.New("fx.App.Extend must be called before the application is started")
+	}
+
+	ext := &module{
+		// Reject options that are only valid in New.
+		parent: app.root,
+		app:    app,
+		log:    app.log(),
+		trace:  []string{fxreflect.CallerStack(1, 2)[0].String()},
+	}
+	for _, opt := range opts {
+		opt.apply(ext)
+	}
+	app.logOptionErrors()
+	app.checkModuleConflicts()
+	if app.err != nil {
+		return app.err
+	}
+
+	// Detach the module so that it builds into the top-level scope.
+	ext.parent = nil
+	ext.build(app, app.container)
+
+	var endSpan func(error)
+	app.traceCtx, endSpan = app.startSpan(context.Background(), "fx.Extend")
+	defer func() { endSpan(app.err) }()
+
+	if app.linter != nil {
+		// Hooks appended while extending are not appended late.
+		app.linter.built.Store(false)
+		defer app.linter.built.Store(true)
+	}
+
+	app.collectLazyGroupTypes(ext)
+	if app.hasDuplicatePolicy {
+		// Resolve against the constructors already in the container.
+		ext.resolveDuplicates(append([]*module{app.root}, app.extensions...)...)
+	}
+	ext.provideAll()
+	app.extensions = append(app.extensions, ext)
+	app.err = multierr.Append(app.err, ext.decorateAll())
+	if app.err == nil {
+		ext.logDecoratorChains()
+	}
+	ext.provideConditionals()
+	ext.installAllEventLoggers()
+	if app.err != nil {
+		return app.err
+	}
+
+	// Invoke verifies the graph is still acyclic
+	// now that new constructors were added to it.
+	if err := app.container.Invoke(func() {}); err != nil {
+		app.err = app.withMeta(err)
+		return app.err
+	}
+	app.invokeAll(ext)
+	if app.err == nil && len(app.startInvokes) == 0 {
+		app.err = app.checkGroupsConsumed()
+	}
+	if app.manifest != nil && app.err == nil {
+		app.manifest.write()
+	}
+	return app.err
 }
 
 func (app *App) log() fxevent.Logger {
 	return app.root.log
 }
 
+// flushLog flushes the application's logger if it buffers events.
+// Errors are ignored: they cannot be reported through the logger,
+// and syncing standard streams commonly fails harmlessly.
+func (app *App) flushLog() {
+	log := app.log()
+	if n, ok := log.(stampedLogger); ok {
+		log = n.Logger
+	}
+	f, ok := log.(fxevent.Flusher)
+	if !ok {
+		return
+	}
+	app.log().LogEvent(&fxevent.Flushing{})
+	_ = f.Flush()
+}
+
 // DotGraph contains a DOT language visualization of the dependency graph in
 // an Fx application. It is provided in the container by default at
 // initialization. On failure to build the dependency graph, it is attached
 // to the error and if possible, colorized to highlight the root cause of the
 // failure.
 //
+// Use [DiffGraphs] to compare the graphs of two applications.
+//
 // Note that DotGraph does not yet recognize [Decorate] and [Replace].
 type DotGraph string
 
@@ -551,6 +1169,10 @@ func (err errorWithGraph) Error() string {
 	return err.err.Error()
 }
 
+func (err errorWithGraph) Unwrap() error {
+	return err.err
+}
+
 // VisualizeError returns the visualization of the error if available.
 //
 // Note that VisualizeError does not yet recognize [Decorate] and [Replace].
@@ -593,6 +1215,7 @@ func (app *App) exit(code int) {
 // Run will exit with a non-zero status code
 // if startup or shutdown operations fail,
 // or if the [Shutdowner] supplied a non-zero exit code.
+// Use [App.RunErr] to handle these failures instead.
 func (app *App) Run() {
 	// Historically, we do not os.Exit(0) even though most applications
 	// cede control to Fx with they call app.Run. To avoid a breaking
@@ -602,26 +1225,136 @@ func (app *App) Run() {
 	}
 }
 
+// RunErr is similar to [App.Run], but returns an error
+// instead of exiting the process if the application fails.
+//
+// RunErr returns an error if startup or shutdown operations fail.
+// If the application was shut down by the [Shutdowner] with a non-zero
+// exit code, RunErr returns an [*ExitError] holding that code.
+// This lets main handle failures of the application itself:
+//
+//	func main() {
+//		if err := fx.New(opts).RunErr(); err != nil {
+//			log.Print(err)
+//			os.Exit(fx.ExitCodeOf(err))
+//		}
+//	}
+func (app *App) RunErr() error {
+	return app.runErr(context.Background(), app.Wait)
+}
+
+// RunContext is similar to [App.RunErr],
+// but binds the application to the lifetime of ctx.
+// The application is stopped gracefully when ctx is canceled,
+// as if it had received a termination signal,
+// and RunContext returns after it has stopped.
+// This lets applications run inside other frameworks or tests
+// that own their lifetime:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	go func() { errc <- app.RunContext(ctx) }()
+//
+// Startup is aborted if ctx is canceled before the application has started.
+// Shutdown uses a context that is not canceled with ctx,
+// so that OnStop hooks get the full [StopTimeout].
+func (app *App) RunContext(ctx context.Context) error {
+	return app.runErr(ctx, func() <-chan ShutdownSignal {
+		return app.waitContext(ctx)
+	})
+}
+
+// waitContext is like Wait, but also delivers a termination signal
+// when ctx is done.
+func (app *App) waitContext(ctx context.Context) <-chan ShutdownSignal {
+	wait := app.Wait()
+	sigc := make(chan ShutdownSignal, 1)
+	go func() {
+		select {
+		case sig := <-wait:
+			sigc <- sig
+		case <-ctx.Done():
+			sigc <- ShutdownSignal{Signal: _sigTERM}
+		}
+	}()
+	return sigc
+}
+
 func (app *App) run(done func() <-chan ShutdownSignal) (exitCode int) {
-	startCtx, cancel := app.clock.WithTimeout(context.Background(), app.StartTimeout())
+	return ExitCodeOf(app.runErr(context.Background(), done))
+}
+
+func (app *App) runErr(ctx context.Context, done func() <-chan ShutdownSignal) error {
+	startCtx, cancel := app.clock.WithTimeout(ctx, app.StartTimeout())
 	defer cancel()
 
 	if err := app.Start(startCtx); err != nil {
-		return 1
+		return err
+	}
+
+	// Stop even if ctx was canceled: it's what stopped the application.
+	ctx = context.WithoutCancel(ctx)
+
+	if err := app.runBefore(startCtx); err != nil {
+		stopCtx, cancel := app.clock.WithTimeout(ctx, app.StopTimeout())
+		defer cancel()
+		return multierr.Append(err, app.Stop(stopCtx))
 	}
 
 	sig := <-done()
 	app.log().LogEvent(&fxevent.Stopping{Signal: sig.Signal})
-	exitCode = sig.ExitCode
+	app.runAfter(sig)
 
-	stopCtx, cancel := app.clock.WithTimeout(context.Background(), app.StopTimeout())
+	stopCtx, cancel := app.clock.WithTimeout(ctx, app.StopTimeout())
 	defer cancel()
 
 	if err := app.Stop(stopCtx); err != nil {
-		return 1
+		return err
 	}
 
-	return exitCode
+	if sig.ExitCode != 0 || sig.Err != nil {
+		return &ExitError{Code: sig.ExitCode, Err: sig.Err}
+	}
+	return nil
+}
+
+// ExitError is returned by [App.RunErr] if the application was shut down
+// with a non-zero exit code.
+type ExitError struct {
+	// Code is the exit code passed to [Shutdowner.Shutdown]
+	// with the [ExitCode] option.
+	Code int
+
+	// Err is the error passed to [Shutdowner.Shutdown]
+	// with the [ShutdownError] option, if any.
+	Err error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("application exited with code %d: %v", e.Code, e.Err)
+	}
+	return fmt.Sprintf("application exited with code %d", e.Code)
+}
+
+// Unwrap returns the error that caused the application to shut down, if any.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCodeOf returns the exit code a process should exit with
+// after [App.RunErr] returned err:
+// 0 if err is nil, the code of an [*ExitError], and 1 otherwise.
+func ExitCodeOf(err error) int {
+	var exitErr *ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.Code
+	default:
+		return 1
+	}
 }
 
 // Err returns any error encountered during New's initialization. See the
@@ -636,8 +1369,10 @@ func (app *App) Err() error {
 }
 
 var (
-	_onStartHook = "OnStart"
-	_onStopHook  = "OnStop"
+	_onStartHook  = "OnStart"
+	_onStopHook   = "OnStop"
+	_onPauseHook  = "OnPause"
+	_onResumeHook = "OnResume"
 )
 
 // Start kicks off all long-running goroutines, like network servers or
@@ -657,53 +1392,104 @@ var (
 //
 // Note that Start short-circuits immediately if the New constructor
 // encountered any errors in application initialization.
+//
+// An application may be started again after it stopped, which runs its
+// OnStart hooks again, but Start fails if the application is already
+// starting, started, paused or stopping. See [AppState] for details.
 func (app *App) Start(ctx context.Context) (err error) {
+	// Check the state before anything else so that starting an
+	// application that is already running has no effect.
+	if app.err == nil {
+		if state, ok := app.state.Transition(AppStarting, AppCreated, AppStopped); !ok {
+			return fmt.Errorf("cannot start application that is %v", state)
+		}
+		if err := app.appCtx.checkRestart(); err != nil {
+			app.state.Set(AppStopped)
+			return err
+		}
+	}
+
+	begin := app.clock.Now()
 	defer func() {
-		app.log().LogEvent(&fxevent.Started{Err: err})
+		hooks, _ := app.lifecycle.HookRuns()
+		app.log().LogEvent(&fxevent.Started{
+			Err:              err,
+			Runtime:          app.clock.Since(begin),
+			InitRuntime:      app.initRuntime,
+			ConstructorCount: int(app.constructorsRun.Load()),
+			HookCount:        hooks,
+		})
+		if app.allocs != nil {
+			app.log().LogEvent(&fxevent.StartSummary{
+				Constructors: app.allocs.summary(),
+			})
+		}
+		if err != nil {
+			app.flushLog()
+		}
 	}()
 
+	app.startCalled.Store(true)
 	if app.err != nil {
 		// Some provides failed, short-circuit immediately.
 		return app.err
 	}
 
+	defer func() {
+		if err != nil {
+			app.state.Set(AppStopped)
+		} else {
+			app.state.Set(AppStarted)
+		}
+	}()
+
 	return withTimeout(ctx, &withTimeoutParams{
-		hook:      _onStartHook,
-		callback:  app.start,
-		lifecycle: app.lifecycle,
-		log:       app.log(),
+		hook:       _onStartHook,
+		callback:   app.start,
+		lifecycle:  app.lifecycle,
+		log:        app.log(),
+		dumpStacks: app.dumpStacksOnTimeout,
 	})
 }
 
-// withRollback will execute an anonymous function with a given context.
-// if the anon func returns an error, rollback methods will be called and related events emitted
-func (app *App) withRollback(
-	ctx context.Context,
-	f func(context.Context) error,
-) error {
-	if err := f(ctx); err != nil {
-		app.log().LogEvent(&fxevent.RollingBack{StartErr: err})
-
-		stopErr := app.lifecycle.Stop(ctx)
-		app.log().LogEvent(&fxevent.RolledBack{Err: stopErr})
+func (app *App) start(ctx context.Context) (err error) {
+	ctx, endSpan := app.startSpan(ctx, "fx.Start")
+	defer func() { endSpan(err) }()
 
-		if stopErr != nil {
-			return multierr.Append(err, stopErr)
+	if err := app.invokeAtStart(); err != nil {
+		return app.rollback(ctx, err, false)
+	}
+	if len(app.startInvokes) > 0 {
+		if err := app.checkGroupsConsumed(); err != nil {
+			return app.rollback(ctx, err, false)
 		}
-
-		return err
 	}
-
+	if err := app.lifecycle.Start(ctx); err != nil {
+		return app.rollback(ctx, err, true)
+	}
 	return nil
 }
 
-func (app *App) start(ctx context.Context) error {
-	return app.withRollback(ctx, func(ctx context.Context) error {
-		if err := app.lifecycle.Start(ctx); err != nil {
-			return err
-		}
-		return nil
+// rollback stops an application that failed to start with err,
+// emitting the related events, and returns a *StartError.
+// hooksRan reports whether OnStart hooks ran.
+func (app *App) rollback(ctx context.Context, err error, hooksRan bool) error {
+	app.log().LogEvent(&fxevent.RollingBack{StartErr: err})
+	app.state.Set(AppStopping)
+
+	stopErr := app.stop(context.WithValue(ctx, rollbackKey{}, true))
+	app.log().LogEvent(&fxevent.RolledBack{Err: stopErr})
+
+	startErr := newStartError(err, stopErr, app.lifecycle.Lifecycle, hooksRan)
+
+	// Let anything already waiting for the application know that it won't
+	// run. This isn't recorded as a shutdown, so that the application
+	// may be started again.
+	app.receivers.b.NotifyWaiters(ShutdownSignal{
+		ExitCode: 1,
+		Err:      startErr,
 	})
+	return startErr
 }
 
 // Stop gracefully stops the application. It executes any registered OnStop
@@ -714,21 +1500,96 @@ func (app *App) start(ctx context.Context) error {
 // called are executed. However, all those hooks are executed, even if some
 // fail.
 func (app *App) Stop(ctx context.Context) (err error) {
+	begin := app.clock.Now()
 	defer func() {
-		app.log().LogEvent(&fxevent.Stopped{Err: err})
+		_, hooks := app.lifecycle.HookRuns()
+		app.log().LogEvent(&fxevent.Stopped{
+			Err:       err,
+			Runtime:   app.clock.Since(begin),
+			HookCount: hooks,
+		})
+		app.flushLog()
 	}()
 
-	cb := func(ctx context.Context) error {
+	app.scheduledShutdowns.CancelAll()
+	app.state.Set(AppStopping)
+	defer app.state.Set(AppStopped)
+
+	cb := func(ctx context.Context) (err error) {
+		ctx, endSpan := app.startSpan(ctx, "fx.Stop")
+		defer func() { endSpan(err) }()
+
 		defer app.receivers.Stop(ctx)
-		return app.lifecycle.Stop(ctx)
+		return app.stop(ctx)
 	}
 
 	return withTimeout(ctx, &withTimeoutParams{
-		hook:      _onStopHook,
-		callback:  cb,
-		lifecycle: app.lifecycle,
-		log:       app.log(),
+		hook:       _onStopHook,
+		callback:   cb,
+		lifecycle:  app.lifecycle,
+		log:        app.log(),
+		dumpStacks: app.dumpStacksOnTimeout,
+	})
+}
+
+// stop stops the goroutines of the ErrGroup, and then the lifecycle.
+func (app *App) stop(ctx context.Context) error {
+	app.appCtx.cancel()
+	return multierr.Append(
+		app.errGroup.stop(ctx),
+		app.lifecycle.Stop(ctx),
+	)
+}
+
+// Pause temporarily quiesces a started application without tearing it down,
+// for example while it fails over or is snapshotted. It executes the OnPause
+// hooks of all started hooks in reverse order, like Stop. Hooks without an
+// OnPause callback are skipped.
+//
+// If an OnPause hook fails, Pause resumes the hooks it already paused and
+// returns the error; the application stays started.
+// Pause returns an error if the application isn't started.
+func (app *App) Pause(ctx context.Context) error {
+	if app.err != nil {
+		return app.err
+	}
+
+	err := withTimeout(ctx, &withTimeoutParams{
+		hook:       _onPauseHook,
+		callback:   app.lifecycle.Pause,
+		lifecycle:  app.lifecycle,
+		log:        app.log(),
+		dumpStacks: app.dumpStacksOnTimeout,
 	})
+	if err == nil {
+		app.state.Set(AppPaused)
+	}
+	return err
+}
+
+// Resume resumes an application paused by [App.Pause]. It executes the
+// OnResume hooks of all started hooks in order, like Start.
+//
+// If an OnResume hook fails, Resume pauses the hooks it already resumed and
+// returns the error; the application stays paused.
+// A paused application may also be stopped without resuming it, in which
+// case only OnStop hooks run.
+func (app *App) Resume(ctx context.Context) error {
+	if app.err != nil {
+		return app.err
+	}
+
+	err := withTimeout(ctx, &withTimeoutParams{
+		hook:       _onResumeHook,
+		callback:   app.lifecycle.Resume,
+		lifecycle:  app.lifecycle,
+		log:        app.log(),
+		dumpStacks: app.dumpStacksOnTimeout,
+	})
+	if err == nil {
+		app.state.Set(AppStarted)
+	}
+	return err
 }
 
 // Done returns a channel of signals to block on after starting the
@@ -749,6 +1610,10 @@ func (app *App) Done() <-chan os.Signal {
 // the exit code (if provied via [ExitCode]) will be available
 // in the [ShutdownSignal] struct.
 // Otherwise, the signal that was received will be set.
+//
+// If the application failed to start, or was shut down because of an error
+// (see [ShutdownError]), the error is set in the Err field of the signal,
+// so that supervisors can decide whether to restart it.
 func (app *App) Wait() <-chan ShutdownSignal {
 	app.receivers.Start() // No-op if running
 	return app.receivers.Wait()
@@ -775,10 +1640,11 @@ func (app *App) dotGraph() (DotGraph, error) {
 }
 
 type withTimeoutParams struct {
-	log       fxevent.Logger
-	hook      string
-	callback  func(context.Context) error
-	lifecycle *lifecycleWrapper
+	log        fxevent.Logger
+	hook       string
+	callback   func(context.Context) error
+	lifecycle  *lifecycleWrapper
+	dumpStacks bool
 }
 
 // errHookCallbackExited is returned when a hook callback does not finish executing
@@ -807,6 +1673,9 @@ func withTimeout(ctx context.Context, param *withTimeoutParams) error {
 	select {
 	case <-ctx.Done():
 		err = ctx.Err()
+		if param.dumpStacks {
+			reportTimedOutHook(param)
+		}
 	case err = <-c:
 		// If the context finished at the same time as the callback
 		// prefer the context error.
@@ -819,6 +1688,24 @@ func withTimeout(ctx context.Context, param *withTimeoutParams) error {
 	return err
 }
 
+// reportTimedOutHook logs the stacks of all goroutines
+// if a hook was still executing when the timeout elapsed.
+func reportTimedOutHook(param *withTimeoutParams) {
+	funcName, callerName, ok := param.lifecycle.RunningHook()
+	if !ok {
+		return
+	}
+
+	stacks, hookStacks := lifecycle.GoroutineStacks()
+	param.log.LogEvent(&fxevent.HookTimedOut{
+		Method:       param.hook,
+		FunctionName: funcName,
+		CallerName:   callerName,
+		HookStacks:   hookStacks,
+		Stacks:       stacks,
+	})
+}
+
 // appLogger logs events to the given Fx app's "current" logger.
 //
 // Use this with lifecycle, for example, to ensure that events always go to the
@@ -31,7 +31,6 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/fx/fxevent"
-	"go.uber.org/fx/internal/fxclock"
 	"go.uber.org/fx/internal/fxlog"
 	"go.uber.org/fx/internal/fxreflect"
 )
@@ -56,6 +55,7 @@ func TestAppRun(t *testing.T) {
 	wg.Wait()
 
 	assert.Equal(t, []string{
+		"Configured",
 		"Provided",
 		"Provided",
 		"Provided",
@@ -91,23 +91,6 @@ func (o withExitOption) apply(m *module) {
 	m.app.osExit = o
 }
 
-// WithClock specifies how Fx accesses time operations.
-//
-// This is an internal option available only to tests defined in this package.
-func WithClock(clock fxclock.Clock) Option {
-	return withClockOption{clock}
-}
-
-type withClockOption struct{ clock fxclock.Clock }
-
-func (o withClockOption) apply(m *module) {
-	m.app.clock = o.clock
-}
-
-func (o withClockOption) String() string {
-	return fmt.Sprintf("WithClock(%v)", o.clock)
-}
-
 func TestAnnotationError(t *testing.T) {
 	wantErr := errors.New("want error")
 	err := &annotationError{
@@ -138,3 +121,31 @@ func TestStartDoesNotRegisterSignals(t *testing.T) {
 	_ = app.Wait() // User signals intent have fx listen for signals. This should call notify
 	assert.True(t, calledNotify, "notify should be called after Wait")
 }
+
+func TestTestOnlyModule(t *testing.T) {
+	// Not parallel: overrides isTesting.
+
+	newApp := func() *App {
+		return New(
+			NopLogger,
+			Module("outer",
+				TestOnlyModule("fakes", Provide(func() int { return 42 })),
+			),
+			Invoke(func(int) {}),
+		)
+	}
+
+	t.Run("in tests", func(t *testing.T) {
+		assert.NoError(t, newApp().Err())
+	})
+
+	t.Run("outside tests", func(t *testing.T) {
+		defer func(f func() bool) { isTesting = f }(isTesting)
+		isTesting = func() bool { return false }
+
+		err := newApp().Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `fx.TestOnlyModule("fakes") from `)
+		assert.Contains(t, err.Error(), "can only be used in tests")
+	})
+}
@@ -110,16 +110,44 @@ func TestNewApp(t *testing.T) {
 			WithLogger(func() fxevent.Logger { return spy }))
 		defer app.RequireStart().RequireStop()
 		require.Equal(t,
-			[]string{"Provided", "Provided", "Provided", "Provided", "LoggerInitialized", "Started"},
+			[]string{"Configured", "Provided", "Provided", "Provided", "Provided", "LoggerInitialized", "Started"},
 			spy.EventTypes())
 
 		// Fx types get provided first to increase chance of
 		// successful custom logger build.
-		assert.Contains(t, spy.Events()[0].(*fxevent.Provided).OutputTypeNames, "fx.Lifecycle")
-		assert.Contains(t, spy.Events()[1].(*fxevent.Provided).OutputTypeNames, "fx.Shutdowner")
-		assert.Contains(t, spy.Events()[2].(*fxevent.Provided).OutputTypeNames, "fx.DotGraph")
-		// Our type should be index 3.
-		assert.Contains(t, spy.Events()[3].(*fxevent.Provided).OutputTypeNames, "struct {}")
+		assert.Contains(t, spy.Events()[1].(*fxevent.Provided).OutputTypeNames, "fx.Lifecycle")
+		assert.Contains(t, spy.Events()[2].(*fxevent.Provided).OutputTypeNames, "fx.Shutdowner")
+		assert.Contains(t, spy.Events()[3].(*fxevent.Provided).OutputTypeNames, "fx.DotGraph")
+		// Our type should be index 4.
+		assert.Contains(t, spy.Events()[4].(*fxevent.Provided).OutputTypeNames, "struct {}")
+	})
+
+	t.Run("ProvidedReportsParams", func(t *testing.T) {
+		t.Parallel()
+
+		type A struct{}
+		type B struct{}
+		type Params struct {
+			In
+
+			A A
+			B B      `optional:"true"`
+			S string `name:"s" optional:"true"`
+		}
+		app, spy := NewSpied(
+			Provide(func() A { return A{} }),
+			Provide(func(Params) struct{} { return struct{}{} }),
+		)
+		require.NoError(t, app.Err())
+
+		provided := spy.Events().SelectByTypeName("Provided")
+		require.Len(t, provided, 5)
+		assert.Empty(t, provided[3].(*fxevent.Provided).InputTypeNames)
+		assert.Equal(t, []string{
+			"fx_test.A",
+			"fx_test.B[optional]",
+			`string[optional, name = "s"]`,
+		}, provided[4].(*fxevent.Provided).InputTypeNames)
 	})
 
 	t.Run("CircularGraphReturnsError", func(t *testing.T) {
@@ -365,7 +393,7 @@ func TestNewApp(t *testing.T) {
 		defer app.RequireStart().RequireStop()
 
 		require.Equal(t,
-			[]string{"Provided", "Provided", "Provided", "Provided", "Decorated", "LoggerInitialized", "Invoking", "Run", "Run", "Invoked", "Started"},
+			[]string{"Configured", "Provided", "Provided", "Provided", "Provided", "Decorated", "LoggerInitialized", "Invoking", "Run", "Run", "Invoked", "Started"},
 			spy.EventTypes())
 	})
 
@@ -386,7 +414,7 @@ func TestNewApp(t *testing.T) {
 		defer app.RequireStart().RequireStop()
 
 		require.Equal(t,
-			[]string{"Provided", "Provided", "Provided", "Provided", "Decorated", "Decorated", "LoggerInitialized", "Started"},
+			[]string{"Configured", "Provided", "Provided", "Provided", "Provided", "Decorated", "Decorated", "DecoratorChain", "LoggerInitialized", "ModuleStarted", "Started"},
 			spy.EventTypes())
 	})
 }
@@ -674,6 +702,7 @@ func TestWithLogger(t *testing.T) {
 		)
 
 		assert.Equal(t, []string{
+			"Configured",
 			"Provided", "Provided", "Provided", "Supplied", "Run", "LoggerInitialized",
 		}, spy.EventTypes())
 
@@ -704,7 +733,7 @@ func TestWithLogger(t *testing.T) {
 			"must provide constructor function, got  (type *bytes.Buffer)",
 		)
 
-		assert.Equal(t, []string{"Provided", "Provided", "Provided", "Supplied", "Provided", "Run", "LoggerInitialized"}, spy.EventTypes())
+		assert.Equal(t, []string{"Configured", "Provided", "Provided", "Provided", "Supplied", "Provided", "Run", "LoggerInitialized"}, spy.EventTypes())
 	})
 
 	t.Run("logger failed to build", func(t *testing.T) {
@@ -1181,6 +1210,40 @@ func TestError(t *testing.T) {
 		err := app.Err()
 		assert.EqualError(t, err, "module failure")
 	})
+
+	t.Run("Location", func(t *testing.T) {
+		t.Parallel()
+
+		errA := errors.New("module A failure")
+		errB := errors.New("module B failure")
+		app, spy := NewSpied(
+			Module("a", Error(errA)),
+			Error(nil, errB),
+		)
+		err := app.Err()
+		require.Error(t, err)
+
+		var optErr *OptionError
+		require.ErrorAs(t, err, &optErr)
+		assert.Equal(t, errA, optErr.Err)
+		assert.Equal(t, "a", optErr.ModuleName)
+		require.Len(t, optErr.ModuleTrace, 3)
+		assert.Contains(t, optErr.ModuleTrace[0], "TestError")
+		assert.Contains(t, optErr.ModuleTrace[1], "(a)")
+		assert.Contains(t, optErr.StackTrace[0], "TestError")
+
+		errs := multierr.Errors(err)
+		require.Len(t, errs, 2)
+		require.ErrorAs(t, errs[1], &optErr)
+		assert.Equal(t, errB, optErr.Err)
+		assert.Empty(t, optErr.ModuleName)
+
+		events := spy.Events().SelectByTypeName("OptionError")
+		require.Len(t, events, 2)
+		assert.Equal(t, errA, events[0].(*fxevent.OptionError).Err)
+		assert.Equal(t, "a", events[0].(*fxevent.OptionError).ModuleName)
+		assert.Equal(t, errB, events[1].(*fxevent.OptionError).Err)
+	})
 }
 
 func TestOptions(t *testing.T) {
@@ -1265,9 +1328,9 @@ func TestOptions(t *testing.T) {
 			Provide(&bytes.Buffer{}), // error, not a constructor
 			WithLogger(func() fxevent.Logger { return spy }),
 		)
-		require.Equal(t, []string{"Provided", "Provided", "Provided", "Provided", "LoggerInitialized"}, spy.EventTypes())
+		require.Equal(t, []string{"Configured", "Provided", "Provided", "Provided", "Provided", "LoggerInitialized"}, spy.EventTypes())
 		// First 3 provides are Fx types (Lifecycle, Shutdowner, DotGraph).
-		assert.Contains(t, spy.Events()[3].(*fxevent.Provided).Err.Error(), "must provide constructor function")
+		assert.Contains(t, spy.Events()[4].(*fxevent.Provided).Err.Error(), "must provide constructor function")
 	})
 }
 
@@ -1312,6 +1375,147 @@ func TestTimeoutOptions(t *testing.T) {
 	assert.True(t, stopped, "app wasn't stopped")
 }
 
+func TestConfiguredEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		app, spy := NewSpied()
+		require.NoError(t, app.Err())
+
+		events := spy.Events().SelectByTypeName("Configured")
+		require.Len(t, events, 1)
+		configured := events[0].(*fxevent.Configured)
+		assert.False(t, configured.Timestamp.IsZero(), "event must be timestamped")
+		configured.Timestamp = time.Time{}
+		assert.Equal(t, &fxevent.Configured{
+			StartTimeout: DefaultTimeout,
+			StopTimeout:  DefaultTimeout,
+			Clock:        "system",
+			Metadata:     fxevent.Metadata{Seq: 1},
+		}, configured)
+	})
+
+	t.Run("customized", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		app, spy := NewSpied(
+			StartTimeout(time.Minute),
+			StopTimeout(time.Hour),
+			WithClock(clock),
+			RecoverFromPanics(),
+		)
+		require.NoError(t, app.Err())
+
+		events := spy.Events().SelectByTypeName("Configured")
+		require.Len(t, events, 1)
+		assert.Equal(t, &fxevent.Configured{
+			StartTimeout:      time.Minute,
+			StopTimeout:       time.Hour,
+			Clock:             "*fxclock.Mock",
+			RecoverFromPanics: true,
+			Metadata:          fxevent.Metadata{Seq: 1, Timestamp: clock.Now()},
+		}, events[0])
+	})
+}
+
+func TestFairStartBudget(t *testing.T) {
+	t.Parallel()
+
+	t.Run("HookExceedsShare", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		var shares []time.Duration
+		app := New(
+			NopLogger,
+			WithClock(clock),
+			FairStartBudget(),
+			Invoke(func(lc Lifecycle) {
+				hook := func(elapse time.Duration) Hook {
+					return Hook{
+						OnStart: func(ctx context.Context) error {
+							deadline, _ := ctx.Deadline()
+							shares = append(shares, deadline.Sub(clock.Now()))
+							clock.Add(elapse)
+							return nil
+						},
+					}
+				}
+				lc.Append(hook(30 * time.Second))
+				lc.Append(hook(2 * time.Minute))
+				lc.Append(hook(0))
+			}),
+		)
+		require.NoError(t, app.Err())
+
+		ctx, cancel := clock.WithTimeout(context.Background(), 3*time.Minute)
+		defer cancel()
+		err := app.Start(ctx)
+		assert.ErrorContains(t, err, "exceeded its fair share of the start timeout (1m15s)")
+		assert.Equal(t, []time.Duration{time.Minute, 75 * time.Second}, shares)
+	})
+
+	t.Run("NotTopLevel", func(t *testing.T) {
+		t.Parallel()
+
+		app := New(NopLogger, Module("child", FairStartBudget()))
+		assert.ErrorContains(t, app.Err(),
+			"fx.FairStartBudget Option should be passed to top-level App, not to fx.Module")
+	})
+}
+
+func TestSlowHookThreshold(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ReportsSlowHook", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		app, spy := NewSpied(
+			WithClock(clock),
+			SlowHookThreshold(time.Second),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(StartHook(func() {
+					clock.AwaitScheduled(1)
+					clock.Add(time.Second)
+					clock.AwaitScheduled(1)
+				}))
+			}),
+		)
+		require.NoError(t, app.Err())
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+
+		events := spy.Events().SelectByTypeName("OnStartSlow")
+		require.Len(t, events, 1)
+		assert.Equal(t, time.Second, events[0].(*fxevent.OnStartSlow).Elapsed)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		app := New(NopLogger, SlowHookThreshold(0))
+		assert.ErrorContains(t, app.Err(), "fx.SlowHookThreshold must be positive, got 0s")
+	})
+
+	t.Run("NotTopLevel", func(t *testing.T) {
+		t.Parallel()
+
+		app := New(NopLogger, Module("child", SlowHookThreshold(time.Second)))
+		assert.ErrorContains(t, app.Err(),
+			"fx.SlowHookThreshold Option should be passed to top-level App, not to fx.Module")
+	})
+
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "fx.SlowHookThreshold(10s)", SlowHookThreshold(10*time.Second).String())
+	})
+}
+
 func TestAppRunTimeout(t *testing.T) {
 	t.Parallel()
 
@@ -1445,9 +1649,216 @@ func TestAppRunTimeout(t *testing.T) {
 	}
 }
 
+func TestAppRunErr(t *testing.T) {
+	t.Parallel()
+
+	// shutdown shuts down the application as soon as it starts.
+	shutdown := func(opts ...ShutdownOption) Option {
+		return Invoke(func(sd Shutdowner, lc Lifecycle) {
+			lc.Append(StartHook(func() error {
+				return sd.Shutdown(opts...)
+			}))
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t, shutdown())
+		err := app.RunErr()
+		assert.NoError(t, err)
+		assert.Zero(t, ExitCodeOf(err))
+	})
+
+	t.Run("exit code", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t, shutdown(ExitCode(3)))
+		err := app.RunErr()
+
+		var exitErr *ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 3, exitErr.Code)
+		assert.Equal(t, 3, ExitCodeOf(err))
+		assert.EqualError(t, err, "application exited with code 3")
+	})
+
+	t.Run("shutdown error", func(t *testing.T) {
+		t.Parallel()
+
+		giveErr := errors.New("great sadness")
+		app := fxtest.New(t, shutdown(ShutdownError(giveErr)))
+		err := app.RunErr()
+
+		var exitErr *ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.ErrorIs(t, err, giveErr)
+		assert.Equal(t, 1, ExitCodeOf(err))
+		assert.EqualError(t, err, "application exited with code 1: great sadness")
+	})
+
+	t.Run("start failure", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			NopLogger,
+			Invoke(func(lc Lifecycle) {
+				lc.Append(StartHook(func() error {
+					return errors.New("great sadness")
+				}))
+			}),
+		)
+		err := app.RunErr()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.Equal(t, 1, ExitCodeOf(err))
+	})
+
+	t.Run("stop failure", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			NopLogger,
+			shutdown(ExitCode(3)),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(StopHook(func() error {
+					return errors.New("great sadness")
+				}))
+			}),
+		)
+		err := app.RunErr()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.Equal(t, 1, ExitCodeOf(err), "stop failures take precedence")
+	})
+
+	t.Run("new failure", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, NopLogger, Invoke(func(int) {}))
+		err := app.RunErr()
+		require.Error(t, err)
+		assert.Equal(t, 1, ExitCodeOf(err))
+	})
+}
+
+func TestAppRunContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("canceled", func(t *testing.T) {
+		t.Parallel()
+
+		var stopCtxErr error
+		started := make(chan struct{})
+		app := NewForTest(t,
+			WithLogger(func() fxevent.Logger {
+				return fxevent.Func(func(e fxevent.Event) {
+					if _, ok := e.(*fxevent.Started); ok {
+						close(started)
+					}
+				})
+			}),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(StopHook(func(ctx context.Context) error {
+					stopCtxErr = ctx.Err()
+					return nil
+				}))
+			}),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errc := make(chan error)
+		go func() { errc <- app.RunContext(ctx) }()
+
+		<-started
+		cancel()
+		assert.NoError(t, <-errc)
+		assert.NoError(t, stopCtxErr, "stop context must not be canceled")
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			Invoke(func(sd Shutdowner, lc Lifecycle) {
+				lc.Append(StartHook(func() error {
+					return sd.Shutdown(ExitCode(3))
+				}))
+			}),
+		)
+		err := app.RunContext(context.Background())
+		assert.Equal(t, 3, ExitCodeOf(err))
+	})
+
+	t.Run("canceled during start", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		app := NewForTest(t,
+			NopLogger,
+			Invoke(func(lc Lifecycle) {
+				lc.Append(StartHook(func(ctx context.Context) error {
+					cancel()
+					<-ctx.Done()
+					return ctx.Err()
+				}))
+			}),
+		)
+		err := app.RunContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
 func TestAppStart(t *testing.T) {
 	t.Parallel()
 
+	t.Run("ReportsTotals", func(t *testing.T) {
+		t.Parallel()
+
+		mockClock := fxclock.NewMock()
+		type A struct{}
+		type B struct{}
+		app, spy := NewSpied(
+			WithClock(mockClock),
+			Provide(func(lc Lifecycle) *A {
+				mockClock.Add(time.Second)
+				lc.Append(StartStopHook(
+					func() { mockClock.Add(2 * time.Second) },
+					func() { mockClock.Add(3 * time.Second) },
+				))
+				return &A{}
+			}),
+			Provide(func(*A, Lifecycle) *B { return &B{} }),
+			Invoke(func(*B) {}),
+		)
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+
+		started := spy.Events().SelectByTypeName("Started")
+		require.Len(t, started, 1)
+		assert.Equal(t, &fxevent.Started{
+			Runtime:          2 * time.Second,
+			InitRuntime:      time.Second,
+			ConstructorCount: 3, // A, B, and the one providing Lifecycle
+			HookCount:        1,
+			Metadata: fxevent.Metadata{
+				Seq:       fxevent.Seq(started[0]),
+				Timestamp: mockClock.Now().Add(-3 * time.Second),
+			},
+		}, started[0])
+
+		stopped := spy.Events().SelectByTypeName("Stopped")
+		require.Len(t, stopped, 1)
+		assert.Equal(t, &fxevent.Stopped{
+			Runtime:   3 * time.Second,
+			HookCount: 1,
+			Metadata: fxevent.Metadata{
+				Seq:       fxevent.Seq(stopped[0]),
+				Timestamp: mockClock.Now(),
+			},
+		}, stopped[0])
+	})
+
 	t.Run("Timeout", func(t *testing.T) {
 		t.Parallel()
 
@@ -1485,6 +1896,83 @@ func TestAppStart(t *testing.T) {
 		cancel()
 	})
 
+	t.Run("TimeoutDumpsStacks", func(t *testing.T) {
+		t.Parallel()
+
+		mockClock := fxclock.NewMock()
+		release := make(chan struct{})
+
+		type A struct{}
+		blocker := func(lc Lifecycle) *A {
+			lc.Append(
+				Hook{
+					OnStart: func(ctx context.Context) error {
+						mockClock.Add(5 * time.Second)
+						<-release
+						return ctx.Err()
+					},
+				},
+			)
+			return &A{}
+		}
+		spy := new(fxlog.Spy)
+		app := NewForTest(t,
+			WithLogger(func() fxevent.Logger { return spy }),
+			WithClock(mockClock),
+			DumpStacksOnTimeout(),
+			Provide(blocker),
+			Invoke(func(*A) {}),
+		)
+
+		ctx, cancel := mockClock.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		err := app.Start(ctx)
+		close(release)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		events := spy.Events().SelectByTypeName("HookTimedOut")
+		require.Len(t, events, 1)
+		e := events[0].(*fxevent.HookTimedOut)
+		assert.Equal(t, "OnStart", e.Method)
+		assert.Contains(t, e.FunctionName, "TestAppStart")
+		assert.Contains(t, e.CallerName, "TestAppStart")
+		assert.Contains(t, e.Stacks, "goroutine ")
+		// Other tests running in parallel may also be executing hooks.
+		assert.NotEmpty(t, e.HookStacks)
+	})
+
+	t.Run("TimeoutWithoutStackDump", func(t *testing.T) {
+		t.Parallel()
+
+		mockClock := fxclock.NewMock()
+		release := make(chan struct{})
+
+		spy := new(fxlog.Spy)
+		app := NewForTest(t,
+			WithLogger(func() fxevent.Logger { return spy }),
+			WithClock(mockClock),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnStart: func(ctx context.Context) error {
+						mockClock.Add(5 * time.Second)
+						<-release
+						return ctx.Err()
+					},
+				})
+			}),
+		)
+
+		ctx, cancel := mockClock.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		err := app.Start(ctx)
+		close(release)
+		require.Error(t, err)
+		assert.Empty(t, spy.Events().SelectByTypeName("HookTimedOut"))
+	})
+
 	t.Run("TimeoutWithFinishedHooks", func(t *testing.T) {
 		t.Parallel()
 
@@ -1693,6 +2181,7 @@ func TestAppStart(t *testing.T) {
 		assert.Contains(t, err.Error(), "OnStart fail")
 
 		assert.Equal(t, []string{
+			"Configured",
 			"Provided", "Provided", "Provided", "Provided",
 			"LoggerInitialized",
 			"Invoking",
@@ -1728,9 +2217,14 @@ func TestAppStart(t *testing.T) {
 		)
 		err := app.Start(context.Background())
 		require.Error(t, err)
-		assert.Equal(t, []error{errStart2, errStop1}, multierr.Errors(err))
+		var startErr *StartError
+		require.ErrorAs(t, err, &startErr)
+		assert.Equal(t, []error{errStart2, errStop1}, startErr.Errors())
+		assert.ErrorIs(t, err, errStart2)
+		assert.ErrorIs(t, err, errStop1)
 
 		assert.Equal(t, []string{
+			"Configured",
 			"Provided", "Provided", "Provided", "Provided",
 			"LoggerInitialized",
 			"Invoking",
@@ -1764,7 +2258,7 @@ func TestAppStart(t *testing.T) {
 		//         /.../go/1.13.3/libexec/src/testing/testing.go:909
 		// Failed: can't invoke non-function {} (type struct {})
 		require.Equal(t,
-			[]string{"Provided", "Provided", "Provided", "LoggerInitialized", "Invoking", "Invoked"},
+			[]string{"Configured", "Provided", "Provided", "Provided", "LoggerInitialized", "Invoking", "Invoked"},
 			spy.EventTypes())
 		failedEvent := spy.Events()[len(spy.EventTypes())-1].(*fxevent.Invoked)
 		assert.Contains(t, failedEvent.Err.Error(), "can't invoke non-function")
@@ -1906,8 +2400,9 @@ func TestAppStart(t *testing.T) {
 		assert.NoError(t, app.Start(ctx))
 		err := app.Start(ctx)
 		if assert.Error(t, err) {
-			assert.ErrorContains(t, err, "attempted to start lifecycle when in state: started")
+			assert.ErrorContains(t, err, "cannot start application that is started")
 		}
+		assert.Equal(t, AppStarted, app.State(), "failed start must not stop the app")
 		app.Stop(ctx)
 		assert.NoError(t, app.Start(ctx))
 		app.Stop(ctx)
@@ -1965,6 +2460,215 @@ func TestAppStop(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "OnStop fail")
 	})
+
+	t.Run("StopErrorPolicy", func(t *testing.T) {
+		t.Parallel()
+
+		// Hooks stop in reverse order: "second" runs before "first".
+		newApp := func(t *testing.T, ran *[]string, opts ...Option) *fxtest.App {
+			hooks := Invoke(func(lc Lifecycle) {
+				for _, name := range []string{"first", "second"} {
+					name := name
+					lc.Append(Hook{OnStop: func(context.Context) error {
+						*ran = append(*ran, name)
+						return errors.New(name + " failed")
+					}})
+				}
+			})
+			app := fxtest.New(t, append(opts, hooks)...)
+			app.RequireStart()
+			return app
+		}
+
+		t.Run("default aggregates", func(t *testing.T) {
+			t.Parallel()
+
+			var ran []string
+			err := newApp(t, &ran).Stop(context.Background())
+			require.Error(t, err)
+			assert.ErrorContains(t, err, "first failed")
+			assert.ErrorContains(t, err, "second failed")
+			assert.Equal(t, []string{"second", "first"}, ran)
+		})
+
+		t.Run("fail fast", func(t *testing.T) {
+			t.Parallel()
+
+			var ran []string
+			err := newApp(t, &ran, StopErrorPolicy(StopFailFast)).Stop(context.Background())
+			assert.EqualError(t, err, "second failed")
+			assert.Equal(t, []string{"second"}, ran)
+		})
+
+		t.Run("best effort", func(t *testing.T) {
+			t.Parallel()
+
+			var ran []string
+			err := newApp(t, &ran, StopErrorPolicy(StopBestEffort)).Stop(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"second", "first"}, ran)
+		})
+
+		t.Run("unknown policy", func(t *testing.T) {
+			t.Parallel()
+
+			app := NewForTest(t, StopErrorPolicy(StopPolicy(42)))
+			assert.EqualError(t, app.Err(), "fx.StopErrorPolicy: unknown policy fx.StopPolicy(42)")
+		})
+
+		t.Run("in module", func(t *testing.T) {
+			t.Parallel()
+
+			app := NewForTest(t, Module("child", StopErrorPolicy(StopFailFast)))
+			assert.ErrorContains(t, app.Err(), "fx.StopErrorPolicy Option should be passed to top-level App")
+		})
+	})
+
+	t.Run("FlushesLogger", func(t *testing.T) {
+		t.Parallel()
+
+		log := new(flushingSpy)
+		app := New(WithLogger(func() fxevent.Logger { return log }))
+		require.NoError(t, app.Start(context.Background()))
+		assert.Zero(t, log.flushed, "logger must not be flushed before stop")
+
+		require.NoError(t, app.Stop(context.Background()))
+		assert.Equal(t, 1, log.flushed)
+
+		types := log.EventTypes()
+		assert.Equal(t, "Flushing", types[len(types)-1])
+	})
+
+	t.Run("FlushesLoggerOnStartFailure", func(t *testing.T) {
+		t.Parallel()
+
+		log := new(flushingSpy)
+		app := New(
+			WithLogger(func() fxevent.Logger { return log }),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{OnStart: func(context.Context) error {
+					return errors.New("great sadness")
+				}})
+			}),
+		)
+		require.Error(t, app.Start(context.Background()))
+		assert.Equal(t, 1, log.flushed)
+	})
+}
+
+// flushingSpy is an fxlog.Spy that counts calls to Flush.
+type flushingSpy struct {
+	fxlog.Spy
+
+	flushed int
+}
+
+var _ fxevent.Flusher = (*flushingSpy)(nil)
+
+func (s *flushingSpy) Flush() error {
+	s.flushed++
+	return nil
+}
+
+func TestAppExtend(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{ A *A }
+
+	t.Run("ProvideSupplyInvoke", func(t *testing.T) {
+		t.Parallel()
+
+		app, spy := NewSpied(Provide(func() *A { return &A{} }))
+		require.NoError(t, app.Err())
+
+		var got *B
+		require.NoError(t, app.Extend(
+			Provide(func(a *A) *B { return &B{A: a} }),
+			Supply("hello"),
+			Invoke(func(b *B, s string) {
+				got = b
+				assert.Equal(t, "hello", s)
+			}),
+		))
+		require.NotNil(t, got)
+		assert.NotNil(t, got.A)
+
+		assert.Len(t, spy.Events().SelectByTypeName("Provided"), 5)
+		assert.Len(t, spy.Events().SelectByTypeName("Supplied"), 1)
+		assert.Len(t, spy.Events().SelectByTypeName("Invoked"), 1)
+
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+	})
+
+	t.Run("DuplicatesResolvedAgainstApp", func(t *testing.T) {
+		t.Parallel()
+
+		a := &A{}
+		app := NewForTest(t, Supply(a))
+		require.NoError(t, app.Err())
+
+		// The default is ignored because the application provides *A.
+		require.NoError(t, app.Extend(
+			Default(&A{}),
+			Provide(func(a *A) *B { return &B{A: a} }),
+		))
+
+		// The constructor is ignored because an extension provides *B.
+		var got *B
+		require.NoError(t, app.Extend(
+			OnDuplicate(KeepFirst),
+			Provide(func() *B { return &B{} }),
+			Invoke(func(b *B) { got = b }),
+		))
+		require.NotNil(t, got)
+		assert.Same(t, a, got.A)
+	})
+
+	t.Run("AfterStart", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t)
+		app.RequireStart().RequireStop()
+
+		err := app.Extend(Provide(func() *A { return &A{} }))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be called before the application is started")
+	})
+
+	t.Run("TopLevelOption", func(t *testing.T) {
+		t.Parallel()
+
+		app, _ := NewSpied()
+		err := app.Extend(StartTimeout(time.Second))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.StartTimeout Option should be passed to top-level App")
+		assert.Equal(t, DefaultTimeout, app.StartTimeout())
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		t.Parallel()
+
+		app, _ := NewSpied(Provide(func(*B) *A { return &A{} }))
+		err := app.Extend(Provide(func(*A) *B { return &B{} }))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cycle detected")
+		assert.EqualError(t, app.Start(context.Background()), err.Error())
+	})
+
+	t.Run("InvokeError", func(t *testing.T) {
+		t.Parallel()
+
+		errFail := errors.New("great sadness")
+		app, _ := NewSpied()
+		err := app.Extend(Invoke(func() error { return errFail }))
+		assert.ErrorIs(t, err, errFail)
+		assert.ErrorIs(t, app.Err(), errFail)
+
+		err = app.Extend(Invoke(func() {}))
+		assert.ErrorIs(t, err, errFail, "later calls must report the earlier failure")
+	})
 }
 
 func TestValidateApp(t *testing.T) {
@@ -2057,6 +2761,22 @@ func TestValidateApp(t *testing.T) {
 		)
 		require.NoError(t, err, "fx.ValidateApp should not return an error")
 	})
+	t.Run("custom logger falls back", func(t *testing.T) {
+		t.Parallel()
+
+		var buff bytes.Buffer
+		err := ValidateApp(
+			Logger(log.New(&buff, "", 0)),
+			WithLogger(func() fxevent.Logger {
+				t.Errorf("WithLogger must not be called")
+				return fxevent.NopLogger
+			}),
+			Invoke(func() {}),
+		)
+		require.NoError(t, err)
+		assert.Contains(t, buff.String(), "[Fx] INVOKE",
+			"events must be logged to the fallback logger")
+	})
 }
 
 func TestHookConstructors(t *testing.T) {
@@ -2381,6 +3101,7 @@ func TestReplaceLogger(t *testing.T) {
 	app := fxtest.New(t, WithLogger(func() fxevent.Logger { return spy }))
 	app.RequireStart().RequireStop()
 	assert.Equal(t, []string{
+		"Configured",
 		"Provided",
 		"Provided",
 		"Provided",
@@ -2390,6 +3111,102 @@ func TestReplaceLogger(t *testing.T) {
 	}, spy.EventTypes())
 }
 
+func TestDecorateLogger(t *testing.T) {
+	t.Parallel()
+
+	// recordingLogger records the types of the events it sees,
+	// and forwards them to Logger unless they're of type drop.
+	type recordingLogger struct {
+		fxevent.Logger
+
+		drop string
+		seen []string
+	}
+	newRecorder := func(drop string) (*recordingLogger, func(fxevent.Logger) fxevent.Logger) {
+		r := &recordingLogger{drop: drop}
+		return r, func(log fxevent.Logger) fxevent.Logger {
+			r.Logger = log
+			return loggerFunc(func(e fxevent.Event) {
+				name := reflect.TypeOf(e).Elem().Name()
+				r.seen = append(r.seen, name)
+				if name != r.drop {
+					r.Logger.LogEvent(e)
+				}
+			})
+		}
+	}
+
+	t.Run("ReplaysEarlierEvents", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		_, decorate := newRecorder("Provided")
+		app := fxtest.New(t,
+			WithLogger(func() fxevent.Logger { return spy }),
+			DecorateLogger(decorate),
+		)
+		app.RequireStart().RequireStop()
+		assert.Equal(t, []string{
+			"Configured",
+			"LoggerInitialized",
+			"Started",
+			"Stopped",
+		}, spy.EventTypes())
+	})
+
+	t.Run("Module", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		rec, decorate := newRecorder("Invoked")
+		app := fxtest.New(t,
+			WithLogger(func() fxevent.Logger { return spy }),
+			Module("child",
+				DecorateLogger(decorate),
+				Provide(func() int { return 42 }),
+				Invoke(func(int) {}),
+			),
+			Invoke(func(int) {}),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{"Provided", "Invoking", "Run", "Invoked"}, rec.seen,
+			"decorator must only see the events of its module")
+		assert.Len(t, spy.Events().SelectByTypeName("Invoked"), 1,
+			"only the root module's Invoked event must be logged")
+	})
+
+	t.Run("Order", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		record := func(name string) func(fxevent.Logger) fxevent.Logger {
+			return func(log fxevent.Logger) fxevent.Logger {
+				return loggerFunc(func(e fxevent.Event) {
+					if _, ok := e.(*fxevent.Invoking); ok {
+						calls = append(calls, name)
+					}
+					log.LogEvent(e)
+				})
+			}
+		}
+		fxtest.New(t,
+			DecorateLogger(record("inner")),
+			DecorateLogger(record("outer")),
+			DecorateLogger(func(fxevent.Logger) fxevent.Logger { return nil }),
+			Invoke(func() {}),
+		)
+		assert.Equal(t, []string{"outer", "inner"}, calls)
+	})
+}
+
+// loggerFunc is an fxevent.Logger implemented by a function.
+type loggerFunc func(fxevent.Event)
+
+func (f loggerFunc) LogEvent(e fxevent.Event) { f(e) }
+
+func identityLogger(log fxevent.Logger) fxevent.Logger { return log }
+
 func TestNopLogger(t *testing.T) {
 	t.Parallel()
 
@@ -2468,6 +3285,7 @@ func TestCustomLoggerWithLifecycle(t *testing.T) {
 	require.NoError(t, app.Stop(context.Background()))
 
 	assert.Equal(t, []string{
+		"Configured",
 		"Provided",
 		"Provided",
 		"Provided",
@@ -2668,11 +3486,181 @@ func TestOptionString(t *testing.T) {
 			give: StopTimeout(5 * time.Second),
 			want: "fx.StopTimeout(5s)",
 		},
+		{
+			desc: "StopErrorPolicy",
+			give: StopErrorPolicy(StopFailFast),
+			want: "fx.StopErrorPolicy(fx.StopFailFast)",
+		},
+		{
+			desc: "WithClock",
+			give: WithClock(fxclock.NewMock()),
+			want: "fx.WithClock(*fxclock.Mock)",
+		},
+		{
+			desc: "MonotonicTimeouts",
+			give: MonotonicTimeouts(),
+			want: "fx.MonotonicTimeouts()",
+		},
+		{
+			desc: "WithTracer",
+			give: WithTracer(nil),
+			want: "fx.WithTracer(<nil>)",
+		},
 		{
 			desc: "RecoverFromPanics",
 			give: RecoverFromPanics(),
 			want: "fx.RecoverFromPanics()",
 		},
+		{
+			desc: "DumpStacksOnTimeout",
+			give: DumpStacksOnTimeout(),
+			want: "fx.DumpStacksOnTimeout()",
+		},
+		{
+			desc: "ProfileLabels",
+			give: ProfileLabels(),
+			want: "fx.ProfileLabels()",
+		},
+		{
+			desc: "Lint",
+			give: Lint(),
+			want: "fx.Lint()",
+		},
+		{
+			desc: "Strict",
+			give: Strict(),
+			want: "fx.Strict()",
+		},
+		{
+			desc: "ProvideWithRetry",
+			give: ProvideWithRetry(os.Open, Retry(3, time.Second)),
+			want: "fx.ProvideWithRetry(os.Open(), fx.Retry(3, 1s))",
+		},
+		{
+			desc: "ProvideIf",
+			give: ProvideIf(testing.Short, bytes.NewReader),
+			want: "fx.ProvideIf(testing.Short(), bytes.NewReader())",
+		},
+		{
+			desc: "RequireGroupNonEmpty",
+			give: RequireGroupNonEmpty[io.Reader]("readers"),
+			want: `fx.RequireGroupNonEmpty[io.Reader]("readers")`,
+		},
+		{
+			desc: "RequireGroupMin",
+			give: RequireGroupMin[io.Reader]("readers", 2),
+			want: `fx.RequireGroupMin[io.Reader]("readers", 2)`,
+		},
+		{
+			desc: "DigContainerOptions",
+			give: DigContainerOptions(),
+			want: "fx.DigContainerOptions([])",
+		},
+		{
+			desc: "DigScopeOptions",
+			give: DigScopeOptions(),
+			want: "fx.DigScopeOptions([])",
+		},
+		{
+			desc: "TestOnlyModule",
+			give: TestOnlyModule("fakes", Provide(bytes.NewReader)),
+			want: `fx.TestOnlyModule("fakes", [fx.Provide(bytes.NewReader())])`,
+		},
+		{
+			desc: "BeforeRun",
+			give: BeforeRun(writePIDFile),
+			want: "fx.BeforeRun(go.uber.org/fx_test.writePIDFile())",
+		},
+		{
+			desc: "AfterRun",
+			give: AfterRun(removePIDFile),
+			want: "fx.AfterRun(go.uber.org/fx_test.removePIDFile())",
+		},
+		{
+			desc: "RecordDemandPaths",
+			give: RecordDemandPaths(),
+			want: "fx.RecordDemandPaths()",
+		},
+		{
+			desc: "ProvideAtStart",
+			give: ProvideAtStart(bytes.NewReader),
+			want: "fx.ProvideAtStart(bytes.NewReader())",
+		},
+		{
+			desc: "InvokeAtStart",
+			give: InvokeAtStart(testing.Short),
+			want: "fx.InvokeAtStart(testing.Short())",
+		},
+		{
+			desc: "AppName",
+			give: AppName("payments-api"),
+			want: `fx.AppName("payments-api")`,
+		},
+		{
+			desc: "DecorateLogger",
+			give: DecorateLogger(identityLogger),
+			want: "fx.DecorateLogger(go.uber.org/fx_test.identityLogger())",
+		},
+		{
+			desc: "InvokeWith",
+			give: InvokeWith(testing.Short, Supply(1)),
+			want: "fx.InvokeWith(testing.Short(), [fx.Supply(int)])",
+		},
+		{
+			desc: "RecoverPanicsIn",
+			give: RecoverPanicsIn(PanicsInConstructors, PanicsInHooks),
+			want: "fx.RecoverPanicsIn([constructors hooks])",
+		},
+		{
+			desc: "OnPanic",
+			give: OnPanic(reportPanic),
+			want: "fx.OnPanic([go.uber.org/fx_test.reportPanic()])",
+		},
+		{
+			desc: "ProvideAll",
+			give: ProvideAll([]any{bytes.NewReader, bytes.NewBuffer}),
+			want: "fx.ProvideAll(bytes.NewReader(), bytes.NewBuffer())",
+		},
+		{
+			desc: "ProvideMap",
+			give: ProvideMap(map[string]any{"reader": bytes.NewReader, "buffer": bytes.NewBuffer}),
+			want: `fx.ProvideMap("buffer": bytes.NewBuffer(), "reader": bytes.NewReader())`,
+		},
+		{
+			desc: "ProvideGeneric",
+			give: ProvideGeneric(StartHook[func()]),
+			want: "fx.ProvideGeneric(go.uber.org/fx.StartHook[...]() (func(func()) fx.Hook))",
+		},
+		{
+			desc: "ProvideWire",
+			give: ProvideWire(bytes.NewReader, WireBind(new(io.Reader), new(*bytes.Reader))),
+			want: "fx.ProvideWire(bytes.NewReader(), fx.WireBind(new(io.Reader), new(*bytes.Reader)))",
+		},
+		{
+			desc: "ProvideDefault",
+			give: ProvideDefault(bytes.NewReader),
+			want: "fx.ProvideDefault(bytes.NewReader())",
+		},
+		{
+			desc: "Default",
+			give: Default(bytes.NewReader(nil)),
+			want: "fx.Default(*bytes.Reader)",
+		},
+		{
+			desc: "WithAppInfo",
+			give: WithAppInfo(AppInfo{Name: "users", Version: "v1.2.3"}),
+			want: `fx.WithAppInfo("users", "v1.2.3")`,
+		},
+		{
+			desc: "OnDuplicate",
+			give: OnDuplicate(KeepLast),
+			want: "fx.OnDuplicate(fx.KeepLast)",
+		},
+		{
+			desc: "OnDuplicate with types",
+			give: OnDuplicate(KeepFirst, new(*bytes.Buffer), new(io.Writer)),
+			want: "fx.OnDuplicate(fx.KeepFirst, new(*bytes.Buffer), new(io.Writer))",
+		},
 		{
 			desc: "Logger",
 			give: WithLogger(func() fxevent.Logger { return testLogger{t} }),
@@ -2703,6 +3691,11 @@ func TestOptionString(t *testing.T) {
 			give: Replace(bytes.NewReader(nil)),
 			want: "fx.Replace(*bytes.Reader)",
 		},
+		{
+			desc: "Append",
+			give: Append[io.Reader]("readers", bytes.NewReader(nil)),
+			want: `fx.Append[io.Reader]("readers")`,
+		},
 	}
 
 	for _, tt := range tests {
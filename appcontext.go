@@ -0,0 +1,88 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// AppContext is a context bound to the lifetime of an application.
+// It is canceled as soon as the application starts stopping,
+// before any OnStop hooks run, and stays canceled afterwards.
+//
+// Constructors that start background work, like consumers or pollers,
+// should use it instead of context.Background so that the work does not
+// outlive the application.
+//
+//	func NewConsumer(ctx fx.AppContext, q *Queue) *Consumer {
+//		c := &Consumer{q: q}
+//		go c.consume(ctx)
+//		return c
+//	}
+//
+// Since the AppContext can't be reset, an application that uses it
+// can't be started again once it stops: [App.Start] fails instead.
+// Applications that never watch it for cancellation,
+// with its Done or Err methods or by deriving contexts from it,
+// may be restarted.
+//
+// The AppContext is provided to all Fx applications.
+type AppContext interface {
+	context.Context
+}
+
+// appContext holds the AppContext of an application
+// and the function that cancels it.
+type appContext struct {
+	context.Context
+
+	cancel context.CancelFunc
+
+	// used records whether the context was watched for cancellation,
+	// with its Done or Err methods.
+	used atomic.Bool
+}
+
+func newAppContext() *appContext {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &appContext{Context: ctx, cancel: cancel}
+}
+
+func (c *appContext) Done() <-chan struct{} {
+	c.used.Store(true)
+	return c.Context.Done()
+}
+
+func (c *appContext) Err() error {
+	c.used.Store(true)
+	return c.Context.Err()
+}
+
+// checkRestart fails if the application can't be started again
+// because the AppContext was watched for cancellation before it stopped.
+func (c *appContext) checkRestart() error {
+	if c.used.Load() && c.Context.Err() != nil {
+		return errors.New("cannot start application again after it stopped: " +
+			"it uses fx.AppContext, which stays canceled")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestAppContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CanceledOnStop", func(t *testing.T) {
+		t.Parallel()
+
+		var appCtx fx.AppContext
+		app := fxtest.New(t,
+			fx.Populate(&appCtx),
+		)
+		require.NotNil(t, appCtx)
+		assert.NoError(t, appCtx.Err(), "context must be alive after New")
+
+		app.RequireStart()
+		assert.NoError(t, appCtx.Err(), "context must be alive after Start")
+
+		app.RequireStop()
+		assert.ErrorIs(t, appCtx.Err(), context.Canceled)
+	})
+
+	t.Run("CanceledBeforeOnStop", func(t *testing.T) {
+		t.Parallel()
+
+		var canceled bool
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle, ctx fx.AppContext) {
+				lc.Append(fx.StopHook(func() {
+					canceled = ctx.Err() != nil
+				}))
+			}),
+		)
+		app.RequireStart().RequireStop()
+		assert.True(t, canceled, "context must be canceled before OnStop hooks run")
+	})
+
+	t.Run("BackgroundConsumer", func(t *testing.T) {
+		t.Parallel()
+
+		returned := make(chan struct{})
+		app := fxtest.New(t,
+			fx.Invoke(func(ctx fx.AppContext) {
+				go func() {
+					defer close(returned)
+					<-ctx.Done()
+				}()
+			}),
+		)
+		app.RequireStart()
+		select {
+		case <-returned:
+			t.Fatal("consumer returned before stop")
+		default:
+		}
+
+		app.RequireStop()
+		<-returned
+	})
+
+	t.Run("CanceledOnRollback", func(t *testing.T) {
+		t.Parallel()
+
+		var appCtx fx.AppContext
+		app := fxtest.New(t,
+			fx.Populate(&appCtx),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() error {
+					return errors.New("great sadness")
+				}))
+			}),
+		)
+		require.Error(t, app.Start(context.Background()))
+		assert.ErrorIs(t, appCtx.Err(), context.Canceled)
+	})
+	t.Run("RestartFailsIfUsed", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle, ctx fx.AppContext) {
+				lc.Append(fx.StartHook(func() {
+					done := ctx.Done()
+					go func() { <-done }()
+				}))
+			}),
+		)
+		app.RequireStart().RequireStop()
+
+		err := app.Start(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot start application again after it stopped")
+		assert.Equal(t, fx.AppStopped, app.State())
+	})
+
+	t.Run("RestartSucceedsIfUnused", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Invoke(func(fx.AppContext) {}),
+		)
+		app.RequireStart().RequireStop()
+		app.RequireStart().RequireStop()
+	})
+}
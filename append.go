@@ -0,0 +1,90 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// Append adds values to the value group with the given name
+// without replacing the values already in it.
+//
+// For example, the following adds a route only available in tests
+// to the "routes" group consumed by the server module:
+//
+//	fxtest.New(t,
+//		server.Module,
+//		fx.Append[Route]("routes", healthcheck.Route{}),
+//	)
+//
+// This is equivalent to a decorator of the group
+// that returns its values followed by the given values.
+// As with [Decorate], the values are only visible to the module
+// Append is passed to and the modules nested in it,
+// so Append may be used to extend a group for a single module.
+//
+// Because Append is a decorator, it follows the same rules: a group may
+// only be decorated once per module, so Append fails if the same module
+// also decorates the group with [Decorate] or another Append of the group.
+// Pass all the values to a single Append instead,
+// or wrap one of them in a nested [Module]:
+//
+//	fx.Append[Route]("routes", healthcheck.Route{}),
+//	fx.Module("debug",
+//		fx.Append[Route]("routes", debug.Route{}),
+//		// ...
+//	),
+//
+// The type parameter is the element type of the group.
+// It may be an interface implemented by the given values.
+func Append[T any](group string, values ...T) Option {
+	return appendOption[T]{
+		group:  group,
+		values: values,
+		stack:  fxreflect.CallerStack(1, 0),
+	}
+}
+
+type appendOption[T any] struct {
+	group  string
+	values []T
+	stack  fxreflect.Stack
+}
+
+func (o appendOption[T]) apply(m *module) {
+	tag := fmt.Sprintf(`group:"%v"`, o.group)
+	m.decorators = append(m.decorators, decorator{
+		Target: Annotate(func(values []T) []T {
+			out := make([]T, 0, len(values)+len(o.values))
+			out = append(out, values...)
+			return append(out, o.values...)
+		}, ParamTags(tag), ResultTags(tag)),
+		Stack: o.stack,
+		Name:  o.String(),
+	})
+}
+
+func (o appendOption[T]) String() string {
+	return fmt.Sprintf("fx.Append[%v](%q)", reflect.TypeOf((*T)(nil)).Elem(), o.group)
+}
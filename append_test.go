@@ -0,0 +1,147 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestAppend(t *testing.T) {
+	t.Parallel()
+
+	type Route interface{ Pattern() string }
+
+	provideRoute := func(pattern string) fx.Option {
+		return fx.Provide(fx.Annotate(
+			func() Route { return route(pattern) },
+			fx.ResultTags(`group:"routes"`),
+		))
+	}
+	patterns := func(routes []Route) []string {
+		var out []string
+		for _, r := range routes {
+			out = append(out, r.Pattern())
+		}
+		return out
+	}
+
+	t.Run("AppendsToGroup", func(t *testing.T) {
+		t.Parallel()
+
+		var got []string
+		app := fxtest.New(t,
+			provideRoute("/users"),
+			fx.Append[Route]("routes", route("/health"), route("/debug")),
+			fx.Invoke(fx.Annotate(func(routes []Route) {
+				got = patterns(routes)
+			}, fx.ParamTags(`group:"routes"`))),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{"/users", "/health", "/debug"}, got)
+	})
+
+	t.Run("EmptyGroup", func(t *testing.T) {
+		t.Parallel()
+
+		var got []string
+		app := fxtest.New(t,
+			fx.Append[Route]("routes", route("/health")),
+			fx.Invoke(fx.Annotate(func(routes []Route) {
+				got = patterns(routes)
+			}, fx.ParamTags(`group:"routes"`))),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{"/health"}, got)
+	})
+
+	t.Run("ScopedToModule", func(t *testing.T) {
+		t.Parallel()
+
+		var inner, outer []string
+		app := fxtest.New(t,
+			provideRoute("/users"),
+			fx.Module("test",
+				fx.Append[Route]("routes", route("/health")),
+				fx.Invoke(fx.Annotate(func(routes []Route) {
+					inner = patterns(routes)
+				}, fx.ParamTags(`group:"routes"`))),
+			),
+			fx.Invoke(fx.Annotate(func(routes []Route) {
+				outer = patterns(routes)
+			}, fx.ParamTags(`group:"routes"`))),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{"/users", "/health"}, inner)
+		assert.Equal(t, []string{"/users"}, outer)
+	})
+
+	t.Run("OncePerModule", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.Append[Route]("routes", route("/health")),
+			fx.Append[Route]("routes", route("/debug")),
+			fx.Invoke(fx.Annotate(func([]Route) {}, fx.ParamTags(`group:"routes"`))),
+		)
+		assert.ErrorContains(t, app.Err(), `fx_test.Route[group="routes"] already decorated`)
+
+		var got []string
+		app = NewForTest(t,
+			fx.Append[Route]("routes", route("/health")),
+			fx.Module("debug",
+				fx.Append[Route]("routes", route("/debug")),
+				fx.Invoke(fx.Annotate(func(routes []Route) {
+					got = patterns(routes)
+				}, fx.ParamTags(`group:"routes"`))),
+			),
+		)
+		assert.NoError(t, app.Err())
+		assert.Equal(t, []string{"/health", "/debug"}, got)
+	})
+
+	t.Run("Event", func(t *testing.T) {
+		t.Parallel()
+
+		app, spy := NewSpied(
+			fx.Append[Route]("routes", route("/health")),
+			fx.Invoke(fx.Annotate(func([]Route) {}, fx.ParamTags(`group:"routes"`))),
+		)
+		assert.NoError(t, app.Err())
+
+		decorated := spy.Events().SelectByTypeName("Decorated")
+		if assert.Len(t, decorated, 1) {
+			assert.Equal(t, `fx.Append[fx_test.Route]("routes")`,
+				decorated[0].(*fxevent.Decorated).DecoratorName)
+		}
+	})
+}
+
+type route string
+
+func (r route) Pattern() string { return string(r) }
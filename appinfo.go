@@ -0,0 +1,186 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"go.uber.org/fx/fxevent"
+)
+
+// AppInfo describes the running application.
+// It's provided to all Fx applications,
+// so loggers, metrics, and health endpoints can report consistent metadata:
+//
+//	func NewHealthHandler(info fx.AppInfo) *HealthHandler {
+//		return &HealthHandler{
+//			Version: info.Version,
+//			Since:   info.StartTime,
+//		}
+//	}
+//
+// The name and version of the application may be set with [WithAppInfo].
+type AppInfo struct {
+	// Name of the application.
+	// Defaults to the import path of the main package.
+	Name string
+
+	// Version of the application.
+	// Defaults to the version of the main module.
+	Version string
+
+	// Build information of the running binary,
+	// or nil if it's not available.
+	BuildInfo *debug.BuildInfo
+
+	// Time at which the application was built with New.
+	StartTime time.Time
+
+	// Version of Fx that the application uses.
+	// This is always [Version].
+	FxVersion string
+}
+
+// WithAppInfo specifies the name and version of the application
+// reported by the [AppInfo] provided to it.
+// Other fields of info are ignored.
+//
+//	fx.New(
+//		fx.WithAppInfo(fx.AppInfo{Name: "users", Version: version}),
+//		...
+//	)
+//
+// WithAppInfo may only be passed to [New].
+func WithAppInfo(info AppInfo) Option {
+	return withAppInfoOption{info}
+}
+
+type withAppInfoOption struct{ info AppInfo }
+
+func (o withAppInfoOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.WithAppInfo Option should be passed to top-level " +
+			"App, not to fx.Module")
+	} else {
+		m.app.info.Name = o.info.Name
+		m.app.info.Version = o.info.Version
+	}
+}
+
+func (o withAppInfoOption) String() string {
+	return fmt.Sprintf("fx.WithAppInfo(%q, %q)", o.info.Name, o.info.Version)
+}
+
+// initAppInfo fills in the fields of the AppInfo not set by WithAppInfo.
+func (app *App) initAppInfo() {
+	info := &app.info
+	info.StartTime = app.clock.Now()
+	info.FxVersion = Version
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.BuildInfo = bi
+		if info.Name == "" {
+			info.Name = bi.Path
+		}
+		if info.Version == "" {
+			info.Version = bi.Main.Version
+		}
+	}
+}
+
+// AppName names the application.
+// The name is reported by the [AppInfo] provided to it,
+// and included in every event it emits,
+// so that processes running several applications,
+// such as tests or binaries hosting multiple services,
+// can tell their events apart:
+//
+//	fx.New(
+//		fx.AppName("payments-api"),
+//		...
+//	)
+//
+// The [fxevent.ConsoleLogger] prefixes the events of named applications
+// with their name, as in "[Fx payments-api]",
+// and the Zap and slog loggers add it to an "app" field.
+//
+// Names set with [WithAppInfo] are included in events as well.
+// AppName may only be passed to [New].
+func AppName(name string) Option {
+	return appNameOption(name)
+}
+
+type appNameOption string
+
+func (o appNameOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.AppName Option should be passed to top-level " +
+			"App, not to fx.Module")
+	} else {
+		m.app.info.Name = string(o)
+	}
+}
+
+func (o appNameOption) String() string {
+	return fmt.Sprintf("fx.AppName(%q)", string(o))
+}
+
+// stampedLogger is an fxevent.Logger that stamps the events it logs
+// with their sequence number and timestamp,
+// and with the name of the application, if any.
+type stampedLogger struct {
+	fxevent.Logger
+
+	app *App
+}
+
+// stampLogger returns a logger that stamps the events it logs
+// on behalf of the application.
+func (app *App) stampLogger(log fxevent.Logger) fxevent.Logger {
+	if _, ok := log.(stampedLogger); ok {
+		return log
+	}
+	return stampedLogger{Logger: log, app: app}
+}
+
+func (l stampedLogger) LogEvent(event fxevent.Event) {
+	l.app.stampEvent(event)
+	l.Logger.LogEvent(event)
+}
+
+// stampEvent sets the name of the application, the next sequence number,
+// and the current time on event, unless they're already set.
+// Events buffered before their logger is built are stamped
+// when they're emitted rather than when they're logged.
+func (app *App) stampEvent(event fxevent.Event) {
+	m := fxevent.MetadataOf(event)
+	if m == nil {
+		return
+	}
+	if m.AppName == "" {
+		m.AppName = app.name
+	}
+	if m.Seq == 0 {
+		m.Seq = app.eventSeq.Add(1)
+		m.Timestamp = app.clock.Now()
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxclock"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+func TestAppInfo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Default", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		clock.Add(time.Hour)
+
+		var info fx.AppInfo
+		fxtest.New(t, fx.WithClock(clock), fx.Populate(&info))
+
+		assert.Equal(t, fx.Version, info.FxVersion)
+		assert.Equal(t, clock.Now(), info.StartTime)
+		if assert.NotNil(t, info.BuildInfo) {
+			assert.Equal(t, info.BuildInfo.Path, info.Name)
+			assert.Equal(t, info.BuildInfo.Main.Version, info.Version)
+		}
+	})
+
+	t.Run("WithAppInfo", func(t *testing.T) {
+		t.Parallel()
+
+		var info fx.AppInfo
+		fxtest.New(t,
+			fx.WithAppInfo(fx.AppInfo{
+				Name:      "users",
+				Version:   "v1.2.3",
+				FxVersion: "ignored",
+			}),
+			fx.Populate(&info),
+		)
+
+		assert.Equal(t, "users", info.Name)
+		assert.Equal(t, "v1.2.3", info.Version)
+		assert.Equal(t, fx.Version, info.FxVersion)
+	})
+
+	t.Run("InModule", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Module("foo", fx.WithAppInfo(fx.AppInfo{Name: "users"})),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.WithAppInfo Option should be passed to top-level App")
+	})
+}
+
+func TestAppName(t *testing.T) {
+	t.Parallel()
+
+	// appNames returns the names of the applications
+	// that emitted the events logged to spy.
+	appNames := func(spy *fxlog.Spy) map[string]struct{} {
+		names := make(map[string]struct{})
+		for _, e := range spy.Events() {
+			names[reflect.ValueOf(e).Elem().FieldByName("AppName").String()] = struct{}{}
+		}
+		return names
+	}
+
+	t.Run("Events", func(t *testing.T) {
+		t.Parallel()
+
+		var info fx.AppInfo
+		spy := new(fxlog.Spy)
+		app := fxtest.New(t,
+			fx.AppName("payments-api"),
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.Populate(&info),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.Equal(t, "payments-api", info.Name)
+		assert.Equal(t, map[string]struct{}{"payments-api": {}}, appNames(spy),
+			"all events must be named")
+	})
+
+	t.Run("WithAppInfo", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		fxtest.New(t,
+			fx.WithAppInfo(fx.AppInfo{Name: "users"}),
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+		)
+		assert.Equal(t, map[string]struct{}{"users": {}}, appNames(spy))
+	})
+
+	t.Run("Unnamed", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		fxtest.New(t, fx.WithLogger(func() fxevent.Logger { return spy }))
+		assert.Equal(t, map[string]struct{}{"": {}}, appNames(spy),
+			"events must not be named after the default AppInfo name")
+	})
+
+	t.Run("ConsoleLogger", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		app := fx.New(
+			fx.AppName("payments-api"),
+			fx.Logger(log.New(&buf, "", 0)),
+		)
+		require.NoError(t, app.Err())
+		require.NotZero(t, buf.Len())
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			assert.True(t, strings.HasPrefix(line, "[Fx payments-api] "), "unexpected line %q", line)
+		}
+	})
+
+	t.Run("Module", func(t *testing.T) {
+		t.Parallel()
+
+		err := fx.New(
+			fx.NopLogger,
+			fx.Module("foo", fx.AppName("payments-api")),
+		).Err()
+		assert.ErrorContains(t, err,
+			"fx.AppName Option should be passed to top-level App, not to fx.Module")
+	})
+}
+
+func TestEventSeq(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ordered", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		spy := new(fxlog.Spy)
+		app := fxtest.New(t,
+			fx.WithClock(clock),
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() { clock.Add(time.Second) }))
+			}),
+		)
+		app.RequireStart().RequireStop()
+
+		events := spy.Events()
+		require.NotEmpty(t, events)
+		for i, e := range events {
+			assert.Equal(t, uint64(i+1), fxevent.Seq(e), "event %d (%T)", i, e)
+			assert.False(t, fxevent.Timestamp(e).IsZero(), "event %d (%T)", i, e)
+		}
+		last := events[len(events)-1]
+		assert.Equal(t, clock.Now(), fxevent.Timestamp(last))
+	})
+
+	t.Run("Buffered", func(t *testing.T) {
+		t.Parallel()
+
+		// Events of modules with their own logger are buffered
+		// until the logger is built, but stamped when emitted.
+		clock := fxclock.NewMock()
+		begin := clock.Now()
+		var root, mod fxlog.Spy
+		fxtest.New(t,
+			fx.WithClock(clock),
+			fx.WithLogger(func() fxevent.Logger { return &root }),
+			fx.Module("mod",
+				fx.WithLogger(func() fxevent.Logger {
+					clock.Add(time.Second)
+					return &mod
+				}),
+				fx.Provide(func() string { return "" }),
+			),
+		)
+
+		provided := mod.Events().SelectByTypeName("Provided")
+		require.Len(t, provided, 1)
+		assert.Equal(t, begin, fxevent.Timestamp(provided[0]))
+
+		// Merged, the events of both loggers are numbered without gaps.
+		var seqs []int
+		for _, e := range append(root.Events(), mod.Events()...) {
+			seqs = append(seqs, int(fxevent.Seq(e)))
+		}
+		sort.Ints(seqs)
+		for i, seq := range seqs {
+			require.Equal(t, i+1, seq)
+		}
+	})
+
+	t.Run("Separate apps", func(t *testing.T) {
+		t.Parallel()
+
+		var a, b fxlog.Spy
+		fxtest.New(t, fx.WithLogger(func() fxevent.Logger { return &a }))
+		fxtest.New(t, fx.WithLogger(func() fxevent.Logger { return &b }))
+		require.NotEmpty(t, a.Events())
+		require.NotEmpty(t, b.Events())
+		assert.Equal(t, uint64(1), fxevent.Seq(a.Events()[0]))
+		assert.Equal(t, uint64(1), fxevent.Seq(b.Events()[0]))
+	})
+}
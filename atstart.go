@@ -0,0 +1,187 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// ProvideAtStart is similar to [Provide], but its constructors may only run
+// once the application is starting.
+// Use it for values that can't be built during initialization,
+// for example, because they require the application to be elected leader,
+// or wait on a future that resolves after the application starts.
+//
+// The results of these constructors may only be used by functions
+// passed to [InvokeAtStart], and by the constructors those functions need.
+// If a constructor or invocation run during initialization depends on them,
+// initialization fails.
+// Once the application has started,
+// they may also be retrieved from the [App.DigContainer].
+//
+// As with Provide, pass [Private] to restrict the constructors
+// to the current module.
+//
+//	fx.New(
+//		fx.ProvideAtStart(election.AwaitLeadership),
+//		fx.InvokeAtStart(func(lc fx.Lifecycle, l *election.Leader) {
+//			lc.Append(fx.StartStopHook(l.RunJobs, l.StopJobs))
+//		}),
+//	)
+func ProvideAtStart(constructors ...interface{}) Option {
+	return provideAtStartOption{
+		Targets: constructors,
+		Stack:   fxreflect.CallerStack(1, 0),
+	}
+}
+
+type provideAtStartOption struct {
+	Targets []interface{}
+	Stack   fxreflect.Stack
+}
+
+func (o provideAtStartOption) apply(mod *module) {
+	var private bool
+
+	targets := make([]interface{}, 0, len(o.Targets))
+	for _, target := range o.Targets {
+		if _, ok := target.(privateOption); ok {
+			private = true
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	for _, target := range targets {
+		mod.provides = append(mod.provides, provide{
+			Target:  target,
+			Stack:   o.Stack,
+			Private: private,
+			AtStart: true,
+		})
+	}
+}
+
+func (o provideAtStartOption) String() string {
+	items := make([]string, len(o.Targets))
+	for i, c := range o.Targets {
+		items[i] = fxreflect.FuncName(c)
+	}
+	return fmt.Sprintf("fx.ProvideAtStart(%s)", strings.Join(items, ", "))
+}
+
+// InvokeAtStart is similar to [Invoke], but its functions run
+// when the application is first started, before any OnStart hooks.
+// They may depend on the results of constructors passed to [ProvideAtStart],
+// and may append hooks to the [Lifecycle]:
+// these hooks run after the hooks appended during initialization.
+//
+// Functions passed to InvokeAtStart run in the order they were provided,
+// with the context of the module they were provided to.
+// If one of them fails, the application fails to start,
+// and starting it again runs the functions from the one that failed.
+// Functions that succeeded are not run again if the application is restarted.
+func InvokeAtStart(funcs ...interface{}) Option {
+	return invokeAtStartOption{
+		Targets: funcs,
+		Stack:   fxreflect.CallerStack(1, 0),
+	}
+}
+
+type invokeAtStartOption struct {
+	Targets []interface{}
+	Stack   fxreflect.Stack
+}
+
+func (o invokeAtStartOption) apply(mod *module) {
+	for _, target := range o.Targets {
+		mod.app.startInvokes = append(mod.app.startInvokes, startInvoke{
+			module: mod,
+			invoke: invoke{Target: target, Stack: o.Stack},
+		})
+	}
+}
+
+func (o invokeAtStartOption) String() string {
+	items := make([]string, len(o.Targets))
+	for i, f := range o.Targets {
+		items[i] = fxreflect.FuncName(f)
+	}
+	return fmt.Sprintf("fx.InvokeAtStart(%s)", strings.Join(items, ", "))
+}
+
+// startInvoke is a function passed to fx.InvokeAtStart
+// and the module it was passed to.
+type startInvoke struct {
+	module *module
+	invoke invoke
+}
+
+// invokeAtStart runs the functions passed to fx.InvokeAtStart
+// that didn't succeed yet, until the first one that fails.
+// Start runs it only while starting, so calls don't overlap.
+func (app *App) invokeAtStart() error {
+	app.startReached.Store(true)
+	for app.startInvoked < len(app.startInvokes) {
+		si := app.startInvokes[app.startInvoked]
+		if err := si.module.invoke(si.invoke); err != nil {
+			return err
+		}
+		app.startInvoked++
+	}
+	return nil
+}
+
+// atStartContainer is a container whose constructors fail
+// if they run before the application starts.
+type atStartContainer struct {
+	container
+
+	app  *App
+	name string // name of the constructor
+}
+
+var _ container = atStartContainer{}
+
+func (c atStartContainer) Provide(constructor interface{}, opts ...dig.ProvideOption) error {
+	// Report the failure through an error result,
+	// adding one if the constructor doesn't have one.
+	return provideWrapped(c.container, constructor, opts, true, func(ft reflect.Type, call constructorFunc) constructorFunc {
+		return func(args []reflect.Value) []reflect.Value {
+			if c.app.startReached.Load() {
+				return call(args)
+			}
+
+			results := make([]reflect.Value, ft.NumOut())
+			for i := range results {
+				results[i] = reflect.Zero(ft.Out(i))
+			}
+			err := fmt.Errorf("fx.ProvideAtStart(%v) can't run before the application starts: "+
+				"only functions passed to fx.InvokeAtStart may depend on it", c.name)
+			results[len(results)-1] = reflect.ValueOf(&err).Elem()
+			return results
+		}
+	})
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestProvideAtStart(t *testing.T) {
+	t.Parallel()
+
+	type Leader struct{ ID string }
+	type Jobs struct{ Leader *Leader }
+
+	t.Run("resolved at start", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		app := fxtest.New(t,
+			ProvideAtStart(func() *Leader {
+				calls = append(calls, "elect")
+				return &Leader{ID: "a"}
+			}),
+			Provide(func(l *Leader) *Jobs { return &Jobs{Leader: l} }),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(StartHook(func() { calls = append(calls, "eager hook") }))
+			}),
+			InvokeAtStart(func(lc Lifecycle, j *Jobs) {
+				calls = append(calls, "invoke "+j.Leader.ID)
+				lc.Append(StartStopHook(
+					func() { calls = append(calls, "late hook") },
+					func() { calls = append(calls, "late stop") },
+				))
+			}),
+		)
+		assert.Empty(t, calls, "nothing may run before start")
+
+		app.RequireStart()
+		assert.Equal(t, []string{"elect", "invoke a", "eager hook", "late hook"}, calls)
+
+		var leader *Leader
+		require.NoError(t, app.DigContainer().Invoke(func(l *Leader) { leader = l }))
+		assert.Equal(t, "a", leader.ID)
+
+		app.RequireStop()
+		assert.Equal(t, "late stop", calls[len(calls)-1])
+	})
+
+	t.Run("eager dependency", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			ProvideAtStart(func() *Leader { return &Leader{} }),
+			Provide(func(l *Leader) *Jobs { return &Jobs{Leader: l} }),
+			Invoke(func(*Jobs) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "can't run before the application starts")
+		assert.Contains(t, err.Error(), "fx.InvokeAtStart")
+	})
+
+	t.Run("constructor error", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			ProvideAtStart(func() (*Leader, error) {
+				return nil, errors.New("great sadness")
+			}),
+			InvokeAtStart(func(*Leader) {}),
+		)
+		require.NoError(t, app.Err())
+
+		err := app.Start(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+	})
+
+	t.Run("invoked once", func(t *testing.T) {
+		t.Parallel()
+
+		var invoked int
+		app := fxtest.New(t, InvokeAtStart(func() { invoked++ }))
+		for i := 0; i < 2; i++ {
+			app.RequireStart().RequireStop()
+		}
+		assert.Equal(t, 1, invoked)
+	})
+
+	t.Run("retried after failure", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		fail := true
+		app := fxtest.New(t,
+			InvokeAtStart(
+				func() { calls = append(calls, "first") },
+				func() error {
+					calls = append(calls, "second")
+					if fail {
+						return errors.New("great sadness")
+					}
+					return nil
+				},
+			),
+		)
+		require.Error(t, app.Start(context.Background()))
+
+		fail = false
+		app.RequireStart().RequireStop()
+		app.RequireStart().RequireStop()
+		assert.Equal(t, []string{"first", "second", "second"}, calls)
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			Module("leader",
+				ProvideAtStart(func() *Leader { return &Leader{ID: "b"} }, Private),
+				InvokeAtStart(func(l *Leader) {
+					assert.Equal(t, "b", l.ID)
+				}),
+			),
+		)
+		app.RequireStart().RequireStop()
+	})
+}
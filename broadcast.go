@@ -120,6 +120,17 @@ func (b *broadcaster) Broadcast(signal ShutdownSignal) error {
 	return nil
 }
 
+// NotifyWaiters sends the given signal to the channels already created
+// via Wait, without recording it for later ones or sending it to Done.
+// It's used to report that the application won't run, which is not
+// a shutdown that later callers of Wait or Done should observe.
+func (b *broadcaster) NotifyWaiters(signal ShutdownSignal) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.broadcastWait(signal)
+}
+
 func (b *broadcaster) broadcast(
 	signal ShutdownSignal,
 	anchors ...func(ShutdownSignal) (int, int),
@@ -0,0 +1,92 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+
+	"go.uber.org/fx/internal/fxclock"
+)
+
+// Clock defines how Fx accesses time.
+// It's used for the start and stop timeouts, scheduled shutdowns,
+// retry backoffs, and the runtimes reported to the [fxevent.Logger].
+type Clock = fxclock.Clock
+
+// WithClock specifies how Fx accesses time.
+// Applications rarely need this outside of tests,
+// where a fake clock makes timeouts deterministic.
+//
+// Defaults to a clock based on the [time] package.
+func WithClock(clock Clock) Option {
+	return withClockOption{clock}
+}
+
+type withClockOption struct{ clock Clock }
+
+func (o withClockOption) apply(m *module) {
+	switch {
+	case m.parent != nil:
+		m.app.err = fmt.Errorf("fx.WithClock Option should be passed to top-level App, " +
+			"not to fx.Module")
+	case o.clock == nil:
+		m.app.err = fmt.Errorf("fx.WithClock: clock must not be nil")
+	default:
+		m.app.clock = o.clock
+	}
+}
+
+func (o withClockOption) String() string {
+	return fmt.Sprintf("fx.WithClock(%T)", o.clock)
+}
+
+// MonotonicTimeouts makes the contexts that Fx creates to bound the
+// application's start and stop in [App.Run], and its scheduled shutdowns,
+// expire with a timer instead of carrying a deadline of their own.
+// Their Deadline method only reports the deadline of their parent context,
+// if any.
+// Contexts passed to [App.Start] and [App.Stop] are used as-is.
+//
+// Use it if hooks forward the deadline of their context to other
+// processes, for example as the deadline of an RPC, which relies on the
+// clocks of those processes agreeing with this one.
+// The timeouts expire at the same time either way:
+// Go measures them with the monotonic clock.
+//
+// It applies to the clock set with [WithClock], if any.
+func MonotonicTimeouts() Option {
+	return monotonicTimeoutsOption{}
+}
+
+type monotonicTimeoutsOption struct{}
+
+func (monotonicTimeoutsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.MonotonicTimeouts Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.monotonicTimeouts = true
+	}
+}
+
+func (monotonicTimeoutsOption) String() string {
+	return "fx.MonotonicTimeouts()"
+}
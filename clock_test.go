@@ -0,0 +1,115 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/fxclock"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+func TestMonotonicTimeouts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("HooksSeeNoDeadline", func(t *testing.T) {
+		t.Parallel()
+
+		var startDeadline, stopDeadline bool
+		app, spy := NewSpied(
+			MonotonicTimeouts(),
+			Invoke(func(lc Lifecycle, s Shutdowner) {
+				lc.Append(Hook{
+					OnStart: func(ctx context.Context) error {
+						_, startDeadline = ctx.Deadline()
+						return s.Shutdown()
+					},
+					OnStop: func(ctx context.Context) error {
+						_, stopDeadline = ctx.Deadline()
+						return nil
+					},
+				})
+			}),
+		)
+
+		require.NoError(t, app.RunErr())
+		assert.False(t, startDeadline, "start context must not carry a deadline")
+		assert.False(t, stopDeadline, "stop context must not carry a deadline")
+
+		configured := spy.Events().SelectByTypeName("Configured")
+		require.Len(t, configured, 1)
+		assert.Equal(t, "system with monotonic timeouts", configured[0].(*fxevent.Configured).Clock)
+	})
+
+	t.Run("StartTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		mockClock := fxclock.NewMock()
+		block := func(ctx context.Context) error {
+			mockClock.Add(5 * time.Second)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		// See TestAppStop/Timeout for why the logger is a spy.
+		spy := new(fxlog.Spy)
+		app := New(
+			WithClock(mockClock),
+			MonotonicTimeouts(),
+			StartTimeout(time.Second),
+			WithLogger(func() fxevent.Logger { return spy }),
+			Invoke(func(lc Lifecycle) { lc.Append(Hook{OnStart: block}) }),
+		)
+
+		assert.ErrorIs(t, app.RunErr(), context.DeadlineExceeded)
+	})
+
+	t.Run("InModule", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Module("child", MonotonicTimeouts()))
+		assert.ErrorContains(t, app.Err(), "fx.MonotonicTimeouts Option should be passed to top-level App")
+	})
+}
+
+func TestWithClock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("InModule", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Module("child", WithClock(fxclock.NewMock())))
+		assert.ErrorContains(t, app.Err(), "fx.WithClock Option should be passed to top-level App")
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, WithClock(nil))
+		assert.EqualError(t, app.Err(), "fx.WithClock: clock must not be nil")
+	})
+}
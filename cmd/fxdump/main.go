@@ -0,0 +1,229 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+// fxdump describes the Fx application built by a Go package,
+// without running it.
+//
+//	fxdump [-func Options] [-graph file.dot] ./internal/server
+//
+// The package must declare a function that takes no arguments
+// and returns an [fx.Option] for the application, named Options by default.
+// It can't be a main package, since those can't be imported:
+// declare the function in a package that the main package imports.
+// fxdump prints whether the application is valid,
+// the types it's missing, its modules, the types its constructors provide,
+// and the order in which its invoked functions would run.
+// With -graph, it also writes the dependency graph of the application
+// in the DOT language to the given file, or to stdout for "-".
+// fxdump exits with status 1 if the application is invalid.
+//
+// fxdump runs [fxdump.Inspect] in a program it generates in a temporary
+// directory, without writing to the tree of the package, so the module of the package must depend on a version of go.uber.org/fx
+// that includes the fxdump package.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Fprintln(os.Stderr, "fxdump:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("fxdump", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: fxdump [-func name] [-graph file] package")
+		flags.PrintDefaults()
+	}
+	funcName := flags.String("func", "Options", "name of the function returning the application's fx.Option")
+	graph := flags.String("graph", "", `write the dependency graph in DOT to this file, or "-" for stdout`)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("expected exactly one package")
+	}
+
+	pkg, err := findPackage(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *graph != "" && *graph != "-" {
+		if *graph, err = filepath.Abs(*graph); err != nil {
+			return err
+		}
+	}
+
+	src, err := generate(programParams{
+		ImportPath: pkg.ImportPath,
+		Func:       *funcName,
+		Graph:      *graph,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Write the program outside the tree of the package,
+	// and overlay it onto a directory inside that tree
+	// so that it may import the package even if it's internal.
+	tmp, err := os.MkdirTemp("", "fxdump")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	mainFile := filepath.Join(tmp, "main.go")
+	if err := os.WriteFile(mainFile, src, 0o644); err != nil {
+		return err
+	}
+	progDir := filepath.Join(pkg.Dir, filepath.Base(tmp))
+	overlay, err := json.Marshal(goOverlay{
+		Replace: map[string]string{filepath.Join(progDir, "main.go"): mainFile},
+	})
+	if err != nil {
+		return err
+	}
+	overlayFile := filepath.Join(tmp, "overlay.json")
+	if err := os.WriteFile(overlayFile, overlay, 0o644); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "run", "-overlay", overlayFile, progDir)
+	cmd.Dir = pkg.Dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// goOverlay is the format of the file passed to the -overlay flag
+// of the go command.
+type goOverlay struct {
+	Replace map[string]string
+}
+
+type goPackage struct {
+	ImportPath string
+	Dir        string
+}
+
+// findPackage resolves a package pattern like "./internal/server"
+// with go list.
+// It fails for main packages, since they can't be imported.
+func findPackage(pattern string) (goPackage, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "list", "-f", "{{.Name}}\t{{.ImportPath}}\t{{.Dir}}", pattern)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return goPackage{}, fmt.Errorf("go list %v: %w\n%s", pattern, err, stderr.Bytes())
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 1 {
+		return goPackage{}, fmt.Errorf("%v matches %d packages, expected one", pattern, len(lines))
+	}
+	fields := strings.SplitN(lines[0], "\t", 3)
+	if len(fields) != 3 {
+		return goPackage{}, fmt.Errorf("unexpected go list output: %q", lines[0])
+	}
+	name, importPath, dir := fields[0], fields[1], fields[2]
+	if name == "main" {
+		return goPackage{}, fmt.Errorf("%v is a main package, which can't be imported: "+
+			"move the function returning the application's fx.Option to another package", importPath)
+	}
+	return goPackage{ImportPath: importPath, Dir: dir}, nil
+}
+
+type programParams struct {
+	ImportPath string // import path of the package
+	Func       string // name of the function returning the fx.Option
+	Graph      string // where to write the graph: a file, "-", or nothing
+}
+
+var _programTmpl = template.Must(template.New("main.go").Parse(`// Code generated by fxdump. DO NOT EDIT.
+
+package main
+
+import (
+	"os"
+
+	"go.uber.org/fx/fxdump"
+	target {{ printf "%q" .ImportPath }}
+)
+
+func main() {
+	r := fxdump.Inspect(target.{{ .Func }}())
+	r.WriteTo(os.Stdout)
+{{- if eq .Graph "-" }}
+	os.Stdout.WriteString("\n" + r.Graph)
+{{- else if .Graph }}
+	if err := os.WriteFile({{ printf "%q" .Graph }}, []byte(r.Graph), 0o644); err != nil {
+		os.Stderr.WriteString(err.Error() + "\n")
+		os.Exit(1)
+	}
+{{- end }}
+	if r.Err != nil {
+		os.Exit(1)
+	}
+}
+`))
+
+// generate returns the source of the program that inspects the application.
+func generate(p programParams) ([]byte, error) {
+	if !isIdent(p.Func) {
+		return nil, fmt.Errorf("invalid function name %q", p.Func)
+	}
+	var buf bytes.Buffer
+	if err := _programTmpl.Execute(&buf, p); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+func isIdent(s string) bool {
+	for i, r := range s {
+		switch {
+		case r == '_', 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+		case i > 0 && '0' <= r && r <= '9':
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Graph", func(t *testing.T) {
+		t.Parallel()
+
+		src, err := generate(programParams{
+			ImportPath: "example.com/app",
+			Func:       "Options",
+			Graph:      "/tmp/app.dot",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, string(src), `target "example.com/app"`)
+		assert.Contains(t, string(src), "fxdump.Inspect(target.Options())")
+		assert.Contains(t, string(src), `os.WriteFile("/tmp/app.dot"`)
+	})
+
+	t.Run("InvalidFunc", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := generate(programParams{
+			ImportPath: "example.com/app",
+			Func:       "Options() // ",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid function name")
+	})
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a program with go run")
+	}
+	t.Parallel()
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+
+		before, err := filepath.Glob("testdata/app/*")
+		require.NoError(t, err)
+
+		graph := filepath.Join(t.TempDir(), "app.dot")
+		var stdout, stderr bytes.Buffer
+		err = run([]string{"-graph", graph, "./testdata/app"}, &stdout, &stderr)
+		require.NoError(t, err, stderr.String())
+
+		after, err := filepath.Glob("testdata/app/*")
+		require.NoError(t, err)
+		assert.Equal(t, before, after, "must not write to the package")
+
+		out := stdout.String()
+		assert.Contains(t, out, "OK\n")
+		assert.Contains(t, out, "greeter")
+		assert.Contains(t, out, "app.Greeting <= go.uber.org/fx/cmd/fxdump/testdata/app.NewGreeting()")
+
+		dot, err := os.ReadFile(graph)
+		require.NoError(t, err)
+		assert.Contains(t, string(dot), "digraph")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+		err := run([]string{"-func", "Broken", "./testdata/app"}, &stdout, &stderr)
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 1, exitErr.ExitCode())
+		assert.Contains(t, stdout.String(), "INVALID")
+		assert.Contains(t, stdout.String(), "Missing types:\n\tapp.Greeting")
+	})
+
+	t.Run("MainPackage", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+		err := run([]string{"./testdata/server"}, &stdout, &stderr)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "testdata/server is a main package, which can't be imported")
+	})
+
+	t.Run("NoPackage", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+		err := run(nil, &stdout, &stderr)
+		require.Error(t, err)
+		assert.Contains(t, stderr.String(), "usage: fxdump")
+	})
+}
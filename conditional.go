@@ -0,0 +1,195 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// ProvideIf registers constructors like [Provide],
+// but only if the given predicate reports true.
+// This allows declaring subsystems gated by configuration or feature flags.
+//
+//	fx.ProvideIf(
+//		func(cfg Config) bool { return cfg.Cache.Enabled },
+//		NewRedisCache,
+//	)
+//
+// The predicate is a function that returns a bool,
+// and optionally an error as its last result.
+// Its dependencies are resolved from the application like an [Invoke]
+// before any other Invoke runs, and after all constructors
+// and decorators registered with other options have been.
+//
+// If the predicate reports false, the constructors are not registered
+// and the types they provide are absent from the application,
+// so they can be consumed as optional dependencies.
+//
+//	type Params struct {
+//		fx.In
+//
+//		Cache *redis.Cache `optional:"true"`
+//	}
+//
+// If the predicate fails, the application fails to build with its error.
+//
+// Predicates run in the order their options were given,
+// so a predicate may depend on types provided by an earlier ProvideIf
+// whose predicate passed, but not by later ones.
+// Conditional constructors do not override defaults
+// provided with [ProvideDefault].
+func ProvideIf(predicate interface{}, constructors ...interface{}) Option {
+	return provideIfOption{
+		Predicate: predicate,
+		Targets:   constructors,
+		Stack:     fxreflect.CallerStack(1, 0),
+	}
+}
+
+type provideIfOption struct {
+	Predicate interface{}
+	Targets   []interface{}
+	Stack     fxreflect.Stack
+}
+
+func (o provideIfOption) apply(m *module) {
+	if err := validatePredicate(o.Predicate); err != nil {
+		m.app.err = fmt.Errorf("fx.ProvideIf(%v) from:\n%+vFailed: %w",
+			fxreflect.FuncName(o.Predicate), o.Stack, err)
+		return
+	}
+
+	var private bool
+	targets := make([]interface{}, 0, len(o.Targets))
+	for _, target := range o.Targets {
+		if _, ok := target.(privateOption); ok {
+			private = true
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	c := conditional{Predicate: o.Predicate, Stack: o.Stack}
+	for _, target := range targets {
+		c.Provides = append(c.Provides, provide{
+			Target:  target,
+			Stack:   o.Stack,
+			Private: private,
+		})
+	}
+	m.conditionals = append(m.conditionals, c)
+}
+
+func (o provideIfOption) String() string {
+	items := make([]string, 0, len(o.Targets)+1)
+	items = append(items, fxreflect.FuncName(o.Predicate))
+	for _, c := range o.Targets {
+		items = append(items, fxreflect.FuncName(c))
+	}
+	return fmt.Sprintf("fx.ProvideIf(%s)", strings.Join(items, ", "))
+}
+
+func validatePredicate(predicate interface{}) error {
+	t := reflect.TypeOf(predicate)
+	if t == nil || t.Kind() != reflect.Func {
+		return fmt.Errorf("must provide predicate function, got %v (%T)", predicate, predicate)
+	}
+	switch {
+	case t.NumOut() == 1 && t.Out(0).Kind() == reflect.Bool:
+	case t.NumOut() == 2 && t.Out(0).Kind() == reflect.Bool && t.Out(1) == _typeOfError:
+	default:
+		return errors.New("predicate must return a bool and optionally an error")
+	}
+	return nil
+}
+
+// conditional is a set of constructors registered with ProvideIf.
+type conditional struct {
+	Predicate interface{}
+	Provides  []provide
+	Stack     fxreflect.Stack
+}
+
+// check runs the predicate of c in the given scope.
+func (c conditional) check(s scope) (bool, error) {
+	fn := reflect.ValueOf(c.Predicate)
+	ft := fn.Type()
+
+	ins := make([]reflect.Type, ft.NumIn())
+	for i := range ins {
+		ins[i] = ft.In(i)
+	}
+
+	// Invoke only reports errors, so capture the bool result separately.
+	var ok bool
+	invoke := reflect.MakeFunc(
+		reflect.FuncOf(ins, []reflect.Type{_typeOfError}, ft.IsVariadic()),
+		func(args []reflect.Value) []reflect.Value {
+			var results []reflect.Value
+			if ft.IsVariadic() {
+				results = fn.CallSlice(args)
+			} else {
+				results = fn.Call(args)
+			}
+			ok = results[0].Bool()
+			if len(results) == 2 {
+				return results[1:]
+			}
+			return []reflect.Value{_nilError}
+		},
+	)
+	if err := s.Invoke(invoke.Interface()); err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+// provideConditionals runs the predicates of the ProvideIf options of m
+// and its submodules, and registers the constructors of those that pass.
+func (m *module) provideConditionals() {
+	for _, c := range m.conditionals {
+		if m.app.err != nil {
+			return
+		}
+
+		ok, err := c.check(m.scope)
+		if err != nil {
+			m.app.err = fmt.Errorf("fx.ProvideIf(%v) from:\n%+vFailed: %w",
+				fxreflect.FuncName(c.Predicate), c.Stack, err)
+			return
+		}
+		if !ok {
+			continue
+		}
+		for _, p := range c.Provides {
+			m.provide(p)
+		}
+	}
+
+	for _, mod := range m.modules {
+		mod.provideConditionals()
+	}
+}
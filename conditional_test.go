@@ -0,0 +1,203 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestProvideIf(t *testing.T) {
+	t.Parallel()
+
+	type config struct{ Enabled bool }
+	type cache struct{}
+	type params struct {
+		fx.In
+
+		Cache *cache `optional:"true"`
+	}
+
+	newCache := func() *cache { return &cache{} }
+	enabled := func(cfg config) bool { return cfg.Enabled }
+
+	t.Run("predicate passes", func(t *testing.T) {
+		t.Parallel()
+
+		var p params
+		app := fxtest.New(t,
+			fx.Supply(config{Enabled: true}),
+			fx.ProvideIf(enabled, newCache),
+			fx.Populate(&p),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.NotNil(t, p.Cache)
+	})
+
+	t.Run("predicate fails", func(t *testing.T) {
+		t.Parallel()
+
+		var p params
+		app := fxtest.New(t,
+			fx.Supply(config{Enabled: false}),
+			fx.ProvideIf(enabled, newCache),
+			fx.Populate(&p),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Nil(t, p.Cache)
+	})
+
+	t.Run("absent type is missing", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Supply(config{Enabled: false}),
+			fx.ProvideIf(enabled, newCache),
+			fx.Invoke(func(*cache) {}),
+		)
+		assert.ErrorContains(t, app.Err(), "missing type: *fx_test.cache")
+	})
+
+	t.Run("predicate sees decorated values", func(t *testing.T) {
+		t.Parallel()
+
+		var p params
+		app := fxtest.New(t,
+			fx.Supply(config{Enabled: false}),
+			fx.Decorate(func(cfg config) config {
+				cfg.Enabled = true
+				return cfg
+			}),
+			fx.ProvideIf(enabled, newCache),
+			fx.Populate(&p),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.NotNil(t, p.Cache)
+	})
+
+	t.Run("decorators apply to conditional types", func(t *testing.T) {
+		t.Parallel()
+
+		var got string
+		app := fxtest.New(t,
+			fx.ProvideIf(func() bool { return true }, func() string { return "hello" }),
+			fx.Decorate(func(s string) string { return s + " world" }),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Equal(t, "hello world", got)
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		var p params
+		app := fxtest.New(t,
+			fx.Module("cache",
+				fx.Supply(config{Enabled: true}, fx.Private),
+				fx.ProvideIf(enabled, newCache),
+			),
+			fx.Populate(&p),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.NotNil(t, p.Cache)
+	})
+
+	t.Run("private", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Module("cache",
+				fx.ProvideIf(func() bool { return true }, newCache, fx.Private),
+			),
+			fx.Invoke(func(*cache) {}),
+		)
+		assert.ErrorContains(t, app.Err(), "missing type: *fx_test.cache")
+	})
+
+	t.Run("predicate error", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.ProvideIf(func() (bool, error) {
+				return false, errors.New("great sadness")
+			}, newCache),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.ProvideIf(")
+		assert.Contains(t, err.Error(), "great sadness")
+	})
+
+	t.Run("predicate missing dependency", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.ProvideIf(enabled, newCache),
+		)
+		assert.ErrorContains(t, app.Err(), "missing type: fx_test.config")
+	})
+
+	t.Run("invalid predicate", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc string
+			give interface{}
+			want string
+		}{
+			{
+				desc: "not a function",
+				give: true,
+				want: "must provide predicate function, got true (bool)",
+			},
+			{
+				desc: "no bool",
+				give: func() int { return 0 },
+				want: "predicate must return a bool and optionally an error",
+			},
+			{
+				desc: "extra result",
+				give: func() (bool, int) { return true, 0 },
+				want: "predicate must return a bool and optionally an error",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := fx.New(fx.NopLogger, fx.ProvideIf(tt.give, newCache))
+				assert.ErrorContains(t, app.Err(), tt.want)
+			})
+		}
+	})
+}
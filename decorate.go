@@ -21,6 +21,7 @@
 package fx
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -175,6 +176,30 @@ import (
 //	    }),
 //	  ),
 //	)
+//
+// # Decorator order
+//
+// A type may be decorated at most once per module.
+// When a type is decorated by a module and by modules containing it,
+// the decorators always apply from the outermost module inwards:
+// each decorator receives the value produced by the decorators of its
+// parent modules, regardless of the order in which options were specified.
+// Decorators in sibling modules do not compose;
+// each applies only within its own module.
+//
+// Fx logs the final chain of decorators for each type decorated more than
+// once as an [fxevent.DecoratorChain] event.
+//
+// # Preserving original values
+//
+// Pass [DecoratePreserveOriginal] to fx.Decorate to keep the values it
+// decorates available in their original form under a name, within the
+// same scope as the decorator.
+//
+//	fx.Decorate(
+//	  func(h http.Handler) http.Handler { return withAuth(h) },
+//	  fx.DecoratePreserveOriginal("undecorated"),
+//	)
 func Decorate(decorators ...interface{}) Option {
 	return decorateOption{
 		Targets: decorators,
@@ -188,14 +213,58 @@ type decorateOption struct {
 }
 
 func (o decorateOption) apply(mod *module) {
+	var preserveAs string
+
+	targets := make([]interface{}, 0, len(o.Targets))
 	for _, target := range o.Targets {
+		if p, ok := target.(preserveOriginalOption); ok {
+			preserveAs = p.name
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	for _, target := range targets {
 		mod.decorators = append(mod.decorators, decorator{
-			Target: target,
-			Stack:  o.Stack,
+			Target:     target,
+			Stack:      o.Stack,
+			PreserveAs: preserveAs,
 		})
 	}
 }
 
+// DecoratePreserveOriginal is an option that can be passed as an argument to
+// [Decorate] to keep the values produced by its decorators available in
+// their undecorated form under the given name.
+//
+// For example, the following makes both the decorated *Server and the
+// original *Server available to the module:
+//
+//	fx.Module("server",
+//		fx.Decorate(
+//			func(s *Server) *Server { return s.WithMiddleware(auth) },
+//			fx.DecoratePreserveOriginal("raw"),
+//		),
+//		fx.Invoke(fx.Annotate(
+//			func(s *Server, raw *Server) { ... },
+//			fx.ParamTags(``, `name:"raw"`),
+//		)),
+//	)
+//
+// The original values are available in the same scope as the decorated
+// values. Decorators used with DecoratePreserveOriginal must not be
+// annotated with fx.Annotate or return fx.Out structs, and each type they
+// return must also be one of their unnamed parameters.
+func DecoratePreserveOriginal(name string) interface{} {
+	return preserveOriginalOption{name: name}
+}
+
+type preserveOriginalOption struct{ name string }
+
+func (o preserveOriginalOption) String() string {
+	return fmt.Sprintf("fx.DecoratePreserveOriginal(%q)", o.name)
+}
+
 func (o decorateOption) String() string {
 	items := make([]string, len(o.Targets))
 	for i, f := range o.Targets {
@@ -204,6 +273,81 @@ func (o decorateOption) String() string {
 	return fmt.Sprintf("fx.Decorate(%s)", strings.Join(items, ", "))
 }
 
+// DecoratorFunc is the set of functions that decorate values of type T
+// for [DecorateT].
+type DecoratorFunc[T any] interface {
+	~func(T) T | ~func(T) (T, error)
+}
+
+// DecorateT is a type-safe form of [Decorate] for a single type.
+// It decorates the values of type T with the given function,
+// whose signature is checked by the compiler instead of when the
+// application is built:
+//
+//	fx.DecorateT[*zap.Logger](func(log *zap.Logger) *zap.Logger {
+//	  return log.Named("myapp")
+//	})
+//
+// The decorator may also return an error.
+// Use [DecorateTWith] for decorators that need other dependencies.
+func DecorateT[T any, F DecoratorFunc[T]](decorator F) Option {
+	return decorateTOption{
+		Func:   "fx.DecorateT",
+		Target: decorator,
+		Type:   reflect.TypeOf((*T)(nil)).Elem(),
+		Stack:  fxreflect.CallerStack(1, 0),
+	}
+}
+
+// DecoratorWithFunc is the set of functions that decorate values of type T
+// with a dependency of type D for [DecorateTWith].
+type DecoratorWithFunc[T, D any] interface {
+	~func(T, D) T | ~func(T, D) (T, error)
+}
+
+// DecorateTWith is similar to [DecorateT],
+// but the decorator also receives a dependency of type D.
+// Use a parameter object to depend on more than one value:
+//
+//	type LoggerParams struct {
+//	  fx.In
+//
+//	  Config  *Config
+//	  Metrics *Metrics `optional:"true"`
+//	}
+//
+//	fx.DecorateTWith[*zap.Logger, LoggerParams](
+//	  func(log *zap.Logger, p LoggerParams) *zap.Logger {
+//	    return log.Named(p.Config.Name)
+//	  },
+//	)
+func DecorateTWith[T, D any, F DecoratorWithFunc[T, D]](decorator F) Option {
+	return decorateTOption{
+		Func:   "fx.DecorateTWith",
+		Target: decorator,
+		Type:   reflect.TypeOf((*T)(nil)).Elem(),
+		Stack:  fxreflect.CallerStack(1, 0),
+	}
+}
+
+type decorateTOption struct {
+	Func   string // name of the function that built the option
+	Target interface{}
+	Type   reflect.Type
+	Stack  fxreflect.Stack
+}
+
+func (o decorateTOption) apply(mod *module) {
+	mod.decorators = append(mod.decorators, decorator{
+		Target: o.Target,
+		Stack:  o.Stack,
+	})
+}
+
+func (o decorateTOption) String() string {
+	return fmt.Sprintf("%v[%v](%v)", o.Func, o.Type, fxreflect.FuncName(o.Target))
+}
+
 // decorator is a single decorator used in Fx.
 type decorator struct {
 	// Decorator provided to Fx.
@@ -215,6 +359,107 @@ type decorator struct {
 	// Whether this decorator was specified via fx.Replace
 	IsReplace   bool
 	ReplaceType reflect.Type // set only if IsReplace
+
+	// If set, the undecorated values are provided under this name.
+	PreserveAs string
+
+	// If set, the name of the decorator reported in events
+	// instead of the name of Target.
+	Name string
+}
+
+// preserveOriginal wraps decorator to record the values it decorates and
+// returns a constructor that provides them under the given name.
+// The constructor depends on the decorated values
+// to ensure that the decorator has run.
+func preserveOriginal(decorator interface{}, name string) (wrapped, ctor interface{}, err error) {
+	if _, ok := decorator.(annotated); ok {
+		return nil, nil, errors.New("fx.DecoratePreserveOriginal cannot be used with fx.Annotate")
+	}
+
+	ft := reflect.TypeOf(decorator)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return nil, nil, fmt.Errorf("decorator must be a function, got %v (%T)", decorator, decorator)
+	}
+
+	type source struct {
+		param int // index of the parameter
+		field int // index of the field in an fx.In parameter, or -1
+	}
+
+	var (
+		resultTypes []reflect.Type
+		sources     []source
+	)
+	for i := 0; i < ft.NumOut(); i++ {
+		rt := ft.Out(i)
+		if rt == _typeOfError && i == ft.NumOut()-1 {
+			break
+		}
+		if isOut(rt) {
+			return nil, nil, errors.New("fx.DecoratePreserveOriginal cannot be used " +
+				"with decorators that return fx.Out structs")
+		}
+
+		src, ok := source{param: -1, field: -1}, false
+		for j := 0; j < ft.NumIn() && !ok; j++ {
+			pt := ft.In(j)
+			if pt == rt {
+				src, ok = source{param: j, field: -1}, true
+				continue
+			}
+			if !isIn(pt) {
+				continue
+			}
+			for k := 1; k < pt.NumField(); k++ {
+				f := pt.Field(k)
+				if f.Type == rt && f.Tag.Get(_nameTag) == "" && f.Tag.Get(_groupTag) == "" {
+					src, ok = source{param: j, field: k}, true
+					break
+				}
+			}
+		}
+		if !ok {
+			return nil, nil, fmt.Errorf("fx.DecoratePreserveOriginal: "+
+				"decorator returns %v but does not accept it as a parameter", rt)
+		}
+		resultTypes = append(resultTypes, rt)
+		sources = append(sources, src)
+	}
+
+	originals := make([]reflect.Value, len(resultTypes))
+	origFn := reflect.ValueOf(decorator)
+	wrapped = reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		for i, src := range sources {
+			v := args[src.param]
+			if src.field >= 0 {
+				v = v.Field(src.field)
+			}
+			originals[i] = v
+		}
+		return origFn.Call(args)
+	}).Interface()
+
+	fields := []reflect.StructField{_outAnnotationField}
+	for i, rt := range resultTypes {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("Field%d", i),
+			Type: rt,
+			Tag:  reflect.StructTag(fmt.Sprintf(`name:%q`, name)),
+		})
+	}
+	outType := reflect.StructOf(fields)
+	ctor = reflect.MakeFunc(
+		reflect.FuncOf(resultTypes, []reflect.Type{outType}, false),
+		func([]reflect.Value) []reflect.Value {
+			out := reflect.New(outType).Elem()
+			for i, v := range originals {
+				out.Field(i + 1).Set(v)
+			}
+			return []reflect.Value{out}
+		},
+	).Interface()
+	return wrapped, ctor, nil
 }
 
 func runDecorator(c container, d decorator, opts ...dig.DecorateOption) (err error) {
@@ -228,10 +473,15 @@ func runDecorator(c container, d decorator, opts ...dig.DecorateOption) (err err
 	switch decorator := decorator.(type) {
 	case annotated:
 		if dcor, derr := decorator.Build(); derr == nil {
-			err = c.Decorate(dcor, opts...)
+			if dcor, _, err = flattenStructs(c, dcor); err == nil {
+				err = c.Decorate(dcor, opts...)
+			}
 		}
 	default:
-		err = c.Decorate(decorator, opts...)
+		var dcor interface{}
+		if dcor, _, err = flattenStructs(c, decorator); err == nil {
+			err = c.Decorate(dcor, opts...)
+		}
 	}
 	return
 }
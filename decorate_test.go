@@ -22,13 +22,16 @@ package fx_test
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
 	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxlog"
 )
 
 func TestDecorateSuccess(t *testing.T) {
@@ -345,6 +348,86 @@ func TestDecorateSuccess(t *testing.T) {
 		)
 		defer app.RequireStart().RequireStop()
 	})
+
+	t.Run("preserve original", func(t *testing.T) {
+		t.Parallel()
+
+		type Logger struct {
+			Name string
+		}
+		type Config struct {
+			Suffix string
+		}
+
+		type params struct {
+			fx.In
+
+			Logger *Logger
+			Raw    *Logger `name:"raw"`
+		}
+
+		var got params
+		app := fxtest.New(t,
+			fx.Provide(
+				func() *Logger { return &Logger{Name: "log"} },
+				func() *Config { return &Config{Suffix: ".decorated"} },
+			),
+			fx.Module("child",
+				fx.Decorate(
+					func(l *Logger, cfg *Config) *Logger {
+						return &Logger{Name: l.Name + cfg.Suffix}
+					},
+					fx.DecoratePreserveOriginal("raw"),
+				),
+				fx.Invoke(func(p params) { got = p }),
+			),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "log.decorated", got.Logger.Name)
+		assert.Equal(t, "log", got.Raw.Name)
+	})
+
+	t.Run("preserve original with fx.In", func(t *testing.T) {
+		t.Parallel()
+
+		type A struct{ Value int }
+		type B struct{ Value int }
+
+		type decorateParams struct {
+			fx.In
+
+			A *A
+			B *B
+		}
+
+		type params struct {
+			fx.In
+
+			A    *A
+			RawA *A `name:"orig"`
+			B    *B
+			RawB *B `name:"orig"`
+		}
+
+		var got params
+		app := fxtest.New(t,
+			fx.Supply(&A{1}, &B{2}),
+			fx.Decorate(
+				func(p decorateParams) (*A, *B, error) {
+					return &A{p.A.Value * 10}, &B{p.B.Value * 10}, nil
+				},
+				fx.DecoratePreserveOriginal("orig"),
+			),
+			fx.Invoke(func(p params) { got = p }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, 10, got.A.Value)
+		assert.Equal(t, 1, got.RawA.Value)
+		assert.Equal(t, 20, got.B.Value)
+		assert.Equal(t, 2, got.RawB.Value)
+	})
 }
 
 func TestDecorateFailure(t *testing.T) {
@@ -485,4 +568,225 @@ func TestDecorateFailure(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "missing dependencies")
 	})
+	t.Run("preserve original requires decorated type as parameter", func(t *testing.T) {
+		type Logger struct {
+			Name string
+		}
+
+		app := NewForTest(t,
+			fx.Provide(func() string { return "name" }),
+			fx.Decorate(
+				func(name string) *Logger { return &Logger{Name: name} },
+				fx.DecoratePreserveOriginal("raw"),
+			),
+		)
+
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "decorator returns *fx_test.Logger but does not accept it as a parameter")
+	})
+
+	t.Run("preserve original with fx.Annotate", func(t *testing.T) {
+		app := NewForTest(t,
+			fx.Provide(func() string { return "name" }),
+			fx.Decorate(
+				fx.Annotate(func(name string) string { return name }),
+				fx.DecoratePreserveOriginal("raw"),
+			),
+		)
+
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.DecoratePreserveOriginal cannot be used with fx.Annotate")
+	})
+}
+
+func TestDecoratorChain(t *testing.T) {
+	t.Parallel()
+
+	type Logger struct {
+		Name string
+	}
+
+	named := func(suffix string) func(*Logger) *Logger {
+		return func(l *Logger) *Logger {
+			return &Logger{Name: l.Name + "." + suffix}
+		}
+	}
+
+	t.Run("nested modules", func(t *testing.T) {
+		t.Parallel()
+
+		var inner, sibling string
+		spy := new(fxlog.Spy)
+		app := fxtest.New(t,
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.Supply(&Logger{Name: "root"}),
+			fx.Decorate(named("app")),
+			fx.Module("outer",
+				fx.Decorate(named("outer")),
+				fx.Module("inner",
+					fx.Decorate(named("inner")),
+					fx.Invoke(func(l *Logger) { inner = l.Name }),
+				),
+			),
+			fx.Module("sibling",
+				fx.Invoke(func(l *Logger) { sibling = l.Name }),
+			),
+		)
+		defer app.RequireStart().RequireStop()
+
+		// Decorators apply from the outermost module inwards.
+		assert.Equal(t, "root.app.outer.inner", inner)
+		assert.Equal(t, "root.app", sibling)
+
+		events := spy.Events().SelectByTypeName("DecoratorChain")
+		require.Len(t, events, 2)
+
+		outer := events[0].(*fxevent.DecoratorChain)
+		assert.Equal(t, "*fx_test.Logger", outer.TypeName)
+		assert.Equal(t, "outer", outer.ModuleName)
+		require.Len(t, outer.DecoratorNames, 2)
+		assert.Contains(t, outer.DecoratorNames[0], "TestDecoratorChain")
+
+		chain := events[1].(*fxevent.DecoratorChain)
+		assert.Equal(t, "inner", chain.ModuleName)
+		require.Len(t, chain.DecoratorNames, 3)
+		assert.Equal(t, outer.DecoratorNames, chain.DecoratorNames[:2])
+	})
+
+	t.Run("single decorator", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		app := fxtest.New(t,
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.Supply(&Logger{Name: "root"}),
+			fx.Module("a", fx.Decorate(named("a"))),
+			fx.Module("b", fx.Decorate(named("b"))),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Empty(t, spy.Events().SelectByTypeName("DecoratorChain"))
+	})
+}
+
+func TestDecorateT(t *testing.T) {
+	t.Parallel()
+
+	type Logger struct{ Name string }
+	type Config struct{ Prefix string }
+
+	provideLogger := fx.Provide(func() *Logger { return &Logger{Name: "logger"} })
+
+	t.Run("decorator", func(t *testing.T) {
+		t.Parallel()
+
+		var got *Logger
+		app := fxtest.New(t,
+			provideLogger,
+			fx.DecorateT[*Logger](func(l *Logger) *Logger {
+				return &Logger{Name: "decorated " + l.Name}
+			}),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "decorated logger", got.Name)
+	})
+
+	t.Run("decorator error", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			provideLogger,
+			fx.DecorateT[*Logger](func(*Logger) (*Logger, error) {
+				return nil, errors.New("great sadness")
+			}),
+			fx.Invoke(func(*Logger) {}),
+		)
+		assert.ErrorContains(t, app.Err(), "great sadness")
+	})
+
+	t.Run("named decorator type", func(t *testing.T) {
+		t.Parallel()
+
+		type loggerDecorator func(*Logger) *Logger
+
+		var got *Logger
+		app := fxtest.New(t,
+			provideLogger,
+			fx.DecorateT[*Logger](loggerDecorator(func(l *Logger) *Logger {
+				return &Logger{Name: "named " + l.Name}
+			})),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "named logger", got.Name)
+	})
+
+	t.Run("with dependency", func(t *testing.T) {
+		t.Parallel()
+
+		var got *Logger
+		app := fxtest.New(t,
+			provideLogger,
+			fx.Supply(&Config{Prefix: "app."}),
+			fx.DecorateTWith[*Logger, *Config](func(l *Logger, c *Config) *Logger {
+				return &Logger{Name: c.Prefix + l.Name}
+			}),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "app.logger", got.Name)
+	})
+
+	t.Run("with parameter object", func(t *testing.T) {
+		t.Parallel()
+
+		type Params struct {
+			fx.In
+
+			Config *Config
+			Suffix string `name:"suffix" optional:"true"`
+		}
+
+		var got *Logger
+		app := fxtest.New(t,
+			provideLogger,
+			fx.Supply(&Config{Prefix: "app."}),
+			fx.DecorateTWith[*Logger, Params](func(l *Logger, p Params) (*Logger, error) {
+				return &Logger{Name: p.Config.Prefix + l.Name + p.Suffix}, nil
+			}),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "app.logger", got.Name)
+	})
+
+	t.Run("missing dependency", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			provideLogger,
+			fx.DecorateTWith[*Logger, *Config](func(l *Logger, _ *Config) *Logger { return l }),
+			fx.Invoke(func(*Logger) {}),
+		)
+		assert.ErrorContains(t, app.Err(), "missing type: *fx_test.Config")
+	})
+
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+
+		opt := fx.DecorateT[*Logger](func(l *Logger) *Logger { return l })
+		assert.True(t, strings.HasPrefix(fmt.Sprint(opt), "fx.DecorateT[*fx_test.Logger]("), "%v", opt)
+
+		opt = fx.DecorateTWith[*Logger, *Config](func(l *Logger, _ *Config) *Logger { return l })
+		assert.True(t, strings.HasPrefix(fmt.Sprint(opt), "fx.DecorateTWith[*fx_test.Logger]("), "%v", opt)
+	})
 }
@@ -0,0 +1,68 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import "go.uber.org/fx/internal/fxreflect"
+
+// ProvideDefault registers constructors like [Provide],
+// except that they're ignored if any other constructor in the application
+// provides the same types.
+// This allows library modules to ship sensible defaults
+// that applications can override by providing their own values,
+// without using [Decorate] or [Replace]:
+//
+//	var Module = fx.Module("client",
+//		fx.ProvideDefault(NewJSONSerializer),
+//		fx.Provide(New),
+//	)
+//
+//	fx.New(
+//		client.Module,
+//		fx.Provide(NewProtoSerializer), // used instead of NewJSONSerializer
+//	)
+//
+// Constructors are kept or ignored as a whole:
+// if another constructor provides any of the types of a default constructor,
+// none of its types are provided.
+// Defaults provided with [Private] are only overridden
+// by other private constructors of the same module.
+// If the same type has several defaults and no other constructor,
+// they conflict as if they were provided with Provide.
+func ProvideDefault(constructors ...interface{}) Option {
+	return provideOption{
+		Targets: constructors,
+		Stack:   fxreflect.CallerStack(1, 0),
+		Default: true,
+	}
+}
+
+// Default supplies values like [Supply],
+// except that they're ignored if any other constructor in the application
+// provides the same types.
+// See [ProvideDefault] for details.
+//
+//	fx.Default(Config{Timeout: 5 * time.Second})
+func Default(values ...interface{}) Option {
+	o := Supply(values...).(supplyOption)
+	o.Stack = fxreflect.CallerStack(1, 0)
+	o.Default = true
+	return o
+}
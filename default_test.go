@@ -0,0 +1,123 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestDefault(t *testing.T) {
+	t.Parallel()
+
+	type Config struct{ Timeout time.Duration }
+	type Serializer struct{ Name string }
+
+	library := fx.Module("library",
+		fx.Default(Config{Timeout: time.Second}),
+		fx.ProvideDefault(func() *Serializer { return &Serializer{Name: "json"} }),
+	)
+
+	t.Run("Used", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			cfg Config
+			s   *Serializer
+		)
+		fxtest.New(t, library, fx.Populate(&cfg, &s))
+
+		assert.Equal(t, time.Second, cfg.Timeout)
+		assert.Equal(t, "json", s.Name)
+	})
+
+	t.Run("Overridden", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			cfg Config
+			s   *Serializer
+		)
+		fxtest.New(t,
+			library,
+			fx.Supply(Config{Timeout: time.Minute}),
+			fx.Provide(func() *Serializer { return &Serializer{Name: "proto"} }),
+			fx.Populate(&cfg, &s),
+		)
+
+		assert.Equal(t, time.Minute, cfg.Timeout)
+		assert.Equal(t, "proto", s.Name)
+	})
+
+	t.Run("OverriddenBySiblingModule", func(t *testing.T) {
+		t.Parallel()
+
+		var s *Serializer
+		fxtest.New(t,
+			fx.Module("app",
+				fx.Provide(func() *Serializer { return &Serializer{Name: "proto"} }),
+			),
+			library,
+			fx.Populate(&s),
+		)
+
+		assert.Equal(t, "proto", s.Name)
+	})
+
+	t.Run("IgnoredAsWhole", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			cfg Config
+			s   *Serializer
+		)
+		fxtest.New(t,
+			fx.ProvideDefault(func() (Config, *Serializer) {
+				return Config{Timeout: time.Second}, &Serializer{Name: "json"}
+			}),
+			fx.Provide(func() *Serializer { return &Serializer{Name: "proto"} }),
+			fx.Supply(Config{Timeout: time.Minute}),
+			fx.Populate(&cfg, &s),
+		)
+
+		assert.Equal(t, time.Minute, cfg.Timeout)
+		assert.Equal(t, "proto", s.Name)
+	})
+
+	t.Run("ConflictingDefaults", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Default(Config{Timeout: time.Second}),
+			fx.Default(Config{Timeout: time.Minute}),
+			fx.Invoke(func(Config) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already provided")
+	})
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"go.uber.org/dig"
+)
+
+// RecordDemandPaths is an Option that explains why each constructor runs.
+// With it, the [fxevent.Run] event reported for each constructor
+// includes its DemandPath: the function passed to [Invoke] that needed it,
+// followed by each constructor along the way.
+// For example,
+//
+//	main.run() -> main.NewServer() -> main.NewDB()
+//
+// indicates that NewDB ran because NewServer needed its result,
+// and NewServer ran because run did.
+//
+// Fx determines demand paths from the inputs and outputs
+// of the functions provided to the application,
+// following the parameters of each function in order,
+// the way they are resolved.
+// This makes constructors slower to run,
+// so use it to debug applications, not in production.
+//
+// RecordDemandPaths may only be passed to the top-level App.
+func RecordDemandPaths() Option {
+	return recordDemandPathsOption{}
+}
+
+type recordDemandPathsOption struct{}
+
+func (recordDemandPathsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.RecordDemandPaths Option should be passed to top-level App, " +
+			"not to fx.Module")
+		return
+	}
+	m.app.demand = &demandGraph{providers: make(map[string][]*demandNode)}
+}
+
+func (recordDemandPathsOption) String() string {
+	return "fx.RecordDemandPaths()"
+}
+
+// demandGraph tracks which functions produce and consume each type
+// to explain why constructors run.
+type demandGraph struct {
+	mu sync.Mutex
+
+	// Constructors producing each type, by type key, in the order provided.
+	providers map[string][]*demandNode
+
+	// Function being invoked, if any.
+	root *demandNode
+}
+
+// demandNode is a function provided or invoked in the application.
+type demandNode struct {
+	name   string
+	inputs []string // type keys
+}
+
+// Provided records a constructor with the given inputs and outputs,
+// and returns its node.
+func (g *demandGraph) Provided(name string, info dig.ProvideInfo) *demandNode {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	n := &demandNode{name: name, inputs: inputKeys(info.Inputs)}
+	for _, o := range info.Outputs {
+		key := o.String()
+		g.providers[key] = append(g.providers[key], n)
+	}
+	return n
+}
+
+// Invoking records that the given function is being invoked.
+// Call the returned function once the invocation finishes.
+func (g *demandGraph) Invoking(name string, i invoke) (done func()) {
+	inputs := &inputsContainer{}
+	if err := runInvoke(inputs, i); err != nil {
+		// Invoke will fail with the same error.
+		return func() {}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.root = &demandNode{name: name, inputs: inputs.keys}
+	return func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.root = nil
+	}
+}
+
+// Path returns the chain of functions that caused target to run,
+// starting with the function being invoked.
+// It returns nil if target didn't run for an invocation,
+// for example, if it was needed to build the fxevent.Logger.
+func (g *demandGraph) Path(target *demandNode) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.root == nil || target == nil {
+		return nil
+	}
+	visited := make(map[*demandNode]struct{})
+	if path := g.search(g.root, target, visited); path != nil {
+		return append([]string{g.root.name}, path...)
+	}
+	return nil
+}
+
+// search returns the first path from the inputs of n to target,
+// trying inputs in the order dig resolves them.
+func (g *demandGraph) search(n, target *demandNode, visited map[*demandNode]struct{}) []string {
+	for _, key := range n.inputs {
+		for _, p := range g.providers[key] {
+			if p == target {
+				return []string{p.name}
+			}
+			if _, ok := visited[p]; ok {
+				continue
+			}
+			visited[p] = struct{}{}
+			if path := g.search(p, target, visited); path != nil {
+				return append([]string{p.name}, path...)
+			}
+		}
+	}
+	return nil
+}
+
+// inputKeys returns the keys of the types the given inputs consume,
+// matching the keys of the outputs that produce them.
+func inputKeys(inputs []*dig.Input) []string {
+	keys := make([]string, len(inputs))
+	for i, in := range inputs {
+		key := strings.NewReplacer("[optional]", "", "optional, ", "").Replace(in.String())
+		if strings.Contains(key, "group = ") {
+			// Value groups are consumed as slices of the values produced.
+			key = strings.TrimPrefix(key, "[]")
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// demandRoot is the result of the constructors
+// that inputsContainer builds from invoked functions.
+type demandRoot struct{}
+
+// inputsContainer is a container that records the inputs
+// of the function passed to Invoke instead of calling it.
+type inputsContainer struct {
+	container
+
+	keys []string
+}
+
+func (c *inputsContainer) Invoke(fn interface{}, _ ...dig.InvokeOption) error {
+	ft := reflect.TypeOf(fn)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return fmt.Errorf("can't invoke non-function %v (type %v)", fn, ft)
+	}
+
+	in := make([]reflect.Type, ft.NumIn())
+	for i := range in {
+		in[i] = ft.In(i)
+	}
+	rootType := reflect.TypeOf(demandRoot{})
+	stub := reflect.MakeFunc(
+		reflect.FuncOf(in, []reflect.Type{rootType}, ft.IsVariadic()),
+		func([]reflect.Value) []reflect.Value {
+			return []reflect.Value{reflect.Zero(rootType)}
+		},
+	)
+
+	var info dig.ProvideInfo
+	if err := dig.New(dig.DryRun(true)).Provide(stub.Interface(), dig.FillProvideInfo(&info)); err != nil {
+		return err
+	}
+	c.keys = inputKeys(info.Inputs)
+	return nil
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+// demandPaths returns the demand paths of the constructors that ran,
+// by constructor name, with names shortened to the names of the functions.
+func demandPaths(spy *fxlog.Spy) map[string][]string {
+	short := func(name string) string {
+		name = strings.TrimPrefix(name, "fx.Annotate(")
+		name = strings.TrimPrefix(name, "go.uber.org/fx_test.")
+		name, _, _ = strings.Cut(name, "()")
+		return name
+	}
+
+	paths := make(map[string][]string)
+	for _, e := range spy.Events().SelectByTypeName("Run") {
+		run := e.(*fxevent.Run)
+		var path []string
+		for _, name := range run.DemandPath {
+			path = append(path, short(name))
+		}
+		paths[short(run.Name)] = path
+	}
+	return paths
+}
+
+type (
+	demandDB     struct{}
+	demandCache  struct{}
+	demandServer struct{}
+)
+
+func demandNewDB() *demandDB                                { return &demandDB{} }
+func demandNewCache(*demandDB) *demandCache                 { return &demandCache{} }
+func demandNewServer(*demandCache, *demandDB) *demandServer { return &demandServer{} }
+func demandNewA(*demandDB) string                           { return "a" }
+func demandNewB() string                                    { return "b" }
+func demandRun(*demandServer)                               {}
+func demandRunNames([]string)                               {}
+func demandNewEmptyCache() *demandCache                     { return &demandCache{} }
+
+type demandParams struct {
+	In
+
+	DB    *demandDB    `name:"primary"`
+	Cache *demandCache `optional:"true"`
+}
+
+func demandRunParams(demandParams) {}
+
+func TestRecordDemandPaths(t *testing.T) {
+	t.Parallel()
+
+	t.Run("chain", func(t *testing.T) {
+		t.Parallel()
+
+		app, spy := NewSpied(
+			RecordDemandPaths(),
+			Provide(
+				demandNewDB,
+				demandNewCache,
+				demandNewServer,
+			),
+			Invoke(demandRun),
+		)
+		require.NoError(t, app.Err())
+
+		assert.Equal(t, map[string][]string{
+			"demandNewDB":     {"demandRun", "demandNewServer", "demandNewCache", "demandNewDB"},
+			"demandNewCache":  {"demandRun", "demandNewServer", "demandNewCache"},
+			"demandNewServer": {"demandRun", "demandNewServer"},
+		}, demandPaths(spy))
+	})
+
+	t.Run("named and optional", func(t *testing.T) {
+		t.Parallel()
+
+		app, spy := NewSpied(
+			RecordDemandPaths(),
+			Provide(
+				Annotate(demandNewDB, ResultTags(`name:"primary"`)),
+				demandNewEmptyCache,
+			),
+			Invoke(demandRunParams),
+		)
+		require.NoError(t, app.Err())
+
+		assert.Equal(t, map[string][]string{
+			"demandNewDB":         {"demandRunParams", "demandNewDB"},
+			"demandNewEmptyCache": {"demandRunParams", "demandNewEmptyCache"},
+		}, demandPaths(spy))
+	})
+
+	t.Run("value group", func(t *testing.T) {
+		t.Parallel()
+
+		app, spy := NewSpied(
+			RecordDemandPaths(),
+			Provide(
+				demandNewDB,
+				Annotate(demandNewA, ResultTags(`group:"names"`)),
+				Annotate(demandNewB, ResultTags(`group:"names"`)),
+			),
+			Module("server",
+				Invoke(Annotate(demandRunNames, ParamTags(`group:"names"`))),
+			),
+		)
+		require.NoError(t, app.Err())
+
+		assert.Equal(t, map[string][]string{
+			"demandNewDB": {"demandRunNames", "demandNewA", "demandNewDB"},
+			"demandNewA":  {"demandRunNames", "demandNewA"},
+			"demandNewB":  {"demandRunNames", "demandNewB"},
+		}, demandPaths(spy))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		app, spy := NewSpied(
+			Provide(demandNewDB),
+			Invoke(func(*demandDB) {}),
+		)
+		require.NoError(t, app.Err())
+
+		runs := spy.Events().SelectByTypeName("Run")
+		require.Len(t, runs, 1)
+		assert.Nil(t, runs[0].(*fxevent.Run).DemandPath)
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Module("foo", RecordDemandPaths()))
+		assert.ErrorContains(t, app.Err(),
+			"fx.RecordDemandPaths Option should be passed to top-level App, not to fx.Module")
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+
+	"go.uber.org/dig"
+)
+
+// DigContainerOptions passes options to the dig container
+// that underlies the application.
+// It's an escape hatch for advanced users who need dig features
+// that Fx doesn't expose yet.
+//
+//	fx.New(
+//		fx.DigContainerOptions(dig.RecoverFromPanics()),
+//		...
+//	)
+//
+// The options are applied after the ones Fx sets,
+// so they may change behavior Fx relies on.
+// This option is not covered by Fx's compatibility guarantees:
+// it may change or be removed once Fx wraps the features it's used for.
+func DigContainerOptions(opts ...dig.Option) Option {
+	return digContainerOptionsOption(opts)
+}
+
+type digContainerOptionsOption []dig.Option
+
+func (o digContainerOptionsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.DigContainerOptions Option should be passed to top-level App, " +
+			"not to fx.Module")
+		return
+	}
+	m.app.digOptions = append(m.app.digOptions, o...)
+}
+
+func (o digContainerOptionsOption) String() string {
+	return fmt.Sprintf("fx.DigContainerOptions(%v)", []dig.Option(o))
+}
+
+// DigScopeOptions passes options to the dig scope
+// that underlies the [Module] it's passed to.
+// Like [DigContainerOptions], it's an escape hatch
+// that is not covered by Fx's compatibility guarantees.
+//
+//	fx.Module("server",
+//		fx.DigScopeOptions(...),
+//		...
+//	)
+func DigScopeOptions(opts ...dig.ScopeOption) Option {
+	return digScopeOptionsOption(opts)
+}
+
+type digScopeOptionsOption []dig.ScopeOption
+
+func (o digScopeOptionsOption) apply(m *module) {
+	if m.parent == nil {
+		m.app.err = fmt.Errorf("fx.DigScopeOptions Option should be passed to fx.Module, " +
+			"not to top-level App")
+		return
+	}
+	m.scopeOptions = append(m.scopeOptions, o...)
+}
+
+func (o digScopeOptionsOption) String() string {
+	return fmt.Sprintf("fx.DigScopeOptions(%v)", []dig.ScopeOption(o))
+}
+
+// DigContainer returns the dig container that underlies the application.
+// It's an escape hatch for advanced users who need dig features
+// that Fx doesn't expose yet, such as inspecting the graph.
+//
+// Values provided or invoked directly on the container
+// bypass Fx: they are not logged, linted, or traced.
+// This method is not covered by Fx's compatibility guarantees:
+// it may change or be removed once Fx wraps the features it's used for.
+func (app *App) DigContainer() *dig.Container {
+	return app.container
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/fx"
+)
+
+func TestDigContainerOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("applied", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.DigContainerOptions(dig.RecoverFromPanics()),
+			fx.Provide(func() int { panic("great sadness") }),
+			fx.Invoke(func(int) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		var pe dig.PanicError
+		assert.ErrorAs(t, err, &pe)
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Module("child", fx.DigContainerOptions()),
+		)
+		assert.ErrorContains(t, app.Err(),
+			"fx.DigContainerOptions Option should be passed to top-level App, not to fx.Module")
+	})
+}
+
+func TestDigScopeOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Module("child",
+				fx.DigScopeOptions(),
+				fx.Provide(func() int { return 42 }),
+			),
+			fx.Invoke(func(int) {}),
+		)
+		assert.NoError(t, app.Err())
+	})
+
+	t.Run("top-level", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(fx.NopLogger, fx.DigScopeOptions())
+		assert.ErrorContains(t, app.Err(),
+			"fx.DigScopeOptions Option should be passed to fx.Module, not to top-level App")
+	})
+}
+
+func TestAppDigContainer(t *testing.T) {
+	t.Parallel()
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Provide(func() int { return 42 }),
+	)
+	require.NoError(t, app.Err())
+
+	var got int
+	require.NoError(t, app.DigContainer().Invoke(func(i int) { got = i }))
+	assert.Equal(t, 42, got)
+}
@@ -195,6 +195,22 @@
 //		// ...
 //	}
 //
+// Optional fields of basic types may specify a fallback with the default
+// tag instead of checking for the zero value in the constructor.
+// The default is used only if no value is provided for the field;
+// provided zero values are kept.
+//
+//	type ServerParams struct {
+//		fx.In
+//
+//		Port    int           `name:"port" optional:"true" default:"8080"`
+//		Timeout time.Duration `optional:"true" default:"5s"`
+//	}
+//
+// Defaults are supported for strings, booleans, numbers, [time.Duration],
+// and types that implement [encoding.TextUnmarshaler].
+// The default tag may also be passed to [ParamTags].
+//
 // # Value Groups
 //
 // To make it easier to produce and consume many values of the same type, Fx
@@ -335,6 +351,28 @@
 //		// Consumed as []Handler in ServerParams.
 //	}
 //
+// # Keyed value groups
+//
+// Values may be contributed to a value group with a key by adding a key tag
+// to the result field. Parameter structs can then consume the group as a
+// map[string]T keyed by it, instead of a []T.
+//
+//	type CodecResult struct {
+//		fx.Out
+//
+//		Codec Codec `group:"codecs" key:"json"`
+//	}
+//
+//	type RegistryParams struct {
+//		fx.In
+//
+//		Codecs map[string]Codec `group:"codecs"`
+//	}
+//
+// Only values contributed with a key are included in the map, and they are
+// not included in []T. Fx fails if two values of a group share a key.
+// With fx.Annotate, use fx.ResultTags(`group:"codecs" key:"json"`).
+//
 // # Unexported fields
 //
 // By default, a type that embeds fx.In may not have any unexported fields. The
@@ -357,4 +395,48 @@
 //		Logger *zap.Logger
 //		mu     sync.Mutex
 //	}
+//
+// # Inline Structs
+//
+// Parameter structs that share a set of dependencies may declare them once on
+// a plain struct, and embed it with the inline struct tag. Fields of inline
+// structs are injected as if they were declared on the parameter struct
+// itself, including their struct tags.
+//
+//	type Common struct {
+//		Logger *zap.Logger
+//		Config *Config `optional:"true"`
+//	}
+//
+//	type HandlerParams struct {
+//		fx.In
+//		Common `inline:"true"`
+//
+//		Mux *http.ServeMux
+//	}
+//
+// Inline structs may themselves embed other inline structs. It is an error
+// for a field of an inline struct to have the same name as another field of
+// the parameter struct.
+//
+// Without the inline tag, an embedded struct is a dependency on a value of
+// that struct type, like any other field.
+//
+// Result structs may be composed the same way. The fields of fx.Out structs,
+// and of structs with the inline tag, embedded in a result struct are
+// provided as if they were declared on the result struct itself.
+//
+//	type ObservabilityResult struct {
+//		fx.Out
+//
+//		Metrics *Metrics
+//		Tracer  *Tracer
+//	}
+//
+//	type ServerResult struct {
+//		fx.Out
+//		ObservabilityResult
+//
+//		Server *http.Server
+//	}
 package fx // import "go.uber.org/fx"
@@ -0,0 +1,435 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/dig"
+)
+
+// DuplicatePolicy specifies how Fx resolves two constructors that provide
+// the same type. See [OnDuplicate].
+type DuplicatePolicy int
+
+const (
+	// FailOnDuplicate fails the application when a type is provided more
+	// than once. This is the default behavior.
+	FailOnDuplicate DuplicatePolicy = iota
+
+	// KeepFirst keeps the constructor that was provided first and ignores
+	// the later ones.
+	KeepFirst
+
+	// KeepLast keeps the constructor that was provided last and ignores
+	// the earlier ones.
+	KeepLast
+)
+
+func (p DuplicatePolicy) String() string {
+	switch p {
+	case FailOnDuplicate:
+		return "fx.FailOnDuplicate"
+	case KeepFirst:
+		return "fx.KeepFirst"
+	case KeepLast:
+		return "fx.KeepLast"
+	default:
+		return fmt.Sprintf("DuplicatePolicy(%d)", int(p))
+	}
+}
+
+// OnDuplicate specifies how constructors that provide a type that is
+// already provided are handled. By default, Fx fails the application.
+//
+// This is intended for applications that aggregate options from many
+// sources, like plugins, where accidental duplicates are expected:
+//
+//	fx.New(
+//		fx.OnDuplicate(fx.KeepLast),
+//		plugins.Options(),
+//	)
+//
+// The policy applies to constructors provided by the module that
+// OnDuplicate is used in and to its submodules, unless they specify their
+// own policy. When the same type is provided twice, the policy of the
+// module providing it second decides which constructor is kept.
+//
+// If types are given, the policy only applies to those types. Types are
+// specified as pointers to them, the same way as with [As].
+//
+//	fx.OnDuplicate(fx.KeepFirst, new(*Config), new(io.Writer))
+//
+// Constructors are kept or ignored as a whole: if a constructor that
+// provides several types is ignored, none of its types are provided.
+func OnDuplicate(policy DuplicatePolicy, types ...interface{}) Option {
+	o := onDuplicateOption{Policy: policy}
+	for _, t := range types {
+		rt := reflect.TypeOf(t)
+		if rt == nil || rt.Kind() != reflect.Ptr {
+			o.Err = fmt.Errorf("fx.OnDuplicate: expected a pointer to a type, got %v", rt)
+			break
+		}
+		o.Types = append(o.Types, rt.Elem())
+	}
+	return o
+}
+
+type onDuplicateOption struct {
+	Policy DuplicatePolicy
+	Types  []reflect.Type
+	Err    error
+}
+
+func (o onDuplicateOption) apply(m *module) {
+	if o.Err != nil {
+		m.app.err = o.Err
+		return
+	}
+	m.duplicates = append(m.duplicates, o)
+	m.app.hasDuplicatePolicy = true
+}
+
+func (o onDuplicateOption) String() string {
+	items := make([]string, 0, len(o.Types)+1)
+	items = append(items, o.Policy.String())
+	for _, t := range o.Types {
+		items = append(items, fmt.Sprintf("new(%v)", t))
+	}
+	return fmt.Sprintf("fx.OnDuplicate(%s)", strings.Join(items, ", "))
+}
+
+// appliesTo reports whether the policy applies to the given output of a
+// constructor, as reported by dig.
+func (o onDuplicateOption) appliesTo(output string) bool {
+	if len(o.Types) == 0 {
+		return true
+	}
+	for _, t := range o.Types {
+		// Named values are reported as `T[name = "..."]`.
+		if output == t.String() || strings.HasPrefix(output, t.String()+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// duplicatePolicy returns the policy that applies to the given output of
+// a constructor provided by this module.
+func (m *module) duplicatePolicy(output string) DuplicatePolicy {
+	for mod := m; mod != nil; mod = mod.parent {
+		for i := len(mod.duplicates) - 1; i >= 0; i-- {
+			if o := mod.duplicates[i]; o.appliesTo(output) {
+				return o.Policy
+			}
+		}
+	}
+	return FailOnDuplicate
+}
+
+// providedOutput is a constructor provided by a module in the application,
+// along with the keys of the values it provides.
+type providedOutput struct {
+	mod     *module
+	idx     int // index into mod.provides
+	keys    []string
+	ignored bool
+	built   bool // already provided to the container, so it can't be ignored
+}
+
+func (po *providedOutput) isDefault() bool {
+	return po.mod.provides[po.idx].IsDefault
+}
+
+// resolveDuplicates removes constructors from the module tree that are
+// ignored per the OnDuplicate policies in effect,
+// and defaults for types provided by other constructors.
+//
+// built are modules whose constructors were already provided to the
+// container, e.g. the root module when the application is extended.
+// Their constructors are taken into account but always kept.
+//
+// This must be called before provideAll.
+func (m *module) resolveDuplicates(built ...*module) {
+	var all []*providedOutput
+	for _, mod := range built {
+		if !mod.collectOutputs(&all) {
+			return
+		}
+	}
+	for _, po := range all {
+		po.built = true
+	}
+	if !m.collectOutputs(&all) {
+		// Let provideAll report the error.
+		return
+	}
+
+	// Defaults are ignored if any other constructor provides their types.
+	overridden := make(map[string]struct{})
+	for _, po := range all {
+		if !po.isDefault() {
+			for _, key := range po.keys {
+				overridden[key] = struct{}{}
+			}
+		}
+	}
+	for _, po := range all {
+		if po.built || !po.isDefault() {
+			continue
+		}
+		for _, key := range po.keys {
+			if _, ok := overridden[key]; ok {
+				po.ignored = true
+				break
+			}
+		}
+	}
+
+	seen := make(map[string]*providedOutput)
+	for _, po := range all {
+		if po.ignored {
+			continue
+		}
+
+		var shadowed []*providedOutput
+		for _, key := range po.keys {
+			prev, ok := seen[key]
+			if !ok || prev.ignored || po.built {
+				continue
+			}
+
+			switch po.mod.duplicatePolicy(key[strings.IndexByte(key, ' ')+1:]) {
+			case KeepFirst:
+				po.ignored = true
+			case KeepLast:
+				if !prev.built {
+					// Otherwise, let the container report the duplicate.
+					shadowed = append(shadowed, prev)
+				}
+			}
+		}
+
+		if po.ignored {
+			continue
+		}
+		for _, prev := range shadowed {
+			prev.ignored = true
+		}
+		for _, key := range po.keys {
+			seen[key] = po
+		}
+	}
+
+	kept := make(map[*module][]provide)
+	for _, po := range all {
+		if po.built {
+			continue
+		}
+		if _, ok := kept[po.mod]; !ok {
+			kept[po.mod] = []provide{}
+		}
+		if !po.ignored {
+			kept[po.mod] = append(kept[po.mod], po.mod.provides[po.idx])
+		}
+	}
+	for mod, provides := range kept {
+		mod.provides = provides
+	}
+}
+
+// collectOutputs appends the constructors provided by this module and its
+// submodules to outputs, in the order provideAll provides them. It reports
+// false if the outputs of any of the constructors could not be determined.
+func (m *module) collectOutputs(outputs *[]*providedOutput) bool {
+	for i, p := range m.provides {
+		var info dig.ProvideInfo
+		c := dig.New(dig.DryRun(true))
+		if err := runProvide(c, p, dig.FillProvideInfo(&info)); err != nil {
+			return false
+		}
+
+		// Private values only conflict with other values in the same
+		// module.
+		scope := ""
+		if p.Private {
+			scope = fmt.Sprintf("%p", m)
+		}
+
+		keys := make([]string, 0, len(info.Outputs))
+		for _, o := range info.Outputs {
+			out := o.String()
+			if strings.Contains(out, "group = ") {
+				// Value groups accept any number of values.
+				continue
+			}
+			keys = append(keys, scope+" "+out)
+		}
+		*outputs = append(*outputs, &providedOutput{
+			mod:  m,
+			idx:  i,
+			keys: keys,
+		})
+	}
+
+	for _, mod := range m.modules {
+		if !mod.collectOutputs(outputs) {
+			return false
+		}
+	}
+	return true
+}
+
+// ProviderInfo describes a constructor provided to an application.
+type ProviderInfo struct {
+	// ConstructorName is the name of the constructor,
+	// or fx.Supply(T) for supplied values.
+	ConstructorName string
+
+	// ModuleName is the name of the module that provided the constructor,
+	// or empty for the root module.
+	ModuleName string
+
+	// ModuleTrace records where the constructor was provided,
+	// and the modules it was provided through, innermost first.
+	ModuleTrace []string
+
+	// Private is true if the constructor was provided with [Private].
+	Private bool
+
+	// Meta is the metadata attached to the constructor with [Meta], if any.
+	Meta map[string]string
+}
+
+func (pi ProviderInfo) describe() string {
+	if pi.ModuleName == "" {
+		return pi.ConstructorName
+	}
+	return fmt.Sprintf("%v in module %q", pi.ConstructorName, pi.ModuleName)
+}
+
+// DuplicateProvideError is the error an application fails with
+// when a constructor provides a type that another constructor provides.
+// Use errors.As to inspect it:
+//
+//	var dup *fx.DuplicateProvideError
+//	if errors.As(app.Err(), &dup) {
+//		// ...
+//	}
+type DuplicateProvideError struct {
+	// Type is the type provided more than once, along with its name,
+	// if any, as in `*sql.DB[name = "ro"]`.
+	Type string
+
+	// First is the constructor that provided the type first,
+	// and Second the one that provided it again.
+	First, Second ProviderInfo
+
+	err error // reported by dig
+}
+
+func (e *DuplicateProvideError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v is already provided by %v from:\n", e.Type, e.First.describe())
+	for _, frame := range e.First.ModuleTrace {
+		fmt.Fprintf(&sb, "\t%v\n", frame)
+	}
+	fmt.Fprintf(&sb, "and cannot be provided again by %v from:\n", e.Second.describe())
+	for _, frame := range e.Second.ModuleTrace {
+		fmt.Fprintf(&sb, "\t%v\n", frame)
+	}
+	sb.WriteString("To provide several values of the same type, " +
+		"give them different names with the name tag, " +
+		"collect them in a value group with the group tag, " +
+		"or keep them to their modules with fx.Private; " +
+		"see fx.ResultTags and fx.Tag")
+	return sb.String()
+}
+
+// Unwrap returns the error reported by the container.
+func (e *DuplicateProvideError) Unwrap() error {
+	return e.err
+}
+
+// providerInfo describes the constructor p provided by this module
+// under the given name.
+func (m *module) providerInfo(name string, p provide) ProviderInfo {
+	return ProviderInfo{
+		ConstructorName: name,
+		ModuleName:      m.name,
+		ModuleTrace:     append([]string{p.Stack[0].String()}, m.trace...),
+		Private:         p.Private,
+		Meta:            p.Meta,
+	}
+}
+
+// recordProvider records the outputs of a constructor that was provided
+// successfully, so that later constructors that provide them again
+// are reported with a DuplicateProvideError.
+func (m *module) recordProvider(name string, p provide, info dig.ProvideInfo) {
+	for _, o := range info.Outputs {
+		out := o.String()
+		if strings.Contains(out, "group = ") {
+			continue
+		}
+		if _, ok := m.app.providers[out]; ok {
+			continue
+		}
+		if m.app.providers == nil {
+			m.app.providers = make(map[string]ProviderInfo)
+		}
+		m.app.providers[out] = m.providerInfo(name, p)
+	}
+}
+
+// duplicateError returns a DuplicateProvideError wrapping err
+// if the constructor p failed to be provided
+// because one of its outputs is already provided.
+// It returns nil otherwise.
+func (m *module) duplicateError(name string, p provide, err error) error {
+	if len(m.app.providers) == 0 {
+		return nil
+	}
+
+	// Determine the outputs of the constructor on its own:
+	// if it can't be provided to an empty container,
+	// it failed for other reasons.
+	var info dig.ProvideInfo
+	c := dig.New(dig.DryRun(true))
+	if runProvide(c, p, dig.FillProvideInfo(&info)) != nil {
+		return nil
+	}
+
+	for _, o := range info.Outputs {
+		out := o.String()
+		if first, ok := m.app.providers[out]; ok {
+			return &DuplicateProvideError{
+				Type:   out,
+				First:  first,
+				Second: m.providerInfo(name, p),
+				err:    err,
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestOnDuplicate(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ Value string }
+	type B struct{ Value string }
+
+	newA := func(v string) func() *A {
+		return func() *A { return &A{Value: v} }
+	}
+
+	t.Run("fails by default", func(t *testing.T) {
+		t.Parallel()
+
+		err := fx.New(
+			fx.Provide(newA("first")),
+			fx.Provide(newA("second")),
+			fx.Invoke(func(*A) {}),
+		).Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already provided")
+	})
+
+	t.Run("keep first", func(t *testing.T) {
+		t.Parallel()
+
+		var a *A
+		app := fxtest.New(t,
+			fx.OnDuplicate(fx.KeepFirst),
+			fx.Provide(newA("first")),
+			fx.Module("plugin", fx.Provide(newA("second"))),
+			fx.Populate(&a),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Equal(t, "first", a.Value)
+	})
+
+	t.Run("keep last", func(t *testing.T) {
+		t.Parallel()
+
+		var a *A
+		app := fxtest.New(t,
+			fx.OnDuplicate(fx.KeepLast),
+			fx.Provide(newA("first")),
+			fx.Module("plugin", fx.Provide(newA("second"))),
+			fx.Populate(&a),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Equal(t, "second", a.Value)
+	})
+
+	t.Run("scoped to module", func(t *testing.T) {
+		t.Parallel()
+
+		var a *A
+		app := fxtest.New(t,
+			fx.Provide(newA("first")),
+			fx.Module("plugin",
+				fx.OnDuplicate(fx.KeepLast),
+				fx.Provide(newA("second")),
+			),
+			fx.Populate(&a),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Equal(t, "second", a.Value)
+
+		err := fx.New(
+			fx.Provide(newA("first")),
+			fx.Module("plugin", fx.OnDuplicate(fx.KeepLast)),
+			fx.Module("other", fx.Provide(newA("second"))),
+			fx.Invoke(func(*A) {}),
+		).Err()
+		require.Error(t, err, "policy must not apply outside the module")
+		assert.Contains(t, err.Error(), "already provided")
+	})
+
+	t.Run("scoped to type", func(t *testing.T) {
+		t.Parallel()
+
+		var a *A
+		app := fxtest.New(t,
+			fx.OnDuplicate(fx.KeepFirst, new(*A)),
+			fx.Provide(newA("first"), newA("second")),
+			fx.Populate(&a),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Equal(t, "first", a.Value)
+
+		err := fx.New(
+			fx.OnDuplicate(fx.KeepFirst, new(*A)),
+			fx.Provide(
+				func() *B { return &B{} },
+				func() *B { return &B{} },
+			),
+			fx.Invoke(func(*B) {}),
+		).Err()
+		require.Error(t, err, "policy must not apply to other types")
+		assert.Contains(t, err.Error(), "already provided")
+	})
+
+	t.Run("named values", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			fx.In
+
+			A *A `name:"a"`
+		}
+
+		var got *A
+		app := fxtest.New(t,
+			fx.OnDuplicate(fx.KeepLast, new(*A)),
+			fx.Provide(
+				fx.Annotate(newA("first"), fx.ResultTags(`name:"a"`)),
+				fx.Annotate(newA("second"), fx.ResultTags(`name:"a"`)),
+				newA("unnamed"),
+			),
+			fx.Invoke(func(p params, a *A) {
+				got = p.A
+				assert.Equal(t, "unnamed", a.Value)
+			}),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Equal(t, "second", got.Value)
+	})
+
+	t.Run("value groups are not duplicates", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			fx.In
+
+			As []*A `group:"as"`
+		}
+
+		var got []*A
+		app := fxtest.New(t,
+			fx.OnDuplicate(fx.KeepLast),
+			fx.Provide(
+				fx.Annotate(newA("first"), fx.ResultTags(`group:"as"`)),
+				fx.Annotate(newA("second"), fx.ResultTags(`group:"as"`)),
+			),
+			fx.Invoke(func(p params) { got = p.As }),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Len(t, got, 2)
+	})
+
+	t.Run("supply", func(t *testing.T) {
+		t.Parallel()
+
+		var a *A
+		app := fxtest.New(t,
+			fx.OnDuplicate(fx.KeepLast),
+			fx.Supply(&A{Value: "first"}),
+			fx.Supply(&A{Value: "second"}),
+			fx.Populate(&a),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Equal(t, "second", a.Value)
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		t.Parallel()
+
+		err := fx.New(fx.OnDuplicate(fx.KeepFirst, A{})).Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected a pointer to a type")
+	})
+}
+
+func TestDuplicateProvideError(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	newA := func() *A { return &A{} }
+
+	t.Run("across modules", func(t *testing.T) {
+		t.Parallel()
+
+		err := fx.New(
+			fx.NopLogger,
+			fx.Module("first", fx.Provide(newA)),
+			fx.Module("second", fx.Provide(fx.Annotate(newA, fx.ResultTags(`name:"a"`)), newA)),
+		).Err()
+		require.Error(t, err)
+
+		var dup *fx.DuplicateProvideError
+		require.True(t, errors.As(err, &dup), "expected a DuplicateProvideError, got %v", err)
+		assert.Equal(t, "*fx_test.A", dup.Type)
+		assert.Equal(t, "first", dup.First.ModuleName)
+		assert.Equal(t, "second", dup.Second.ModuleName)
+		assert.Contains(t, dup.First.ConstructorName, "TestDuplicateProvideError")
+		require.NotEmpty(t, dup.First.ModuleTrace)
+		assert.Contains(t, dup.First.ModuleTrace[0], "duplicate_test.go")
+		assert.Contains(t, dup.First.ModuleTrace[1], "(first)")
+		assert.Contains(t, dup.Second.ModuleTrace[1], "(second)")
+
+		msg := err.Error()
+		assert.Contains(t, msg, `*fx_test.A is already provided by`)
+		assert.Contains(t, msg, `in module "first" from:`)
+		assert.Contains(t, msg, `and cannot be provided again by`)
+		assert.Contains(t, msg, `in module "second" from:`)
+		assert.Contains(t, msg, "fx.Private")
+	})
+
+	t.Run("named", func(t *testing.T) {
+		t.Parallel()
+
+		named := fx.Annotate(newA, fx.ResultTags(`name:"a"`))
+		err := fx.New(fx.NopLogger, fx.Provide(named), fx.Provide(named)).Err()
+
+		var dup *fx.DuplicateProvideError
+		require.True(t, errors.As(err, &dup), "expected a DuplicateProvideError, got %v", err)
+		assert.Equal(t, `*fx_test.A[name = "a"]`, dup.Type)
+		assert.Empty(t, dup.First.ModuleName)
+	})
+
+	t.Run("supplied", func(t *testing.T) {
+		t.Parallel()
+
+		err := fx.New(fx.NopLogger, fx.Provide(newA), fx.Supply(&A{})).Err()
+
+		var dup *fx.DuplicateProvideError
+		require.True(t, errors.As(err, &dup), "expected a DuplicateProvideError, got %v", err)
+		assert.Equal(t, "fx.Supply(*fx_test.A)", dup.Second.ConstructorName)
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		t.Parallel()
+
+		err := fx.New(
+			fx.NopLogger,
+			fx.Provide(newA),
+			fx.Provide(func() (*A, *A) { return nil, nil }),
+		).Err()
+		require.Error(t, err)
+
+		var dup *fx.DuplicateProvideError
+		assert.False(t, errors.As(err, &dup), "constructors that are invalid on their own are not duplicates")
+	})
+}
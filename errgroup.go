@@ -0,0 +1,172 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrGroup runs goroutines for the lifetime of an application.
+// It is similar to errgroup.Group from golang.org/x/sync,
+// but bound to the application's lifecycle.
+//
+// Goroutines started with [ErrGroup.Go] receive a context that is canceled
+// when the application stops, or when another goroutine of the group fails.
+// When the application stops, it waits for them to return before running
+// OnStop hooks, within the application's stop timeout, and reports the first
+// error returned by them as a failure to stop.
+// Errors caused by the cancellation of the context are not reported.
+//
+//	fx.Invoke(func(lc fx.Lifecycle, g *fx.ErrGroup, c *Consumer) {
+//		lc.Append(fx.StartHook(func() {
+//			g.Go(c.Consume)
+//		}))
+//	})
+//
+// The ErrGroup is provided to all Fx applications.
+type ErrGroup struct {
+	shutdowner Shutdowner
+
+	mu              sync.Mutex
+	run             *errGroupRun
+	shutdownOnError bool
+	shutdownOpts    []ShutdownOption
+}
+
+// errGroupRun holds the goroutines of an ErrGroup
+// started during a single run of the application.
+type errGroupRun struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// Guarded by ErrGroup.mu.
+	running int   // goroutines that didn't return yet
+	err     error // first error returned by a goroutine
+}
+
+func newErrGroup(s Shutdowner) *ErrGroup {
+	return &ErrGroup{
+		shutdowner: s,
+		run:        newErrGroupRun(),
+	}
+}
+
+func newErrGroupRun() *errGroupRun {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &errGroupRun{ctx: ctx, cancel: cancel}
+}
+
+// Go runs f in a new goroutine.
+// The context passed to f is canceled when the application stops
+// or another goroutine of the group fails.
+func (g *ErrGroup) Go(f func(ctx context.Context) error) {
+	g.mu.Lock()
+	run := g.run
+	run.wg.Add(1)
+	run.running++
+	g.mu.Unlock()
+
+	go func() {
+		defer run.wg.Done()
+		defer func() {
+			g.mu.Lock()
+			run.running--
+			g.mu.Unlock()
+		}()
+		if err := f(run.ctx); err != nil {
+			g.fail(run, err)
+		}
+	}()
+}
+
+// ShutdownOnError causes the application to shut down
+// when a goroutine of the group fails,
+// as if by [Shutdowner.Shutdown] with the given options
+// and the [ShutdownError] of the goroutine.
+func (g *ErrGroup) ShutdownOnError(opts ...ShutdownOption) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.shutdownOnError = true
+	g.shutdownOpts = opts
+}
+
+func (g *ErrGroup) fail(run *errGroupRun, err error) {
+	if run.ctx.Err() != nil && errors.Is(err, context.Canceled) {
+		// The goroutine was asked to stop.
+		return
+	}
+
+	g.mu.Lock()
+	first := run.err == nil
+	if first {
+		run.err = err
+		run.cancel()
+	}
+	shutdown, opts := first && g.shutdownOnError, g.shutdownOpts
+	g.mu.Unlock()
+
+	if shutdown {
+		// Shutdown only fails if nothing is waiting for the application,
+		// in which case the error is still reported when it stops.
+		opts = append([]ShutdownOption{ShutdownError(err)}, opts...)
+		_ = g.shutdowner.Shutdown(opts...)
+	}
+}
+
+// stop cancels the goroutines of the group and waits for them to return.
+// It returns the first error returned by them.
+// Goroutines started afterwards are part of the next run of the application.
+//
+// ctx may have expired already, e.g. when rolling back a start that timed out,
+// so it only fails if goroutines are still running once it's done.
+func (g *ErrGroup) stop(ctx context.Context) error {
+	g.mu.Lock()
+	run := g.run
+	g.run = newErrGroupRun()
+	g.mu.Unlock()
+
+	run.cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run.wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		g.mu.Lock()
+		running := run.running
+		g.mu.Unlock()
+		if running > 0 {
+			return fmt.Errorf("%d fx.ErrGroup goroutines did not return: %w", running, ctx.Err())
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return run.err
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestErrGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CanceledOnStop", func(t *testing.T) {
+		t.Parallel()
+
+		returned := make(chan struct{})
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle, g *fx.ErrGroup) {
+				lc.Append(fx.StartHook(func() {
+					g.Go(func(ctx context.Context) error {
+						defer close(returned)
+						<-ctx.Done()
+						return ctx.Err()
+					})
+				}))
+			}),
+		)
+		app.RequireStart()
+		select {
+		case <-returned:
+			t.Fatal("goroutine returned before stop")
+		default:
+		}
+
+		require.NoError(t, app.Stop(context.Background()))
+		<-returned
+	})
+
+	t.Run("WaitsBeforeOnStop", func(t *testing.T) {
+		t.Parallel()
+
+		var stopped bool
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle, g *fx.ErrGroup) {
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error {
+						g.Go(func(ctx context.Context) error {
+							<-ctx.Done()
+							stopped = true
+							return nil
+						})
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						assert.True(t, stopped, "goroutine must return before OnStop")
+						return nil
+					},
+				})
+			}),
+		)
+		app.RequireStart().RequireStop()
+		assert.True(t, stopped)
+	})
+
+	t.Run("ErrorReportedOnStop", func(t *testing.T) {
+		t.Parallel()
+
+		errFail := errors.New("great sadness")
+		app := fxtest.New(t,
+			fx.Invoke(func(g *fx.ErrGroup) {
+				g.Go(func(context.Context) error { return errFail })
+			}),
+		)
+		app.RequireStart()
+		err := app.Stop(context.Background())
+		assert.ErrorIs(t, err, errFail)
+	})
+
+	t.Run("ErrorCancelsGroup", func(t *testing.T) {
+		t.Parallel()
+
+		errFail := errors.New("great sadness")
+		canceled := make(chan struct{})
+		app := fxtest.New(t,
+			fx.Invoke(func(g *fx.ErrGroup) {
+				g.Go(func(ctx context.Context) error {
+					<-ctx.Done()
+					close(canceled)
+					return ctx.Err()
+				})
+				g.Go(func(context.Context) error { return errFail })
+			}),
+		)
+		app.RequireStart()
+		<-canceled
+
+		err := app.Stop(context.Background())
+		assert.ErrorIs(t, err, errFail)
+		assert.NotErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("ShutdownOnError", func(t *testing.T) {
+		t.Parallel()
+
+		errFail := errors.New("great sadness")
+		app := fxtest.New(t,
+			fx.Invoke(func(g *fx.ErrGroup) {
+				g.ShutdownOnError(fx.ExitCode(3))
+				g.Go(func(context.Context) error { return errFail })
+			}),
+		)
+		app.RequireStart()
+
+		sig := <-app.Wait()
+		assert.Equal(t, 3, sig.ExitCode)
+		assert.ErrorIs(t, sig.Err, errFail)
+		assert.ErrorIs(t, app.Stop(context.Background()), errFail)
+	})
+
+	t.Run("StopTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		app := fxtest.New(t,
+			fx.Invoke(func(g *fx.ErrGroup) {
+				g.Go(func(context.Context) error {
+					<-release
+					return nil
+				})
+			}),
+		)
+		app.RequireStart()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := app.Stop(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("StartTimeoutWithoutGoroutines", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}))
+			}),
+		)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := app.Start(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NotContains(t, err.Error(), "fx.ErrGroup")
+	})
+
+	t.Run("Restart", func(t *testing.T) {
+		t.Parallel()
+
+		var g *fx.ErrGroup
+		app := fxtest.New(t, fx.Populate(&g))
+
+		for i := 0; i < 2; i++ {
+			app.RequireStart()
+			returned := make(chan struct{})
+			g.Go(func(ctx context.Context) error {
+				defer close(returned)
+				<-ctx.Done()
+				return nil
+			})
+			app.RequireStop()
+			<-returned
+		}
+	})
+}
@@ -0,0 +1,389 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fxcron runs jobs on a schedule
+// for as long as an Fx application runs.
+//
+// Jobs are registered by providing [Job] values
+// to the "cron" value group, for example with [AsJob].
+// [Module] starts running them when the application starts,
+// and waits for running jobs to finish when it stops.
+//
+//	fx.New(
+//		fxcron.Module(),
+//		fx.Provide(
+//			fxcron.AsJob(func(db *sql.DB) fxcron.Job {
+//				return fxcron.Job{
+//					Name:     "purge-sessions",
+//					Schedule: fxcron.Every(time.Hour),
+//					Run: func(ctx context.Context) error {
+//						_, err := db.ExecContext(ctx, "DELETE FROM sessions WHERE expired")
+//						return err
+//					},
+//				}
+//			}),
+//		),
+//	)
+//
+// Each run of a job is reported to the [fxevent.Logger] of the application,
+// if one was specified with [fx.WithLogger], as an [fxevent.CronJobExecuted]
+// event. Use [OnJobDone] to report runs to metrics.
+package fxcron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/fxclock"
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// Group is the name of the value group that [Module] reads jobs from.
+const Group = "cron"
+
+// Job is a function run on a schedule by a [Scheduler].
+type Job struct {
+	// Name identifies the job in events and [JobResult]s.
+	// It defaults to the name of the Run function.
+	Name string
+
+	// Schedule decides when the job runs. Required.
+	Schedule Schedule
+
+	// Run is the function to run. Required.
+	//
+	// Its context is canceled if the application stops
+	// and the job doesn't return before the stop timeout.
+	// Runs of the same job never overlap:
+	// the next run is scheduled after the previous one returns.
+	Run func(ctx context.Context) error
+}
+
+// Schedule decides when a [Job] runs.
+type Schedule interface {
+	// Next returns the time the job should run next,
+	// given the current time.
+	// It returns the zero time if the job should not run again.
+	Next(now time.Time) time.Time
+}
+
+// ScheduleFunc adapts a function into a [Schedule].
+type ScheduleFunc func(now time.Time) time.Time
+
+// Next calls the function.
+func (f ScheduleFunc) Next(now time.Time) time.Time {
+	return f(now)
+}
+
+// Every returns a schedule that runs a job at fixed intervals,
+// measured from the end of its previous run.
+// It panics if d is not positive.
+func Every(d time.Duration) Schedule {
+	if d <= 0 {
+		panic("fxcron: non-positive interval for Every")
+	}
+	return everySchedule(d)
+}
+
+type everySchedule time.Duration
+
+func (s everySchedule) Next(now time.Time) time.Time {
+	return now.Add(time.Duration(s))
+}
+
+// AsJob annotates a constructor that returns a [Job]
+// so that its result is added to the jobs run by [Module].
+//
+//	fx.Provide(fxcron.AsJob(newCleanupJob))
+func AsJob(f any) any {
+	return fx.Annotate(f, fx.ResultTags(`group:"`+Group+`"`))
+}
+
+// Module provides a *[Scheduler] configured with the given options
+// that runs the jobs of the "cron" value group,
+// started and stopped with the application.
+func Module(opts ...Option) fx.Option {
+	return fx.Module("fxcron",
+		fx.Provide(func(p moduleParams) (*Scheduler, error) {
+			// Options passed to Module take precedence over the logger
+			// of the application.
+			s, err := New(p.Jobs, append([]Option{Logger(p.Logger)}, opts...)...)
+			if err != nil {
+				return nil, err
+			}
+			p.Lifecycle.Append(fx.StartStopHook(s.Start, s.Stop))
+			return s, nil
+		}),
+		fx.Invoke(func(*Scheduler) {}),
+	)
+}
+
+type moduleParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Jobs      []Job          `group:"cron"`
+	Logger    fxevent.Logger `optional:"true"`
+}
+
+// Option configures a [Scheduler].
+type Option interface {
+	apply(*config)
+}
+
+type config struct {
+	clock     fx.Clock
+	logger    fxevent.Logger
+	onJobDone func(JobResult)
+}
+
+// Clock sets the source of time used to schedule jobs.
+// Tests can use a fake clock to run jobs deterministically.
+// It defaults to a clock based on the [time] package.
+func Clock(c fx.Clock) Option {
+	return clockOption{c}
+}
+
+type clockOption struct{ clock fx.Clock }
+
+func (o clockOption) apply(c *config) {
+	if o.clock != nil {
+		c.clock = o.clock
+	}
+}
+
+// Logger sets the logger that runs of jobs are reported to
+// as [fxevent.CronJobExecuted] events.
+// [Module] defaults to the [fxevent.Logger] of the application, if any.
+func Logger(l fxevent.Logger) Option {
+	return loggerOption{l}
+}
+
+type loggerOption struct{ logger fxevent.Logger }
+
+func (o loggerOption) apply(c *config) {
+	c.logger = o.logger
+}
+
+// JobResult describes a run of a job.
+type JobResult struct {
+	// Name of the job.
+	Name string
+
+	// ScheduledAt is the time the run was scheduled for.
+	ScheduledAt time.Time
+
+	// Runtime is how long the job ran for.
+	Runtime time.Duration
+
+	// Err is the error returned by the job, if any.
+	// Panics in jobs are recovered and reported as errors.
+	Err error
+}
+
+// OnJobDone registers a function called after each run of a job.
+// It's called from the goroutine that ran the job,
+// and is intended for reporting metrics.
+func OnJobDone(f func(JobResult)) Option {
+	return onJobDoneOption(f)
+}
+
+type onJobDoneOption func(JobResult)
+
+func (o onJobDoneOption) apply(c *config) {
+	c.onJobDone = o
+}
+
+type schedulerState int
+
+const (
+	schedulerIdle schedulerState = iota
+	schedulerRunning
+	schedulerStopped
+)
+
+// Scheduler runs jobs on their schedules.
+// Build one with [New], or use [Module].
+type Scheduler struct {
+	cfg  config
+	jobs []Job
+
+	mu    sync.Mutex
+	state schedulerState
+
+	// stopping is canceled when the scheduler stops scheduling runs.
+	// jobCtx is the context of jobs, canceled when they must stop.
+	stopping   context.Context
+	stopCancel context.CancelFunc
+	jobCtx     context.Context
+	jobCancel  context.CancelFunc
+	wg         sync.WaitGroup
+
+	logMu sync.Mutex // serializes events sent to cfg.logger
+}
+
+// New builds a scheduler for the given jobs.
+// It returns an error if a job has no schedule or function.
+// The scheduler runs jobs once it's started with [Scheduler.Start].
+func New(jobs []Job, opts ...Option) (*Scheduler, error) {
+	cfg := config{clock: fxclock.System}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+
+	jobs = append([]Job(nil), jobs...)
+	for i, job := range jobs {
+		if job.Run == nil {
+			return nil, fmt.Errorf("fxcron: job %d (%q) has no Run function", i, job.Name)
+		}
+		if job.Name == "" {
+			jobs[i].Name = fxreflect.FuncName(job.Run)
+		}
+		if job.Schedule == nil {
+			return nil, fmt.Errorf("fxcron: job %q has no Schedule", jobs[i].Name)
+		}
+	}
+
+	stopping, stopCancel := context.WithCancel(context.Background())
+	jobCtx, jobCancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		cfg:        cfg,
+		jobs:       jobs,
+		stopping:   stopping,
+		stopCancel: stopCancel,
+		jobCtx:     jobCtx,
+		jobCancel:  jobCancel,
+	}, nil
+}
+
+// Start starts scheduling the jobs.
+// A scheduler can only be started once.
+func (s *Scheduler) Start(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state != schedulerIdle {
+		return errors.New("fxcron: scheduler can only be started once")
+	}
+	s.state = schedulerRunning
+
+	s.wg.Add(len(s.jobs))
+	for _, job := range s.jobs {
+		go s.schedule(job)
+	}
+	return nil
+}
+
+// Stop stops scheduling runs, and waits for running jobs to finish.
+// If ctx expires first, Stop cancels the contexts of running jobs
+// and returns the error of ctx.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	wasRunning := s.state == schedulerRunning
+	s.state = schedulerStopped
+	s.mu.Unlock()
+
+	s.stopCancel()
+	defer s.jobCancel()
+	if !wasRunning {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.jobCancel()
+		return ctx.Err()
+	}
+}
+
+// schedule runs job whenever its schedule says to, until the scheduler
+// stops or the schedule ends.
+func (s *Scheduler) schedule(job Job) {
+	defer s.wg.Done()
+
+	for {
+		now := s.cfg.clock.Now()
+		next := job.Schedule.Next(now)
+		if next.IsZero() {
+			return
+		}
+
+		// Contexts of fake clocks may not propagate cancellation from
+		// their parent, so wait on both.
+		wait, cancel := s.cfg.clock.WithTimeout(s.stopping, next.Sub(now))
+		select {
+		case <-wait.Done():
+		case <-s.stopping.Done():
+		}
+		cancel()
+
+		if s.stopping.Err() != nil {
+			return
+		}
+		s.run(job, next)
+	}
+}
+
+func (s *Scheduler) run(job Job, scheduled time.Time) {
+	start := s.cfg.clock.Now()
+	err := s.call(job)
+	res := JobResult{
+		Name:        job.Name,
+		ScheduledAt: scheduled,
+		Runtime:     s.cfg.clock.Since(start),
+		Err:         err,
+	}
+
+	if s.cfg.logger != nil {
+		s.logMu.Lock()
+		s.cfg.logger.LogEvent(&fxevent.CronJobExecuted{
+			JobName:     res.Name,
+			ScheduledAt: res.ScheduledAt,
+			Runtime:     res.Runtime,
+			Err:         res.Err,
+		})
+		s.logMu.Unlock()
+	}
+	if s.cfg.onJobDone != nil {
+		s.cfg.onJobDone(res)
+	}
+}
+
+func (s *Scheduler) call(job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("fxcron: job %q panicked: %v", job.Name, r)
+		}
+	}()
+	return job.Run(s.jobCtx)
+}
@@ -0,0 +1,295 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxcron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxclock"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+// results collects the results of jobs.
+type results struct {
+	mu   sync.Mutex
+	ch   chan JobResult
+	runs []JobResult
+}
+
+func newResults() *results {
+	return &results{ch: make(chan JobResult, 16)}
+}
+
+func (r *results) record(res JobResult) {
+	r.mu.Lock()
+	r.runs = append(r.runs, res)
+	r.mu.Unlock()
+	r.ch <- res
+}
+
+func TestModule(t *testing.T) {
+	t.Parallel()
+
+	var (
+		clock   = fxclock.NewMock()
+		start   = clock.Now()
+		results = newResults()
+		events  = make(chan fxevent.Event, 16)
+	)
+	spy := loggerFunc(func(e fxevent.Event) {
+		if e, ok := e.(*fxevent.CronJobExecuted); ok {
+			events <- e
+		}
+	})
+
+	app := fxtest.New(t,
+		fx.WithLogger(func() fxevent.Logger { return spy }),
+		Module(Clock(clock), OnJobDone(results.record)),
+		fx.Provide(
+			AsJob(func() Job {
+				return Job{
+					Name:     "tick",
+					Schedule: Every(time.Minute),
+					Run:      func(context.Context) error { return nil },
+				}
+			}),
+		),
+	)
+	app.RequireStart()
+
+	clock.AwaitScheduled(1)
+	clock.Add(time.Minute)
+	res := <-results.ch
+	assert.Equal(t, "tick", res.Name)
+	assert.Equal(t, start.Add(time.Minute), res.ScheduledAt)
+	assert.NoError(t, res.Err)
+
+	e := (<-events).(*fxevent.CronJobExecuted)
+	assert.Equal(t, "tick", e.JobName)
+	assert.Equal(t, start.Add(time.Minute), e.ScheduledAt)
+
+	clock.AwaitScheduled(1)
+	clock.Add(time.Minute)
+	res = <-results.ch
+	assert.Equal(t, start.Add(2*time.Minute), res.ScheduledAt)
+
+	app.RequireStop()
+}
+
+func TestModuleInvalidJob(t *testing.T) {
+	t.Parallel()
+
+	app := fx.New(
+		fx.NopLogger,
+		Module(),
+		fx.Supply(fx.Annotated{Group: Group, Target: Job{Name: "broken", Run: func(context.Context) error { return nil }}}),
+	)
+	assert.ErrorContains(t, app.Err(), `fxcron: job "broken" has no Schedule`)
+}
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NoRun", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New([]Job{{Name: "empty", Schedule: Every(time.Second)}})
+		assert.ErrorContains(t, err, `job 0 ("empty") has no Run function`)
+	})
+
+	t.Run("DefaultName", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := New([]Job{{Schedule: Every(time.Second), Run: namedJob}})
+		require.NoError(t, err)
+		assert.Contains(t, s.jobs[0].Name, "namedJob")
+	})
+
+	t.Run("NotStarted", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := New(nil)
+		require.NoError(t, err)
+		assert.NoError(t, s.Stop(context.Background()))
+	})
+
+	t.Run("StartedTwice", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := New(nil)
+		require.NoError(t, err)
+		require.NoError(t, s.Start(context.Background()))
+		defer s.Stop(context.Background())
+		assert.ErrorContains(t, s.Start(context.Background()), "scheduler can only be started once")
+	})
+
+	t.Run("ErrorsAndPanics", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		results := newResults()
+		s, err := New([]Job{
+			{
+				Name:     "fails",
+				Schedule: Every(time.Second),
+				Run:      func(context.Context) error { return errors.New("great sadness") },
+			},
+			{
+				Name:     "panics",
+				Schedule: Every(2 * time.Second),
+				Run:      func(context.Context) error { panic("oops") },
+			},
+		}, Clock(clock), OnJobDone(results.record))
+		require.NoError(t, err)
+		require.NoError(t, s.Start(context.Background()))
+		defer s.Stop(context.Background())
+
+		clock.AwaitScheduled(2)
+		clock.Add(2 * time.Second)
+		errs := map[string]string{}
+		for i := 0; i < 2; i++ {
+			res := <-results.ch
+			errs[res.Name] = res.Err.Error()
+		}
+		assert.Equal(t, map[string]string{
+			"fails":  "great sadness",
+			"panics": `fxcron: job "panics" panicked: oops`,
+		}, errs)
+	})
+
+	t.Run("ScheduleEnds", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		results := newResults()
+		var once sync.Once
+		s, err := New([]Job{{
+			Name: "once",
+			Schedule: ScheduleFunc(func(now time.Time) (next time.Time) {
+				once.Do(func() { next = now.Add(time.Second) })
+				return next
+			}),
+			Run: func(context.Context) error { return nil },
+		}}, Clock(clock), OnJobDone(results.record))
+		require.NoError(t, err)
+		require.NoError(t, s.Start(context.Background()))
+
+		clock.AwaitScheduled(1)
+		clock.Add(time.Second)
+		<-results.ch
+
+		// The job's goroutine exits once its schedule ends,
+		// so Stop doesn't wait for anything.
+		require.NoError(t, s.Stop(context.Background()))
+		assert.Len(t, results.runs, 1)
+	})
+
+	t.Run("DrainOnStop", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		var (
+			running = make(chan struct{})
+			release = make(chan struct{})
+			done    bool
+		)
+		s, err := New([]Job{{
+			Name:     "slow",
+			Schedule: Every(time.Second),
+			Run: func(context.Context) error {
+				close(running)
+				<-release
+				done = true
+				return nil
+			},
+		}}, Clock(clock))
+		require.NoError(t, err)
+		require.NoError(t, s.Start(context.Background()))
+
+		clock.AwaitScheduled(1)
+		clock.Add(time.Second)
+		<-running
+
+		stopped := make(chan error)
+		go func() { stopped <- s.Stop(context.Background()) }()
+		close(release)
+		require.NoError(t, <-stopped)
+		assert.True(t, done, "running job must finish before Stop returns")
+	})
+
+	t.Run("StopTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		var (
+			running = make(chan struct{})
+			exited  = make(chan struct{})
+		)
+		s, err := New([]Job{{
+			Name:     "stuck",
+			Schedule: Every(time.Second),
+			Run: func(ctx context.Context) error {
+				defer close(exited)
+				close(running)
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}}, Clock(clock))
+		require.NoError(t, err)
+		require.NoError(t, s.Start(context.Background()))
+
+		clock.AwaitScheduled(1)
+		clock.Add(time.Second)
+		<-running
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, s.Stop(ctx), context.Canceled)
+		<-exited
+	})
+}
+
+func TestEvery(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 10, 16, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, now.Add(time.Hour), Every(time.Hour).Next(now))
+	assert.PanicsWithValue(t, "fxcron: non-positive interval for Every", func() { Every(0) })
+}
+
+func namedJob(context.Context) error { return nil }
+
+type loggerFunc func(fxevent.Event)
+
+func (f loggerFunc) LogEvent(e fxevent.Event) { f(e) }
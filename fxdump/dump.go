@@ -0,0 +1,242 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+// Package fxdump describes an Fx application without running it,
+// for local debugging and for checks in CI.
+//
+// [Inspect] validates the application like [fx.ValidateApp],
+// and reports its modules, the types each constructor provides,
+// the order in which its invoked functions would run,
+// the types it's missing, and its dependency graph:
+//
+//	func TestOptions(t *testing.T) {
+//		r := fxdump.Inspect(app.Options())
+//		if r.Err != nil {
+//			r.WriteTo(os.Stderr)
+//			t.Fatal(r.Err)
+//		}
+//	}
+//
+// The fxdump command runs Inspect on the Options function of a package.
+package fxdump
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+)
+
+// Report describes an Fx application.
+type Report struct {
+	// Err is the error that would prevent the application from starting,
+	// or nil if it's valid.
+	Err error
+
+	// Modules lists the names of the modules of the application
+	// that provide or invoke functions, in the order they were first seen.
+	Modules []string
+
+	// Provides lists the constructors and values provided to the
+	// application, in the order they were provided.
+	Provides []Provide
+
+	// Invokes lists the functions invoked by the application,
+	// in the order they would run.
+	// Invokes stops at the first invoked function that would fail.
+	Invokes []Invoke
+
+	// Missing lists the types that Err reports missing:
+	// the dependencies of the first function that can't be built
+	// that no constructor visible to it provides.
+	Missing []string
+
+	// Graph is the dependency graph of the application in the DOT language.
+	// If Err is a dependency error, the graph highlights its root cause.
+	Graph string
+}
+
+// Provide describes a constructor or value provided to the application.
+type Provide struct {
+	// Name is the name of the constructor,
+	// or "fx.Supply" for supplied values.
+	Name string
+
+	// Module is the name of the module the constructor was provided to,
+	// or empty for the top-level App.
+	Module string
+
+	// Types lists the types the constructor provides.
+	Types []string
+}
+
+// Invoke describes a function invoked by the application.
+type Invoke struct {
+	// Name is the name of the function.
+	Name string
+
+	// Module is the name of the module the function was invoked in,
+	// or empty for the top-level App.
+	Module string
+}
+
+// Inspect validates the application built from opts
+// without running its constructors or invoked functions,
+// and reports what it found.
+// Since nothing runs, the targets of options like [fx.Populate]
+// are left unset.
+func Inspect(opts ...fx.Option) *Report {
+	var r Report
+	rec := &recorder{Report: &r}
+	app := fx.New(
+		fx.Options(opts...),
+		fx.DigContainerOptions(dig.DryRun(true)),
+		// Loggers aren't built in dry runs, but decorators apply.
+		fx.DecorateLogger(func(fxevent.Logger) fxevent.Logger {
+			return rec
+		}),
+	)
+	r.Err = app.Err()
+	r.Missing = missingTypes(r.Err)
+
+	var graph bytes.Buffer
+	var vopts []dig.VisualizeOption
+	if r.Err != nil && dig.CanVisualizeError(r.Err) {
+		vopts = append(vopts, dig.VisualizeError(r.Err))
+	}
+	if err := dig.Visualize(app.DigContainer(), &graph, vopts...); err == nil {
+		r.Graph = graph.String()
+	}
+	return &r
+}
+
+// recorder builds a Report from the events of an application.
+type recorder struct {
+	*Report
+}
+
+func (r *recorder) LogEvent(event fxevent.Event) {
+	switch e := event.(type) {
+	case *fxevent.Provided:
+		r.addModule(e.ModuleName)
+		r.Provides = append(r.Provides, Provide{
+			Name:   e.ConstructorName,
+			Module: e.ModuleName,
+			Types:  e.OutputTypeNames,
+		})
+	case *fxevent.Supplied:
+		r.addModule(e.ModuleName)
+		r.Provides = append(r.Provides, Provide{
+			Name:   "fx.Supply",
+			Module: e.ModuleName,
+			Types:  []string{e.TypeName},
+		})
+	case *fxevent.Invoking:
+		r.addModule(e.ModuleName)
+		r.Invokes = append(r.Invokes, Invoke{
+			Name:   e.FunctionName,
+			Module: e.ModuleName,
+		})
+	}
+}
+
+func (r *recorder) addModule(name string) {
+	if name == "" {
+		return
+	}
+	for _, m := range r.Modules {
+		if m == name {
+			return
+		}
+	}
+	r.Modules = append(r.Modules, name)
+}
+
+// missingTypes returns the types that dig reported missing in err,
+// without its suggestions for what was meant instead.
+//
+// Dig reports the missing dependencies of a single function,
+// so this is empty if err has any other root cause.
+func missingTypes(err error) []string {
+	if err == nil {
+		return nil
+	}
+	msg := dig.RootCause(err).Error()
+	for _, prefix := range []string{"missing type: ", "missing types: "} {
+		if list, ok := strings.CutPrefix(msg, prefix); ok {
+			var missing []string
+			for _, t := range strings.Split(list, "; ") {
+				t, _, _ = strings.Cut(t, " (did you mean ")
+				missing = append(missing, t)
+			}
+			return missing
+		}
+	}
+	return nil
+}
+
+// WriteTo writes a human-readable description of the application to w.
+// It doesn't include the graph: write [Report.Graph] separately
+// to render it with Graphviz.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var b bytes.Buffer
+	if r.Err != nil {
+		fmt.Fprintf(&b, "INVALID: %v\n", r.Err)
+	} else {
+		fmt.Fprintln(&b, "OK")
+	}
+
+	if len(r.Missing) > 0 {
+		fmt.Fprintln(&b, "\nMissing types:")
+		for _, t := range r.Missing {
+			fmt.Fprintf(&b, "\t%v\n", t)
+		}
+	}
+
+	if len(r.Modules) > 0 {
+		fmt.Fprintln(&b, "\nModules:")
+		for _, m := range r.Modules {
+			fmt.Fprintf(&b, "\t%v\n", m)
+		}
+	}
+
+	fmt.Fprintln(&b, "\nProvides:")
+	for _, p := range r.Provides {
+		fmt.Fprintf(&b, "\t%v <= %v%v\n", strings.Join(p.Types, ", "), p.Name, inModule(p.Module))
+	}
+
+	fmt.Fprintln(&b, "\nInvokes:")
+	for i, inv := range r.Invokes {
+		fmt.Fprintf(&b, "\t%d. %v%v\n", i+1, inv.Name, inModule(inv.Module))
+	}
+
+	n, err := w.Write(b.Bytes())
+	return int64(n), err
+}
+
+func inModule(name string) string {
+	if name == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (module %q)", name)
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fxdump_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxdump"
+	"go.uber.org/fx/fxevent"
+)
+
+type (
+	config string
+	server struct{}
+)
+
+func newConfig() config { return "" }
+
+func newServer(config) *server {
+	panic("constructors must not run")
+}
+
+func register(*server) {
+	panic("invoked functions must not run")
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+
+		r := fxdump.Inspect(
+			fx.Module("config", fx.Provide(newConfig)),
+			fx.Module("http",
+				fx.Provide(newServer),
+				fx.Supply(42),
+				fx.Invoke(register),
+			),
+			fx.Invoke(func(config) {}),
+		)
+		require.NoError(t, r.Err)
+		assert.Equal(t, []string{"config", "http"}, r.Modules)
+		assert.Contains(t, r.Provides, fxdump.Provide{
+			Name:   "go.uber.org/fx/fxdump_test.newServer()",
+			Module: "http",
+			Types:  []string{"*fxdump_test.server"},
+		})
+		assert.Contains(t, r.Provides, fxdump.Provide{
+			Name:   "fx.Supply",
+			Module: "http",
+			Types:  []string{"int"},
+		})
+		assert.Equal(t, []fxdump.Invoke{
+			{Name: "go.uber.org/fx/fxdump_test.register()", Module: "http"},
+			{Name: "go.uber.org/fx/fxdump_test.TestInspect.func1.1()"},
+		}, r.Invokes)
+		assert.Empty(t, r.Missing)
+		assert.Contains(t, r.Graph, "digraph")
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		t.Parallel()
+
+		r := fxdump.Inspect(
+			fx.Provide(newServer),
+			fx.Invoke(register),
+		)
+		require.Error(t, r.Err)
+		assert.Equal(t, []string{"fxdump_test.config"}, r.Missing)
+		assert.Contains(t, r.Graph, "digraph")
+	})
+
+	t.Run("MissingIgnoresProvidedTypes", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			fx.In
+
+			Lifecycle  fx.Lifecycle
+			Shutdowner fx.Shutdowner
+			Port       int      `name:"port"`
+			Timeout    float64  `optional:"true"`
+			Handlers   []string `group:"handlers"`
+			Server     *server  `name:"public"`
+		}
+		r := fxdump.Inspect(
+			fx.Supply(fx.Annotated{Name: "port", Target: 8080}),
+			fx.Invoke(func(params) {}),
+		)
+		require.Error(t, r.Err)
+		assert.Equal(t, []string{`*fxdump_test.server[name="public"]`}, r.Missing)
+	})
+
+	t.Run("MissingPrivateType", func(t *testing.T) {
+		t.Parallel()
+
+		r := fxdump.Inspect(
+			fx.Module("config", fx.Provide(newConfig, fx.Private)),
+			fx.Provide(newServer),
+			fx.Invoke(register),
+		)
+		require.Error(t, r.Err)
+		assert.Equal(t, []string{"fxdump_test.config"}, r.Missing)
+	})
+
+	t.Run("CustomLogger", func(t *testing.T) {
+		t.Parallel()
+
+		r := fxdump.Inspect(
+			fx.WithLogger(func() fxevent.Logger {
+				panic("logger must not be built")
+			}),
+			fx.Invoke(register),
+			fx.Provide(newServer, newConfig),
+		)
+		require.NoError(t, r.Err)
+		assert.Len(t, r.Invokes, 1)
+	})
+}
+
+func TestReportWriteTo(t *testing.T) {
+	t.Parallel()
+
+	r := fxdump.Inspect(
+		fx.Module("http",
+			fx.Provide(newServer),
+			fx.Invoke(register),
+		),
+	)
+
+	var out strings.Builder
+	_, err := r.WriteTo(&out)
+	require.NoError(t, err)
+
+	got := out.String()
+	assert.True(t, strings.HasPrefix(got, "INVALID: "), "got %q", got)
+	assert.Contains(t, got, "\nMissing types:\n\tfxdump_test.config\n")
+	assert.Contains(t, got, "\nModules:\n\thttp\n")
+	assert.Contains(t, got, "\t*fxdump_test.server <= go.uber.org/fx/fxdump_test.newServer() (module \"http\")\n")
+	assert.Contains(t, got, "\nInvokes:\n\t1. go.uber.org/fx/fxdump_test.register() (module \"http\")\n")
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxevent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+var (
+	_typeOfError  = reflect.TypeOf((*error)(nil)).Elem()
+	_typeOfSignal = reflect.TypeOf((*os.Signal)(nil)).Elem()
+)
+
+// _eventTypes maps the names of events supported by [Marshal] and
+// [Unmarshal] to their types.
+var _eventTypes = make(map[string]reflect.Type)
+
+func init() {
+	for _, e := range []Event{
+		&OnStartExecuting{},
+		&OnStartExecuted{},
+		&OnStopExecuting{},
+		&OnStopExecuted{},
+		&OnPauseExecuting{},
+		&OnPauseExecuted{},
+		&OnResumeExecuting{},
+		&OnResumeExecuted{},
+		&Configured{},
+		&Supplied{},
+		&Provided{},
+		&Replaced{},
+		&Decorated{},
+		&DecoratorChain{},
+		&Run{},
+		&Retrying{},
+		&OnStartRetrying{},
+		&Invoking{},
+		&Invoked{},
+		&Stopping{},
+		&Stopped{},
+		&RollingBack{},
+		&RolledBack{},
+		&Started{},
+		&StartSummary{},
+		&LoggerInitialized{},
+		&HookTimedOut{},
+		&OnStartSlow{},
+		&DynamicHookAppended{},
+		&ModuleStarted{},
+		&ModuleStopped{},
+		&LintWarning{},
+		&ShutdownScheduled{},
+		&ShutdownCanceled{},
+		&ShutdownFired{},
+		&OptionError{},
+		&Flushing{},
+		&CronJobExecuted{},
+		&ModuleConflict{},
+		&LoggerPanicked{},
+	} {
+		t := reflect.TypeOf(e).Elem()
+		_eventTypes[t.Name()] = t
+	}
+}
+
+// envelope is the JSON representation of an event.
+type envelope struct {
+	Type  string                     `json:"type"`
+	Event map[string]json.RawMessage `json:"event"`
+}
+
+// Marshal encodes an event as JSON so that it may be shipped to another
+// process and replayed into any [Logger] with [Unmarshal].
+//
+//	{"type":"Provided","event":{"ConstructorName":"main.NewServer()",...}}
+//
+// Errors are encoded as their messages, and signals by their names.
+func Marshal(e Event) ([]byte, error) {
+	v := reflect.ValueOf(e)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, fmt.Errorf("cannot marshal event %T", e)
+	}
+	v = v.Elem()
+	t := v.Type()
+	if _eventTypes[t.Name()] != t {
+		return nil, fmt.Errorf("cannot marshal unknown event %T", e)
+	}
+
+	env := envelope{Type: t.Name(), Event: make(map[string]json.RawMessage)}
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		fv := v.FieldByIndex(f.Index)
+
+		var x interface{}
+		switch {
+		case f.Type == _typeOfError, f.Type == _typeOfSignal:
+			if fv.IsNil() {
+				continue
+			}
+			x = fmt.Sprint(fv.Interface())
+		default:
+			x = fv.Interface()
+		}
+
+		b, err := json.Marshal(x)
+		if err != nil {
+			return nil, fmt.Errorf("marshal %v.%v: %w", t.Name(), f.Name, err)
+		}
+		env.Event[f.Name] = b
+	}
+	return json.Marshal(env)
+}
+
+// Unmarshal decodes an event encoded with [Marshal].
+//
+// Errors are decoded as errors with the original messages,
+// and signals as [os.Signal] values with the original names.
+// Fields unknown to this version of Fx are ignored.
+func Unmarshal(data []byte) (Event, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	t, ok := _eventTypes[env.Type]
+	if !ok {
+		return nil, fmt.Errorf("cannot unmarshal unknown event %q", env.Type)
+	}
+
+	v := reflect.New(t)
+	for name, raw := range env.Event {
+		f, ok := t.FieldByName(name)
+		if !ok || !f.IsExported() {
+			continue
+		}
+
+		fv := v.Elem().FieldByIndex(f.Index)
+		switch f.Type {
+		case _typeOfError, _typeOfSignal:
+			var msg string
+			if err := json.Unmarshal(raw, &msg); err != nil {
+				return nil, fmt.Errorf("unmarshal %v.%v: %w", t.Name(), name, err)
+			}
+			if f.Type == _typeOfError {
+				fv.Set(reflect.ValueOf(errors.New(msg)))
+			} else {
+				fv.Set(reflect.ValueOf(signal(msg)))
+			}
+		default:
+			if err := json.Unmarshal(raw, fv.Addr().Interface()); err != nil {
+				return nil, fmt.Errorf("unmarshal %v.%v: %w", t.Name(), name, err)
+			}
+		}
+	}
+	return v.Interface().(Event), nil
+}
+
+// signal is an os.Signal decoded by Unmarshal.
+type signal string
+
+var _ os.Signal = signal("")
+
+func (s signal) String() string { return string(s) }
+
+func (signal) Signal() {}
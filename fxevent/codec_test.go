@@ -0,0 +1,177 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxevent
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	someError := errors.New("some error")
+	deadline := time.Date(2024, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	events := []Event{
+		&OnStartExecuting{FunctionName: "hook.onStart", CallerName: "bytes.NewBuffer"},
+		&OnStartExecuted{FunctionName: "hook.onStart", CallerName: "bytes.NewBuffer", Method: "OnStart", Runtime: time.Millisecond, Err: someError},
+		&OnStopExecuting{FunctionName: "hook.onStop", CallerName: "bytes.NewBuffer"},
+		&OnStopExecuted{FunctionName: "hook.onStop", CallerName: "bytes.NewBuffer", Runtime: time.Millisecond},
+		&OnPauseExecuting{FunctionName: "hook.onPause", CallerName: "bytes.NewBuffer"},
+		&OnPauseExecuted{FunctionName: "hook.onPause", CallerName: "bytes.NewBuffer", Runtime: time.Millisecond, Err: someError},
+		&OnResumeExecuting{FunctionName: "hook.onResume", CallerName: "bytes.NewBuffer"},
+		&OnResumeExecuted{FunctionName: "hook.onResume", CallerName: "bytes.NewBuffer", Runtime: time.Millisecond},
+		&Configured{StartTimeout: time.Second, StopTimeout: time.Minute, Clock: "system", RecoverFromPanics: true},
+		&Supplied{TypeName: "*bytes.Buffer", StackTrace: []string{"main.main"}, ModuleTrace: []string{"main.main"}, ModuleName: "myModule"},
+		&Provided{ConstructorName: "bytes.NewBuffer()", OutputTypeNames: []string{"*bytes.Buffer"}, InputTypeNames: []string{"[]uint8"}, Private: true},
+		&Replaced{OutputTypeNames: []string{"*bytes.Buffer"}, Err: someError},
+		&Decorated{DecoratorName: "bytes.NewBuffer()", OutputTypeNames: []string{"*bytes.Buffer"}},
+		&DecoratorChain{TypeName: "*bytes.Buffer", DecoratorNames: []string{"a()", "b()"}},
+		&Run{Name: "bytes.NewBuffer()", Kind: "provide", Runtime: time.Millisecond, DemandPath: []string{"main.run()", "bytes.NewBuffer()"}},
+		&Retrying{ConstructorName: "db.Open()", Attempt: 1, Attempts: 3, Delay: time.Second, Err: someError},
+		&OnStartRetrying{FunctionName: "main.connect()", CallerName: "main.NewClient()", Attempt: 1, Attempts: 3, Delay: time.Second, Err: someError},
+		&Invoking{FunctionName: "bytes.NewBuffer()", ModuleName: "myModule"},
+		&Invoked{FunctionName: "bytes.NewBuffer()", Err: someError, Trace: "foo()\n\tbar/baz.go:42", Try: true},
+		&Stopping{Signal: syscall.SIGINT},
+		&Stopped{Err: someError, Runtime: time.Second, HookCount: 2},
+		&RollingBack{StartErr: someError},
+		&RolledBack{},
+		&Started{Runtime: time.Second, InitRuntime: time.Minute, ConstructorCount: 3, HookCount: 2},
+		&StartSummary{Constructors: []ConstructorAllocations{{Name: "bytes.NewBuffer()", ModuleName: "myModule", Bytes: 1024, Objects: 2}}},
+		&LoggerInitialized{ConstructorName: "bytes.NewBuffer()"},
+		&HookTimedOut{Method: "OnStart", FunctionName: "hook.onStart", HookStacks: []string{"a"}, Stacks: "b"},
+		&OnStartSlow{FunctionName: "hook.onStart", CallerName: "bytes.NewBuffer", Elapsed: time.Second},
+		&DynamicHookAppended{CallerName: "bytes.NewBuffer", OnStartName: "hook.onStart", OnStopName: "hook.onStop"},
+		&ModuleStarted{ModuleName: "server", Runtime: time.Millisecond},
+		&ModuleStopped{ModuleName: "server", Runtime: time.Second},
+		&LintWarning{Rule: "unused", Message: "never used", FunctionName: "bytes.NewBuffer()"},
+		&ShutdownScheduled{Deadline: deadline, ExitCode: 1},
+		&ShutdownCanceled{Deadline: deadline},
+		&ShutdownFired{Deadline: deadline, ExitCode: 2, Err: someError},
+		&OptionError{Err: someError, ModuleName: "myModule", ModuleTrace: []string{"main.main"}, StackTrace: []string{"main.main"}},
+		&Flushing{Metadata: Metadata{AppName: "payments-api", Seq: 42, Timestamp: deadline}},
+		&CronJobExecuted{JobName: "cleanup", ScheduledAt: deadline, Runtime: time.Second, Err: someError},
+		&ModuleConflict{ModuleName: "logging", Modules: []string{`"logging" from a.go:1`, `"logging" from b.go:2`}},
+		&LoggerPanicked{LoggerType: "*fxevent.ZapLogger", EventType: "*fxevent.Started", Err: someError, Stack: "main.main()"},
+	}
+	require.Len(t, events, len(_eventTypes), "every event must be covered")
+
+	for _, give := range events {
+		give := give
+		name := reflect.TypeOf(give).Elem().Name()
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := Marshal(give)
+			require.NoError(t, err)
+
+			got, err := Unmarshal(data)
+			require.NoError(t, err)
+			require.IsType(t, give, got)
+
+			// Replaying the decoded event must log the same output
+			// as logging the original one.
+			var want, replayed bytes.Buffer
+			(&ConsoleLogger{W: &want}).LogEvent(give)
+			(&ConsoleLogger{W: &replayed}).LogEvent(got)
+			assert.Equal(t, want.String(), replayed.String())
+		})
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil event", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Marshal((*Provided)(nil))
+		assert.ErrorContains(t, err, "cannot marshal event *fxevent.Provided")
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Marshal(&unknownEvent{})
+		assert.ErrorContains(t, err, "cannot marshal unknown event *fxevent.unknownEvent")
+	})
+}
+
+type unknownEvent struct{ Metadata }
+
+func (*unknownEvent) event() {}
+
+func TestUnmarshalErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give string
+		want string
+	}{
+		{
+			desc: "invalid JSON",
+			give: `{`,
+			want: "unexpected end of JSON input",
+		},
+		{
+			desc: "unknown event",
+			give: `{"type":"Exploded","event":{}}`,
+			want: `cannot unmarshal unknown event "Exploded"`,
+		},
+		{
+			desc: "invalid field",
+			give: `{"type":"Provided","event":{"Private":"yes"}}`,
+			want: "unmarshal Provided.Private",
+		},
+		{
+			desc: "invalid error",
+			give: `{"type":"Stopped","event":{"Err":42}}`,
+			want: "unmarshal Stopped.Err",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Unmarshal([]byte(tt.give))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestUnmarshalIgnoresUnknownFields(t *testing.T) {
+	t.Parallel()
+
+	got, err := Unmarshal([]byte(`{"type":"Invoking","event":{"FunctionName":"f()","Color":"blue"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, &Invoking{FunctionName: "f()"}, got)
+}
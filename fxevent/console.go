@@ -30,18 +30,32 @@ import (
 // messages to the console.
 //
 // Use this during development.
+//
+// Events of named applications are prefixed with the name,
+// for example, "[Fx payments-api]".
 type ConsoleLogger struct {
 	W io.Writer
+
+	// Set on the copy of the logger used for a single event.
+	appName string
 }
 
 var _ Logger = (*ConsoleLogger)(nil)
 
 func (l *ConsoleLogger) logf(msg string, args ...interface{}) {
-	fmt.Fprintf(l.W, "[Fx] "+msg+"\n", args...)
+	prefix := "[Fx] "
+	if l.appName != "" {
+		prefix = "[Fx " + l.appName + "] "
+	}
+	fmt.Fprintf(l.W, prefix+msg+"\n", args...)
 }
 
 // LogEvent logs the given event to the provided Zap logger.
 func (l *ConsoleLogger) LogEvent(event Event) {
+	if name := appName(event); name != "" {
+		l = &ConsoleLogger{W: l.W, appName: name}
+	}
+
 	switch e := event.(type) {
 	case *OnStartExecuting:
 		l.logf("HOOK OnStart\t\t%s executing (caller: %s)", e.FunctionName, e.CallerName)
@@ -59,6 +73,25 @@ func (l *ConsoleLogger) LogEvent(event Event) {
 		} else {
 			l.logf("HOOK OnStop\t\t%s called by %s ran successfully in %s", e.FunctionName, e.CallerName, e.Runtime)
 		}
+	case *OnPauseExecuting:
+		l.logf("HOOK OnPause\t\t%s executing (caller: %s)", e.FunctionName, e.CallerName)
+	case *OnPauseExecuted:
+		if e.Err != nil {
+			l.logf("HOOK OnPause\t\t%s called by %s failed in %s: %+v", e.FunctionName, e.CallerName, e.Runtime, e.Err)
+		} else {
+			l.logf("HOOK OnPause\t\t%s called by %s ran successfully in %s", e.FunctionName, e.CallerName, e.Runtime)
+		}
+	case *OnResumeExecuting:
+		l.logf("HOOK OnResume\t\t%s executing (caller: %s)", e.FunctionName, e.CallerName)
+	case *OnResumeExecuted:
+		if e.Err != nil {
+			l.logf("HOOK OnResume\t\t%s called by %s failed in %s: %+v", e.FunctionName, e.CallerName, e.Runtime, e.Err)
+		} else {
+			l.logf("HOOK OnResume\t\t%s called by %s ran successfully in %s", e.FunctionName, e.CallerName, e.Runtime)
+		}
+	case *Configured:
+		l.logf("CONFIG\tstart timeout: %v, stop timeout: %v, clock: %v, recover from panics: %v",
+			e.StartTimeout, e.StopTimeout, e.Clock, e.RecoverFromPanics)
 	case *Supplied:
 		if e.Err != nil {
 			l.logf("ERROR\tFailed to supply %v: %+v", e.TypeName, e.Err)
@@ -104,15 +137,35 @@ func (l *ConsoleLogger) LogEvent(event Event) {
 		if e.Err != nil {
 			l.logf("Error after options were applied: %+v", e.Err)
 		}
+	case *DecoratorChain:
+		chain := strings.Join(e.DecoratorNames, " -> ")
+		if e.ModuleName != "" {
+			l.logf("DECORATE\t%v chain: %v from module %q", e.TypeName, chain, e.ModuleName)
+		} else {
+			l.logf("DECORATE\t%v chain: %v", e.TypeName, chain)
+		}
 	case *Run:
 		var moduleStr string
 		if e.ModuleName != "" {
 			moduleStr = fmt.Sprintf(" from module %q", e.ModuleName)
 		}
 		l.logf("RUN\t%v: %v in %s%v", e.Kind, e.Name, e.Runtime, moduleStr)
+		if len(e.DemandPath) > 0 {
+			l.logf("\tdemand path: %v", strings.Join(e.DemandPath, " -> "))
+		}
 		if e.Err != nil {
 			l.logf("Error returned: %+v", e.Err)
 		}
+	case *Retrying:
+		var moduleStr string
+		if e.ModuleName != "" {
+			moduleStr = fmt.Sprintf(" from module %q", e.ModuleName)
+		}
+		l.logf("RETRY\t%v%v failed attempt %d of %d, retrying in %v: %+v",
+			e.ConstructorName, moduleStr, e.Attempt, e.Attempts, e.Delay, e.Err)
+	case *OnStartRetrying:
+		l.logf("RETRY\tOnStart hook %v of %v failed attempt %d of %d, retrying in %v: %+v",
+			e.FunctionName, e.CallerName, e.Attempt, e.Attempts, e.Delay, e.Err)
 
 	case *Invoking:
 		if e.ModuleName != "" {
@@ -121,7 +174,9 @@ func (l *ConsoleLogger) LogEvent(event Event) {
 			l.logf("INVOKE\t\t%s", e.FunctionName)
 		}
 	case *Invoked:
-		if e.Err != nil {
+		if e.Err != nil && e.Try {
+			l.logf("WARNING\t\tfx.TryInvoke(%v) called from:\n%+vFailed: %+v", e.FunctionName, e.Trace, e.Err)
+		} else if e.Err != nil {
 			l.logf("ERROR\t\tfx.Invoke(%v) called from:\n%+vFailed: %+v", e.FunctionName, e.Trace, e.Err)
 		}
 	case *Stopping:
@@ -140,7 +195,13 @@ func (l *ConsoleLogger) LogEvent(event Event) {
 		if e.Err != nil {
 			l.logf("ERROR\t\tFailed to start: %+v", e.Err)
 		} else {
-			l.logf("RUNNING")
+			l.logf("RUNNING\tstarted in %s after %s of initialization, ran %d constructors and %d OnStart hooks",
+				e.Runtime, e.InitRuntime, e.ConstructorCount, e.HookCount)
+		}
+	case *StartSummary:
+		l.logf("SUMMARY\theap allocations of %d constructors", len(e.Constructors))
+		for _, a := range e.Constructors {
+			l.logf("\t%v", a)
 		}
 	case *LoggerInitialized:
 		if e.Err != nil {
@@ -148,5 +209,64 @@ func (l *ConsoleLogger) LogEvent(event Event) {
 		} else {
 			l.logf("LOGGER\tInitialized custom logger from %v", e.ConstructorName)
 		}
+	case *HookTimedOut:
+		l.logf("ERROR\t\t%s hook %s called by %s timed out, goroutine stacks:\n%s",
+			e.Method, e.FunctionName, e.CallerName, e.Stacks)
+	case *OnStartSlow:
+		l.logf("WARNING\t\tOnStart hook %s called by %s still running after %s",
+			e.FunctionName, e.CallerName, e.Elapsed)
+	case *DynamicHookAppended:
+		l.logf("HOOK		appended during start (caller: %s)%s%s", e.CallerName,
+			hookFunc("OnStart", e.OnStartName), hookFunc("OnStop", e.OnStopName))
+	case *ModuleStarted:
+		l.logf("MODULE\t%s started in %s", e.ModuleName, e.Runtime)
+	case *ModuleStopped:
+		l.logf("MODULE\t%s stopped in %s", e.ModuleName, e.Runtime)
+	case *LintWarning:
+		if e.ModuleName != "" {
+			l.logf("WARNING\t%v: %v from module %q", e.Rule, e.Message, e.ModuleName)
+		} else {
+			l.logf("WARNING\t%v: %v", e.Rule, e.Message)
+		}
+	case *ShutdownScheduled:
+		l.logf("SHUTDOWN\tScheduled at %v with exit code %d", e.Deadline, e.ExitCode)
+	case *ShutdownCanceled:
+		l.logf("SHUTDOWN\tCanceled shutdown scheduled at %v", e.Deadline)
+	case *ShutdownFired:
+		if e.Err != nil {
+			l.logf("ERROR\t\tFailed to shut down as scheduled at %v: %+v", e.Deadline, e.Err)
+		} else {
+			l.logf("SHUTDOWN\tShutting down as scheduled at %v with exit code %d", e.Deadline, e.ExitCode)
+		}
+	case *OptionError:
+		location := "unknown location"
+		if len(e.ModuleTrace) > 0 {
+			location = e.ModuleTrace[0]
+		}
+		if e.ModuleName != "" {
+			l.logf("ERROR\t\tfx.Error from %v in module %q: %+v", location, e.ModuleName, e.Err)
+		} else {
+			l.logf("ERROR\t\tfx.Error from %v: %+v", location, e.Err)
+		}
+	case *Flushing:
+		l.logf("FLUSHING")
+	case *CronJobExecuted:
+		if e.Err != nil {
+			l.logf("ERROR\t\tCron job %q scheduled at %v failed after %s: %+v", e.JobName, e.ScheduledAt, e.Runtime, e.Err)
+		} else {
+			l.logf("CRON\t\t%q scheduled at %v ran in %s", e.JobName, e.ScheduledAt, e.Runtime)
+		}
+	case *ModuleConflict:
+		l.logf("WARNING\t%d modules are named %q:\n\t%s", len(e.Modules), e.ModuleName, strings.Join(e.Modules, "\n\t"))
+	case *LoggerPanicked:
+		l.logf("ERROR\t\tLogger %v panicked while logging %v: %+v\n%s", e.LoggerType, e.EventType, e.Err, e.Stack)
+	}
+}
+
+// hookFunc describes a function of a hook for the console, if it's set.
+func hookFunc(method, name string) string {
+	if name == "" {
+		return ""
 	}
+	return fmt.Sprintf("\n\t%s: %s", method, name)
 }
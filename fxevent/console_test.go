@@ -50,6 +50,8 @@ func (e *richError) Format(w fmt.State, c rune) {
 func TestConsoleLogger(t *testing.T) {
 	t.Parallel()
 
+	deadline := time.Date(2024, 10, 16, 12, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name string
 		give Event
@@ -98,6 +100,76 @@ func TestConsoleLogger(t *testing.T) {
 			},
 			want: "[Fx] HOOK OnStop		hook.onStart1 called by bytes.NewBuffer ran successfully in 3ms\n",
 		},
+		{
+			name: "OnPauseExecuting",
+			give: &OnPauseExecuting{
+				FunctionName: "hook.onPause1",
+				CallerName:   "bytes.NewBuffer",
+			},
+			want: "[Fx] HOOK OnPause		hook.onPause1 executing (caller: bytes.NewBuffer)\n",
+		},
+		{
+			name: "OnPauseExecutedError",
+			give: &OnPauseExecuted{
+				FunctionName: "hook.onPause1",
+				CallerName:   "bytes.NewBuffer",
+				Err:          fmt.Errorf("some error"),
+			},
+			want: "[Fx] HOOK OnPause		hook.onPause1 called by bytes.NewBuffer failed in 0s: some error\n",
+		},
+		{
+			name: "OnPauseExecutedError/rich error",
+			give: &OnPauseExecuted{
+				FunctionName: "hook.onPause1",
+				CallerName:   "bytes.NewBuffer",
+				Err:          &richError{},
+			},
+			want: "[Fx] HOOK OnPause		hook.onPause1 called by bytes.NewBuffer failed in 0s: rich error\n",
+		},
+		{
+			name: "OnPauseExecuted",
+			give: &OnPauseExecuted{
+				FunctionName: "hook.onPause1",
+				CallerName:   "bytes.NewBuffer",
+				Runtime:      time.Millisecond * 3,
+			},
+			want: "[Fx] HOOK OnPause		hook.onPause1 called by bytes.NewBuffer ran successfully in 3ms\n",
+		},
+		{
+			name: "OnResumeExecuting",
+			give: &OnResumeExecuting{
+				FunctionName: "hook.onResume1",
+				CallerName:   "bytes.NewBuffer",
+			},
+			want: "[Fx] HOOK OnResume		hook.onResume1 executing (caller: bytes.NewBuffer)\n",
+		},
+		{
+			name: "OnResumeExecutedError",
+			give: &OnResumeExecuted{
+				FunctionName: "hook.onResume1",
+				CallerName:   "bytes.NewBuffer",
+				Err:          fmt.Errorf("some error"),
+			},
+			want: "[Fx] HOOK OnResume		hook.onResume1 called by bytes.NewBuffer failed in 0s: some error\n",
+		},
+		{
+			name: "OnResumeExecutedError/rich error",
+			give: &OnResumeExecuted{
+				FunctionName: "hook.onResume1",
+				CallerName:   "bytes.NewBuffer",
+				Err:          &richError{},
+			},
+			want: "[Fx] HOOK OnResume		hook.onResume1 called by bytes.NewBuffer failed in 0s: rich error\n",
+		},
+		{
+			name: "OnResumeExecuted",
+			give: &OnResumeExecuted{
+				FunctionName: "hook.onResume1",
+				CallerName:   "bytes.NewBuffer",
+				Runtime:      time.Millisecond * 3,
+			},
+			want: "[Fx] HOOK OnResume		hook.onResume1 called by bytes.NewBuffer ran successfully in 3ms\n",
+		},
 		{
 			name: "OnStartExecutedError",
 			give: &OnStartExecuted{
@@ -135,6 +207,16 @@ func TestConsoleLogger(t *testing.T) {
 			give: &Provided{Err: &richError{}},
 			want: "[Fx] Error after options were applied: rich error\n",
 		},
+		{
+			name: "Configured",
+			give: &Configured{
+				StartTimeout:      15 * time.Second,
+				StopTimeout:       time.Minute,
+				Clock:             "system",
+				RecoverFromPanics: true,
+			},
+			want: "[Fx] CONFIG\tstart timeout: 15s, stop timeout: 1m0s, clock: system, recover from panics: true\n",
+		},
 		{
 			name: "Supplied",
 			give: &Supplied{
@@ -265,6 +347,23 @@ func TestConsoleLogger(t *testing.T) {
 			give: &Decorated{Err: &richError{}},
 			want: "[Fx] Error after options were applied: rich error\n",
 		},
+		{
+			name: "DecoratorChain",
+			give: &DecoratorChain{
+				TypeName:       "*bytes.Buffer",
+				DecoratorNames: []string{"main.a()", "main.b()"},
+			},
+			want: "[Fx] DECORATE	*bytes.Buffer chain: main.a() -> main.b()\n",
+		},
+		{
+			name: "DecoratorChain with module",
+			give: &DecoratorChain{
+				TypeName:       "*bytes.Buffer",
+				ModuleName:     "myModule",
+				DecoratorNames: []string{"main.a()", "main.b()"},
+			},
+			want: "[Fx] DECORATE	*bytes.Buffer chain: main.a() -> main.b() from module \"myModule\"\n",
+		},
 		{
 			name: "Run",
 			give: &Run{Name: "bytes.NewBuffer()", Kind: "constructor", Runtime: 10 * time.Nanosecond},
@@ -280,6 +379,19 @@ func TestConsoleLogger(t *testing.T) {
 			},
 			want: "[Fx] RUN\tconstructor: bytes.NewBuffer() in 50ms from module \"myModule\"\n",
 		},
+		{
+			name: "Run with demand path",
+			give: &Run{
+				Name:       "bytes.NewBuffer()",
+				Kind:       "constructor",
+				Runtime:    10 * time.Nanosecond,
+				DemandPath: []string{"main.run()", "main.NewServer()", "bytes.NewBuffer()"},
+			},
+			want: joinLines(
+				"[Fx] RUN\tconstructor: bytes.NewBuffer() in 10ns",
+				"[Fx] \tdemand path: main.run() -> main.NewServer() -> bytes.NewBuffer()",
+			),
+		},
 		{
 			name: "RunError",
 			give: &Run{
@@ -293,6 +405,41 @@ func TestConsoleLogger(t *testing.T) {
 				"[Fx] Error returned: terrible constructor error",
 			),
 		},
+		{
+			name: "Retrying",
+			give: &Retrying{
+				ConstructorName: "db.Open()",
+				Attempt:         1,
+				Attempts:        3,
+				Delay:           time.Second,
+				Err:             errors.New("connection refused"),
+			},
+			want: "[Fx] RETRY\tdb.Open() failed attempt 1 of 3, retrying in 1s: connection refused\n",
+		},
+		{
+			name: "Retrying with module",
+			give: &Retrying{
+				ConstructorName: "db.Open()",
+				ModuleName:      "myModule",
+				Attempt:         2,
+				Attempts:        3,
+				Delay:           2 * time.Second,
+				Err:             errors.New("connection refused"),
+			},
+			want: "[Fx] RETRY\tdb.Open() from module \"myModule\" failed attempt 2 of 3, retrying in 2s: connection refused\n",
+		},
+		{
+			name: "OnStartRetrying",
+			give: &OnStartRetrying{
+				FunctionName: "main.connect()",
+				CallerName:   "main.NewClient()",
+				Attempt:      1,
+				Attempts:     3,
+				Delay:        time.Second,
+				Err:          errors.New("connection refused"),
+			},
+			want: "[Fx] RETRY\tOnStart hook main.connect() of main.NewClient() failed attempt 1 of 3, retrying in 1s: connection refused\n",
+		},
 		{
 			name: "Invoking",
 			give: &Invoking{FunctionName: "bytes.NewBuffer()"},
@@ -334,6 +481,21 @@ func TestConsoleLogger(t *testing.T) {
 				"Failed: rich error",
 			),
 		},
+		{
+			name: "Invoked/Try",
+			give: &Invoked{
+				FunctionName: "bytes.NewBuffer()",
+				Err:          errors.New("some error"),
+				Trace:        "foo()\n\tbar/baz.go:42\n",
+				Try:          true,
+			},
+			want: joinLines(
+				"[Fx] WARNING		fx.TryInvoke(bytes.NewBuffer()) called from:",
+				"foo()",
+				"	bar/baz.go:42",
+				"Failed: some error",
+			),
+		},
 		{
 			name: "StartError",
 			give: &Started{Err: errors.New("some error")},
@@ -381,8 +543,18 @@ func TestConsoleLogger(t *testing.T) {
 		},
 		{
 			name: "Started",
-			give: &Started{},
-			want: "[Fx] RUNNING\n",
+			give: &Started{Runtime: 3 * time.Millisecond, InitRuntime: 10 * time.Millisecond, ConstructorCount: 12, HookCount: 4},
+			want: "[Fx] RUNNING\tstarted in 3ms after 10ms of initialization, ran 12 constructors and 4 OnStart hooks\n",
+		},
+		{
+			name: "StartSummary",
+			give: &StartSummary{Constructors: []ConstructorAllocations{
+				{Name: "bytes.NewBuffer()", ModuleName: "myModule", Bytes: 1024, Objects: 2},
+				{Name: "main.New()", Bytes: 16, Objects: 1},
+			}},
+			want: "[Fx] SUMMARY\theap allocations of 2 constructors\n" +
+				"[Fx] \tbytes.NewBuffer() from module \"myModule\" allocated 1024 bytes in 2 objects\n" +
+				"[Fx] \tmain.New() allocated 16 bytes in 1 objects\n",
 		},
 		{
 			name: "CustomLoggerError",
@@ -399,6 +571,143 @@ func TestConsoleLogger(t *testing.T) {
 			give: &LoggerInitialized{ConstructorName: "go.uber.org/fx/fxevent.TestConsoleLogger.func1()"},
 			want: "[Fx] LOGGER	Initialized custom logger from go.uber.org/fx/fxevent.TestConsoleLogger.func1()\n",
 		},
+		{
+			name: "HookTimedOut",
+			give: &HookTimedOut{
+				Method:       "OnStart",
+				FunctionName: "hook.onStart",
+				CallerName:   "bytes.NewBuffer",
+				Stacks:       "goroutine 1 [running]:",
+			},
+			want: "[Fx] ERROR		OnStart hook hook.onStart called by bytes.NewBuffer timed out, goroutine stacks:\n" +
+				"goroutine 1 [running]:\n",
+		},
+		{
+			name: "OnStartSlow",
+			give: &OnStartSlow{
+				FunctionName: "hook.onStart",
+				CallerName:   "bytes.NewBuffer",
+				Elapsed:      10 * time.Second,
+			},
+			want: "[Fx] WARNING\t\tOnStart hook hook.onStart called by bytes.NewBuffer still running after 10s\n",
+		},
+		{
+			name: "DynamicHookAppended",
+			give: &DynamicHookAppended{
+				CallerName:  "bytes.NewBuffer",
+				OnStartName: "hook.onStart",
+				OnStopName:  "hook.onStop",
+			},
+			want: "[Fx] HOOK		appended during start (caller: bytes.NewBuffer)\n" +
+				"	OnStart: hook.onStart\n" +
+				"	OnStop: hook.onStop\n",
+		},
+		{
+			name: "DynamicHookAppended/OnStopOnly",
+			give: &DynamicHookAppended{
+				CallerName: "bytes.NewBuffer",
+				OnStopName: "hook.onStop",
+			},
+			want: "[Fx] HOOK		appended during start (caller: bytes.NewBuffer)\n" +
+				"	OnStop: hook.onStop\n",
+		},
+		{
+			name: "ModuleStarted",
+			give: &ModuleStarted{ModuleName: "server", Runtime: time.Millisecond},
+			want: "[Fx] MODULE	server started in 1ms\n",
+		},
+		{
+			name: "ModuleStopped",
+			give: &ModuleStopped{ModuleName: "server", Runtime: time.Second},
+			want: "[Fx] MODULE	server stopped in 1s\n",
+		},
+		{
+			name: "LintWarning",
+			give: &LintWarning{
+				Rule:    "too-many-params",
+				Message: "bytes.NewBuffer() has 12 parameters",
+			},
+			want: "[Fx] WARNING	too-many-params: bytes.NewBuffer() has 12 parameters\n",
+		},
+		{
+			name: "LintWarningWithModule",
+			give: &LintWarning{
+				Rule:       "too-many-params",
+				Message:    "bytes.NewBuffer() has 12 parameters",
+				ModuleName: "myModule",
+			},
+			want: "[Fx] WARNING	too-many-params: bytes.NewBuffer() has 12 parameters from module \"myModule\"\n",
+		},
+		{
+			name: "ShutdownScheduled",
+			give: &ShutdownScheduled{Deadline: deadline, ExitCode: 3},
+			want: "[Fx] SHUTDOWN	Scheduled at 2024-10-16 12:00:00 +0000 UTC with exit code 3\n",
+		},
+		{
+			name: "ShutdownCanceled",
+			give: &ShutdownCanceled{Deadline: deadline},
+			want: "[Fx] SHUTDOWN	Canceled shutdown scheduled at 2024-10-16 12:00:00 +0000 UTC\n",
+		},
+		{
+			name: "ShutdownFired",
+			give: &ShutdownFired{Deadline: deadline},
+			want: "[Fx] SHUTDOWN	Shutting down as scheduled at 2024-10-16 12:00:00 +0000 UTC with exit code 0\n",
+		},
+		{
+			name: "ShutdownFiredError",
+			give: &ShutdownFired{Deadline: deadline, Err: errors.New("some error")},
+			want: "[Fx] ERROR		Failed to shut down as scheduled at 2024-10-16 12:00:00 +0000 UTC: some error\n",
+		},
+		{
+			name: "OptionError",
+			give: &OptionError{
+				Err:         errors.New("some error"),
+				ModuleTrace: []string{"main.main (main.go:12)"},
+			},
+			want: "[Fx] ERROR\t\tfx.Error from main.main (main.go:12): some error\n",
+		},
+		{
+			name: "OptionErrorWithModule",
+			give: &OptionError{
+				Err:         errors.New("some error"),
+				ModuleName:  "myModule",
+				ModuleTrace: []string{"main.module (main.go:12)", "main.main (main.go:20)"},
+			},
+			want: "[Fx] ERROR\t\tfx.Error from main.module (main.go:12) in module \"myModule\": some error\n",
+		},
+		{
+			name: "Flushing",
+			give: &Flushing{},
+			want: "[Fx] FLUSHING\n",
+		},
+		{
+			name: "CronJobExecuted",
+			give: &CronJobExecuted{JobName: "cleanup", ScheduledAt: deadline, Runtime: time.Second},
+			want: "[Fx] CRON\t\t\"cleanup\" scheduled at 2024-10-16 12:00:00 +0000 UTC ran in 1s\n",
+		},
+		{
+			name: "CronJobExecutedError",
+			give: &CronJobExecuted{JobName: "cleanup", ScheduledAt: deadline, Runtime: time.Second, Err: errors.New("some error")},
+			want: "[Fx] ERROR\t\tCron job \"cleanup\" scheduled at 2024-10-16 12:00:00 +0000 UTC failed after 1s: some error\n",
+		},
+		{
+			name: "ModuleConflict",
+			give: &ModuleConflict{
+				ModuleName: "logging",
+				Modules:    []string{`"logging" v1 from a.go:1`, `"logging" v2 from b.go:2`},
+			},
+			want: "[Fx] WARNING\t2 modules are named \"logging\":\n\t\"logging\" v1 from a.go:1\n\t\"logging\" v2 from b.go:2\n",
+		},
+		{
+			name: "LoggerPanicked",
+			give: &LoggerPanicked{
+				LoggerType: "*fxevent.ZapLogger",
+				EventType:  "*fxevent.Started",
+				Err:        errors.New("panic: great sadness"),
+				Stack:      "main.main()",
+			},
+			want: "[Fx] ERROR\t\tLogger *fxevent.ZapLogger panicked while logging *fxevent.Started: panic: great sadness\nmain.main()\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -417,3 +726,16 @@ func TestConsoleLogger(t *testing.T) {
 func joinLines(lines ...string) string {
 	return strings.Join(lines, "\n") + "\n"
 }
+
+func TestConsoleLoggerAppName(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := &ConsoleLogger{W: &buf}
+	l.LogEvent(&Started{Metadata: Metadata{AppName: "payments-api"}})
+	l.LogEvent(&Started{})
+	assert.Equal(t, joinLines(
+		"[Fx payments-api] RUNNING\tstarted in 0s after 0s of initialization, ran 0 constructors and 0 OnStart hooks",
+		"[Fx] RUNNING\tstarted in 0s after 0s of initialization, ran 0 constructors and 0 OnStart hooks",
+	), buf.String())
+}
@@ -70,4 +70,21 @@
 // The events contain enough information for observability and debugging purposes.
 // If you need more information in them,
 // feel free to open an issue to discuss the addition.
+//
+// # Shipping Events Across Processes
+//
+// Use [Marshal] to encode an event as JSON,
+// and [Unmarshal] to decode it in another process
+// and replay it into any [Logger].
+// For example, a supervisor can collect events from its children
+// and log them centrally.
+//
+//	// In the child:
+//	data, err := fxevent.Marshal(e)
+//
+//	// In the supervisor:
+//	e, err := fxevent.Unmarshal(data)
+//	if err == nil {
+//		logger.LogEvent(e)
+//	}
 package fxevent
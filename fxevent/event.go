@@ -21,33 +21,116 @@
 package fxevent
 
 import (
+	"fmt"
 	"os"
+	"reflect"
 	"time"
 )
 
 // Event defines an event emitted by fx.
 type Event interface {
 	event() // Only fxlog can implement this interface.
+	metadata() *Metadata
+}
+
+// Metadata describes when and by which application an event was emitted.
+// Every event embeds it, and Fx fills it in when it emits the event.
+type Metadata struct {
+	// AppName is the name of the application that emitted the event, if any.
+	AppName string
+
+	// Seq orders the events emitted by the application, starting at 1.
+	// Sequence numbers increase with every event,
+	// so that events recorded by several loggers,
+	// which may batch or reorder them, can be put back in order.
+	Seq uint64
+
+	// Timestamp is the time at which the application emitted the event.
+	Timestamp time.Time
+}
+
+func (m *Metadata) metadata() *Metadata { return m }
+
+// MetadataOf returns the metadata of e, which may be modified,
+// or nil if e is a nil pointer.
+func MetadataOf(e Event) *Metadata {
+	if e == nil {
+		return nil
+	}
+	if v := reflect.ValueOf(e); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+	return e.metadata()
+}
+
+// appName returns the name of the application that emitted e, if any.
+func appName(e Event) string {
+	if m := MetadataOf(e); m != nil {
+		return m.AppName
+	}
+	return ""
+}
+
+// Seq returns the sequence number of e among the events emitted
+// by its application, or 0 if e wasn't emitted by an application.
+// See [Metadata.Seq].
+func Seq(e Event) uint64 {
+	if m := MetadataOf(e); m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+// Timestamp returns the time at which the application emitted e,
+// or the zero time if e wasn't emitted by an application.
+func Timestamp(e Event) time.Time {
+	if m := MetadataOf(e); m != nil {
+		return m.Timestamp
+	}
+	return time.Time{}
 }
 
 // Passing events by type to make Event hashable in the future.
-func (*OnStartExecuting) event()  {}
-func (*OnStartExecuted) event()   {}
-func (*OnStopExecuting) event()   {}
-func (*OnStopExecuted) event()    {}
-func (*Supplied) event()          {}
-func (*Provided) event()          {}
-func (*Replaced) event()          {}
-func (*Decorated) event()         {}
-func (*Run) event()               {}
-func (*Invoking) event()          {}
-func (*Invoked) event()           {}
-func (*Stopping) event()          {}
-func (*Stopped) event()           {}
-func (*RollingBack) event()       {}
-func (*RolledBack) event()        {}
-func (*Started) event()           {}
-func (*LoggerInitialized) event() {}
+func (*OnStartExecuting) event()    {}
+func (*OnStartExecuted) event()     {}
+func (*OnStopExecuting) event()     {}
+func (*OnStopExecuted) event()      {}
+func (*OnPauseExecuting) event()    {}
+func (*OnPauseExecuted) event()     {}
+func (*OnResumeExecuting) event()   {}
+func (*OnResumeExecuted) event()    {}
+func (*Configured) event()          {}
+func (*Supplied) event()            {}
+func (*Provided) event()            {}
+func (*Replaced) event()            {}
+func (*Decorated) event()           {}
+func (*DecoratorChain) event()      {}
+func (*Run) event()                 {}
+func (*Retrying) event()            {}
+func (*OnStartRetrying) event()     {}
+func (*Invoking) event()            {}
+func (*Invoked) event()             {}
+func (*Stopping) event()            {}
+func (*Stopped) event()             {}
+func (*RollingBack) event()         {}
+func (*RolledBack) event()          {}
+func (*Started) event()             {}
+func (*StartSummary) event()        {}
+func (*LoggerInitialized) event()   {}
+func (*HookTimedOut) event()        {}
+func (*OnStartSlow) event()         {}
+func (*DynamicHookAppended) event() {}
+func (*ModuleStarted) event()       {}
+func (*ModuleStopped) event()       {}
+func (*LintWarning) event()         {}
+func (*ShutdownScheduled) event()   {}
+func (*ShutdownCanceled) event()    {}
+func (*ShutdownFired) event()       {}
+func (*OptionError) event()         {}
+func (*Flushing) event()            {}
+func (*CronJobExecuted) event()     {}
+func (*ModuleConflict) event()      {}
+func (*LoggerPanicked) event()      {}
 
 // OnStartExecuting is emitted before an OnStart hook is executed.
 type OnStartExecuting struct {
@@ -57,6 +140,8 @@ type OnStartExecuting struct {
 	// CallerName is the name of the function that scheduled the hook for
 	// execution.
 	CallerName string
+
+	Metadata
 }
 
 // OnStartExecuted is emitted after an OnStart hook has been executed.
@@ -77,6 +162,8 @@ type OnStartExecuted struct {
 
 	// Err is non-nil if the hook failed to execute.
 	Err error
+
+	Metadata
 }
 
 // OnStopExecuting is emitted before an OnStop hook is executed.
@@ -87,6 +174,8 @@ type OnStopExecuting struct {
 	// CallerName is the name of the function that scheduled the hook for
 	// execution.
 	CallerName string
+
+	Metadata
 }
 
 // OnStopExecuted is emitted after an OnStop hook has been executed.
@@ -103,6 +192,87 @@ type OnStopExecuted struct {
 
 	// Err is non-nil if the hook failed to execute.
 	Err error
+
+	Metadata
+}
+
+// OnPauseExecuting is emitted before an OnPause hook is executed.
+type OnPauseExecuting struct {
+	// FunctionName is the name of the function that will be executed.
+	FunctionName string
+
+	// CallerName is the name of the function that scheduled the hook for
+	// execution.
+	CallerName string
+
+	Metadata
+}
+
+// OnPauseExecuted is emitted after an OnPause hook has been executed.
+type OnPauseExecuted struct {
+	// FunctionName is the name of the function that was executed.
+	FunctionName string
+
+	// CallerName is the name of the function that scheduled the hook for
+	// execution.
+	CallerName string
+
+	// Runtime specifies how long it took to run this hook.
+	Runtime time.Duration
+
+	// Err is non-nil if the hook failed to execute.
+	Err error
+
+	Metadata
+}
+
+// OnResumeExecuting is emitted before an OnResume hook is executed.
+type OnResumeExecuting struct {
+	// FunctionName is the name of the function that will be executed.
+	FunctionName string
+
+	// CallerName is the name of the function that scheduled the hook for
+	// execution.
+	CallerName string
+
+	Metadata
+}
+
+// OnResumeExecuted is emitted after an OnResume hook has been executed.
+type OnResumeExecuted struct {
+	// FunctionName is the name of the function that was executed.
+	FunctionName string
+
+	// CallerName is the name of the function that scheduled the hook for
+	// execution.
+	CallerName string
+
+	// Runtime specifies how long it took to run this hook.
+	Runtime time.Duration
+
+	// Err is non-nil if the hook failed to execute.
+	Err error
+
+	Metadata
+}
+
+// Configured is emitted when an application is constructed,
+// with the settings that it was configured with.
+type Configured struct {
+	// StartTimeout is the timeout for starting the application.
+	StartTimeout time.Duration
+
+	// StopTimeout is the timeout for stopping the application.
+	StopTimeout time.Duration
+
+	// Clock describes the clock used by the application for timeouts.
+	// It is "system" unless the clock was replaced.
+	Clock string
+
+	// RecoverFromPanics is true if fx.RecoverFromPanics was used.
+	RecoverFromPanics bool
+
+	Metadata
 }
 
 // Supplied is emitted after a value is added with fx.Supply.
@@ -121,6 +291,8 @@ type Supplied struct {
 
 	// Err is non-nil if we failed to supply the value.
 	Err error
+
+	Metadata
 }
 
 // Provided is emitted when a constructor is provided to Fx.
@@ -139,6 +311,11 @@ type Provided struct {
 	// this constructor.
 	OutputTypeNames []string
 
+	// InputTypeNames is a list of names of types that this constructor
+	// depends on, annotated with whether they are optional, named,
+	// or value groups.
+	InputTypeNames []string
+
 	// ModuleName is the name of the module in which the constructor was
 	// provided to.
 	ModuleName string
@@ -148,6 +325,8 @@ type Provided struct {
 
 	// Private denotes whether the provided constructor is a [Private] constructor.
 	Private bool
+
+	Metadata
 }
 
 // Replaced is emitted when a value replaces a type in Fx.
@@ -166,6 +345,8 @@ type Replaced struct {
 
 	// Err is non-nil if we failed to supply the value.
 	Err error
+
+	Metadata
 }
 
 // Decorated is emitted when a decorator is executed in Fx.
@@ -189,6 +370,26 @@ type Decorated struct {
 
 	// Err is non-nil if we failed to run this decorator.
 	Err error
+
+	Metadata
+}
+
+// DecoratorChain is emitted after all decorators have been applied
+// for each type that is decorated more than once in a module,
+// by decorators of the module and of modules containing it.
+type DecoratorChain struct {
+	// TypeName is the name of the decorated type.
+	TypeName string
+
+	// ModuleName is the name of the innermost module that decorates the
+	// type. It is empty for the top-level application.
+	ModuleName string
+
+	// DecoratorNames lists the decorators of the type in the order they are
+	// applied: decorators of outer modules come before those of inner ones.
+	DecoratorNames []string
+
+	Metadata
 }
 
 // Run is emitted after a constructor, decorator, or supply/replace stub is run by Fx.
@@ -209,6 +410,64 @@ type Run struct {
 	// Err is non-nil if the function returned an error.
 	// If fx.RecoverFromPanics is used, this will include panics.
 	Err error
+
+	// DemandPath explains why a constructor was run
+	// if the application uses fx.RecordDemandPaths.
+	// It starts with the function passed to fx.Invoke that needed it,
+	// followed by the constructors that needed each next one,
+	// and ends with the constructor that was run.
+	DemandPath []string
+
+	Metadata
+}
+
+// Retrying is emitted when a constructor provided with fx.ProvideWithRetry
+// fails and is about to be retried.
+type Retrying struct {
+	// ConstructorName is the name of the constructor that failed.
+	ConstructorName string
+
+	// ModuleName is the name of the module in which the constructor was
+	// provided, if any.
+	ModuleName string
+
+	// Attempt is the number of the attempt that failed, starting at 1.
+	Attempt int
+
+	// Attempts is the maximum number of attempts.
+	Attempts int
+
+	// Delay is how long Fx waits before the next attempt.
+	Delay time.Duration
+
+	// Err is the error returned by the failed attempt.
+	Err error
+
+	Metadata
+}
+
+// OnStartRetrying is emitted when an OnStart hook annotated with
+// fx.OnStartRetry fails and is about to be retried.
+type OnStartRetrying struct {
+	// FunctionName is the name of the hook function that failed.
+	FunctionName string
+
+	// CallerName is the name of the annotated function that appended the hook.
+	CallerName string
+
+	// Attempt is the number of the attempt that failed, starting at 1.
+	Attempt int
+
+	// Attempts is the maximum number of attempts.
+	Attempts int
+
+	// Delay is how long Fx waits before the next attempt.
+	Delay time.Duration
+
+	// Err is the error returned by the failed attempt.
+	Err error
+
+	Metadata
 }
 
 // Invoking is emitted before we invoke a function specified with fx.Invoke.
@@ -218,6 +477,8 @@ type Invoking struct {
 
 	// ModuleName is the name of the module in which the value was added to.
 	ModuleName string
+
+	Metadata
 }
 
 // Invoked is emitted after we invoke a function specified with fx.Invoke,
@@ -235,6 +496,12 @@ type Invoked struct {
 	// Trace records information about where the fx.Invoke call was made.
 	// Note that this is NOT a stack trace of the error itself.
 	Trace string
+
+	// Try is set if the function was passed to fx.TryInvoke,
+	// in which case Err doesn't stop the application.
+	Try bool
+
+	Metadata
 }
 
 // Started is emitted when an application is started successfully and/or it
@@ -242,6 +509,69 @@ type Invoked struct {
 type Started struct {
 	// Err is non-nil if the application failed to start successfully.
 	Err error
+
+	// Runtime is how long the application took to start,
+	// not including the time spent in fx.New.
+	Runtime time.Duration
+
+	// InitRuntime is how long fx.New took to build the application.
+	// Together with Runtime, it's the total startup duration.
+	InitRuntime time.Duration
+
+	// ConstructorCount is the number of constructors that were run
+	// when the application started.
+	ConstructorCount int
+
+	// HookCount is the number of OnStart hooks that were run,
+	// including any that failed.
+	HookCount int
+
+	Metadata
+}
+
+// StartSummary is emitted after Started when the heap allocations of
+// constructors are reported with fx.ReportConstructorAllocations.
+type StartSummary struct {
+	// Constructors holds the heap allocations of the constructors that ran
+	// so far, from the one that allocated the most bytes to the least.
+	Constructors []ConstructorAllocations
+
+	Metadata
+}
+
+// ConstructorAllocations are the heap allocations made by a constructor
+// while it ran, as reported by [StartSummary].
+// They include allocations made concurrently by other goroutines.
+type ConstructorAllocations struct {
+	// Name is the name of the constructor.
+	Name string
+
+	// ModuleName is the name of the module in which the constructor was
+	// provided, if any.
+	ModuleName string
+
+	// Bytes is the number of bytes allocated.
+	Bytes uint64
+
+	// Objects is the number of objects allocated.
+	Objects uint64
+}
+
+func (a ConstructorAllocations) String() string {
+	var moduleStr string
+	if a.ModuleName != "" {
+		moduleStr = fmt.Sprintf(" from module %q", a.ModuleName)
+	}
+	return fmt.Sprintf("%v%v allocated %d bytes in %d objects", a.Name, moduleStr, a.Bytes, a.Objects)
+}
+
+// allocationStrings formats the given allocations for logging.
+func allocationStrings(allocs []ConstructorAllocations) []string {
+	strs := make([]string, len(allocs))
+	for i, a := range allocs {
+		strs[i] = a.String()
+	}
+	return strs
 }
 
 // Stopping is emitted when the application receives a signal to shut down
@@ -250,6 +580,8 @@ type Started struct {
 type Stopping struct {
 	// Signal is the signal that caused this shutdown.
 	Signal os.Signal
+
+	Metadata
 }
 
 // Stopped is emitted when the application has finished shutting down, whether
@@ -257,6 +589,15 @@ type Stopping struct {
 type Stopped struct {
 	// Err is non-nil if errors were encountered during shutdown.
 	Err error
+
+	// Runtime is how long the application took to stop.
+	Runtime time.Duration
+
+	// HookCount is the number of OnStop hooks that were run,
+	// including any that failed.
+	HookCount int
+
+	Metadata
 }
 
 // RollingBack is emitted when the application failed to start up due to an
@@ -264,6 +605,8 @@ type Stopped struct {
 type RollingBack struct {
 	// StartErr is the error that caused this rollback.
 	StartErr error
+
+	Metadata
 }
 
 // RolledBack is emitted after a service has been rolled back, whether it
@@ -271,6 +614,8 @@ type RollingBack struct {
 type RolledBack struct {
 	// Err is non-nil if the rollback failed.
 	Err error
+
+	Metadata
 }
 
 // LoggerInitialized is emitted when a logger supplied with fx.WithLogger is
@@ -282,4 +627,233 @@ type LoggerInitialized struct {
 
 	// Err is non-nil if the logger failed to build.
 	Err error
+
+	Metadata
+}
+
+// HookTimedOut is emitted when an application fails to start or stop in
+// time while a hook is still executing, and stack dumps were requested with
+// fx.DumpStacksOnTimeout.
+type HookTimedOut struct {
+	// Method specifies the kind of the hook. This is one of "OnStart" and
+	// "OnStop".
+	Method string
+
+	// FunctionName is the name of the hook function that was executing
+	// when the timeout elapsed.
+	FunctionName string
+
+	// CallerName is the name of the function that scheduled the hook for
+	// execution.
+	CallerName string
+
+	// HookStacks holds the stacks of goroutines that were executing a
+	// lifecycle hook when the timeout elapsed.
+	HookStacks []string
+
+	// Stacks holds the stacks of all goroutines in the process, as
+	// formatted by runtime.Stack.
+	Stacks string
+
+	Metadata
+}
+
+// OnStartSlow is emitted periodically while an OnStart hook runs for longer
+// than the threshold set with fx.SlowHookThreshold,
+// so that slow hooks can be identified before the start times out.
+type OnStartSlow struct {
+	// FunctionName is the name of the hook function that is still running.
+	FunctionName string
+
+	// CallerName is the name of the function that scheduled the hook for
+	// execution.
+	CallerName string
+
+	// Elapsed is how long the hook has been running.
+	Elapsed time.Duration
+
+	Metadata
+}
+
+// DynamicHookAppended is emitted when a hook is appended to the lifecycle
+// while the application is starting, typically from within an OnStart hook.
+// The hook runs after the hooks that were already appended,
+// and its OnStop function runs on shutdown like any other.
+type DynamicHookAppended struct {
+	// CallerName is the name of the function that appended the hook.
+	CallerName string
+
+	// OnStartName is the name of the hook's OnStart function, if any.
+	OnStartName string
+
+	// OnStopName is the name of the hook's OnStop function, if any.
+	OnStopName string
+
+	Metadata
+}
+
+// ModuleStarted is emitted while the application starts, once the OnStart
+// hooks appended by a module and by the modules it contains have all run
+// successfully. Modules without OnStart hooks are reported started as soon
+// as the application begins starting.
+//
+// A module that contains other modules is reported after them.
+type ModuleStarted struct {
+	// ModuleName is the name of the module.
+	ModuleName string
+
+	// Runtime is the time spent running the OnStart hooks of the module.
+	Runtime time.Duration
+
+	Metadata
+}
+
+// ModuleStopped is emitted while the application stops, once the OnStop
+// hooks appended by a module and by the modules it contains have all run,
+// whether they succeeded or not. Modules without OnStop hooks to run are
+// reported stopped as soon as the application begins stopping.
+//
+// A module that contains other modules is reported after them.
+type ModuleStopped struct {
+	// ModuleName is the name of the module.
+	ModuleName string
+
+	// Runtime is the time spent running the OnStop hooks of the module.
+	Runtime time.Duration
+
+	Metadata
+}
+
+// LintWarning is emitted when fx.Lint is used and Fx finds a suspicious
+// pattern in the application. Warnings do not fail the application.
+type LintWarning struct {
+	// Rule identifies the check that produced this warning,
+	// e.g. "too-many-params".
+	Rule string
+
+	// Message describes the problem.
+	Message string
+
+	// FunctionName is the name of the function that the warning is about,
+	// if any.
+	FunctionName string
+
+	// ModuleName is the name of the module in which the function was
+	// provided, if any.
+	ModuleName string
+
+	Metadata
+}
+
+// ShutdownScheduled is emitted when a shutdown of the application is
+// scheduled with fx.ShutdownScheduler.
+type ShutdownScheduled struct {
+	// Deadline is the time at which the application will be shut down.
+	Deadline time.Time
+
+	// ExitCode is the exit code the application will be shut down with.
+	ExitCode int
+
+	Metadata
+}
+
+// ShutdownCanceled is emitted when a scheduled shutdown of the application
+// is canceled before its deadline, either explicitly or because the
+// application was stopped.
+type ShutdownCanceled struct {
+	// Deadline is the time at which the application would have been shut
+	// down.
+	Deadline time.Time
+
+	Metadata
+}
+
+// ShutdownFired is emitted when the deadline of a scheduled shutdown is
+// reached and the application is being shut down.
+type ShutdownFired struct {
+	// Deadline is the time at which the shutdown was scheduled.
+	Deadline time.Time
+
+	// ExitCode is the exit code the application is shut down with.
+	ExitCode int
+
+	// Err is non-nil if the shutdown signal could not be delivered.
+	Err error
+
+	Metadata
+}
+
+// OptionError is emitted for each error registered with fx.Error.
+type OptionError struct {
+	// Err is the error passed to fx.Error.
+	Err error
+
+	// ModuleName is the name of the module fx.Error was passed to, if any.
+	ModuleName string
+
+	// ModuleTrace contains the call site of fx.Error,
+	// followed by the modules it was passed to.
+	ModuleTrace []string
+
+	// StackTrace is the stack trace of the call to fx.Error.
+	StackTrace []string
+
+	Metadata
+}
+
+// Flushing is emitted before Fx flushes a logger that implements [Flusher],
+// after the application stopped or failed to start.
+// It is the last event emitted for that run of the application.
+type Flushing struct {
+	Metadata
+}
+
+// CronJobExecuted is emitted after a job scheduled by the fxcron package
+// runs.
+type CronJobExecuted struct {
+	// JobName is the name of the job.
+	JobName string
+
+	// ScheduledAt is the time the job was scheduled to run at.
+	ScheduledAt time.Time
+
+	// Runtime is how long the job ran for.
+	Runtime time.Duration
+
+	// Err is the error returned by the job, if any.
+	Err error
+
+	Metadata
+}
+
+// ModuleConflict is emitted when fx.OnModuleConflict(fx.WarnOnModuleConflict)
+// is used and the application includes more than one module with the same
+// name.
+type ModuleConflict struct {
+	// ModuleName is the name shared by the conflicting modules.
+	ModuleName string
+
+	// Modules identifies each of the conflicting modules by name, version
+	// (if any), and the location where it was declared.
+	Modules []string
+
+	Metadata
+}
+
+// LoggerPanicked is emitted by the logger returned by [Tee]
+// to the other loggers when one of them panics while logging an event.
+type LoggerPanicked struct {
+	// LoggerType is the type of the logger that panicked.
+	LoggerType string
+
+	// EventType is the type of the event it was logging.
+	EventType string
+
+	// Err describes the value of the panic.
+	Err error
+
+	// Stack is the stack trace of the goroutine when the logger panicked.
+	Stack string
+
+	Metadata
 }
@@ -22,7 +22,9 @@ package fxevent
 
 import (
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/goleak"
 )
 
@@ -35,11 +37,19 @@ func TestForCoverage(t *testing.T) {
 		&OnStartExecuted{},
 		&OnStopExecuting{},
 		&OnStopExecuted{},
+		&OnPauseExecuting{},
+		&OnPauseExecuted{},
+		&OnResumeExecuting{},
+		&OnResumeExecuted{},
+		&Configured{},
 		&Supplied{},
 		&Provided{},
 		&Replaced{},
 		&Decorated{},
+		&DecoratorChain{},
 		&Run{},
+		&Retrying{},
+		&OnStartRetrying{},
 		&Invoking{},
 		&Invoked{},
 		&Stopping{},
@@ -47,7 +57,22 @@ func TestForCoverage(t *testing.T) {
 		&RollingBack{},
 		&RolledBack{},
 		&Started{},
+		&StartSummary{},
 		&LoggerInitialized{},
+		&HookTimedOut{},
+		&OnStartSlow{},
+		&DynamicHookAppended{},
+		&ModuleStarted{},
+		&ModuleStopped{},
+		&LintWarning{},
+		&ShutdownScheduled{},
+		&ShutdownCanceled{},
+		&ShutdownFired{},
+		&OptionError{},
+		&Flushing{},
+		&CronJobExecuted{},
+		&ModuleConflict{},
+		&LoggerPanicked{},
 	}
 
 	for _, e := range events {
@@ -55,6 +80,21 @@ func TestForCoverage(t *testing.T) {
 	}
 }
 
+func TestSeqAndTimestamp(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2024, 10, 16, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, uint64(42), Seq(&Invoking{Metadata: Metadata{Seq: 42}}))
+	assert.Equal(t, ts, Timestamp(&Started{Metadata: Metadata{Timestamp: ts}}))
+
+	assert.Zero(t, Seq(&Invoking{}))
+	assert.True(t, Timestamp(&Invoking{}).IsZero())
+
+	var nilEvent *Invoking
+	assert.Zero(t, Seq(nilEvent))
+	assert.True(t, Timestamp(nilEvent).IsZero())
+}
+
 func TestMain(m *testing.M) {
 	goleak.VerifyTestMain(m)
 }
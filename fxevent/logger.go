@@ -26,6 +26,16 @@ type Logger interface {
 	LogEvent(Event)
 }
 
+// Flusher is implemented by loggers that buffer events.
+//
+// Fx flushes its logger when the application stops or fails to start,
+// after emitting a [Flushing] event,
+// so that the last events of the application are not lost.
+type Flusher interface {
+	// Flush writes buffered events to their destination.
+	Flush() error
+}
+
 // NopLogger is an Fx event logger that ignores all messages.
 var NopLogger = nopLogger{}
 
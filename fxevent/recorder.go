@@ -0,0 +1,95 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxevent
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Func is an adapter to use an ordinary function as a [Logger].
+//
+//	fx.WithLogger(func(m *Metrics) fxevent.Logger {
+//		return fxevent.Func(func(e fxevent.Event) {
+//			m.CountEvent(e)
+//		})
+//	})
+type Func func(Event)
+
+var _ Logger = Func(nil)
+
+// LogEvent calls f(event).
+func (f Func) LogEvent(event Event) { f(event) }
+
+// Recorder is a [Logger] that records the events it receives,
+// for use in tests of code that emits or consumes Fx events.
+//
+//	var rec fxevent.Recorder
+//	app := fxtest.New(t,
+//		fx.WithLogger(func() fxevent.Logger { return &rec }),
+//		...
+//	)
+//	assert.Contains(t, rec.EventTypes(), "Started")
+//
+// The zero value is ready to use. A Recorder is safe for concurrent use.
+type Recorder struct {
+	mu     sync.RWMutex
+	events []Event
+}
+
+var _ Logger = (*Recorder)(nil)
+
+// LogEvent records the event.
+func (r *Recorder) LogEvent(event Event) {
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+// Events returns the recorded events, in the order they were received.
+func (r *Recorder) Events() []Event {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := make([]Event, len(r.events))
+	copy(events, r.events)
+	return events
+}
+
+// EventTypes returns the names of the types of the recorded events,
+// such as "Started", in the order they were received.
+func (r *Recorder) EventTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]string, len(r.events))
+	for i, e := range r.events {
+		types[i] = reflect.TypeOf(e).Elem().Name()
+	}
+	return types
+}
+
+// Reset discards the recorded events.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.events = nil
+	r.mu.Unlock()
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxevent
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunc(t *testing.T) {
+	t.Parallel()
+
+	var got []Event
+	var log Logger = Func(func(e Event) { got = append(got, e) })
+
+	log.LogEvent(&Started{})
+	log.LogEvent(&Stopped{})
+	assert.Equal(t, []Event{&Started{}, &Stopped{}}, got)
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Records", func(t *testing.T) {
+		t.Parallel()
+
+		var rec Recorder
+		assert.Empty(t, rec.Events())
+
+		rec.LogEvent(&Started{})
+		rec.LogEvent(&Stopped{})
+		assert.Equal(t, []Event{&Started{}, &Stopped{}}, rec.Events())
+		assert.Equal(t, []string{"Started", "Stopped"}, rec.EventTypes())
+	})
+
+	t.Run("EventsIsACopy", func(t *testing.T) {
+		t.Parallel()
+
+		var rec Recorder
+		rec.LogEvent(&Started{})
+
+		events := rec.Events()
+		events[0] = &Stopped{}
+		assert.Equal(t, []string{"Started"}, rec.EventTypes())
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		t.Parallel()
+
+		var rec Recorder
+		rec.LogEvent(&Started{})
+		rec.Reset()
+		assert.Empty(t, rec.Events())
+
+		rec.LogEvent(&Stopped{})
+		assert.Equal(t, []string{"Stopped"}, rec.EventTypes())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			rec Recorder
+			wg  sync.WaitGroup
+		)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rec.LogEvent(&Started{})
+				_ = rec.Events()
+			}()
+		}
+		wg.Wait()
+		assert.Len(t, rec.Events(), 10)
+	})
+}
@@ -86,6 +86,19 @@ func (l *SlogLogger) logError(msg string, fields ...any) {
 
 // LogEvent logs the given event to the provided Zap logger.
 func (l *SlogLogger) LogEvent(event Event) {
+	var attrs []any
+	if name := appName(event); name != "" {
+		attrs = append(attrs, slog.String("app", name))
+	}
+	if seq := Seq(event); seq != 0 {
+		attrs = append(attrs, slog.Uint64("seq", seq))
+	}
+	if len(attrs) > 0 {
+		el := *l
+		el.Logger = l.Logger.With(attrs...)
+		l = &el
+	}
+
 	switch e := event.(type) {
 	case *OnStartExecuting:
 		l.logEvent("OnStart hook executing",
@@ -125,6 +138,51 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slog.String("runtime", e.Runtime.String()),
 			)
 		}
+	case *OnPauseExecuting:
+		l.logEvent("OnPause hook executing",
+			slog.String("callee", e.FunctionName),
+			slog.String("caller", e.CallerName),
+		)
+	case *OnPauseExecuted:
+		if e.Err != nil {
+			l.logError("OnPause hook failed",
+				slog.String("callee", e.FunctionName),
+				slog.String("caller", e.CallerName),
+				slogErr(e.Err),
+			)
+		} else {
+			l.logEvent("OnPause hook executed",
+				slog.String("callee", e.FunctionName),
+				slog.String("caller", e.CallerName),
+				slog.String("runtime", e.Runtime.String()),
+			)
+		}
+	case *OnResumeExecuting:
+		l.logEvent("OnResume hook executing",
+			slog.String("callee", e.FunctionName),
+			slog.String("caller", e.CallerName),
+		)
+	case *OnResumeExecuted:
+		if e.Err != nil {
+			l.logError("OnResume hook failed",
+				slog.String("callee", e.FunctionName),
+				slog.String("caller", e.CallerName),
+				slogErr(e.Err),
+			)
+		} else {
+			l.logEvent("OnResume hook executed",
+				slog.String("callee", e.FunctionName),
+				slog.String("caller", e.CallerName),
+				slog.String("runtime", e.Runtime.String()),
+			)
+		}
+	case *Configured:
+		l.logEvent("configured",
+			slog.String("starttimeout", e.StartTimeout.String()),
+			slog.String("stoptimeout", e.StopTimeout.String()),
+			slog.String("clock", e.Clock),
+			slog.Bool("recoverfrompanics", e.RecoverFromPanics),
+		)
 	case *Supplied:
 		if e.Err != nil {
 			l.logError("error encountered while applying options",
@@ -149,6 +207,7 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slogStrings("moduletrace", e.ModuleTrace),
 				slogMaybeModuleField(e.ModuleName),
 				slog.String("type", rtype),
+				slogMaybeStrings("params", e.InputTypeNames),
 				slogMaybeBool("private", e.Private),
 			)
 		}
@@ -192,6 +251,12 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slogMaybeModuleField(e.ModuleName),
 				slogErr(e.Err))
 		}
+	case *DecoratorChain:
+		l.logEvent("decorator chain",
+			slog.String("type", e.TypeName),
+			slogStrings("decorators", e.DecoratorNames),
+			slogMaybeModuleField(e.ModuleName),
+		)
 	case *Run:
 		if e.Err != nil {
 			l.logError("error returned",
@@ -206,8 +271,27 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slog.String("kind", e.Kind),
 				slog.String("runtime", e.Runtime.String()),
 				slogMaybeModuleField(e.ModuleName),
+				slogMaybeStrings("demand_path", e.DemandPath),
 			)
 		}
+	case *Retrying:
+		l.logError("constructor failed, retrying",
+			slog.String("constructor", e.ConstructorName),
+			slog.Int("attempt", e.Attempt),
+			slog.Int("attempts", e.Attempts),
+			slog.String("delay", e.Delay.String()),
+			slogMaybeModuleField(e.ModuleName),
+			slogErr(e.Err),
+		)
+	case *OnStartRetrying:
+		l.logError("OnStart hook failed, retrying",
+			slog.String("callee", e.FunctionName),
+			slog.String("caller", e.CallerName),
+			slog.Int("attempt", e.Attempt),
+			slog.Int("attempts", e.Attempts),
+			slog.String("delay", e.Delay.String()),
+			slogErr(e.Err),
+		)
 	case *Invoking:
 		// Do not log stack as it will make logs hard to read.
 		l.logEvent("invoking",
@@ -215,7 +299,14 @@ func (l *SlogLogger) LogEvent(event Event) {
 			slogMaybeModuleField(e.ModuleName),
 		)
 	case *Invoked:
-		if e.Err != nil {
+		if e.Err != nil && e.Try {
+			l.logEvent("best-effort invoke failed",
+				slogErr(e.Err),
+				slog.String("stack", e.Trace),
+				slog.String("function", e.FunctionName),
+				slogMaybeModuleField(e.ModuleName),
+			)
+		} else if e.Err != nil {
 			l.logError("invoke failed",
 				slogErr(e.Err),
 				slog.String("stack", e.Trace),
@@ -240,14 +331,118 @@ func (l *SlogLogger) LogEvent(event Event) {
 		if e.Err != nil {
 			l.logError("start failed", slogErr(e.Err))
 		} else {
-			l.logEvent("started")
+			l.logEvent("started",
+				slog.String("runtime", e.Runtime.String()),
+				slog.String("init_runtime", e.InitRuntime.String()),
+				slog.Int("constructors", e.ConstructorCount),
+				slog.Int("hooks", e.HookCount),
+			)
 		}
+	case *StartSummary:
+		l.logEvent("start summary",
+			slogStrings("constructors", allocationStrings(e.Constructors)),
+		)
 	case *LoggerInitialized:
 		if e.Err != nil {
 			l.logError("custom logger initialization failed", slogErr(e.Err))
 		} else {
 			l.logEvent("initialized custom fxevent.Logger", slog.String("function", e.ConstructorName))
 		}
+	case *HookTimedOut:
+		l.logError("hook timed out",
+			slog.String("method", e.Method),
+			slog.String("callee", e.FunctionName),
+			slog.String("caller", e.CallerName),
+			slogStrings("hookstacks", e.HookStacks),
+			slog.String("stacks", e.Stacks),
+		)
+	case *OnStartSlow:
+		l.logEvent("OnStart hook is slow",
+			slog.String("callee", e.FunctionName),
+			slog.String("caller", e.CallerName),
+			slog.String("elapsed", e.Elapsed.String()),
+		)
+	case *DynamicHookAppended:
+		l.logEvent("hook appended during start",
+			slog.String("caller", e.CallerName),
+			slogMaybeString("onstart", e.OnStartName),
+			slogMaybeString("onstop", e.OnStopName),
+		)
+	case *ModuleStarted:
+		l.logEvent("module started",
+			slogMaybeModuleField(e.ModuleName),
+			slog.String("runtime", e.Runtime.String()),
+		)
+	case *ModuleStopped:
+		l.logEvent("module stopped",
+			slogMaybeModuleField(e.ModuleName),
+			slog.String("runtime", e.Runtime.String()),
+		)
+	case *LintWarning:
+		l.logEvent("lint warning",
+			slog.String("rule", e.Rule),
+			slog.String("message", e.Message),
+			slogMaybeString("function", e.FunctionName),
+			slogMaybeModuleField(e.ModuleName),
+		)
+	case *ShutdownScheduled:
+		l.logEvent("shutdown scheduled",
+			slog.Time("deadline", e.Deadline),
+			slog.Int("exitcode", e.ExitCode),
+		)
+	case *ShutdownCanceled:
+		l.logEvent("scheduled shutdown canceled",
+			slog.Time("deadline", e.Deadline),
+		)
+	case *ShutdownFired:
+		if e.Err != nil {
+			l.logError("scheduled shutdown failed",
+				slog.Time("deadline", e.Deadline),
+				slog.Int("exitcode", e.ExitCode),
+				slogErr(e.Err),
+			)
+		} else {
+			l.logEvent("shutting down as scheduled",
+				slog.Time("deadline", e.Deadline),
+				slog.Int("exitcode", e.ExitCode),
+			)
+		}
+	case *OptionError:
+		l.logError("error option",
+			slogStrings("stacktrace", e.StackTrace),
+			slogStrings("moduletrace", e.ModuleTrace),
+			slogMaybeModuleField(e.ModuleName),
+			slogErr(e.Err),
+		)
+	case *Flushing:
+		l.logEvent("flushing logger")
+	case *CronJobExecuted:
+		if e.Err != nil {
+			l.logError("cron job failed",
+				slog.String("job", e.JobName),
+				slog.Time("scheduled", e.ScheduledAt),
+				slog.String("runtime", e.Runtime.String()),
+				slogErr(e.Err),
+			)
+		} else {
+			l.logEvent("cron job executed",
+				slog.String("job", e.JobName),
+				slog.Time("scheduled", e.ScheduledAt),
+				slog.String("runtime", e.Runtime.String()),
+			)
+		}
+	case *ModuleConflict:
+		l.logEvent("module conflict",
+			slog.String("module", e.ModuleName),
+			slogStrings("modules", e.Modules),
+		)
+	case *LoggerPanicked:
+		l.logError("logger panicked",
+			slog.String("logger", e.LoggerType),
+			slog.String("event", e.EventType),
+			slogErr(e.Err),
+			slog.String("stack", e.Stack),
+		)
 	}
 }
 
@@ -260,6 +455,20 @@ func slogMaybeModuleField(name string) slog.Attr {
 	return slog.String("module", name)
 }
 
+func slogMaybeString(name, value string) slog.Attr {
+	if len(value) == 0 {
+		return slog.Any(name, slogFieldSkip{})
+	}
+	return slog.String(name, value)
+}
+
+func slogMaybeStrings(key string, strs []string) slog.Attr {
+	if len(strs) == 0 {
+		return slog.Any(key, slogFieldSkip{})
+	}
+	return slogStrings(key, strs)
+}
+
 func slogMaybeBool(name string, b bool) slog.Attr {
 	if !b {
 		return slog.Any(name, slogFieldSkip{})
@@ -0,0 +1,131 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxevent
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// Tee returns a Logger that sends each event to all the given loggers,
+// in order.
+//
+//	fx.WithLogger(func(log *zap.Logger, m *Metrics) fxevent.Logger {
+//		return fxevent.Tee(
+//			&fxevent.ZapLogger{Logger: log},
+//			m.EventLogger(),
+//		)
+//	})
+//
+// A panic in one of the loggers does not prevent the others
+// from receiving the event: it is recovered,
+// and reported to the other loggers with a [LoggerPanicked] event
+// once the event was sent to all of them.
+//
+// The returned Logger implements [Flusher],
+// and flushes the given loggers that implement it.
+//
+// Nil loggers are ignored.
+// If only one logger remains, Tee returns it as-is.
+func Tee(loggers ...Logger) Logger {
+	var ls teeLogger
+	for _, l := range loggers {
+		switch l := l.(type) {
+		case nil:
+		case teeLogger:
+			ls = append(ls, l...)
+		default:
+			ls = append(ls, l)
+		}
+	}
+
+	switch len(ls) {
+	case 0:
+		return NopLogger
+	case 1:
+		return ls[0]
+	default:
+		return ls
+	}
+}
+
+type teeLogger []Logger
+
+var (
+	_ Logger  = teeLogger(nil)
+	_ Flusher = teeLogger(nil)
+)
+
+func (t teeLogger) LogEvent(event Event) {
+	type loggerPanic struct {
+		logger int // index of the logger that panicked
+		event  *LoggerPanicked
+	}
+	var panics []loggerPanic
+	for i, l := range t {
+		if p := logEventRecover(l, event); p != nil {
+			p.LoggerType = fmt.Sprintf("%T", l)
+			p.EventType = fmt.Sprintf("%T", event)
+			if m := MetadataOf(event); m != nil {
+				p.AppName = m.AppName
+				p.Timestamp = m.Timestamp
+			}
+			panics = append(panics, loggerPanic{logger: i, event: p})
+		}
+	}
+
+	// Report the panics to the other loggers once they received the event.
+	for _, p := range panics {
+		for i, l := range t {
+			if i != p.logger {
+				// Panics while reporting a panic are dropped.
+				logEventRecover(l, p.event)
+			}
+		}
+	}
+}
+
+// logEventRecover logs event to l, and reports the panic raised by l,
+// if any.
+func logEventRecover(l Logger, event Event) (panicked *LoggerPanicked) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = &LoggerPanicked{
+				Err:   fmt.Errorf("panic: %v", r),
+				Stack: string(debug.Stack()),
+			}
+		}
+	}()
+	l.LogEvent(event)
+	return nil
+}
+
+// Flush flushes all the loggers that implement Flusher.
+func (t teeLogger) Flush() error {
+	var errs []error
+	for _, l := range t {
+		if f, ok := l.(Flusher); ok {
+			errs = append(errs, f.Flush())
+		}
+	}
+	return errors.Join(errs...)
+}
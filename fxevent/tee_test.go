@@ -0,0 +1,142 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxevent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger []Event
+
+func (r *recordingLogger) LogEvent(e Event) { *r = append(*r, e) }
+
+type panicLogger struct{}
+
+type flushLogger struct {
+	recordingLogger
+	flushed int
+	err     error
+}
+
+func (f *flushLogger) Flush() error {
+	f.flushed++
+	return f.err
+}
+
+func (panicLogger) LogEvent(Event) { panic("great sadness") }
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SendsToAll", func(t *testing.T) {
+		t.Parallel()
+
+		var a, b recordingLogger
+		log := Tee(&a, &b)
+
+		log.LogEvent(&Started{})
+		log.LogEvent(&Stopped{})
+
+		want := []Event{&Started{}, &Stopped{}}
+		assert.Equal(t, want, []Event(a))
+		assert.Equal(t, want, []Event(b))
+	})
+
+	t.Run("PanicIsolation", func(t *testing.T) {
+		t.Parallel()
+
+		var a, b recordingLogger
+		log := Tee(&a, panicLogger{}, &b)
+
+		started := &Started{Metadata: Metadata{AppName: "payments-api"}}
+		assert.NotPanics(t, func() {
+			log.LogEvent(started)
+		})
+
+		for _, events := range [][]Event{a, b} {
+			require.Len(t, events, 2)
+			assert.Same(t, started, events[0])
+			p, ok := events[1].(*LoggerPanicked)
+			require.True(t, ok, "panic must be reported after the event")
+			assert.Equal(t, "fxevent.panicLogger", p.LoggerType)
+			assert.Equal(t, "*fxevent.Started", p.EventType)
+			assert.EqualError(t, p.Err, "panic: great sadness")
+			assert.Contains(t, p.Stack, "panicLogger.LogEvent")
+			assert.Equal(t, "payments-api", p.AppName)
+		}
+	})
+
+	t.Run("PanicWhileReporting", func(t *testing.T) {
+		t.Parallel()
+
+		log := Tee(panicLogger{}, panicLogger{})
+		assert.NotPanics(t, func() {
+			log.LogEvent(&Started{})
+		})
+	})
+
+	t.Run("Flatten", func(t *testing.T) {
+		t.Parallel()
+
+		var a, b, c recordingLogger
+		log := Tee(Tee(&a, &b), nil, &c)
+
+		assert.Len(t, log, 3)
+		log.LogEvent(&Started{})
+		assert.Len(t, a, 1)
+		assert.Len(t, b, 1)
+		assert.Len(t, c, 1)
+	})
+
+	t.Run("Flush", func(t *testing.T) {
+		t.Parallel()
+
+		var a recordingLogger
+		b := &flushLogger{err: errors.New("great sadness")}
+		c := &flushLogger{}
+		log := Tee(&a, b, c)
+
+		f, ok := log.(Flusher)
+		if assert.True(t, ok, "Tee must implement Flusher") {
+			assert.EqualError(t, f.Flush(), "great sadness")
+		}
+		assert.Equal(t, 1, b.flushed)
+		assert.Equal(t, 1, c.flushed)
+	})
+
+	t.Run("Single", func(t *testing.T) {
+		t.Parallel()
+
+		var a recordingLogger
+		assert.Same(t, &a, Tee(nil, &a))
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, NopLogger, Tee())
+		assert.Equal(t, NopLogger, Tee(nil))
+	})
+}
@@ -21,6 +21,7 @@
 package fxevent
 
 import (
+	"reflect"
 	"strings"
 
 	"go.uber.org/zap"
@@ -33,9 +34,19 @@ type ZapLogger struct {
 
 	logLevel   zapcore.Level // default: zapcore.InfoLevel
 	errorLevel *zapcore.Level
+
+	// Per-event-type overrides, keyed by the concrete event type.
+	eventLevels map[reflect.Type]zapcore.Level
+	dropped     map[reflect.Type]struct{}
+
+	// Set on the copy of the logger used for a single event.
+	drop bool
 }
 
-var _ Logger = (*ZapLogger)(nil)
+var (
+	_ Logger  = (*ZapLogger)(nil)
+	_ Flusher = (*ZapLogger)(nil)
+)
 
 // UseErrorLevel sets the level of error logs emitted by Fx to level.
 func (l *ZapLogger) UseErrorLevel(level zapcore.Level) {
@@ -47,7 +58,65 @@ func (l *ZapLogger) UseLogLevel(level zapcore.Level) {
 	l.logLevel = level
 }
 
+// UseEventLogLevel sets the level of non-error logs emitted by Fx for
+// events of the same type as event, overriding the level set with
+// UseLogLevel.
+//
+//	logger.UseEventLogLevel(&fxevent.Provided{}, zapcore.DebugLevel)
+func (l *ZapLogger) UseEventLogLevel(event Event, level zapcore.Level) {
+	if l.eventLevels == nil {
+		l.eventLevels = make(map[reflect.Type]zapcore.Level)
+	}
+	l.eventLevels[reflect.TypeOf(event)] = level
+}
+
+// DropEvents stops Fx from emitting non-error logs for events of the same
+// types as the given events. Errors reported by these events are still
+// logged.
+//
+//	logger.DropEvents(&fxevent.Provided{}, &fxevent.Run{})
+func (l *ZapLogger) DropEvents(events ...Event) {
+	if l.dropped == nil {
+		l.dropped = make(map[reflect.Type]struct{})
+	}
+	for _, e := range events {
+		l.dropped[reflect.TypeOf(e)] = struct{}{}
+	}
+}
+
+// forEvent returns the logger to use for the given event
+// after applying per-event-type overrides
+// and adding the name of the application and the sequence number
+// of the event, if any.
+func (l *ZapLogger) forEvent(event Event) *ZapLogger {
+	name, seq := appName(event), Seq(event)
+	if len(l.eventLevels) == 0 && len(l.dropped) == 0 && name == "" && seq == 0 {
+		return l
+	}
+
+	t := reflect.TypeOf(event)
+	el := *l
+	if lvl, ok := l.eventLevels[t]; ok {
+		el.logLevel = lvl
+	}
+	_, el.drop = l.dropped[t]
+	var fields []zap.Field
+	if name != "" {
+		fields = append(fields, zap.String("app", name))
+	}
+	if seq != 0 {
+		fields = append(fields, zap.Uint64("seq", seq))
+	}
+	if len(fields) > 0 {
+		el.Logger = l.Logger.With(fields...)
+	}
+	return &el
+}
+
 func (l *ZapLogger) logEvent(msg string, fields ...zap.Field) {
+	if l.drop {
+		return
+	}
 	l.Logger.Log(l.logLevel, msg, fields...)
 }
 
@@ -59,8 +128,15 @@ func (l *ZapLogger) logError(msg string, fields ...zap.Field) {
 	l.Logger.Log(lvl, msg, fields...)
 }
 
+// Flush syncs the underlying Zap logger.
+func (l *ZapLogger) Flush() error {
+	return l.Logger.Sync()
+}
+
 // LogEvent logs the given event to the provided Zap logger.
 func (l *ZapLogger) LogEvent(event Event) {
+	l = l.forEvent(event)
+
 	switch e := event.(type) {
 	case *OnStartExecuting:
 		l.logEvent("OnStart hook executing",
@@ -100,6 +176,51 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.String("runtime", e.Runtime.String()),
 			)
 		}
+	case *OnPauseExecuting:
+		l.logEvent("OnPause hook executing",
+			zap.String("callee", e.FunctionName),
+			zap.String("caller", e.CallerName),
+		)
+	case *OnPauseExecuted:
+		if e.Err != nil {
+			l.logError("OnPause hook failed",
+				zap.String("callee", e.FunctionName),
+				zap.String("caller", e.CallerName),
+				zap.Error(e.Err),
+			)
+		} else {
+			l.logEvent("OnPause hook executed",
+				zap.String("callee", e.FunctionName),
+				zap.String("caller", e.CallerName),
+				zap.String("runtime", e.Runtime.String()),
+			)
+		}
+	case *OnResumeExecuting:
+		l.logEvent("OnResume hook executing",
+			zap.String("callee", e.FunctionName),
+			zap.String("caller", e.CallerName),
+		)
+	case *OnResumeExecuted:
+		if e.Err != nil {
+			l.logError("OnResume hook failed",
+				zap.String("callee", e.FunctionName),
+				zap.String("caller", e.CallerName),
+				zap.Error(e.Err),
+			)
+		} else {
+			l.logEvent("OnResume hook executed",
+				zap.String("callee", e.FunctionName),
+				zap.String("caller", e.CallerName),
+				zap.String("runtime", e.Runtime.String()),
+			)
+		}
+	case *Configured:
+		l.logEvent("configured",
+			zap.String("starttimeout", e.StartTimeout.String()),
+			zap.String("stoptimeout", e.StopTimeout.String()),
+			zap.String("clock", e.Clock),
+			zap.Bool("recoverfrompanics", e.RecoverFromPanics),
+		)
 	case *Supplied:
 		if e.Err != nil {
 			l.logError("error encountered while applying options",
@@ -124,6 +245,7 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.Strings("moduletrace", e.ModuleTrace),
 				moduleField(e.ModuleName),
 				zap.String("type", rtype),
+				maybeStrings("params", e.InputTypeNames),
 				maybeBool("private", e.Private),
 			)
 		}
@@ -167,6 +289,12 @@ func (l *ZapLogger) LogEvent(event Event) {
 				moduleField(e.ModuleName),
 				zap.Error(e.Err))
 		}
+	case *DecoratorChain:
+		l.logEvent("decorator chain",
+			zap.String("type", e.TypeName),
+			zap.Strings("decorators", e.DecoratorNames),
+			moduleField(e.ModuleName),
+		)
 	case *Run:
 		if e.Err != nil {
 			l.logError("error returned",
@@ -181,8 +309,27 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.String("kind", e.Kind),
 				zap.String("runtime", e.Runtime.String()),
 				moduleField(e.ModuleName),
+				maybeStrings("demand_path", e.DemandPath),
 			)
 		}
+	case *Retrying:
+		l.logError("constructor failed, retrying",
+			zap.String("constructor", e.ConstructorName),
+			zap.Int("attempt", e.Attempt),
+			zap.Int("attempts", e.Attempts),
+			zap.String("delay", e.Delay.String()),
+			moduleField(e.ModuleName),
+			zap.Error(e.Err),
+		)
+	case *OnStartRetrying:
+		l.logError("OnStart hook failed, retrying",
+			zap.String("callee", e.FunctionName),
+			zap.String("caller", e.CallerName),
+			zap.Int("attempt", e.Attempt),
+			zap.Int("attempts", e.Attempts),
+			zap.String("delay", e.Delay.String()),
+			zap.Error(e.Err),
+		)
 	case *Invoking:
 		// Do not log stack as it will make logs hard to read.
 		l.logEvent("invoking",
@@ -190,7 +337,14 @@ func (l *ZapLogger) LogEvent(event Event) {
 			moduleField(e.ModuleName),
 		)
 	case *Invoked:
-		if e.Err != nil {
+		if e.Err != nil && e.Try {
+			l.logEvent("best-effort invoke failed",
+				zap.Error(e.Err),
+				zap.String("stack", e.Trace),
+				zap.String("function", e.FunctionName),
+				moduleField(e.ModuleName),
+			)
+		} else if e.Err != nil {
 			l.logError("invoke failed",
 				zap.Error(e.Err),
 				zap.String("stack", e.Trace),
@@ -215,14 +369,118 @@ func (l *ZapLogger) LogEvent(event Event) {
 		if e.Err != nil {
 			l.logError("start failed", zap.Error(e.Err))
 		} else {
-			l.logEvent("started")
+			l.logEvent("started",
+				zap.String("runtime", e.Runtime.String()),
+				zap.String("init_runtime", e.InitRuntime.String()),
+				zap.Int("constructors", e.ConstructorCount),
+				zap.Int("hooks", e.HookCount),
+			)
 		}
+	case *StartSummary:
+		l.logEvent("start summary",
+			zap.Strings("constructors", allocationStrings(e.Constructors)),
+		)
 	case *LoggerInitialized:
 		if e.Err != nil {
 			l.logError("custom logger initialization failed", zap.Error(e.Err))
 		} else {
 			l.logEvent("initialized custom fxevent.Logger", zap.String("function", e.ConstructorName))
 		}
+	case *HookTimedOut:
+		l.logError("hook timed out",
+			zap.String("method", e.Method),
+			zap.String("callee", e.FunctionName),
+			zap.String("caller", e.CallerName),
+			zap.Strings("hookstacks", e.HookStacks),
+			zap.String("stacks", e.Stacks),
+		)
+	case *OnStartSlow:
+		l.logEvent("OnStart hook is slow",
+			zap.String("callee", e.FunctionName),
+			zap.String("caller", e.CallerName),
+			zap.String("elapsed", e.Elapsed.String()),
+		)
+	case *DynamicHookAppended:
+		l.logEvent("hook appended during start",
+			zap.String("caller", e.CallerName),
+			maybeString("onstart", e.OnStartName),
+			maybeString("onstop", e.OnStopName),
+		)
+	case *ModuleStarted:
+		l.logEvent("module started",
+			moduleField(e.ModuleName),
+			zap.String("runtime", e.Runtime.String()),
+		)
+	case *ModuleStopped:
+		l.logEvent("module stopped",
+			moduleField(e.ModuleName),
+			zap.String("runtime", e.Runtime.String()),
+		)
+	case *LintWarning:
+		l.logEvent("lint warning",
+			zap.String("rule", e.Rule),
+			zap.String("message", e.Message),
+			maybeString("function", e.FunctionName),
+			moduleField(e.ModuleName),
+		)
+	case *ShutdownScheduled:
+		l.logEvent("shutdown scheduled",
+			zap.Time("deadline", e.Deadline),
+			zap.Int("exitcode", e.ExitCode),
+		)
+	case *ShutdownCanceled:
+		l.logEvent("scheduled shutdown canceled",
+			zap.Time("deadline", e.Deadline),
+		)
+	case *ShutdownFired:
+		if e.Err != nil {
+			l.logError("scheduled shutdown failed",
+				zap.Time("deadline", e.Deadline),
+				zap.Int("exitcode", e.ExitCode),
+				zap.Error(e.Err),
+			)
+		} else {
+			l.logEvent("shutting down as scheduled",
+				zap.Time("deadline", e.Deadline),
+				zap.Int("exitcode", e.ExitCode),
+			)
+		}
+	case *OptionError:
+		l.logError("error option",
+			zap.Strings("stacktrace", e.StackTrace),
+			zap.Strings("moduletrace", e.ModuleTrace),
+			moduleField(e.ModuleName),
+			zap.Error(e.Err),
+		)
+	case *Flushing:
+		l.logEvent("flushing logger")
+	case *CronJobExecuted:
+		if e.Err != nil {
+			l.logError("cron job failed",
+				zap.String("job", e.JobName),
+				zap.Time("scheduled", e.ScheduledAt),
+				zap.String("runtime", e.Runtime.String()),
+				zap.Error(e.Err),
+			)
+		} else {
+			l.logEvent("cron job executed",
+				zap.String("job", e.JobName),
+				zap.Time("scheduled", e.ScheduledAt),
+				zap.String("runtime", e.Runtime.String()),
+			)
+		}
+	case *ModuleConflict:
+		l.logEvent("module conflict",
+			zap.String("module", e.ModuleName),
+			zap.Strings("modules", e.Modules),
+		)
+	case *LoggerPanicked:
+		l.logError("logger panicked",
+			zap.String("logger", e.LoggerType),
+			zap.String("event", e.EventType),
+			zap.Error(e.Err),
+			zap.String("stack", e.Stack),
+		)
 	}
 }
 
@@ -233,6 +491,20 @@ func moduleField(name string) zap.Field {
 	return zap.String("module", name)
 }
 
+func maybeString(name, value string) zap.Field {
+	if len(value) == 0 {
+		return zap.Skip()
+	}
+	return zap.String(name, value)
+}
+
+func maybeStrings(name string, strs []string) zap.Field {
+	if len(strs) > 0 {
+		return zap.Strings(name, strs)
+	}
+	return zap.Skip()
+}
+
 func maybeBool(name string, b bool) zap.Field {
 	if b {
 		return zap.Bool(name, true)
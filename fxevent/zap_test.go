@@ -39,6 +39,7 @@ func TestZapLogger(t *testing.T) {
 	t.Parallel()
 
 	someError := errors.New("some error")
+	deadline := time.Date(2024, 10, 16, 12, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name        string
@@ -98,6 +99,86 @@ func TestZapLogger(t *testing.T) {
 				"runtime": "3ms",
 			},
 		},
+		{
+			name: "OnPauseExecuting",
+			give: &OnPauseExecuting{
+				FunctionName: "hook.onPause1",
+				CallerName:   "bytes.NewBuffer",
+			},
+			wantMessage: "OnPause hook executing",
+			wantFields: map[string]interface{}{
+				"caller": "bytes.NewBuffer",
+				"callee": "hook.onPause1",
+			},
+		},
+		{
+			name: "OnPauseExecuted/Error",
+			give: &OnPauseExecuted{
+				FunctionName: "hook.onPause1",
+				CallerName:   "bytes.NewBuffer",
+				Err:          fmt.Errorf("some error"),
+			},
+			wantMessage: "OnPause hook failed",
+			wantFields: map[string]interface{}{
+				"caller": "bytes.NewBuffer",
+				"callee": "hook.onPause1",
+				"error":  "some error",
+			},
+		},
+		{
+			name: "OnPauseExecuted",
+			give: &OnPauseExecuted{
+				FunctionName: "hook.onPause1",
+				CallerName:   "bytes.NewBuffer",
+				Runtime:      time.Millisecond * 3,
+			},
+			wantMessage: "OnPause hook executed",
+			wantFields: map[string]interface{}{
+				"caller":  "bytes.NewBuffer",
+				"callee":  "hook.onPause1",
+				"runtime": "3ms",
+			},
+		},
+		{
+			name: "OnResumeExecuting",
+			give: &OnResumeExecuting{
+				FunctionName: "hook.onResume1",
+				CallerName:   "bytes.NewBuffer",
+			},
+			wantMessage: "OnResume hook executing",
+			wantFields: map[string]interface{}{
+				"caller": "bytes.NewBuffer",
+				"callee": "hook.onResume1",
+			},
+		},
+		{
+			name: "OnResumeExecuted/Error",
+			give: &OnResumeExecuted{
+				FunctionName: "hook.onResume1",
+				CallerName:   "bytes.NewBuffer",
+				Err:          fmt.Errorf("some error"),
+			},
+			wantMessage: "OnResume hook failed",
+			wantFields: map[string]interface{}{
+				"caller": "bytes.NewBuffer",
+				"callee": "hook.onResume1",
+				"error":  "some error",
+			},
+		},
+		{
+			name: "OnResumeExecuted",
+			give: &OnResumeExecuted{
+				FunctionName: "hook.onResume1",
+				CallerName:   "bytes.NewBuffer",
+				Runtime:      time.Millisecond * 3,
+			},
+			wantMessage: "OnResume hook executed",
+			wantFields: map[string]interface{}{
+				"caller":  "bytes.NewBuffer",
+				"callee":  "hook.onResume1",
+				"runtime": "3ms",
+			},
+		},
 		{
 			name: "OnStartExecuted/Error",
 			give: &OnStartExecuted{
@@ -126,6 +207,21 @@ func TestZapLogger(t *testing.T) {
 				"runtime": "3ms",
 			},
 		},
+		{
+			name: "Configured",
+			give: &Configured{
+				StartTimeout: 15 * time.Second,
+				StopTimeout:  time.Minute,
+				Clock:        "system",
+			},
+			wantMessage: "configured",
+			wantFields: map[string]interface{}{
+				"starttimeout":      "15s",
+				"stoptimeout":       "1m0s",
+				"clock":             "system",
+				"recoverfrompanics": false,
+			},
+		},
 		{
 			name: "Supplied",
 			give: &Supplied{
@@ -175,6 +271,24 @@ func TestZapLogger(t *testing.T) {
 				"module":      "myModule",
 			},
 		},
+		{
+			name: "ProvideWithParams",
+			give: &Provided{
+				ConstructorName: "bytes.NewBuffer()",
+				StackTrace:      []string{"main.main", "runtime.main"},
+				ModuleTrace:     []string{"main.main"},
+				OutputTypeNames: []string{"*bytes.Buffer"},
+				InputTypeNames:  []string{"[]uint8", `string[optional, name = "prefix"]`},
+			},
+			wantMessage: "provided",
+			wantFields: map[string]interface{}{
+				"constructor": "bytes.NewBuffer()",
+				"stacktrace":  []interface{}{"main.main", "runtime.main"},
+				"moduletrace": []interface{}{"main.main"},
+				"type":        "*bytes.Buffer",
+				"params":      []interface{}{"[]uint8", `string[optional, name = "prefix"]`},
+			},
+		},
 		{
 			name: "PrivateProvide",
 			give: &Provided{
@@ -272,6 +386,20 @@ func TestZapLogger(t *testing.T) {
 				"error":       "some error",
 			},
 		},
+		{
+			name: "DecoratorChain",
+			give: &DecoratorChain{
+				TypeName:       "*bytes.Buffer",
+				ModuleName:     "myModule",
+				DecoratorNames: []string{"main.a()", "main.b()"},
+			},
+			wantMessage: "decorator chain",
+			wantFields: map[string]interface{}{
+				"type":       "*bytes.Buffer",
+				"decorators": []interface{}{"main.a()", "main.b()"},
+				"module":     "myModule",
+			},
+		},
 		{
 			name:        "Run",
 			give:        &Run{Name: "bytes.NewBuffer()", Kind: "constructor", Runtime: time.Second},
@@ -298,6 +426,22 @@ func TestZapLogger(t *testing.T) {
 				"runtime": "1ms",
 			},
 		},
+		{
+			name: "Run with demand path",
+			give: &Run{
+				Name:       "bytes.NewBuffer()",
+				Kind:       "constructor",
+				Runtime:    time.Millisecond,
+				DemandPath: []string{"main.run()", "bytes.NewBuffer()"},
+			},
+			wantMessage: "run",
+			wantFields: map[string]interface{}{
+				"name":        "bytes.NewBuffer()",
+				"kind":        "constructor",
+				"runtime":     "1ms",
+				"demand_path": []interface{}{"main.run()", "bytes.NewBuffer()"},
+			},
+		},
 		{
 			name: "Run/Error",
 			give: &Run{
@@ -312,6 +456,46 @@ func TestZapLogger(t *testing.T) {
 				"error": "some error",
 			},
 		},
+		{
+			name: "Retrying/Error",
+			give: &Retrying{
+				ConstructorName: "db.Open()",
+				ModuleName:      "myModule",
+				Attempt:         1,
+				Attempts:        3,
+				Delay:           time.Second,
+				Err:             someError,
+			},
+			wantMessage: "constructor failed, retrying",
+			wantFields: map[string]interface{}{
+				"constructor": "db.Open()",
+				"module":      "myModule",
+				"attempt":     int64(1),
+				"attempts":    int64(3),
+				"delay":       "1s",
+				"error":       "some error",
+			},
+		},
+		{
+			name: "OnStartRetrying/Error",
+			give: &OnStartRetrying{
+				FunctionName: "main.connect()",
+				CallerName:   "main.NewClient()",
+				Attempt:      1,
+				Attempts:     3,
+				Delay:        time.Second,
+				Err:          someError,
+			},
+			wantMessage: "OnStart hook failed, retrying",
+			wantFields: map[string]interface{}{
+				"callee":   "main.connect()",
+				"caller":   "main.NewClient()",
+				"attempt":  int64(1),
+				"attempts": int64(3),
+				"delay":    "1s",
+				"error":    "some error",
+			},
+		},
 		{
 			name:        "Invoking/Success",
 			give:        &Invoking{ModuleName: "myModule", FunctionName: "bytes.NewBuffer()"},
@@ -331,6 +515,16 @@ func TestZapLogger(t *testing.T) {
 				"function": "bytes.NewBuffer()",
 			},
 		},
+		{
+			name:        "Invoked/Try",
+			give:        &Invoked{FunctionName: "bytes.NewBuffer()", Err: someError, Try: true},
+			wantMessage: "best-effort invoke failed",
+			wantFields: map[string]interface{}{
+				"error":    "some error",
+				"stack":    "",
+				"function": "bytes.NewBuffer()",
+			},
+		},
 		{
 			name:        "Start/Error",
 			give:        &Started{Err: someError},
@@ -373,9 +567,24 @@ func TestZapLogger(t *testing.T) {
 		},
 		{
 			name:        "Started",
-			give:        &Started{},
+			give:        &Started{Runtime: 3 * time.Millisecond, InitRuntime: 10 * time.Millisecond, ConstructorCount: 12, HookCount: 4},
 			wantMessage: "started",
-			wantFields:  map[string]interface{}{},
+			wantFields: map[string]interface{}{
+				"runtime":      "3ms",
+				"init_runtime": "10ms",
+				"constructors": int64(12),
+				"hooks":        int64(4),
+			},
+		},
+		{
+			name: "StartSummary",
+			give: &StartSummary{Constructors: []ConstructorAllocations{
+				{Name: "bytes.NewBuffer()", ModuleName: "myModule", Bytes: 1024, Objects: 2},
+			}},
+			wantMessage: "start summary",
+			wantFields: map[string]interface{}{
+				"constructors": []interface{}{`bytes.NewBuffer() from module "myModule" allocated 1024 bytes in 2 objects`},
+			},
 		},
 		{
 			name:        "LoggerInitialized/Error",
@@ -393,6 +602,203 @@ func TestZapLogger(t *testing.T) {
 				"function": "bytes.NewBuffer()",
 			},
 		},
+		{
+			name: "HookTimedOut/Error",
+			give: &HookTimedOut{
+				Method:       "OnStop",
+				FunctionName: "hook.onStop",
+				CallerName:   "bytes.NewBuffer",
+				HookStacks:   []string{"goroutine 2 [chan receive]:"},
+				Stacks:       "goroutine 1 [running]:",
+			},
+			wantMessage: "hook timed out",
+			wantFields: map[string]interface{}{
+				"method":     "OnStop",
+				"callee":     "hook.onStop",
+				"caller":     "bytes.NewBuffer",
+				"hookstacks": []interface{}{"goroutine 2 [chan receive]:"},
+				"stacks":     "goroutine 1 [running]:",
+			},
+		},
+		{
+			name: "OnStartSlow",
+			give: &OnStartSlow{
+				FunctionName: "hook.onStart",
+				CallerName:   "bytes.NewBuffer",
+				Elapsed:      10 * time.Second,
+			},
+			wantMessage: "OnStart hook is slow",
+			wantFields: map[string]interface{}{
+				"callee":  "hook.onStart",
+				"caller":  "bytes.NewBuffer",
+				"elapsed": "10s",
+			},
+		},
+		{
+			name: "DynamicHookAppended",
+			give: &DynamicHookAppended{
+				CallerName:  "bytes.NewBuffer",
+				OnStartName: "hook.onStart",
+			},
+			wantMessage: "hook appended during start",
+			wantFields: map[string]interface{}{
+				"caller":  "bytes.NewBuffer",
+				"onstart": "hook.onStart",
+			},
+		},
+		{
+			name:        "ModuleStarted",
+			give:        &ModuleStarted{ModuleName: "server", Runtime: time.Millisecond},
+			wantMessage: "module started",
+			wantFields: map[string]interface{}{
+				"module":  "server",
+				"runtime": "1ms",
+			},
+		},
+		{
+			name:        "ModuleStopped",
+			give:        &ModuleStopped{ModuleName: "server", Runtime: time.Second},
+			wantMessage: "module stopped",
+			wantFields: map[string]interface{}{
+				"module":  "server",
+				"runtime": "1s",
+			},
+		},
+		{
+			name: "LintWarning",
+			give: &LintWarning{
+				Rule:         "too-many-params",
+				Message:      "bytes.NewBuffer() has 12 parameters",
+				FunctionName: "bytes.NewBuffer()",
+			},
+			wantMessage: "lint warning",
+			wantFields: map[string]interface{}{
+				"rule":     "too-many-params",
+				"message":  "bytes.NewBuffer() has 12 parameters",
+				"function": "bytes.NewBuffer()",
+			},
+		},
+		{
+			name: "LintWarningWithModule",
+			give: &LintWarning{
+				Rule:       "late-hook",
+				Message:    "hook appended after the application was built",
+				ModuleName: "myModule",
+			},
+			wantMessage: "lint warning",
+			wantFields: map[string]interface{}{
+				"rule":    "late-hook",
+				"message": "hook appended after the application was built",
+				"module":  "myModule",
+			},
+		},
+		{
+			name:        "ShutdownScheduled",
+			give:        &ShutdownScheduled{Deadline: deadline, ExitCode: 3},
+			wantMessage: "shutdown scheduled",
+			wantFields: map[string]interface{}{
+				"deadline": deadline,
+				"exitcode": int64(3),
+			},
+		},
+		{
+			name:        "ShutdownCanceled",
+			give:        &ShutdownCanceled{Deadline: deadline},
+			wantMessage: "scheduled shutdown canceled",
+			wantFields: map[string]interface{}{
+				"deadline": deadline,
+			},
+		},
+		{
+			name:        "ShutdownFired",
+			give:        &ShutdownFired{Deadline: deadline},
+			wantMessage: "shutting down as scheduled",
+			wantFields: map[string]interface{}{
+				"deadline": deadline,
+				"exitcode": int64(0),
+			},
+		},
+		{
+			name:        "ShutdownFired/Error",
+			give:        &ShutdownFired{Deadline: deadline, Err: someError},
+			wantMessage: "scheduled shutdown failed",
+			wantFields: map[string]interface{}{
+				"deadline": deadline,
+				"exitcode": int64(0),
+				"error":    "some error",
+			},
+		},
+		{
+			name: "OptionError/Error",
+			give: &OptionError{
+				Err:         someError,
+				ModuleName:  "myModule",
+				ModuleTrace: []string{"main.module", "main.main"},
+				StackTrace:  []string{"main.module", "main.main"},
+			},
+			wantMessage: "error option",
+			wantFields: map[string]interface{}{
+				"stacktrace":  []interface{}{"main.module", "main.main"},
+				"moduletrace": []interface{}{"main.module", "main.main"},
+				"module":      "myModule",
+				"error":       "some error",
+			},
+		},
+		{
+			name:        "Flushing",
+			give:        &Flushing{},
+			wantMessage: "flushing logger",
+			wantFields:  map[string]interface{}{},
+		},
+		{
+			name:        "CronJobExecuted",
+			give:        &CronJobExecuted{JobName: "cleanup", ScheduledAt: deadline, Runtime: time.Second},
+			wantMessage: "cron job executed",
+			wantFields: map[string]interface{}{
+				"job":       "cleanup",
+				"scheduled": deadline,
+				"runtime":   "1s",
+			},
+		},
+		{
+			name:        "CronJobExecuted/Error",
+			give:        &CronJobExecuted{JobName: "cleanup", ScheduledAt: deadline, Runtime: time.Second, Err: someError},
+			wantMessage: "cron job failed",
+			wantFields: map[string]interface{}{
+				"job":       "cleanup",
+				"scheduled": deadline,
+				"runtime":   "1s",
+				"error":     "some error",
+			},
+		},
+		{
+			name: "ModuleConflict",
+			give: &ModuleConflict{
+				ModuleName: "logging",
+				Modules:    []string{"a", "b"},
+			},
+			wantMessage: "module conflict",
+			wantFields: map[string]interface{}{
+				"module":  "logging",
+				"modules": []interface{}{"a", "b"},
+			},
+		},
+		{
+			name: "LoggerPanicked/Error",
+			give: &LoggerPanicked{
+				LoggerType: "*fxevent.ConsoleLogger",
+				EventType:  "*fxevent.Started",
+				Err:        someError,
+				Stack:      "main.main()",
+			},
+			wantMessage: "logger panicked",
+			wantFields: map[string]interface{}{
+				"logger": "*fxevent.ConsoleLogger",
+				"event":  "*fxevent.Started",
+				"error":  "some error",
+				"stack":  "main.main()",
+			},
+		},
 	}
 
 	t.Run("debug observer, log at default (info)", func(t *testing.T) {
@@ -510,4 +916,92 @@ func TestZapLogger(t *testing.T) {
 			require.Len(t, logs, 1)
 		}
 	})
+
+	t.Run("per-event log level", func(t *testing.T) {
+		t.Parallel()
+
+		core, observedLogs := observer.New(zap.DebugLevel)
+		logger := &ZapLogger{Logger: zap.New(core)}
+		logger.UseEventLogLevel(&Provided{}, zapcore.DebugLevel)
+
+		logger.LogEvent(&Provided{
+			ConstructorName: "bytes.NewBuffer()",
+			OutputTypeNames: []string{"*bytes.Buffer"},
+		})
+		logger.LogEvent(&Started{})
+
+		logs := observedLogs.TakeAll()
+		require.Len(t, logs, 2)
+		assert.Equal(t, "provided", logs[0].Message)
+		assert.Equal(t, zapcore.DebugLevel, logs[0].Level)
+		assert.Equal(t, "started", logs[1].Message)
+		assert.Equal(t, zapcore.InfoLevel, logs[1].Level)
+	})
+
+	t.Run("drop events", func(t *testing.T) {
+		t.Parallel()
+
+		core, observedLogs := observer.New(zap.DebugLevel)
+		logger := &ZapLogger{Logger: zap.New(core)}
+		logger.DropEvents(&Provided{}, &Run{})
+
+		logger.LogEvent(&Provided{
+			ConstructorName: "bytes.NewBuffer()",
+			OutputTypeNames: []string{"*bytes.Buffer"},
+		})
+		logger.LogEvent(&Run{Name: "bytes.NewBuffer()", Kind: "provide"})
+		logger.LogEvent(&Started{})
+		assert.Equal(t, []string{"started"}, zapMessages(observedLogs.TakeAll()))
+
+		// Errors are still logged.
+		logger.LogEvent(&Run{
+			Name: "bytes.NewBuffer()",
+			Kind: "provide",
+			Err:  errors.New("great sadness"),
+		})
+		assert.Equal(t, []string{"error returned"}, zapMessages(observedLogs.TakeAll()))
+	})
+}
+
+func zapMessages(logs []observer.LoggedEntry) []string {
+	msgs := make([]string, len(logs))
+	for i, l := range logs {
+		msgs[i] = l.Message
+	}
+	return msgs
+}
+
+func TestZapLoggerAppName(t *testing.T) {
+	t.Parallel()
+
+	core, observedLogs := observer.New(zap.DebugLevel)
+	logger := &ZapLogger{Logger: zap.New(core)}
+	logger.LogEvent(&Invoking{FunctionName: "main.run()", Metadata: Metadata{AppName: "payments-api"}})
+	logger.LogEvent(&Invoking{FunctionName: "main.run()"})
+
+	logs := observedLogs.TakeAll()
+	require.Len(t, logs, 2)
+	assert.Equal(t, map[string]interface{}{
+		"app":      "payments-api",
+		"function": "main.run()",
+	}, logs[0].ContextMap())
+	assert.Equal(t, map[string]interface{}{
+		"function": "main.run()",
+	}, logs[1].ContextMap())
+}
+
+func TestZapLoggerSeq(t *testing.T) {
+	t.Parallel()
+
+	core, observedLogs := observer.New(zap.DebugLevel)
+	logger := &ZapLogger{Logger: zap.New(core)}
+	logger.LogEvent(&Invoking{FunctionName: "main.run()", Metadata: Metadata{AppName: "payments-api", Seq: 3}})
+
+	logs := observedLogs.TakeAll()
+	require.Len(t, logs, 1)
+	assert.Equal(t, map[string]interface{}{
+		"app":      "payments-api",
+		"seq":      uint64(3),
+		"function": "main.run()",
+	}, logs[0].ContextMap())
 }
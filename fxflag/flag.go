@@ -0,0 +1,345 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fxflag gates parts of an Fx application on feature flags.
+//
+// Adapt a feature flag service, like LaunchDarkly or ConfigCat,
+// to a [Source], and provide it to the application.
+// Flags may then be evaluated while the application is built,
+// to decide which constructors and decorators to register with
+// [ProvideIf] and [DecorateIf],
+// or when it starts, with a [*Flag] provided by [Lookup].
+// Whole groups of options and modules can be gated with [OptionsIf]
+// and [ModuleIf], which evaluate flags with a Source given to them.
+//
+//	fx.New(
+//		fx.Provide(func(c *ld.LDClient) fxflag.Source {
+//			return fxflag.SourceFunc(func(_ context.Context, flag string) (bool, error) {
+//				return c.BoolVariation(flag, ldcontext.New("my-service"), false)
+//			})
+//		}),
+//		fxflag.ProvideIf("redis-cache", NewRedisCache),
+//		fxflag.Lookup("new-checkout"),
+//		fx.Invoke(fx.Annotate(
+//			func(lc fx.Lifecycle, f *fxflag.Flag, s *Server) {
+//				lc.Append(fx.StartHook(func() {
+//					s.UseNewCheckout(f.Enabled())
+//				}))
+//			},
+//			fx.ParamTags(`name:"new-checkout"`),
+//		)),
+//	)
+package fxflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx"
+)
+
+// Source evaluates feature flags by name.
+type Source interface {
+	// Enabled reports whether the given flag is enabled.
+	Enabled(ctx context.Context, flag string) (bool, error)
+}
+
+// SourceFunc adapts a function to a [Source].
+type SourceFunc func(ctx context.Context, flag string) (bool, error)
+
+var _ Source = SourceFunc(nil)
+
+// Enabled calls f.
+func (f SourceFunc) Enabled(ctx context.Context, flag string) (bool, error) {
+	return f(ctx, flag)
+}
+
+// Static returns a [Source] that reports the given flags,
+// and all other flags as disabled.
+// This is most helpful in tests.
+func Static(flags map[string]bool) Source {
+	return SourceFunc(func(_ context.Context, flag string) (bool, error) {
+		return flags[flag], nil
+	})
+}
+
+// evaluate evaluates flag with src while the application is built,
+// when there is no context to bound the evaluation with.
+func evaluate(src Source, flag string) (bool, error) {
+	ok, err := src.Enabled(context.Background(), flag)
+	if err != nil {
+		return false, fmt.Errorf("evaluate flag %q: %w", flag, err)
+	}
+	return ok, nil
+}
+
+// ProvideIf registers constructors like [fx.Provide],
+// but only if the given flag is enabled,
+// as reported by the [Source] of the application
+// while the application is built.
+// See [fx.ProvideIf] for details.
+//
+//	fxflag.ProvideIf("redis-cache", NewRedisCache)
+func ProvideIf(flag string, constructors ...interface{}) fx.Option {
+	return fx.ProvideIf(func(src Source) (bool, error) {
+		return evaluate(src, flag)
+	}, constructors...)
+}
+
+// DecorateIf registers decorators like [fx.Decorate],
+// but they only modify the values they decorate if the given flag is
+// enabled, as reported by the [Source] of the application when the
+// decorated values are built.
+// Otherwise, the values are left as is.
+//
+//	fxflag.DecorateIf("verbose-logs", func(log *zap.Logger) *zap.Logger {
+//		return log.WithOptions(zap.IncreaseLevel(zap.DebugLevel))
+//	})
+//
+// Each decorator must accept the types it returns as parameters,
+// so that they can be left as is,
+// and may not use fx.In or fx.Out structs.
+func DecorateIf(flag string, decorators ...interface{}) fx.Option {
+	gated := make([]interface{}, len(decorators))
+	for i, d := range decorators {
+		g, err := gateDecorator(flag, d)
+		if err != nil {
+			return fx.Error(fmt.Errorf("fxflag.DecorateIf(%q): %w", flag, err))
+		}
+		gated[i] = g
+	}
+	return fx.Decorate(gated...)
+}
+
+// OptionsIf groups options like [fx.Options],
+// but only if the given flag is enabled, as reported by src.
+// Otherwise, the options are left out of the application entirely.
+//
+//	fxflag.OptionsIf(src, "audit-log",
+//		fx.Provide(NewAuditLog),
+//		fx.Invoke(func(*AuditLog) {}),
+//	)
+//
+// Options are applied before any constructor runs,
+// so unlike [ProvideIf], the flag is evaluated with the given [Source]
+// when OptionsIf is called rather than with the Source of the application.
+// If it can't be evaluated, the application fails to build with its error.
+func OptionsIf(src Source, flag string, opts ...fx.Option) fx.Option {
+	ok, err := evaluate(src, flag)
+	if err != nil {
+		return fx.Error(fmt.Errorf("fxflag.OptionsIf(%q): %w", flag, err))
+	}
+	if !ok {
+		return fx.Options()
+	}
+	return fx.Options(opts...)
+}
+
+// ModuleIf builds a named module like [fx.Module],
+// but only if the given flag is enabled, as reported by src.
+// The flag is evaluated as with [OptionsIf].
+//
+//	fxflag.ModuleIf(src, "new-checkout", "checkout",
+//		fx.Provide(NewCheckoutHandler),
+//	)
+func ModuleIf(src Source, flag, name string, opts ...fx.Option) fx.Option {
+	ok, err := evaluate(src, flag)
+	if err != nil {
+		return fx.Error(fmt.Errorf("fxflag.ModuleIf(%q): %w", flag, err))
+	}
+	if !ok {
+		return fx.Options()
+	}
+	return fx.Module(name, opts...)
+}
+
+var (
+	_typeOfError  = reflect.TypeOf((*error)(nil)).Elem()
+	_typeOfSource = reflect.TypeOf((*Source)(nil)).Elem()
+)
+
+// gateDecorator wraps decorator d into a decorator that also depends on the
+// Source, and passes through its decorated values if flag is disabled.
+func gateDecorator(flag string, d interface{}) (interface{}, error) {
+	fn := reflect.ValueOf(d)
+	if d == nil || fn.Kind() != reflect.Func {
+		return nil, fmt.Errorf("must provide decorator function, got %v (%T)", d, d)
+	}
+	ft := fn.Type()
+	if ft.IsVariadic() {
+		return nil, errors.New("decorators must not be variadic")
+	}
+
+	ins := []reflect.Type{_typeOfSource}
+	for i := 0; i < ft.NumIn(); i++ {
+		if dig.IsIn(ft.In(i)) {
+			return nil, errors.New("decorators must not accept fx.In structs")
+		}
+		ins = append(ins, ft.In(i))
+	}
+
+	// passthrough[i] is the index of the parameter returned as result i
+	// if the flag is disabled.
+	var (
+		outs        []reflect.Type
+		passthrough []int
+	)
+	hasErr := ft.NumOut() > 0 && ft.Out(ft.NumOut()-1) == _typeOfError
+	for i := 0; i < ft.NumOut(); i++ {
+		t := ft.Out(i)
+		if hasErr && i == ft.NumOut()-1 {
+			break
+		}
+		if dig.IsOut(t) {
+			return nil, errors.New("decorators must not return fx.Out structs")
+		}
+		idx := -1
+		for j := 0; j < ft.NumIn(); j++ {
+			if ft.In(j) == t {
+				idx = j
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("decorator must accept the %v it returns", t)
+		}
+		outs = append(outs, t)
+		passthrough = append(passthrough, idx)
+	}
+
+	gatedT := reflect.FuncOf(ins, append(outs, _typeOfError), false)
+	return reflect.MakeFunc(gatedT, func(args []reflect.Value) []reflect.Value {
+		src, _ := args[0].Interface().(Source)
+		args = args[1:]
+
+		results := make([]reflect.Value, len(outs)+1)
+		ok, err := evaluate(src, flag)
+		switch {
+		case err != nil:
+			for i, t := range outs {
+				results[i] = reflect.Zero(t)
+			}
+			results[len(outs)] = reflect.ValueOf(&err).Elem()
+			return results
+		case !ok:
+			for i, idx := range passthrough {
+				results[i] = args[idx]
+			}
+			results[len(outs)] = reflect.Zero(_typeOfError)
+			return results
+		}
+
+		out := fn.Call(args)
+		copy(results, out[:len(outs)])
+		if hasErr {
+			results[len(outs)] = out[len(outs)]
+		} else {
+			results[len(outs)] = reflect.Zero(_typeOfError)
+		}
+		return results
+	}).Interface(), nil
+}
+
+// Flag is a feature flag evaluated when the application starts,
+// provided by [Lookup].
+// Like a future, its value is only available once it's resolved:
+// OnStart hooks appended by constructors and functions that depend on it
+// run after it's resolved.
+type Flag struct {
+	name    string
+	done    chan struct{}
+	enabled bool
+}
+
+// Lookup provides a [*Flag] for the given flag, named after the flag.
+// The flag is evaluated with the [Source] of the application
+// the first time the application starts.
+// If it can't be evaluated, the application fails to start.
+//
+//	fxflag.Lookup("new-checkout")
+//
+//	type Params struct {
+//		fx.In
+//
+//		NewCheckout *fxflag.Flag `name:"new-checkout"`
+//	}
+func Lookup(flag string) fx.Option {
+	return fx.Provide(fx.Annotate(
+		func(lc fx.Lifecycle, src Source) *Flag {
+			f := &Flag{name: flag, done: make(chan struct{})}
+			lc.Append(fx.StartHook(func(ctx context.Context) error {
+				return f.resolve(ctx, src)
+			}))
+			return f
+		},
+		fx.ResultTags(fmt.Sprintf("name:%q", flag)),
+	))
+}
+
+func (f *Flag) resolve(ctx context.Context, src Source) error {
+	select {
+	case <-f.done:
+		return nil // already resolved by an earlier start
+	default:
+	}
+
+	ok, err := src.Enabled(ctx, f.name)
+	if err != nil {
+		return fmt.Errorf("evaluate flag %q: %w", f.name, err)
+	}
+	f.enabled = ok
+	close(f.done)
+	return nil
+}
+
+// Name returns the name of the flag.
+func (f *Flag) Name() string {
+	return f.name
+}
+
+// Done returns a channel that's closed once the flag is resolved.
+func (f *Flag) Done() <-chan struct{} {
+	return f.done
+}
+
+// Enabled reports whether the flag is enabled.
+// It reports false until the flag is resolved.
+func (f *Flag) Enabled() bool {
+	select {
+	case <-f.done:
+		return f.enabled
+	default:
+		return false
+	}
+}
+
+// Wait waits for the flag to be resolved, and reports whether it's enabled.
+// It returns the error of ctx if ctx is done first.
+func (f *Flag) Wait(ctx context.Context) (bool, error) {
+	select {
+	case <-f.done:
+		return f.enabled, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
@@ -0,0 +1,307 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxflag_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxflag"
+	"go.uber.org/fx/fxtest"
+)
+
+type cache struct{ name string }
+
+func TestProvideIf(t *testing.T) {
+	t.Parallel()
+
+	newCache := func() *cache { return &cache{name: "redis"} }
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+
+		var c *cache
+		app := fxtest.New(t,
+			fx.Supply(fx.Annotate(fxflag.Static(map[string]bool{"redis-cache": true}), fx.As(new(fxflag.Source)))),
+			fxflag.ProvideIf("redis-cache", newCache),
+			fx.Populate(&c),
+		)
+		app.RequireStart().RequireStop()
+		require.NotNil(t, c)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		var p struct {
+			fx.In
+
+			Cache *cache `optional:"true"`
+		}
+		app := fxtest.New(t,
+			fx.Supply(fx.Annotate(fxflag.Static(nil), fx.As(new(fxflag.Source)))),
+			fxflag.ProvideIf("redis-cache", newCache),
+			fx.Populate(&p),
+		)
+		app.RequireStart().RequireStop()
+		assert.Nil(t, p.Cache)
+	})
+
+	t.Run("source error", func(t *testing.T) {
+		t.Parallel()
+
+		src := fxflag.SourceFunc(func(context.Context, string) (bool, error) {
+			return false, errors.New("great sadness")
+		})
+		app := fx.New(
+			fx.NopLogger,
+			fx.Supply(fx.Annotate(src, fx.As(new(fxflag.Source)))),
+			fxflag.ProvideIf("redis-cache", newCache),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `evaluate flag "redis-cache": great sadness`)
+	})
+}
+
+func TestOptionsIf(t *testing.T) {
+	t.Parallel()
+
+	newCache := func() *cache { return &cache{name: "redis"} }
+	src := fxflag.Static(map[string]bool{"redis-cache": true})
+
+	tests := []struct {
+		desc string
+		give func(fxflag.Source, string) fx.Option
+	}{
+		{
+			desc: "OptionsIf",
+			give: func(src fxflag.Source, flag string) fx.Option {
+				return fxflag.OptionsIf(src, flag, fx.Provide(newCache))
+			},
+		},
+		{
+			desc: "ModuleIf",
+			give: func(src fxflag.Source, flag string) fx.Option {
+				return fxflag.ModuleIf(src, flag, "cache", fx.Provide(newCache))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("enabled", func(t *testing.T) {
+				t.Parallel()
+
+				var c *cache
+				app := fxtest.New(t, tt.give(src, "redis-cache"), fx.Populate(&c))
+				app.RequireStart().RequireStop()
+				require.NotNil(t, c)
+				assert.Equal(t, "redis", c.name)
+			})
+
+			t.Run("disabled", func(t *testing.T) {
+				t.Parallel()
+
+				var p struct {
+					fx.In
+
+					Cache *cache `optional:"true"`
+				}
+				app := fxtest.New(t, tt.give(src, "memcached"), fx.Populate(&p))
+				app.RequireStart().RequireStop()
+				assert.Nil(t, p.Cache)
+			})
+
+			t.Run("source error", func(t *testing.T) {
+				t.Parallel()
+
+				src := fxflag.SourceFunc(func(context.Context, string) (bool, error) {
+					return false, errors.New("great sadness")
+				})
+				err := fx.New(fx.NopLogger, tt.give(src, "redis-cache")).Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), `fxflag.`+tt.desc+`("redis-cache")`)
+				assert.Contains(t, err.Error(), `evaluate flag "redis-cache": great sadness`)
+			})
+		})
+	}
+}
+
+func TestDecorateIf(t *testing.T) {
+	t.Parallel()
+
+	rename := func(c *cache) *cache { return &cache{name: c.name + "-decorated"} }
+
+	tests := []struct {
+		desc  string
+		flags map[string]bool
+		want  string
+	}{
+		{desc: "enabled", flags: map[string]bool{"decorate": true}, want: "redis-decorated"},
+		{desc: "disabled", want: "redis"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var c *cache
+			app := fxtest.New(t,
+				fx.Supply(fx.Annotate(fxflag.Static(tt.flags), fx.As(new(fxflag.Source)))),
+				fx.Supply(&cache{name: "redis"}),
+				fxflag.DecorateIf("decorate", rename),
+				fx.Populate(&c),
+			)
+			app.RequireStart().RequireStop()
+			assert.Equal(t, tt.want, c.name)
+		})
+	}
+
+	t.Run("decorator error", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Supply(fx.Annotate(fxflag.Static(map[string]bool{"decorate": true}), fx.As(new(fxflag.Source)))),
+			fx.Supply(&cache{name: "redis"}),
+			fxflag.DecorateIf("decorate", func(*cache) (*cache, error) {
+				return nil, errors.New("great sadness")
+			}),
+			fx.Invoke(func(*cache) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+	})
+
+	t.Run("invalid decorators", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    interface{}
+			wantErr string
+		}{
+			{
+				desc:    "not a function",
+				give:    42,
+				wantErr: "must provide decorator function, got 42 (int)",
+			},
+			{
+				desc:    "new type",
+				give:    func(*cache) string { return "" },
+				wantErr: "decorator must accept the string it returns",
+			},
+			{
+				desc:    "variadic",
+				give:    func(...*cache) *cache { return nil },
+				wantErr: "decorators must not be variadic",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				err := fx.New(fx.NopLogger, fxflag.DecorateIf("decorate", tt.give)).Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), `fxflag.DecorateIf("decorate"): `+tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	type params struct {
+		fx.In
+
+		Flag *fxflag.Flag `name:"new-checkout"`
+	}
+
+	t.Run("resolved at start", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			f         *fxflag.Flag
+			atStart   bool
+			evaluated int
+		)
+		src := fxflag.SourceFunc(func(_ context.Context, flag string) (bool, error) {
+			evaluated++
+			return flag == "new-checkout", nil
+		})
+		app := fxtest.New(t,
+			fx.Supply(fx.Annotate(src, fx.As(new(fxflag.Source)))),
+			fxflag.Lookup("new-checkout"),
+			fx.Invoke(func(lc fx.Lifecycle, p params) {
+				f = p.Flag
+				lc.Append(fx.StartHook(func() { atStart = f.Enabled() }))
+			}),
+		)
+
+		assert.Equal(t, "new-checkout", f.Name())
+		assert.False(t, f.Enabled(), "flag must not be resolved before start")
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := f.Wait(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		app.RequireStart().RequireStop()
+		assert.True(t, atStart, "flag must be resolved before dependent hooks")
+		<-f.Done()
+		ok, err := f.Wait(context.Background())
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		app.RequireStart().RequireStop()
+		assert.Equal(t, 1, evaluated, "flag must only be evaluated once")
+	})
+
+	t.Run("source error", func(t *testing.T) {
+		t.Parallel()
+
+		src := fxflag.SourceFunc(func(context.Context, string) (bool, error) {
+			return false, errors.New("great sadness")
+		})
+		app := fx.New(
+			fx.NopLogger,
+			fx.Supply(fx.Annotate(src, fx.As(new(fxflag.Source)))),
+			fxflag.Lookup("new-checkout"),
+			fx.Invoke(func(params) {}),
+		)
+		require.NoError(t, app.Err())
+		err := app.Start(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `evaluate flag "new-checkout": great sadness`)
+	})
+}
@@ -0,0 +1,26 @@
+module go.uber.org/fx/fxgrpc
+
+go 1.22
+
+replace go.uber.org/fx => ../
+
+require (
+	github.com/stretchr/testify v1.8.1
+	go.uber.org/fx v1.24.0
+	go.uber.org/goleak v1.2.0
+	go.uber.org/multierr v1.10.0
+	google.golang.org/grpc v1.64.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.uber.org/dig v1.18.0 // indirect
+	go.uber.org/zap v1.26.0 // indirect
+	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+	google.golang.org/protobuf v1.33.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
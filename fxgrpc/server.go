@@ -0,0 +1,272 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fxgrpc runs a gRPC server
+// for as long as an Fx application runs.
+//
+// Services are registered by providing [Service] functions
+// to the "grpc.services" value group, for example with [AsService].
+// [Module] provides a *grpc.Server with those services,
+// and with the interceptors and options of the
+// "grpc.unary_interceptors", "grpc.stream_interceptors",
+// and "grpc.server_options" value groups.
+// The server starts listening when the application starts,
+// and stops gracefully when it stops.
+//
+//	fx.New(
+//		fx.Supply(fxgrpc.Config{Addr: ":9090"}),
+//		fxgrpc.Module(),
+//		fx.Provide(
+//			fxgrpc.AsService(func(users *UserStore) fxgrpc.Service {
+//				return func(s grpc.ServiceRegistrar) {
+//					userpb.RegisterUserServiceServer(s, users)
+//				}
+//			}),
+//			fxgrpc.AsUnaryInterceptor(newAuthInterceptor),
+//		),
+//	)
+//
+// Tests can bind the server to a random port with the "127.0.0.1:0" address,
+// and find out which one it's listening on with [Server.Addr].
+//
+// fxgrpc is a separate Go module, so that applications
+// that don't use gRPC don't depend on it.
+package fxgrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc"
+)
+
+// Names of the value groups that [Module] reads from.
+const (
+	ServiceGroup           = "grpc.services"
+	UnaryInterceptorGroup  = "grpc.unary_interceptors"
+	StreamInterceptorGroup = "grpc.stream_interceptors"
+	ServerOptionGroup      = "grpc.server_options"
+)
+
+// Service registers a gRPC service with a server,
+// usually by calling the Register function generated for the service.
+type Service func(grpc.ServiceRegistrar)
+
+// AsService annotates a constructor that returns a [Service]
+// so that the service is served by [Module].
+func AsService(f any) any {
+	return asGroup(f, ServiceGroup)
+}
+
+// AsUnaryInterceptor annotates a constructor that returns a
+// grpc.UnaryServerInterceptor so that [Module] installs it.
+//
+// Value groups are unordered,
+// so interceptors must not depend on the order they run in.
+// Chain interceptors that do with grpc.ChainUnaryInterceptor,
+// and provide the chain as a single [ServerOptionGroup] option.
+func AsUnaryInterceptor(f any) any {
+	return asGroup(f, UnaryInterceptorGroup)
+}
+
+// AsStreamInterceptor annotates a constructor that returns a
+// grpc.StreamServerInterceptor so that [Module] installs it.
+// As with [AsUnaryInterceptor], the order of interceptors is unspecified.
+func AsStreamInterceptor(f any) any {
+	return asGroup(f, StreamInterceptorGroup)
+}
+
+// AsServerOption annotates a constructor that returns a
+// grpc.ServerOption so that [Module] builds the server with it.
+func AsServerOption(f any) any {
+	return asGroup(f, ServerOptionGroup)
+}
+
+func asGroup(f any, group string) any {
+	return fx.Annotate(f, fx.ResultTags(`group:"`+group+`"`))
+}
+
+// Config configures the server run by [Module].
+type Config struct {
+	// Addr is the TCP address the server listens on. Required.
+	Addr string
+}
+
+// Module provides a *grpc.Server serving the services
+// of the "grpc.services" value group,
+// and a *[Server] that runs it on the address of the [Config]
+// in the container, started and stopped with the application.
+//
+// If the server fails after it started listening,
+// the application is shut down with [fx.ShutdownError] and exit code 1.
+func Module() fx.Option {
+	return fx.Module("fxgrpc",
+		fx.Provide(
+			newGRPCServer,
+			func(lc fx.Lifecycle, sd fx.Shutdowner, cfg Config, srv *grpc.Server) *Server {
+				s := New(srv, cfg.Addr)
+				s.shutdowner = sd
+				lc.Append(fx.StartStopHook(s.Start, s.Stop))
+				return s
+			},
+		),
+		fx.Invoke(func(*Server) {}),
+	)
+}
+
+type serverParams struct {
+	fx.In
+
+	Services           []Service                      `group:"grpc.services"`
+	UnaryInterceptors  []grpc.UnaryServerInterceptor  `group:"grpc.unary_interceptors"`
+	StreamInterceptors []grpc.StreamServerInterceptor `group:"grpc.stream_interceptors"`
+	Options            []grpc.ServerOption            `group:"grpc.server_options"`
+}
+
+func newGRPCServer(p serverParams) *grpc.Server {
+	opts := append([]grpc.ServerOption(nil), p.Options...)
+	if len(p.UnaryInterceptors) > 0 {
+		opts = append(opts, grpc.ChainUnaryInterceptor(p.UnaryInterceptors...))
+	}
+	if len(p.StreamInterceptors) > 0 {
+		opts = append(opts, grpc.ChainStreamInterceptor(p.StreamInterceptors...))
+	}
+
+	srv := grpc.NewServer(opts...)
+	for _, register := range p.Services {
+		register(srv)
+	}
+	return srv
+}
+
+// Server runs a *grpc.Server.
+// Build one with [New], or use [Module].
+type Server struct {
+	srv        *grpc.Server
+	addr       string
+	shutdowner fx.Shutdowner // shuts down the application if serving fails
+
+	mu       sync.Mutex
+	ln       net.Listener  // non-nil once started
+	done     chan struct{} // closed once Serve returns
+	serveErr error         // error returned by Serve, if any
+}
+
+// New builds a Server that runs srv on the given TCP address
+// once it's started with [Server.Start].
+func New(srv *grpc.Server, addr string) *Server {
+	return &Server{srv: srv, addr: addr}
+}
+
+// Start listens on the address of the server, and serves requests
+// in a new goroutine.
+// A server can only be started once.
+func (s *Server) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ln != nil {
+		return errors.New("fxgrpc: server can only be started once")
+	}
+
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("fxgrpc: listen on %q: %w", s.addr, err)
+	}
+
+	s.ln = ln
+	s.done = make(chan struct{})
+	go s.serve(ln, s.done)
+	return nil
+}
+
+func (s *Server) serve(ln net.Listener, done chan struct{}) {
+	defer close(done)
+
+	// Serve returns nil once the server stops,
+	// or ErrServerStopped if it stopped before Serve began.
+	err := s.srv.Serve(ln)
+	if err == nil || errors.Is(err, grpc.ErrServerStopped) {
+		return
+	}
+
+	err = fmt.Errorf("fxgrpc: serve on %v: %w", ln.Addr(), err)
+	s.mu.Lock()
+	s.serveErr = err
+	s.mu.Unlock()
+	if s.shutdowner != nil {
+		_ = s.shutdowner.Shutdown(fx.ShutdownError(err))
+	}
+}
+
+// Stop stops the server gracefully, waiting for pending RPCs
+// to complete. If ctx expires first, Stop closes the server's connections,
+// cancelling pending RPCs, and returns the error of ctx.
+//
+// Stop also returns the error the server failed with, if any.
+func (s *Server) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	done := s.done
+	s.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(stopped)
+	}()
+
+	var err error
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		err = ctx.Err()
+		s.srv.Stop()
+		<-stopped
+	}
+	<-done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.serveErr != nil {
+		return multierr.Append(err, s.serveErr)
+	}
+	return err
+}
+
+// Addr returns the address the server is listening on,
+// or nil if it hasn't started.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ln == nil {
+		return nil
+	}
+	return s.ln.Addr()
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func check(t *testing.T, addr net.Addr) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	t.Helper()
+
+	conn, err := grpc.NewClient(addr.String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	res, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return 0, err
+	}
+	return res.Status, nil
+}
+
+func TestModule(t *testing.T) {
+	t.Parallel()
+
+	var (
+		server  *Server
+		methods []string
+	)
+	app := fxtest.New(t,
+		fx.Supply(Config{Addr: "127.0.0.1:0"}),
+		Module(),
+		fx.Provide(
+			AsService(func() Service {
+				return func(s grpc.ServiceRegistrar) {
+					healthpb.RegisterHealthServer(s, health.NewServer())
+				}
+			}),
+			AsUnaryInterceptor(func() grpc.UnaryServerInterceptor {
+				return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+					methods = append(methods, info.FullMethod)
+					return handler(ctx, req)
+				}
+			}),
+		),
+		fx.Populate(&server),
+	)
+	assert.Nil(t, server.Addr(), "server must not listen before it starts")
+
+	app.RequireStart()
+	addr := server.Addr()
+	require.NotNil(t, addr)
+
+	status, err := check(t, addr)
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status)
+	assert.Equal(t, []string{"/grpc.health.v1.Health/Check"}, methods)
+
+	app.RequireStop()
+	_, err = check(t, addr)
+	assert.Error(t, err, "server must not serve after it stops")
+}
+
+func TestModuleNoServices(t *testing.T) {
+	t.Parallel()
+
+	var srv *grpc.Server
+	app := fxtest.New(t,
+		fx.Supply(Config{Addr: "127.0.0.1:0"}),
+		Module(),
+		fx.Populate(&srv),
+	)
+	defer app.RequireStart().RequireStop()
+
+	assert.Empty(t, srv.GetServiceInfo())
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("listen error", func(t *testing.T) {
+		t.Parallel()
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer ln.Close()
+
+		s := New(grpc.NewServer(), ln.Addr().String())
+		err = s.Start(context.Background())
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "fxgrpc: listen on")
+		assert.Nil(t, s.Addr())
+		assert.NoError(t, s.Stop(context.Background()))
+	})
+
+	t.Run("start twice", func(t *testing.T) {
+		t.Parallel()
+
+		s := New(grpc.NewServer(), "127.0.0.1:0")
+		require.NoError(t, s.Start(context.Background()))
+		defer func() {
+			assert.NoError(t, s.Stop(context.Background()))
+		}()
+
+		err := s.Start(context.Background())
+		assert.ErrorContains(t, err, "can only be started once")
+	})
+
+	t.Run("stop before start", func(t *testing.T) {
+		t.Parallel()
+
+		s := New(grpc.NewServer(), "127.0.0.1:0")
+		assert.NoError(t, s.Stop(context.Background()))
+	})
+
+	t.Run("stop timeout", func(t *testing.T) {
+		t.Parallel()
+
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		srv := grpc.NewServer(grpc.UnaryInterceptor(
+			func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				close(entered)
+				select {
+				case <-release:
+				case <-ctx.Done():
+				}
+				return handler(ctx, req)
+			},
+		))
+		healthpb.RegisterHealthServer(srv, health.NewServer())
+
+		s := New(srv, "127.0.0.1:0")
+		require.NoError(t, s.Start(context.Background()))
+		defer close(release)
+
+		conn, err := grpc.NewClient(s.Addr().String(),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		rpcDone := make(chan error)
+		go func() {
+			_, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+			rpcDone <- err
+		}()
+
+		<-entered // the RPC is now pending
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, s.Stop(ctx), context.DeadlineExceeded)
+		assert.Error(t, <-rpcDone, "pending RPC must be cancelled")
+	})
+}
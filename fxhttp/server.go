@@ -0,0 +1,250 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fxhttp runs an HTTP server
+// for as long as an Fx application runs.
+//
+// Handlers are registered by providing [Route] values
+// to the "routes" value group, for example with [AsRoute].
+// [Module] provides an *http.Server serving them,
+// configured with the [Config] in the container, if any.
+// The server starts listening when the application starts,
+// and shuts down gracefully when it stops.
+//
+//	fx.New(
+//		fx.Supply(fxhttp.Config{Addr: ":8080", ReadHeaderTimeout: time.Second}),
+//		fxhttp.Module(),
+//		fx.Provide(
+//			fxhttp.AsRoute(func(users *UserStore) fxhttp.Route {
+//				return fxhttp.Route{
+//					Pattern: "GET /users/{id}",
+//					Handler: users.Handler(),
+//				}
+//			}),
+//		),
+//	)
+//
+// Tests can bind the server to a random port with the "127.0.0.1:0" address,
+// and find out which one it's listening on with [Server.Addr].
+package fxhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/multierr"
+)
+
+// Group is the name of the value group that [Module] reads routes from.
+const Group = "routes"
+
+// Route is an HTTP handler served by [Module].
+type Route struct {
+	// Pattern is the pattern the handler is registered with,
+	// as accepted by [http.ServeMux], such as "GET /users/{id}". Required.
+	Pattern string
+
+	// Handler handles requests matching the pattern. Required.
+	Handler http.Handler
+}
+
+// AsRoute annotates a constructor that returns a [Route]
+// so that its result is served by [Module].
+//
+//	fx.Provide(fxhttp.AsRoute(newHealthRoute))
+func AsRoute(f any) any {
+	return fx.Annotate(f, fx.ResultTags(`group:"`+Group+`"`))
+}
+
+// Config configures the *http.Server provided by [Module].
+// Zero values have the same meaning as in [http.Server].
+type Config struct {
+	// Addr is the TCP address the server listens on.
+	// It defaults to ":http".
+	Addr string
+
+	// Timeouts of the server.
+	// See the documentation of http.Server for their meaning.
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+}
+
+// Module provides an *http.Server configured with the [Config]
+// in the container, if any, that serves the routes of the "routes"
+// value group, and a *[Server] that starts and stops it
+// with the application.
+//
+// If the server fails after it started listening,
+// the application is shut down with [fx.ShutdownError] and exit code 1.
+func Module() fx.Option {
+	return fx.Module("fxhttp",
+		fx.Provide(
+			newHTTPServer,
+			func(lc fx.Lifecycle, sd fx.Shutdowner, srv *http.Server) *Server {
+				s := New(srv)
+				s.shutdowner = sd
+				lc.Append(fx.StartStopHook(s.Start, s.Stop))
+				return s
+			},
+		),
+		fx.Invoke(func(*Server) {}),
+	)
+}
+
+type serverParams struct {
+	fx.In
+
+	Config Config  `optional:"true"`
+	Routes []Route `group:"routes"`
+}
+
+func newHTTPServer(p serverParams) (*http.Server, error) {
+	mux := http.NewServeMux()
+	for _, r := range p.Routes {
+		if err := handle(mux, r); err != nil {
+			return nil, err
+		}
+	}
+	return &http.Server{
+		Addr:              p.Config.Addr,
+		Handler:           mux,
+		ReadTimeout:       p.Config.ReadTimeout,
+		ReadHeaderTimeout: p.Config.ReadHeaderTimeout,
+		WriteTimeout:      p.Config.WriteTimeout,
+		IdleTimeout:       p.Config.IdleTimeout,
+	}, nil
+}
+
+// handle registers r with mux, turning the panics of mux into errors,
+// for example for invalid or conflicting patterns.
+func handle(mux *http.ServeMux, r Route) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("fxhttp: cannot register route %q: %v", r.Pattern, p)
+		}
+	}()
+	mux.Handle(r.Pattern, r.Handler)
+	return nil
+}
+
+// Server runs an *http.Server.
+// Build one with [New], or use [Module].
+type Server struct {
+	srv        *http.Server
+	shutdowner fx.Shutdowner // shuts down the application if serving fails
+
+	mu       sync.Mutex
+	ln       net.Listener  // non-nil once started
+	done     chan struct{} // closed once Serve returns
+	serveErr error         // error returned by Serve, if unexpected
+}
+
+// New builds a Server that runs srv once it's started with [Server.Start].
+func New(srv *http.Server) *Server {
+	return &Server{srv: srv}
+}
+
+// Start listens on the address of the server, and serves requests
+// in a new goroutine.
+// A server can only be started once.
+func (s *Server) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ln != nil {
+		return errors.New("fxhttp: server can only be started once")
+	}
+
+	addr := s.srv.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("fxhttp: listen on %q: %w", addr, err)
+	}
+
+	s.ln = ln
+	s.done = make(chan struct{})
+	go s.serve(ln, s.done)
+	return nil
+}
+
+func (s *Server) serve(ln net.Listener, done chan struct{}) {
+	defer close(done)
+
+	err := s.srv.Serve(ln)
+	if errors.Is(err, http.ErrServerClosed) {
+		return
+	}
+
+	err = fmt.Errorf("fxhttp: serve on %v: %w", ln.Addr(), err)
+	s.mu.Lock()
+	s.serveErr = err
+	s.mu.Unlock()
+	if s.shutdowner != nil {
+		_ = s.shutdowner.Shutdown(fx.ShutdownError(err))
+	}
+}
+
+// Stop shuts the server down gracefully, waiting for active requests
+// to complete. If ctx expires first, Stop closes the server's connections
+// and returns the error of ctx.
+//
+// Stop also returns the error the server failed with, if any.
+func (s *Server) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	done := s.done
+	s.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+
+	err := s.srv.Shutdown(ctx)
+	if err != nil {
+		_ = s.srv.Close()
+	}
+	<-done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return multierr.Append(err, s.serveErr)
+}
+
+// Addr returns the address the server is listening on,
+// or nil if it hasn't started.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ln == nil {
+		return nil
+	}
+	return s.ln.Addr()
+}
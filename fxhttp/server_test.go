@@ -0,0 +1,167 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxhttp
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func get(t *testing.T, url string) string {
+	t.Helper()
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	res, err := client.Get(url)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestModule(t *testing.T) {
+	t.Parallel()
+
+	var (
+		server *Server
+		srv    *http.Server
+	)
+	app := fxtest.New(t,
+		fx.Supply(Config{Addr: "127.0.0.1:0", ReadHeaderTimeout: time.Second}),
+		Module(),
+		fx.Provide(
+			AsRoute(func() Route {
+				return Route{
+					Pattern: "GET /hello/{name}",
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						io.WriteString(w, "hello "+r.PathValue("name"))
+					}),
+				}
+			}),
+		),
+		fx.Populate(&server, &srv),
+	)
+	assert.Nil(t, server.Addr(), "server must not listen before it starts")
+	assert.Equal(t, time.Second, srv.ReadHeaderTimeout)
+
+	app.RequireStart()
+	addr := server.Addr()
+	require.NotNil(t, addr)
+	assert.Equal(t, "hello world", get(t, "http://"+addr.String()+"/hello/world"))
+
+	app.RequireStop()
+	_, err := net.Dial("tcp", addr.String())
+	assert.Error(t, err, "server must not listen after it stops")
+}
+
+func TestModuleInvalidRoutes(t *testing.T) {
+	t.Parallel()
+
+	handler := http.NotFoundHandler()
+	app := fx.New(
+		fx.NopLogger,
+		Module(),
+		fx.Supply(
+			fx.Annotated{Group: Group, Target: Route{Pattern: "/dup", Handler: handler}},
+		),
+		fx.Provide(AsRoute(func() Route { return Route{Pattern: "/dup", Handler: handler} })),
+	)
+	assert.ErrorContains(t, app.Err(), `fxhttp: cannot register route "/dup"`)
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotStarted", func(t *testing.T) {
+		t.Parallel()
+
+		s := New(&http.Server{})
+		assert.Nil(t, s.Addr())
+		assert.NoError(t, s.Stop(context.Background()))
+	})
+
+	t.Run("StartedTwice", func(t *testing.T) {
+		t.Parallel()
+
+		s := New(&http.Server{Addr: "127.0.0.1:0"})
+		require.NoError(t, s.Start(context.Background()))
+		defer s.Stop(context.Background())
+		assert.ErrorContains(t, s.Start(context.Background()), "server can only be started once")
+	})
+
+	t.Run("ListenError", func(t *testing.T) {
+		t.Parallel()
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer ln.Close()
+
+		s := New(&http.Server{Addr: ln.Addr().String()})
+		err = s.Start(context.Background())
+		assert.ErrorContains(t, err, "fxhttp: listen on")
+		assert.Nil(t, s.Addr())
+	})
+
+	t.Run("ShutdownTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		started, release := make(chan struct{}), make(chan struct{})
+		s := New(&http.Server{
+			Addr: "127.0.0.1:0",
+			Handler: http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				close(started)
+				<-release
+			}),
+		})
+		require.NoError(t, s.Start(context.Background()))
+
+		client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+		reqDone := make(chan struct{})
+		go func() {
+			defer close(reqDone)
+			if res, err := client.Get("http://" + s.Addr().String()); err == nil {
+				res.Body.Close()
+			}
+		}()
+		<-started
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, s.Stop(ctx), context.Canceled)
+		close(release)
+		<-reqDone
+	})
+}
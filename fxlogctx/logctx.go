@@ -0,0 +1,117 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fxlogctx attaches loggers to contexts
+// so that code handling a request can log with the fields of that request.
+//
+// Attach a logger to a context with [NewContext] or [With],
+// and retrieve it with [FromContext]:
+//
+//	func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+//		ctx := fxlogctx.With(r.Context(), zap.String("user", user))
+//		h.store.Get(ctx, key)
+//	}
+//
+//	func (s *store) Get(ctx context.Context, key string) {
+//		fxlogctx.FromContext(ctx).Info("getting", zap.String("key", key))
+//	}
+//
+// [Module] provides a [Middleware] that attaches the application's
+// *zap.Logger to the context of each HTTP request,
+// along with fields identifying the request.
+//
+//	fx.New(
+//		fx.Provide(zap.NewProduction),
+//		fxlogctx.Module,
+//		fx.Invoke(func(mw fxlogctx.Middleware, mux *http.ServeMux) {
+//			// ...
+//			http.ListenAndServe(addr, mw(mux))
+//		}),
+//	)
+//
+// Functions with a Slog prefix do the same for *slog.Logger.
+package fxlogctx
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader is the HTTP header from which [Middleware]
+// reads the ID of a request.
+const RequestIDHeader = "X-Request-Id"
+
+// Module provides a [Middleware] for the *zap.Logger of the application.
+var Module = fx.Module("fxlogctx",
+	fx.Provide(NewMiddleware),
+)
+
+type zapKey struct{}
+
+// NewContext returns a copy of ctx that carries log.
+func NewContext(ctx context.Context, log *zap.Logger) context.Context {
+	return context.WithValue(ctx, zapKey{}, log)
+}
+
+// FromContext returns the logger attached to ctx.
+// If ctx does not carry a logger,
+// it returns the global logger returned by [zap.L].
+func FromContext(ctx context.Context) *zap.Logger {
+	if log, ok := ctx.Value(zapKey{}).(*zap.Logger); ok {
+		return log
+	}
+	return zap.L()
+}
+
+// With returns a copy of ctx carrying the logger of ctx
+// with the given fields added to it.
+func With(ctx context.Context, fields ...zap.Field) context.Context {
+	return NewContext(ctx, FromContext(ctx).With(fields...))
+}
+
+// Middleware wraps an HTTP handler
+// to attach a logger to the context of each request.
+type Middleware func(http.Handler) http.Handler
+
+// NewMiddleware builds a [Middleware] that attaches log to the context of
+// each request, with the following fields added to it:
+//
+//	method:     the HTTP method of the request
+//	path:       the URL path of the request
+//	request_id: the value of the X-Request-Id header, if any
+func NewMiddleware(log *zap.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fields := []zap.Field{
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+			}
+			if id := r.Header.Get(RequestIDHeader); id != "" {
+				fields = append(fields, zap.String("request_id", id))
+			}
+
+			ctx := NewContext(r.Context(), log.With(fields...))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
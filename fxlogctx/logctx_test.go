@@ -0,0 +1,133 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxlogctx
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no logger", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Same(t, zap.L(), FromContext(context.Background()))
+	})
+
+	t.Run("with fields", func(t *testing.T) {
+		t.Parallel()
+
+		core, logs := observer.New(zapcore.InfoLevel)
+		ctx := NewContext(context.Background(), zap.New(core))
+		ctx = With(ctx, zap.String("user", "alice"))
+		ctx = With(ctx, zap.Int("attempt", 2))
+
+		FromContext(ctx).Info("hello")
+
+		entries := logs.AllUntimed()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "hello", entries[0].Message)
+		assert.Equal(t, map[string]interface{}{
+			"user":    "alice",
+			"attempt": int64(2),
+		}, entries[0].ContextMap())
+	})
+}
+
+func TestSlogFromContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no logger", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Same(t, slog.Default(), SlogFromContext(context.Background()))
+	})
+
+	t.Run("with attributes", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		log := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+		ctx := NewSlogContext(context.Background(), log)
+		ctx = SlogWith(ctx, "user", "alice")
+
+		SlogFromContext(ctx).Info("hello")
+		assert.Equal(t, "level=INFO msg=hello user=alice\n", buf.String())
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	var mw Middleware
+	app := fxtest.New(t,
+		fx.Supply(zap.New(core)),
+		Module,
+		fx.Populate(&mw),
+	)
+	defer app.RequireStart().RequireStop()
+
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info("handling")
+	}))
+
+	req := httptest.NewRequest("GET", "/users/alice", nil)
+	req.Header.Set(RequestIDHeader, "abc123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest("POST", "/users", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, map[string]interface{}{
+		"method":     "GET",
+		"path":       "/users/alice",
+		"request_id": "abc123",
+	}, entries[0].ContextMap())
+	assert.Equal(t, map[string]interface{}{
+		"method": "POST",
+		"path":   "/users",
+	}, entries[1].ContextMap())
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxlogctx
+
+import (
+	"context"
+	"log/slog"
+)
+
+type slogKey struct{}
+
+// NewSlogContext returns a copy of ctx that carries log.
+func NewSlogContext(ctx context.Context, log *slog.Logger) context.Context {
+	return context.WithValue(ctx, slogKey{}, log)
+}
+
+// SlogFromContext returns the slog logger attached to ctx.
+// If ctx does not carry one, it returns [slog.Default].
+func SlogFromContext(ctx context.Context) *slog.Logger {
+	if log, ok := ctx.Value(slogKey{}).(*slog.Logger); ok {
+		return log
+	}
+	return slog.Default()
+}
+
+// SlogWith returns a copy of ctx carrying the slog logger of ctx
+// with the given attributes added to it.
+// Arguments are handled as with [slog.Logger.With].
+func SlogWith(ctx context.Context, args ...any) context.Context {
+	return NewSlogContext(ctx, SlogFromContext(ctx).With(args...))
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fxplugin loads Fx options from Go plugins at runtime.
+//
+// A plugin is a Go package in package main built with
+// -buildmode=plugin that exports a function with the following
+// signature:
+//
+//	func FxOptions() fx.Option
+//
+// Options loaded from a plugin are placed inside an [fx.Module] named
+// after the plugin file, so that events logged by Fx and errors returned
+// by the application identify the plugin they came from.
+//
+// See the documentation of the standard library plugin package for the
+// restrictions that apply to Go plugins.
+package fxplugin
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"plugin"
+	"strings"
+
+	"go.uber.org/fx"
+)
+
+// Symbol is the name of the function that plugins must export.
+const Symbol = "FxOptions"
+
+// lookuper is the subset of *plugin.Plugin used by this package.
+type lookuper interface {
+	Lookup(string) (plugin.Symbol, error)
+}
+
+// open opens the plugin at the given path. Overridden in tests.
+var open = func(path string) (lookuper, error) {
+	return plugin.Open(path)
+}
+
+// Load loads the plugins at the given paths and returns their options.
+//
+// Each plugin's options are placed inside an [fx.Module] named after the
+// file without its extension. If a plugin cannot be loaded, does not
+// export [Symbol] with the expected signature, or panics while producing
+// its options, the application fails with an error naming the plugin.
+func Load(paths ...string) fx.Option {
+	opts := make([]fx.Option, 0, len(paths))
+	for _, path := range paths {
+		opts = append(opts, load(path))
+	}
+	return fx.Options(opts...)
+}
+
+// LoadDir loads all plugins with the ".so" extension in the given
+// directory in lexical order. See [Load] for details.
+func LoadDir(dir string) fx.Option {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return fx.Error(fmt.Errorf("fxplugin: %w", err))
+	}
+	return Load(paths...)
+}
+
+// Name returns the name of the module that options loaded from the
+// plugin at the given path are placed in.
+func Name(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+func load(path string) fx.Option {
+	opt, err := options(path)
+	if err != nil {
+		return fx.Error(fmt.Errorf("fxplugin: load %q: %w", path, err))
+	}
+	return fx.Module(Name(path), opt)
+}
+
+func options(path string) (opt fx.Option, err error) {
+	p, err := open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := p.Lookup(Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	fn, ok := sym.(func() fx.Option)
+	if !ok {
+		return nil, fmt.Errorf("symbol %v has type %T, want func() fx.Option", Symbol, sym)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v panicked: %v", Symbol, r)
+		}
+	}()
+
+	opt = fn()
+	if opt == nil {
+		return nil, errors.New(Symbol + " returned a nil fx.Option")
+	}
+	return opt, nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxplugin
+
+import (
+	"errors"
+	"fmt"
+	"plugin"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type fakePlugin map[string]plugin.Symbol
+
+func (p fakePlugin) Lookup(name string) (plugin.Symbol, error) {
+	sym, ok := p[name]
+	if !ok {
+		return nil, fmt.Errorf("symbol %v not found", name)
+	}
+	return sym, nil
+}
+
+// stubOpen replaces open for the duration of the test.
+// Tests that use it must not run in parallel.
+func stubOpen(t *testing.T, plugins map[string]fakePlugin) {
+	orig := open
+	t.Cleanup(func() { open = orig })
+
+	open = func(path string) (lookuper, error) {
+		p, ok := plugins[path]
+		if !ok {
+			return nil, errors.New("no such file")
+		}
+		return p, nil
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		stubOpen(t, map[string]fakePlugin{
+			"plugins/greeter.so": {
+				Symbol: func() fx.Option {
+					return fx.Provide(func() string { return "hello" })
+				},
+			},
+		})
+
+		var got string
+		app := fxtest.New(t,
+			Load("plugins/greeter.so"),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Equal(t, "hello", got)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		stubOpen(t, map[string]fakePlugin{
+			"missing-symbol.so": {},
+			"wrong-type.so":     {Symbol: func() error { return nil }},
+			"panics.so":         {Symbol: func() fx.Option { panic("great sadness") }},
+			"nil.so":            {Symbol: func() fx.Option { return nil }},
+		})
+
+		tests := []struct {
+			path    string
+			wantErr string
+		}{
+			{"does-not-exist.so", "no such file"},
+			{"missing-symbol.so", "symbol FxOptions not found"},
+			{"wrong-type.so", "symbol FxOptions has type func() error, want func() fx.Option"},
+			{"panics.so", "FxOptions panicked: great sadness"},
+			{"nil.so", "FxOptions returned a nil fx.Option"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.path, func(t *testing.T) {
+				err := fx.New(fx.NopLogger, Load(tt.path)).Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), fmt.Sprintf("fxplugin: load %q", tt.path))
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "greeter", Name("/usr/lib/app/greeter.so"))
+	assert.Equal(t, "greeter", Name("greeter"))
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+// Package fxsd reports the lifecycle of an Fx application
+// to process supervisors: systemd through the sd_notify protocol,
+// and Kubernetes through the preStop convention.
+//
+// [Module] watches the events of the application, and
+//
+//   - sends READY=1 once the application has started,
+//   - sends WATCHDOG=1 periodically while the application runs,
+//     if systemd enabled the watchdog for the service,
+//   - sends STOPPING=1 when [fx.App.Run] receives a shutdown signal,
+//     after waiting for the [PreStopDelay], if any.
+//
+// Outside of systemd, where NOTIFY_SOCKET is unset,
+// no notifications are sent.
+//
+// Module decorates the [fxevent.Logger] of the application,
+// so the application must specify one with [fx.WithLogger],
+// and Module must be passed to the top-level App:
+//
+//	fx.New(
+//		fx.WithLogger(func(log *zap.Logger) fxevent.Logger {
+//			return &fxevent.ZapLogger{Logger: log}
+//		}),
+//		fxsd.Module(fxsd.PreStopDelay(5*time.Second)),
+//		// ...
+//	).Run()
+package fxsd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+)
+
+// Environment variables set by systemd for the service.
+const (
+	notifySocketEnv = "NOTIFY_SOCKET"
+	watchdogUSecEnv = "WATCHDOG_USEC"
+	watchdogPIDEnv  = "WATCHDOG_PID"
+)
+
+// Notification states of the sd_notify protocol.
+const (
+	Ready    = "READY=1"
+	Stopping = "STOPPING=1"
+	Watchdog = "WATCHDOG=1"
+)
+
+// Module provides a *[Notifier] configured with the given options,
+// and decorates the [fxevent.Logger] of the application
+// to send notifications as the application starts and stops.
+func Module(opts ...Option) fx.Option {
+	return fx.Options(
+		fx.Provide(func() *Notifier { return New(opts...) }),
+		fx.Decorate(func(log fxevent.Logger, n *Notifier) fxevent.Logger {
+			return fxevent.Tee(log, n)
+		}),
+	)
+}
+
+// Option configures a [Notifier].
+type Option interface {
+	apply(*Notifier)
+}
+
+// Socket sets the path of the socket notifications are sent to.
+// It defaults to the value of the NOTIFY_SOCKET environment variable.
+// If the path is empty, no notifications are sent.
+func Socket(path string) Option {
+	return socketOption(path)
+}
+
+type socketOption string
+
+func (o socketOption) apply(n *Notifier) {
+	n.socket = string(o)
+}
+
+// WatchdogInterval sets how long systemd waits for a WATCHDOG=1
+// notification before it considers the service hung.
+// The Notifier sends notifications twice as often.
+//
+// It defaults to the value of the WATCHDOG_USEC environment variable
+// if WATCHDOG_PID is unset or matches the current process.
+// If the interval is zero, no watchdog notifications are sent.
+func WatchdogInterval(d time.Duration) Option {
+	return watchdogOption(d)
+}
+
+type watchdogOption time.Duration
+
+func (o watchdogOption) apply(n *Notifier) {
+	n.watchdog = time.Duration(o)
+}
+
+// PreStopDelay sets how long [fx.App.Run] keeps the application running
+// after it receives a shutdown signal, and before its OnStop hooks run.
+//
+// This follows the Kubernetes convention of sleeping in a preStop hook:
+// it gives load balancers time to stop sending requests to a pod
+// that is being terminated, before the application stops serving them.
+// The delay counts towards the termination grace period of the pod.
+//
+// It defaults to zero.
+func PreStopDelay(d time.Duration) Option {
+	return preStopDelayOption(d)
+}
+
+type preStopDelayOption time.Duration
+
+func (o preStopDelayOption) apply(n *Notifier) {
+	n.preStopDelay = time.Duration(o)
+}
+
+// Notifier sends sd_notify notifications for an application.
+// It implements [fxevent.Logger] to send them
+// as the application starts and stops.
+//
+// All methods on Notifier are concurrency-safe.
+type Notifier struct {
+	socket       string
+	watchdog     time.Duration
+	preStopDelay time.Duration
+	sleep        func(time.Duration) // time.Sleep override; used for testing only
+
+	mu           sync.Mutex
+	stopWatchdog chan struct{} // non-nil while the watchdog runs
+	watchdogDone chan struct{}
+}
+
+var _ fxevent.Logger = (*Notifier)(nil)
+
+// New builds a Notifier configured with the given options
+// and the environment of the process.
+func New(opts ...Option) *Notifier {
+	n := &Notifier{
+		socket:   os.Getenv(notifySocketEnv),
+		watchdog: watchdogFromEnv(),
+		sleep:    time.Sleep,
+	}
+	for _, opt := range opts {
+		opt.apply(n)
+	}
+	return n
+}
+
+// watchdogFromEnv returns the watchdog interval requested by systemd
+// for this process, or zero if none was requested.
+func watchdogFromEnv() time.Duration {
+	if pid := os.Getenv(watchdogPIDEnv); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	usec, err := strconv.ParseInt(os.Getenv(watchdogUSecEnv), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// Enabled reports whether the Notifier sends notifications.
+func (n *Notifier) Enabled() bool {
+	return n.socket != ""
+}
+
+// Notify sends the given states, such as [Ready] or "STATUS=...",
+// in a single notification.
+// It does nothing if the Notifier is not [Notifier.Enabled].
+func (n *Notifier) Notify(states ...string) error {
+	if !n.Enabled() || len(states) == 0 {
+		return nil
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.socket, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("fxsd: connect to %q: %w", n.socket, err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(strings.Join(states, "\n"))); err != nil {
+		return fmt.Errorf("fxsd: notify %q: %w", n.socket, err)
+	}
+	return nil
+}
+
+// LogEvent sends the notifications for the given event.
+// Notifications that fail to send are dropped:
+// supervision must not prevent the application from running.
+func (n *Notifier) LogEvent(event fxevent.Event) {
+	switch e := event.(type) {
+	case *fxevent.Started:
+		if e.Err != nil {
+			_ = n.Notify(status("failed to start: " + e.Err.Error()))
+			return
+		}
+		_ = n.Notify(Ready, status("running"))
+		n.startWatchdog()
+	case *fxevent.Stopping:
+		if n.preStopDelay > 0 {
+			_ = n.Notify(status(fmt.Sprintf("stopping in %v", n.preStopDelay)))
+			n.sleep(n.preStopDelay)
+		}
+		_ = n.Notify(Stopping, status("stopping"))
+	case *fxevent.Stopped:
+		n.stopWatchdogLoop()
+		if e.Err != nil {
+			_ = n.Notify(status("failed to stop: " + e.Err.Error()))
+		}
+	}
+}
+
+// status returns a STATUS notification with the given message,
+// which must fit on a single line.
+func status(msg string) string {
+	return "STATUS=" + strings.ReplaceAll(msg, "\n", " ")
+}
+
+func (n *Notifier) startWatchdog() {
+	if !n.Enabled() || n.watchdog <= 0 {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.stopWatchdog != nil {
+		return // already running
+	}
+
+	stop, done := make(chan struct{}), make(chan struct{})
+	n.stopWatchdog, n.watchdogDone = stop, done
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(n.watchdog / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				_ = n.Notify(Watchdog)
+			}
+		}
+	}()
+}
+
+// stopWatchdogLoop stops sending watchdog notifications,
+// and waits for the goroutine sending them to exit.
+func (n *Notifier) stopWatchdogLoop() {
+	n.mu.Lock()
+	stop, done := n.stopWatchdog, n.watchdogDone
+	n.stopWatchdog, n.watchdogDone = nil, nil
+	n.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fxsd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+// listen returns the path of a socket that receives notifications,
+// and a channel of the notifications it received.
+func listen(t *testing.T) (string, <-chan string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unixgram sockets are not supported on Windows")
+	}
+
+	// Socket paths are limited in length, and t.TempDir may be too long.
+	dir, err := os.MkdirTemp("", "fxsd")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+
+	msgs := make(chan string, 100)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf := make([]byte, 4096)
+		for {
+			n, _, err := conn.ReadFromUnix(buf)
+			if err != nil {
+				return
+			}
+			msgs <- string(buf[:n])
+		}
+	}()
+	t.Cleanup(func() {
+		conn.Close()
+		<-done
+	})
+	return path, msgs
+}
+
+func receive(t *testing.T, msgs <-chan string) string {
+	select {
+	case msg := <-msgs:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a notification")
+		return ""
+	}
+}
+
+func TestModule(t *testing.T) {
+	t.Parallel()
+
+	t.Run("notifies", func(t *testing.T) {
+		t.Parallel()
+
+		path, msgs := listen(t)
+		var shutdowner fx.Shutdowner
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return fxevent.NopLogger }),
+			Module(Socket(path), WatchdogInterval(0)),
+			fx.Populate(&shutdowner),
+		)
+
+		errc := make(chan error, 1)
+		go func() { errc <- app.RunErr() }()
+		assert.Equal(t, "READY=1\nSTATUS=running", receive(t, msgs))
+
+		require.NoError(t, shutdowner.Shutdown())
+		assert.Equal(t, "STOPPING=1\nSTATUS=stopping", receive(t, msgs))
+		assert.NoError(t, <-errc)
+	})
+
+	t.Run("start failure", func(t *testing.T) {
+		t.Parallel()
+
+		path, msgs := listen(t)
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return fxevent.NopLogger }),
+			Module(Socket(path), WatchdogInterval(0)),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() error {
+					return errors.New("great\nsadness")
+				}))
+			}),
+		)
+		require.Error(t, app.Start(context.Background()))
+		assert.Equal(t, "STATUS=failed to start: great sadness", receive(t, msgs))
+	})
+}
+
+func TestNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		n := New(Socket(""))
+		assert.False(t, n.Enabled())
+		assert.NoError(t, n.Notify(Ready))
+		n.LogEvent(&fxevent.Started{})
+		n.LogEvent(&fxevent.Stopped{})
+	})
+
+	t.Run("watchdog", func(t *testing.T) {
+		t.Parallel()
+
+		path, msgs := listen(t)
+		n := New(Socket(path), WatchdogInterval(10*time.Millisecond))
+		n.LogEvent(&fxevent.Started{})
+		assert.Equal(t, "READY=1\nSTATUS=running", receive(t, msgs))
+		assert.Equal(t, "WATCHDOG=1", receive(t, msgs))
+		assert.Equal(t, "WATCHDOG=1", receive(t, msgs))
+		n.LogEvent(&fxevent.Stopped{})
+	})
+
+	t.Run("pre-stop delay", func(t *testing.T) {
+		t.Parallel()
+
+		path, msgs := listen(t)
+		n := New(Socket(path), PreStopDelay(5*time.Second))
+
+		var slept time.Duration
+		n.sleep = func(d time.Duration) {
+			// The delay is announced before sleeping.
+			assert.Equal(t, "STATUS=stopping in 5s", receive(t, msgs))
+			slept = d
+		}
+		n.LogEvent(&fxevent.Stopping{})
+		assert.Equal(t, 5*time.Second, slept)
+		assert.Equal(t, "STOPPING=1\nSTATUS=stopping", receive(t, msgs))
+	})
+
+	t.Run("unreachable socket", func(t *testing.T) {
+		t.Parallel()
+
+		n := New(Socket(filepath.Join(t.TempDir(), "missing.sock")))
+		assert.ErrorContains(t, n.Notify(Ready), "fxsd: connect to")
+	})
+}
+
+func TestWatchdogFromEnv(t *testing.T) {
+	tests := []struct {
+		desc string
+		usec string
+		pid  string
+		want time.Duration
+	}{
+		{desc: "unset"},
+		{desc: "interval", usec: "30000000", want: 30 * time.Second},
+		{desc: "this process", usec: "1000", pid: strconv.Itoa(os.Getpid()), want: time.Millisecond},
+		{desc: "other process", usec: "1000", pid: "1"},
+		{desc: "invalid", usec: "soon"},
+		{desc: "negative", usec: "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Setenv(watchdogUSecEnv, tt.usec)
+			t.Setenv(watchdogPIDEnv, tt.pid)
+			assert.Equal(t, tt.want, watchdogFromEnv())
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/internal/fxhook"
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// ErrChaos is the error that [Chaos] injects into constructors and hooks,
+// wrapped with the name of the function that failed.
+var ErrChaos = errors.New("fxtest: failure injected by fxtest.Chaos")
+
+// Chaos makes constructors and lifecycle hooks fail at random, so that tests
+// exercise how the application handles failures while it starts and stops,
+// such as the rollback of the hooks that already started.
+//
+// Each call to a constructor or hook fails with probability failureRate,
+// from 0 to 1, either with an error that wraps [ErrChaos],
+// or with a panic whose value does.
+// Chaos recovers from these panics with [fx.RecoverPanicsIn],
+// so that they're reported as errors as well.
+//
+// Failures are drawn from a source seeded with seed: an application that
+// runs its constructors and hooks in the same order fails the same way
+// for the same seed. Log the seed to reproduce a failure.
+//
+//	seed := time.Now().UnixNano()
+//	t.Logf("chaos seed: %v", seed)
+//	app := fx.New(
+//		fxtest.Chaos(seed, 0.1),
+//		...
+//	)
+//
+// Chaos is built on [fx.WithConstructorMiddleware],
+// and decorates [fx.Lifecycle] to wrap the hooks appended to it.
+// It may only be passed to fx.New, not to fx.Module.
+func Chaos(seed int64, failureRate float64) fx.Option {
+	if failureRate < 0 || failureRate > 1 {
+		return fx.Error(fmt.Errorf("fxtest.Chaos: failure rate must be between 0 and 1, got %v", failureRate))
+	}
+
+	c := &chaos{rand: rand.New(rand.NewSource(seed)), rate: failureRate}
+	return fx.Options(
+		fx.WithConstructorMiddleware(c.constructorMiddleware),
+		fx.Decorate(c.lifecycle),
+		fx.RecoverPanicsIn(fx.PanicsInConstructors, fx.PanicsInHooks),
+	)
+}
+
+type chaos struct {
+	mu   sync.Mutex // guards rand
+	rand *rand.Rand
+	rate float64
+}
+
+// strike decides whether the call to the named function fails,
+// and fails it by panicking or returning an error.
+func (c *chaos) strike(kind, name string) error {
+	c.mu.Lock()
+	fail := c.rand.Float64() < c.rate
+	panics := fail && c.rand.Intn(2) == 0
+	c.mu.Unlock()
+
+	if !fail {
+		return nil
+	}
+	err := fmt.Errorf("%w: %v %v", ErrChaos, kind, name)
+	if panics {
+		panic(err)
+	}
+	return err
+}
+
+func (c *chaos) constructorMiddleware(next fx.ConstructorCall) fx.ConstructorCall {
+	return func(info fx.ConstructorInfo, args []interface{}) ([]interface{}, error) {
+		if err := c.strike("constructor", info.FunctionName); err != nil {
+			return nil, err
+		}
+		return next(info, args)
+	}
+}
+
+func (c *chaos) lifecycle(lc fx.Lifecycle) fx.Lifecycle {
+	return chaosLifecycle{lc: lc, chaos: c}
+}
+
+// chaosLifecycle is an fx.Lifecycle whose hooks may fail
+// as decided by Chaos.
+type chaosLifecycle struct {
+	lc    fx.Lifecycle
+	chaos *chaos
+}
+
+// Append wraps the functions of h, keeping the names and caller
+// that Fx reports for them if lc is the Lifecycle of the application.
+func (l chaosLifecycle) Append(h fx.Hook) {
+	if a, ok := l.lc.(fxhook.Appender); ok {
+		a.AppendWrapped(h, 1, l.wrap)
+		return
+	}
+
+	// Fx reports the functions below instead of those of h.
+	h.OnStart = l.wrapFunc("OnStart", h.OnStart)
+	h.OnStop = l.wrapFunc("OnStop", h.OnStop)
+	h.OnPause = l.wrapFunc("OnPause", h.OnPause)
+	h.OnResume = l.wrapFunc("OnResume", h.OnResume)
+	l.lc.Append(h)
+}
+
+func (l chaosLifecycle) wrapFunc(kind string, fn fxhook.Func) fxhook.Func {
+	if fn == nil {
+		return nil
+	}
+	return l.wrap(kind, fxreflect.FuncName(fn), fn)
+}
+
+func (l chaosLifecycle) wrap(kind, name string, fn fxhook.Func) fxhook.Func {
+	kind += " hook"
+	return func(ctx context.Context) error {
+		if err := l.chaos.strike(kind, name); err != nil {
+			return err
+		}
+		return fn(ctx)
+	}
+}
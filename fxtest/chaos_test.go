@@ -0,0 +1,147 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+func TestChaos(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+
+	// startHooks builds an application with n OnStart hooks,
+	// and returns the indexes of the hooks that ran, and the error of Start.
+	startHooks := func(t *testing.T, seed int64, rate float64, n int) ([]int, error) {
+		var ran []int
+		app := fx.New(
+			fx.NopLogger,
+			Chaos(seed, rate),
+			fx.Module("hooks", fx.Invoke(func(lc fx.Lifecycle) {
+				for i := 0; i < n; i++ {
+					i := i
+					lc.Append(fx.Hook{OnStart: func(context.Context) error {
+						ran = append(ran, i)
+						return nil
+					}})
+				}
+			})),
+		)
+		require.NoError(t, app.Err())
+		err := app.Start(context.Background())
+		if err == nil {
+			require.NoError(t, app.Stop(context.Background()))
+		}
+		return ran, err
+	}
+
+	t.Run("NoFailures", func(t *testing.T) {
+		t.Parallel()
+
+		app := New(t,
+			Chaos(42, 0),
+			fx.Provide(func() *A { return &A{} }),
+			fx.Invoke(func(*A) {}),
+		)
+		app.RequireStart().RequireStop()
+	})
+
+	t.Run("Constructors", func(t *testing.T) {
+		t.Parallel()
+
+		for seed := int64(0); seed < 10; seed++ {
+			app := fx.New(
+				fx.NopLogger,
+				Chaos(seed, 1),
+				fx.Provide(func() *A { return &A{} }),
+				fx.Invoke(func(*A) {}),
+			)
+			err := app.Err()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), ErrChaos.Error())
+			assert.Contains(t, err.Error(), "constructor go.uber.org/fx/fxtest.TestChaos")
+		}
+	})
+
+	t.Run("Hooks", func(t *testing.T) {
+		t.Parallel()
+
+		for seed := int64(0); seed < 10; seed++ {
+			ran, err := startHooks(t, seed, 1, 1)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), ErrChaos.Error())
+			assert.Empty(t, ran)
+		}
+	})
+
+	t.Run("Deterministic", func(t *testing.T) {
+		t.Parallel()
+
+		ran1, err1 := startHooks(t, 7, 0.1, 20)
+		ran2, err2 := startHooks(t, 7, 0.1, 20)
+		require.Error(t, err1, "seed 7 should fail one of the hooks")
+		require.Error(t, err2)
+		assert.Less(t, len(ran1), 20)
+		assert.Equal(t, ran1, ran2)
+	})
+
+	t.Run("HookNames", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return &spy }),
+			Chaos(1, 0),
+			fx.Invoke(chaosHookAppender),
+		)
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+
+		events := spy.Events().SelectByTypeName("OnStartExecuted")
+		require.Len(t, events, 1)
+		e := events[0].(*fxevent.OnStartExecuted)
+		assert.Equal(t, "go.uber.org/fx/fxtest.chaosHookStart()", e.FunctionName)
+		assert.Equal(t, "go.uber.org/fx/fxtest.chaosHookAppender", e.CallerName)
+	})
+
+	t.Run("InvalidRate", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(fx.NopLogger, Chaos(1, 1.5))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fxtest.Chaos: failure rate must be between 0 and 1, got 1.5")
+	})
+}
+
+func chaosHookStart(context.Context) error { return nil }
+
+func chaosHookAppender(lc fx.Lifecycle) {
+	lc.Append(fx.Hook{OnStart: chaosHookStart})
+}
@@ -187,10 +187,22 @@ func (l *Lifecycle) RequireStop() {
 	}
 }
 
+// Pause executes all OnPause hooks of started hooks in reverse order.
+func (l *Lifecycle) Pause(ctx context.Context) error {
+	return l.withTimeout(ctx, l.lc.Pause)
+}
+
+// Resume executes all OnResume hooks of paused hooks in order.
+func (l *Lifecycle) Resume(ctx context.Context) error {
+	return l.withTimeout(ctx, l.lc.Resume)
+}
+
 // Append registers a new Hook.
 func (l *Lifecycle) Append(h fx.Hook) {
 	l.lc.Append(lifecycle.Hook{
-		OnStart: h.OnStart,
-		OnStop:  h.OnStop,
+		OnStart:  h.OnStart,
+		OnStop:   h.OnStop,
+		OnPause:  h.OnPause,
+		OnResume: h.OnResume,
 	})
 }
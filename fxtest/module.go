@@ -0,0 +1,99 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import "go.uber.org/fx"
+
+// ModuleOption configures the module harness built by RequireModule.
+type ModuleOption interface {
+	apply(*moduleOptions)
+}
+
+type moduleOptions struct {
+	opts []fx.Option
+}
+
+// Stub provides value as a T to the module under test. Use it to satisfy
+// the dependencies that the module expects the rest of the application to
+// provide.
+//
+//	m := fxtest.RequireModule(t, mymodule.Module,
+//		fxtest.Stub[*Config](cfg),
+//		fxtest.Stub[io.Writer](&buf),
+//	)
+func Stub[T any](value T) ModuleOption {
+	return stubOption[T]{value: value}
+}
+
+type stubOption[T any] struct{ value T }
+
+func (o stubOption[T]) apply(mo *moduleOptions) {
+	value := o.value
+	mo.opts = append(mo.opts, fx.Provide(func() T { return value }))
+}
+
+// Module is a test harness around a single Fx module and the stubs
+// declared for its dependencies.
+type Module struct {
+	tb   TB
+	opts []fx.Option
+}
+
+// RequireModule builds a harness for testing the wiring of module in
+// isolation. Only module and the given stubs are made available to the
+// container. The module is validated without running any of its
+// constructors; validation failures fail the test.
+//
+// Use Get to retrieve values from the module.
+func RequireModule(tb TB, module fx.Option, opts ...ModuleOption) *Module {
+	var mo moduleOptions
+	for _, opt := range opts {
+		opt.apply(&mo)
+	}
+
+	moduleOpts := make([]fx.Option, 0, len(mo.opts)+1)
+	moduleOpts = append(moduleOpts, module)
+	moduleOpts = append(moduleOpts, mo.opts...)
+
+	validateOpts := append([]fx.Option{WithTestLogger(tb)}, moduleOpts...)
+	if err := fx.ValidateApp(validateOpts...); err != nil {
+		tb.Errorf("module validation failed: %v", err)
+		tb.FailNow()
+	}
+
+	return &Module{
+		tb:   tb,
+		opts: moduleOpts,
+	}
+}
+
+// Get builds the module and returns the value of type T from it, failing
+// the test if it cannot be built. Each call builds a new container, so
+// values are not shared between calls to Get. Lifecycle hooks appended
+// by the module are not run.
+func Get[T any](m *Module) T {
+	var v T
+	opts := make([]fx.Option, 0, len(m.opts)+1)
+	opts = append(opts, m.opts...)
+	opts = append(opts, fx.Populate(&v))
+	New(m.tb, opts...)
+	return v
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+)
+
+func TestRequireModule(t *testing.T) {
+	t.Parallel()
+
+	type config struct{ Name string }
+	type greeter struct{ Greeting string }
+
+	module := fx.Module("greeter",
+		fx.Provide(func(cfg *config) *greeter {
+			return &greeter{Greeting: "hello " + cfg.Name}
+		}),
+		fx.Invoke(func(*greeter) {}),
+	)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+
+		m := RequireModule(spy, module, Stub(&config{Name: "fx"}))
+		g := Get[*greeter](m)
+
+		assert.Zero(t, spy.failures, "Module didn't build cleanly.")
+		assert.Equal(t, "hello fx", g.Greeting)
+	})
+
+	t.Run("MissingStub", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+
+		RequireModule(spy, module)
+
+		assert.Equal(t, 1, spy.failures, "Expected module validation to fail.")
+		assert.Contains(t, spy.errors.String(), "module validation failed")
+		assert.Contains(t, spy.errors.String(), "missing type: *fxtest.config")
+	})
+
+	t.Run("StubAsInterface", func(t *testing.T) {
+		t.Parallel()
+
+		type namer interface{ Name() string }
+
+		spy := newTB()
+
+		m := RequireModule(spy,
+			fx.Module("named", fx.Provide(func(n namer) string { return n.Name() })),
+			Stub[namer](fakeNamer("stub")),
+		)
+
+		assert.Equal(t, "stub", Get[string](m))
+		assert.Zero(t, spy.failures)
+	})
+
+	t.Run("GetMissingType", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+
+		m := RequireModule(spy, module, Stub(&config{}))
+		Get[int](m)
+
+		assert.Equal(t, 1, spy.failures, "Expected Get to fail.")
+		assert.Contains(t, spy.errors.String(), "New failed")
+	})
+}
+
+type fakeNamer string
+
+func (n fakeNamer) Name() string { return string(n) }
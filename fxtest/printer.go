@@ -21,6 +21,8 @@
 package fxtest
 
 import (
+	"reflect"
+
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxevent"
 	"go.uber.org/fx/internal/fxlog"
@@ -40,6 +42,60 @@ func WithTestLogger(t TB) fx.Option {
 	})
 }
 
+// NewQuietTestLogger returns an fxevent.Logger that logs to the testing TB
+// like [NewTestLogger], but only logs the Started and Stopped events,
+// and events that carry a non-nil error.
+// This keeps the output of large test suites short
+// while still reporting why an application failed.
+func NewQuietTestLogger(t TB) fxevent.Logger {
+	return &quietLogger{Logger: NewTestLogger(t)}
+}
+
+// WithQuietTestLogger returns an fx.Option that uses the provided TB
+// as the destination for Fx's log output, logging only failures
+// and the Started and Stopped events.
+// See [NewQuietTestLogger].
+//
+//	app := fxtest.New(t, fxtest.WithQuietTestLogger(t), opts...)
+func WithQuietTestLogger(t TB) fx.Option {
+	return fx.WithLogger(func() fxevent.Logger {
+		return NewQuietTestLogger(t)
+	})
+}
+
+type quietLogger struct {
+	fxevent.Logger
+}
+
+func (l *quietLogger) LogEvent(e fxevent.Event) {
+	switch e.(type) {
+	case *fxevent.Started, *fxevent.Stopped:
+	default:
+		if eventErr(e) == nil {
+			return
+		}
+	}
+	l.Logger.LogEvent(e)
+}
+
+// eventErr returns the error carried by e, if any.
+func eventErr(e fxevent.Event) error {
+	v := reflect.ValueOf(e)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	for _, name := range []string{"Err", "StartErr"} {
+		f := v.Elem().FieldByName(name)
+		if !f.IsValid() || !f.CanInterface() {
+			continue
+		}
+		if err, ok := f.Interface().(error); ok && err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type testPrinter struct {
 	TB
 }
@@ -21,9 +21,12 @@
 package fxtest
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
 )
 
 func TestNewTestPrinter(t *testing.T) {
@@ -37,3 +40,47 @@ func TestNewTestPrinter(t *testing.T) {
 dynamic 1
 `, spy.logs.String())
 }
+
+func TestQuietTestLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Events", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+		logger := NewQuietTestLogger(spy)
+		logger.LogEvent(&fxevent.Provided{ConstructorName: "quiet"})
+		logger.LogEvent(&fxevent.Invoked{FunctionName: "quiet"})
+		logger.LogEvent(&fxevent.Invoked{
+			FunctionName: "loud",
+			Err:          errors.New("great sadness"),
+		})
+		logger.LogEvent(&fxevent.RollingBack{StartErr: errors.New("rollback cause")})
+		logger.LogEvent(&fxevent.Started{})
+		logger.LogEvent(&fxevent.Stopped{Err: errors.New("stop failure")})
+
+		logs := spy.logs.String()
+		assert.NotContains(t, logs, "quiet")
+		assert.Contains(t, logs, "great sadness")
+		assert.Contains(t, logs, "rollback cause")
+		assert.Contains(t, logs, "RUNNING")
+		assert.Contains(t, logs, "stop failure")
+	})
+
+	t.Run("App", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+		New(spy,
+			WithQuietTestLogger(spy),
+			fx.Provide(func() string { return "" }),
+			fx.Invoke(func(string) {}),
+		).RequireStart().RequireStop()
+
+		assert.Zero(t, spy.failures)
+		logs := spy.logs.String()
+		assert.NotContains(t, logs, "PROVIDE")
+		assert.NotContains(t, logs, "INVOKE")
+		assert.Contains(t, logs, "[Fx] RUNNING")
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/fx"
+)
+
+// Resource provides a value of type T backed by an external resource,
+// such as a database container or a message broker, to a test application.
+//
+// start acquires the resource when the value is first needed.
+// It returns the value and an optional function that releases the resource.
+// The release function runs as an OnStop hook of the application,
+// so the resource is torn down by [App.RequireStop].
+// If the application is never stopped, for example because fx.New or
+// Start failed, the resource is released when t's test finishes instead,
+// and release errors are reported to t.
+//
+// Because the hook is appended before the constructors that depend on T
+// run, components that use the resource are stopped before it's released.
+//
+//	app := fxtest.New(t,
+//		fxtest.Resource(t, func(ctx context.Context) (*sql.DB, func(context.Context) error, error) {
+//			c, err := postgres.Run(ctx, "postgres:16")
+//			if err != nil {
+//				return nil, nil, err
+//			}
+//			db, err := sql.Open("pgx", c.MustConnectionString(ctx))
+//			return db, c.Terminate, err
+//		}),
+//		fx.Provide(NewUserStore),
+//		fx.Invoke(func(*UserStore) {}),
+//	)
+//	defer app.RequireStart().RequireStop()
+//
+// start runs while the application is built, before any start timeout
+// applies, so it should bound its own work.
+// If start fails, the value is not provided and building the application
+// fails with its error.
+func Resource[T any](t CleanupTB, start func(context.Context) (T, func(context.Context) error, error)) fx.Option {
+	return fx.Provide(func(lc fx.Lifecycle) (T, error) {
+		v, stop, err := start(context.Background())
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		if stop != nil {
+			var once sync.Once
+			release := func(ctx context.Context) (err error) {
+				once.Do(func() { err = stop(ctx) })
+				return err
+			}
+			lc.Append(fx.StopHook(release))
+			t.Cleanup(func() {
+				if err := release(context.Background()); err != nil {
+					t.Errorf("fxtest.Resource: release failed: %v", err)
+				}
+			})
+		}
+		return v, nil
+	})
+}
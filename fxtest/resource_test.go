@@ -0,0 +1,132 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+)
+
+type testResource struct{ name string }
+
+func TestResource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("TornDownAfterComponents", func(t *testing.T) {
+		t.Parallel()
+
+		var events []string
+		spy := newTB()
+		New(spy,
+			Resource(spy, func(context.Context) (*testResource, func(context.Context) error, error) {
+				events = append(events, "resource started")
+				return &testResource{name: "db"}, func(context.Context) error {
+					events = append(events, "resource stopped")
+					return nil
+				}, nil
+			}),
+			fx.Invoke(func(lc fx.Lifecycle, r *testResource) {
+				lc.Append(fx.StopHook(func() {
+					events = append(events, "component using "+r.name+" stopped")
+				}))
+			}),
+		).RequireStart().RequireStop()
+		spy.runCleanups()
+
+		assert.Zero(t, spy.failures)
+		assert.Equal(t, []string{
+			"resource started",
+			"component using db stopped",
+			"resource stopped",
+		}, events, "resource must be released once")
+	})
+
+	t.Run("ReleasedWhenNewFails", func(t *testing.T) {
+		t.Parallel()
+
+		var released bool
+		spy := newTB()
+		New(spy,
+			Resource(spy, func(context.Context) (string, func(context.Context) error, error) {
+				return "broker", func(context.Context) error {
+					released = true
+					return nil
+				}, nil
+			}),
+			fx.Invoke(func(string) error { return errors.New("great sadness") }),
+		)
+		assert.Equal(t, 1, spy.failures)
+		assert.False(t, released, "resource must not be released before the test finishes")
+
+		spy.runCleanups()
+		assert.True(t, released, "resource must be released when the test finishes")
+	})
+
+	t.Run("NilStop", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+		New(spy,
+			Resource(spy, func(context.Context) (string, func(context.Context) error, error) {
+				return "broker", nil, nil
+			}),
+			fx.Invoke(func(string) {}),
+		).RequireStart().RequireStop()
+
+		assert.Zero(t, spy.failures)
+	})
+
+	t.Run("StartFailure", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+		New(spy,
+			Resource(spy, func(context.Context) (string, func(context.Context) error, error) {
+				return "", nil, errors.New("great sadness")
+			}),
+			fx.Invoke(func(string) {}),
+		)
+
+		assert.Equal(t, 1, spy.failures)
+		assert.Contains(t, spy.errors.String(), "great sadness")
+	})
+
+	t.Run("StopFailure", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+		New(spy,
+			Resource(spy, func(context.Context) (string, func(context.Context) error, error) {
+				return "broker", func(context.Context) error {
+					return errors.New("great sadness")
+				}, nil
+			}),
+			fx.Invoke(func(string) {}),
+		).RequireStart().RequireStop()
+
+		assert.Equal(t, 1, spy.failures)
+		assert.Contains(t, spy.errors.String(), "great sadness")
+	})
+}
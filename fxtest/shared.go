@@ -0,0 +1,155 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"go.uber.org/fx"
+)
+
+// SharedProvide provides the given constructors like [fx.Provide],
+// but memoizes their results process-wide:
+// each constructor runs at most once across all applications that use it,
+// and every later application receives the same values.
+// This is intended for test suites that build many applications
+// around heavyweight resources, such as connection pools
+// to a database with a loaded schema.
+//
+//	var sharedDB = fxtest.SharedProvide(newSchemaDB)
+//
+//	func TestUsers(t *testing.T) {
+//		app := fxtest.New(t, sharedDB, fx.Provide(NewUserStore), ...)
+//		...
+//	}
+//
+// A constructor's dependencies are only requested from the application
+// that first runs it; later applications still must be able to provide
+// them, but their values are ignored.
+// Constructors that fail are not memoized and run again the next time.
+//
+// Because memoized constructors run only once, hooks they append
+// to an [fx.Lifecycle] belong to the first application only.
+// Release shared resources from TestMain or with testing.TB.Cleanup instead,
+// and call [InvalidateShared] to build them again.
+//
+// Constructors are identified by their function and type,
+// so closures created from the same function literal share their results.
+// Annotated constructors are not supported.
+func SharedProvide(constructors ...interface{}) fx.Option {
+	opts := make([]fx.Option, 0, len(constructors))
+	for _, c := range constructors {
+		fn := reflect.ValueOf(c)
+		if fn.Kind() != reflect.Func || fn.IsNil() {
+			opts = append(opts, fx.Error(fmt.Errorf(
+				"fxtest.SharedProvide: must provide constructor function, got %v (%T)", c, c)))
+			continue
+		}
+
+		key := sharedKey{ptr: fn.Pointer(), typ: fn.Type()}
+		opts = append(opts, fx.Provide(
+			reflect.MakeFunc(fn.Type(), func(args []reflect.Value) []reflect.Value {
+				return _shared.call(key, fn, args)
+			}).Interface(),
+		))
+	}
+	return fx.Options(opts...)
+}
+
+// InvalidateShared discards the memoized results of the given constructors,
+// so that the next application that uses them runs them again.
+// Call it with no arguments to discard all memoized results.
+//
+// InvalidateShared does not release the discarded values;
+// that remains the caller's responsibility.
+func InvalidateShared(constructors ...interface{}) {
+	_shared.mu.Lock()
+	defer _shared.mu.Unlock()
+
+	if len(constructors) == 0 {
+		_shared.entries = nil
+		return
+	}
+	for _, c := range constructors {
+		fn := reflect.ValueOf(c)
+		if fn.Kind() != reflect.Func || fn.IsNil() {
+			continue
+		}
+		delete(_shared.entries, sharedKey{ptr: fn.Pointer(), typ: fn.Type()})
+	}
+}
+
+var _shared sharedRegistry
+
+type sharedKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// sharedRegistry holds the memoized results of constructors
+// provided with SharedProvide.
+type sharedRegistry struct {
+	mu      sync.Mutex
+	entries map[sharedKey]*sharedEntry
+}
+
+type sharedEntry struct {
+	mu      sync.Mutex // held while the constructor runs
+	results []reflect.Value
+}
+
+func (r *sharedRegistry) entry(key sharedKey) *sharedEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[key]
+	if !ok {
+		if r.entries == nil {
+			r.entries = make(map[sharedKey]*sharedEntry)
+		}
+		e = new(sharedEntry)
+		r.entries[key] = e
+	}
+	return e
+}
+
+// call returns the memoized results of fn, calling it with args if there
+// are none. Concurrent callers wait for the first one to finish.
+func (r *sharedRegistry) call(key sharedKey, fn reflect.Value, args []reflect.Value) []reflect.Value {
+	e := r.entry(key)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.results != nil {
+		return e.results
+	}
+
+	results := fn.Call(args)
+	if n := len(results); n > 0 && fn.Type().Out(n-1) == _typeOfError && !results[n-1].IsNil() {
+		return results
+	}
+	e.results = results
+	return results
+}
+
+var _typeOfError = reflect.TypeOf((*error)(nil)).Elem()
@@ -0,0 +1,140 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type sharedPool struct{ id int32 }
+
+func TestSharedProvide(t *testing.T) {
+	t.Parallel()
+
+	t.Run("BuiltOnce", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		newPool := func() *sharedPool {
+			return &sharedPool{id: calls.Add(1)}
+		}
+		InvalidateShared(newPool) // results of earlier runs with -count
+		shared := SharedProvide(newPool)
+
+		var first, second *sharedPool
+		New(t, shared, fx.Populate(&first)).RequireStart().RequireStop()
+		New(t, shared, fx.Populate(&second)).RequireStart().RequireStop()
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Same(t, first, second)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		newPool := func() *sharedPool {
+			return &sharedPool{id: calls.Add(1)}
+		}
+		InvalidateShared(newPool) // results of earlier runs with -count
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				New(t, SharedProvide(newPool), fx.Invoke(func(*sharedPool) {}))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("FailuresNotMemoized", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		newPool := func() (*sharedPool, error) {
+			if calls.Add(1) == 1 {
+				return nil, errors.New("great sadness")
+			}
+			return &sharedPool{}, nil
+		}
+		InvalidateShared(newPool) // results of earlier runs with -count
+
+		app := fx.New(fx.NopLogger, SharedProvide(newPool), fx.Invoke(func(*sharedPool) {}))
+		require.ErrorContains(t, app.Err(), "great sadness")
+
+		New(t, SharedProvide(newPool), fx.Invoke(func(*sharedPool) {}))
+		New(t, SharedProvide(newPool), fx.Invoke(func(*sharedPool) {}))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Invalidate", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		newPool := func() *sharedPool {
+			return &sharedPool{id: calls.Add(1)}
+		}
+		InvalidateShared(newPool) // results of earlier runs with -count
+
+		var first, second *sharedPool
+		New(t, SharedProvide(newPool), fx.Populate(&first))
+		InvalidateShared(newPool)
+		New(t, SharedProvide(newPool), fx.Populate(&second))
+
+		assert.Equal(t, int32(2), calls.Load())
+		assert.NotSame(t, first, second)
+	})
+
+	t.Run("NotAFunction", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(fx.NopLogger, SharedProvide(42))
+		assert.ErrorContains(t, app.Err(),
+			"fxtest.SharedProvide: must provide constructor function, got 42 (int)")
+	})
+}
+
+func TestInvalidateSharedAll(t *testing.T) {
+	// Not parallel: discards the results of every shared constructor.
+
+	var calls atomic.Int32
+	newPool := func() *sharedPool {
+		return &sharedPool{id: calls.Add(1)}
+	}
+	InvalidateShared(newPool) // results of earlier runs with -count
+
+	New(t, SharedProvide(newPool), fx.Invoke(func(*sharedPool) {}))
+	InvalidateShared()
+	New(t, SharedProvide(newPool), fx.Invoke(func(*sharedPool) {}))
+
+	assert.Equal(t, int32(2), calls.Load())
+}
@@ -27,3 +27,10 @@ type TB interface {
 	Errorf(string, ...interface{})
 	FailNow()
 }
+
+// CleanupTB is a [TB] that can register functions to run when the test
+// finishes. It's satisfied by both *testing.T and *testing.B.
+type CleanupTB interface {
+	TB
+	Cleanup(func())
+}
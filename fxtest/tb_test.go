@@ -26,17 +26,34 @@ import (
 	"testing"
 )
 
-// Verify that TB always matches testing.T.
-var _ TB = (*testing.T)(nil)
+// Verify that TB and CleanupTB always match testing.T.
+var (
+	_ TB        = (*testing.T)(nil)
+	_ CleanupTB = (*testing.T)(nil)
+)
 
 type tb struct {
 	failures int
 	errors   *bytes.Buffer
 	logs     *bytes.Buffer
+	cleanups []func()
 }
 
 func newTB() *tb {
-	return &tb{0, &bytes.Buffer{}, &bytes.Buffer{}}
+	return &tb{errors: &bytes.Buffer{}, logs: &bytes.Buffer{}}
+}
+
+func (t *tb) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+// runCleanups runs the registered cleanup functions
+// in reverse order, as the testing package does.
+func (t *tb) runCleanups() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+	t.cleanups = nil
 }
 
 func (t *tb) FailNow() {
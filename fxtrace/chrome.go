@@ -0,0 +1,231 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fxtrace records the boot and shutdown of an Fx application
+// as a timeline to inspect visually.
+//
+// [Recorder] is an [fx.Tracer] that writes the constructors, decorators,
+// invoked functions and hooks it traced in the Chrome trace event format,
+// which chrome://tracing and https://ui.perfetto.dev display with a lane
+// per module, to see which functions ran concurrently and which held up
+// the others:
+//
+//	rec := fxtrace.NewRecorder()
+//	app := fx.New(fx.WithTracer(rec), opts)
+//	if err := app.Start(ctx); err != nil {
+//		// ...
+//	}
+//	f, err := os.Create("boot.json")
+//	// ...
+//	_, err = rec.WriteTo(f)
+package fxtrace
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// Lane of functions that don't belong to a module,
+// and of the spans Fx starts for the application as a whole.
+const _appLane = "fx"
+
+// Recorder is an [fx.Tracer] that records spans to export them
+// in the Chrome trace event format with [Recorder.WriteTo].
+// Pass it to [fx.WithTracer].
+type Recorder struct {
+	mu    sync.Mutex
+	spans []*span
+}
+
+var _ fx.Tracer = (*Recorder)(nil)
+
+// NewRecorder builds a new Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+type span struct {
+	r *Recorder
+
+	name  string
+	start time.Time
+	attrs []fx.SpanAttribute
+
+	// Set when the span ends, guarded by r.mu.
+	end   time.Time
+	err   error
+	ended bool
+}
+
+// Start implements [fx.Tracer].
+func (r *Recorder) Start(ctx context.Context, name string, start time.Time, attrs ...fx.SpanAttribute) (context.Context, fx.Span) {
+	s := &span{r: r, name: name, start: start, attrs: attrs}
+	r.mu.Lock()
+	r.spans = append(r.spans, s)
+	r.mu.Unlock()
+	return ctx, s
+}
+
+func (s *span) End(end time.Time, err error) {
+	s.r.mu.Lock()
+	defer s.r.mu.Unlock()
+	s.end, s.err, s.ended = end, err, true
+}
+
+func (s *span) attr(key string) string {
+	for _, a := range s.attrs {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// traceEvent is an event of the Chrome trace event format.
+// See https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU.
+type traceEvent struct {
+	Name string            `json:"name"`
+	Cat  string            `json:"cat,omitempty"`
+	Ph   string            `json:"ph"`
+	Ts   float64           `json:"ts"`  // microseconds
+	Dur  float64           `json:"dur"` // microseconds
+	Pid  int               `json:"pid"`
+	Tid  int               `json:"tid"`
+	Args map[string]string `json:"args,omitempty"`
+}
+
+type traceFile struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+// WriteTo writes the spans that ended so far to w as a Chrome trace,
+// in the JSON object format.
+//
+// Each span is a complete event named after its function, if any,
+// in the category named after the span, like "fx.Provide" or "fx.OnStart".
+// Its attributes and error, if any, are the arguments of the event.
+// Spans of functions of a module are in a thread named after the module,
+// and other spans are in a thread named "fx".
+// Timestamps are relative to the earliest span.
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	var spans []span
+	for _, s := range r.spans {
+		if s.ended {
+			spans = append(spans, *s)
+		}
+	}
+	r.mu.Unlock()
+
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].start.Before(spans[j].start)
+	})
+
+	const pid = 1
+	events := []traceEvent{{
+		Name: "process_name",
+		Ph:   "M",
+		Pid:  pid,
+		Args: map[string]string{"name": "fx"},
+	}}
+
+	// Lanes are numbered in the order they're first seen.
+	lanes := make(map[string]int)
+	lane := func(name string) int {
+		if tid, ok := lanes[name]; ok {
+			return tid
+		}
+		tid := len(lanes) + 1
+		lanes[name] = tid
+		events = append(events, traceEvent{
+			Name: "thread_name",
+			Ph:   "M",
+			Pid:  pid,
+			Tid:  tid,
+			Args: map[string]string{"name": name},
+		})
+		return tid
+	}
+	lane(_appLane)
+
+	var origin time.Time
+	if len(spans) > 0 {
+		origin = spans[0].start
+	}
+	for _, s := range spans {
+		name := s.attr("fx.function")
+		if name == "" {
+			name = s.name
+		}
+		laneName := s.attr("fx.module")
+		if laneName == "" {
+			laneName = _appLane
+		}
+
+		args := make(map[string]string, len(s.attrs)+1)
+		for _, a := range s.attrs {
+			args[a.Key] = a.Value
+		}
+		if s.err != nil {
+			args["error"] = s.err.Error()
+		}
+
+		events = append(events, traceEvent{
+			Name: name,
+			Cat:  s.name,
+			Ph:   "X",
+			Ts:   microseconds(s.start.Sub(origin)),
+			Dur:  microseconds(s.end.Sub(s.start)),
+			Pid:  pid,
+			Tid:  lane(laneName),
+			Args: args,
+		})
+	}
+
+	cw := countingWriter{w: w}
+	err := json.NewEncoder(&cw).Encode(traceFile{
+		TraceEvents:     events,
+		DisplayTimeUnit: "ms",
+	})
+	return cw.n, err
+}
+
+func microseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtrace_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtrace"
+)
+
+type (
+	config struct{}
+	server struct{}
+)
+
+type event struct {
+	Name string            `json:"name"`
+	Cat  string            `json:"cat"`
+	Ph   string            `json:"ph"`
+	Ts   float64           `json:"ts"`
+	Dur  float64           `json:"dur"`
+	Tid  int               `json:"tid"`
+	Args map[string]string `json:"args"`
+}
+
+// readTrace exports the trace recorded by rec.
+func readTrace(t *testing.T, rec *fxtrace.Recorder) (threads map[string]int, events []event) {
+	var buf bytes.Buffer
+	n, err := rec.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	var trace struct {
+		TraceEvents []event `json:"traceEvents"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &trace))
+
+	threads = make(map[string]int)
+	for _, e := range trace.TraceEvents {
+		switch e.Ph {
+		case "M":
+			if e.Name == "thread_name" {
+				threads[e.Args["name"]] = e.Tid
+			}
+		case "X":
+			events = append(events, e)
+		}
+	}
+	return threads, events
+}
+
+// findEvent returns the first event of the given category
+// whose name contains the given string.
+func findEvent(t *testing.T, events []event, cat, name string) event {
+	for _, e := range events {
+		if e.Cat == cat && bytes.Contains([]byte(e.Name), []byte(name)) {
+			return e
+		}
+	}
+	t.Fatalf("no %v event named like %q in %v", cat, name, events)
+	return event{}
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lanes per module", func(t *testing.T) {
+		t.Parallel()
+
+		rec := fxtrace.NewRecorder()
+		app := fx.New(
+			fx.NopLogger,
+			fx.WithTracer(rec),
+			fx.Module("config",
+				fx.Provide(func() config { return config{} }),
+			),
+			fx.Module("server",
+				fx.Provide(func(lc fx.Lifecycle, _ config) *server {
+					lc.Append(fx.StartHook(func() {}))
+					return &server{}
+				}),
+			),
+			fx.Invoke(func(*server) {}),
+		)
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+
+		threads, events := readTrace(t, rec)
+		require.Contains(t, threads, "fx")
+		require.Contains(t, threads, "config")
+		require.Contains(t, threads, "server")
+
+		start := findEvent(t, events, "fx.Start", "fx.Start")
+		assert.Equal(t, threads["fx"], start.Tid)
+		assert.Equal(t, threads["fx"], findEvent(t, events, "fx.Invoke", "TestRecorder").Tid)
+
+		cfg := findEvent(t, events, "fx.Provide", "TestRecorder")
+		assert.Equal(t, threads["config"], cfg.Tid)
+		assert.Equal(t, "config", cfg.Args["fx.module"])
+
+		hook := findEvent(t, events, "fx.OnStart", "TestRecorder")
+		assert.Equal(t, threads["server"], hook.Tid)
+		assert.GreaterOrEqual(t, hook.Ts, start.Ts)
+		assert.LessOrEqual(t, hook.Ts+hook.Dur, start.Ts+start.Dur)
+
+		for i := 1; i < len(events); i++ {
+			assert.LessOrEqual(t, events[i-1].Ts, events[i].Ts, "events should be sorted")
+		}
+	})
+
+	t.Run("errors and unended spans", func(t *testing.T) {
+		t.Parallel()
+
+		rec := fxtrace.NewRecorder()
+		start := time.Now()
+		_, span := rec.Start(context.Background(), "fx.Provide", start,
+			fx.SpanAttribute{Key: "fx.function", Value: "main.New()"})
+		span.End(start.Add(1500*time.Microsecond), errors.New("great sadness"))
+		rec.Start(context.Background(), "fx.Invoke", start)
+
+		threads, events := readTrace(t, rec)
+		assert.Equal(t, map[string]int{"fx": 1}, threads)
+		require.Len(t, events, 1)
+		assert.Equal(t, event{
+			Name: "main.New()",
+			Cat:  "fx.Provide",
+			Ph:   "X",
+			Ts:   0,
+			Dur:  1500,
+			Tid:  1,
+			Args: map[string]string{
+				"fx.function": "main.New()",
+				"error":       "great sadness",
+			},
+		}, events[0])
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		threads, events := readTrace(t, fxtrace.NewRecorder())
+		assert.Equal(t, map[string]int{"fx": 1}, threads)
+		assert.Empty(t, events)
+	})
+}
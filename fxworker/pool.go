@@ -0,0 +1,361 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fxworker runs jobs on a pool of goroutines
+// managed by the lifecycle of an Fx application.
+//
+// [Module] provides a *[Pool] that starts with the application,
+// and drains its queued jobs when the application stops.
+//
+//	fx.New(
+//		fxworker.Module(fxworker.Concurrency(8)),
+//		fx.Invoke(func(pool *fxworker.Pool, mux *http.ServeMux) {
+//			mux.HandleFunc("/resize", func(w http.ResponseWriter, r *http.Request) {
+//				err := pool.Submit(r.Context(), func(ctx context.Context) error {
+//					return resize(ctx, r.URL.Query().Get("image"))
+//				})
+//				// ...
+//			})
+//		}),
+//	)
+//
+// Use [OnJobDone] to report the outcome of each job to metrics or logs,
+// and [Pool.Stats] to report the state of the pool.
+package fxworker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// ErrNotRunning is returned by [Pool.Submit]
+// if the pool hasn't started yet or has stopped.
+var ErrNotRunning = errors.New("fxworker: pool is not running")
+
+// Job is a unit of work run by a [Pool].
+// Its context is canceled if the pool stops without draining,
+// or if draining takes longer than the stop timeout.
+type Job func(ctx context.Context) error
+
+// Module provides a *[Pool] configured with the given options,
+// started and stopped with the application.
+//
+// The pool starts before the OnStart hooks of the constructors
+// that depend on it, and stops after their OnStop hooks,
+// so they may submit jobs throughout their lifetime.
+func Module(opts ...Option) fx.Option {
+	return fx.Module("fxworker",
+		fx.Provide(func(lc fx.Lifecycle) *Pool {
+			p := New(opts...)
+			lc.Append(fx.StartStopHook(p.Start, p.Stop))
+			return p
+		}),
+	)
+}
+
+// Option configures a [Pool].
+type Option interface {
+	apply(*config)
+}
+
+type config struct {
+	concurrency int
+	queueSize   int
+	drain       bool
+	onJobDone   func(JobResult)
+}
+
+// Concurrency sets the number of jobs a pool runs at the same time.
+// It defaults to runtime.GOMAXPROCS(0).
+func Concurrency(n int) Option {
+	return concurrencyOption(n)
+}
+
+type concurrencyOption int
+
+func (o concurrencyOption) apply(c *config) {
+	if o > 0 {
+		c.concurrency = int(o)
+	}
+}
+
+// QueueSize sets the number of submitted jobs a pool holds
+// while all of its goroutines are busy.
+// Once the queue is full, [Pool.Submit] blocks.
+// It defaults to zero, so Submit blocks until a goroutine takes the job.
+func QueueSize(n int) Option {
+	return queueSizeOption(n)
+}
+
+type queueSizeOption int
+
+func (o queueSizeOption) apply(c *config) {
+	if o > 0 {
+		c.queueSize = int(o)
+	}
+}
+
+// DrainOnStop sets whether a stopping pool runs the jobs it has queued
+// and waits for running jobs to finish.
+// If disabled, queued jobs are discarded,
+// and the contexts of running jobs are canceled.
+// It defaults to true.
+func DrainOnStop(drain bool) Option {
+	return drainOption(drain)
+}
+
+type drainOption bool
+
+func (o drainOption) apply(c *config) {
+	c.drain = bool(o)
+}
+
+// JobResult describes a job that a [Pool] ran.
+type JobResult struct {
+	// Name of the job function.
+	Name string
+
+	// Runtime is how long the job ran for.
+	Runtime time.Duration
+
+	// Err is the error returned by the job, if any.
+	// Panics in jobs are recovered and reported as errors.
+	Err error
+}
+
+// OnJobDone registers a function called after each job a pool runs.
+// It's called from the goroutine that ran the job,
+// and is intended for reporting metrics or logging failures.
+func OnJobDone(f func(JobResult)) Option {
+	return onJobDoneOption(f)
+}
+
+type onJobDoneOption func(JobResult)
+
+func (o onJobDoneOption) apply(c *config) {
+	c.onJobDone = o
+}
+
+// Stats reports the state of a [Pool].
+type Stats struct {
+	// Number of jobs waiting to run, and number of jobs running.
+	Queued, Running int64
+
+	// Number of jobs that returned successfully, and that failed.
+	Succeeded, Failed int64
+
+	// Number of queued jobs discarded because the pool stopped.
+	Discarded int64
+}
+
+type poolState int
+
+const (
+	poolIdle poolState = iota
+	poolRunning
+	poolStopped
+)
+
+// Pool runs jobs on a fixed number of goroutines.
+// Build one with [New], or use [Module].
+type Pool struct {
+	cfg config
+
+	mu    sync.RWMutex // guards state and sends on queue
+	state poolState
+	queue chan Job
+
+	// ctx is the context of jobs, canceled when they must stop.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	queued, running, succeeded, failed, discarded atomic.Int64
+}
+
+// New builds a pool with the given options.
+// The pool runs jobs once it's started with [Pool.Start].
+func New(opts ...Option) *Pool {
+	cfg := config{
+		concurrency: runtime.GOMAXPROCS(0),
+		drain:       true,
+	}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Pool{
+		cfg:    cfg,
+		queue:  make(chan Job, cfg.queueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start starts the goroutines of the pool.
+// A pool can only be started once.
+func (p *Pool) Start(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.state != poolIdle {
+		return errors.New("fxworker: pool can only be started once")
+	}
+	p.state = poolRunning
+
+	p.wg.Add(p.cfg.concurrency)
+	for i := 0; i < p.cfg.concurrency; i++ {
+		go p.work()
+	}
+	return nil
+}
+
+// Stop stops accepting jobs and waits for the goroutines of the pool
+// to exit, draining queued jobs if configured with [DrainOnStop].
+// If ctx expires first, Stop cancels the contexts of running jobs,
+// discards queued ones, and returns the error of ctx.
+func (p *Pool) Stop(ctx context.Context) error {
+	if !p.cfg.drain {
+		p.cancel()
+	}
+
+	// Submissions hold a read lock while they block on a full queue.
+	// Cancel jobs on expiry so that the queue frees up.
+	stop := context.AfterFunc(ctx, p.cancel)
+	defer stop()
+
+	p.mu.Lock()
+	wasRunning := p.state == poolRunning
+	if p.state != poolStopped {
+		p.state = poolStopped
+		close(p.queue)
+	}
+	p.mu.Unlock()
+	if !wasRunning {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+// Submit queues a job to run on the pool.
+// It blocks while the queue is full, until ctx expires.
+// It returns [ErrNotRunning] if the pool isn't running.
+func (p *Pool) Submit(ctx context.Context, job Job) error {
+	if job == nil {
+		return errors.New("fxworker: cannot submit nil job")
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.state != poolRunning {
+		return ErrNotRunning
+	}
+
+	p.queued.Add(1)
+	select {
+	case p.queue <- job:
+		return nil
+	case <-ctx.Done():
+		p.queued.Add(-1)
+		return ctx.Err()
+	case <-p.ctx.Done():
+		p.queued.Add(-1)
+		return ErrNotRunning
+	}
+}
+
+// Stats reports the current state of the pool.
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Queued:    p.queued.Load(),
+		Running:   p.running.Load(),
+		Succeeded: p.succeeded.Load(),
+		Failed:    p.failed.Load(),
+		Discarded: p.discarded.Load(),
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for job := range p.queue {
+		p.queued.Add(-1)
+		if p.ctx.Err() != nil {
+			p.discarded.Add(1)
+			continue
+		}
+		p.run(job)
+	}
+}
+
+func (p *Pool) run(job Job) {
+	p.running.Add(1)
+	defer p.running.Add(-1)
+
+	start := time.Now()
+	err := p.call(job)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		p.failed.Add(1)
+	} else {
+		p.succeeded.Add(1)
+	}
+	if p.cfg.onJobDone != nil {
+		p.cfg.onJobDone(JobResult{
+			Name:    fxreflect.FuncName(job),
+			Runtime: elapsed,
+			Err:     err,
+		})
+	}
+}
+
+func (p *Pool) call(job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("fxworker: job panicked: %v", r)
+		}
+	}()
+	return job(p.ctx)
+}
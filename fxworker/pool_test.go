@@ -0,0 +1,239 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxworker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestModule(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ran  atomic.Int32
+		pool *Pool
+	)
+	app := fxtest.New(t,
+		Module(Concurrency(2), QueueSize(4)),
+		fx.Populate(&pool),
+		fx.Invoke(func(lc fx.Lifecycle, p *Pool) {
+			lc.Append(fx.StartHook(func(ctx context.Context) error {
+				for i := 0; i < 4; i++ {
+					if err := p.Submit(ctx, func(context.Context) error {
+						ran.Add(1)
+						return nil
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			}))
+		}),
+	)
+	app.RequireStart().RequireStop()
+
+	assert.Equal(t, int32(4), ran.Load(), "queued jobs must be drained on stop")
+	assert.Equal(t, Stats{Succeeded: 4}, pool.Stats())
+	assert.ErrorIs(t, pool.Submit(context.Background(), func(context.Context) error { return nil }), ErrNotRunning)
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotStarted", func(t *testing.T) {
+		t.Parallel()
+
+		p := New()
+		assert.ErrorIs(t, p.Submit(context.Background(), func(context.Context) error { return nil }), ErrNotRunning)
+		assert.NoError(t, p.Stop(context.Background()))
+	})
+
+	t.Run("StartedTwice", func(t *testing.T) {
+		t.Parallel()
+
+		p := New(Concurrency(1))
+		require.NoError(t, p.Start(context.Background()))
+		defer p.Stop(context.Background())
+		assert.ErrorContains(t, p.Start(context.Background()), "pool can only be started once")
+	})
+
+	t.Run("NilJob", func(t *testing.T) {
+		t.Parallel()
+
+		p := New(Concurrency(1))
+		require.NoError(t, p.Start(context.Background()))
+		defer p.Stop(context.Background())
+		assert.ErrorContains(t, p.Submit(context.Background(), nil), "cannot submit nil job")
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		t.Parallel()
+
+		p := New(Concurrency(3))
+		require.NoError(t, p.Start(context.Background()))
+
+		var (
+			mu           sync.Mutex
+			active, peak int
+			release      = make(chan struct{})
+			started      = make(chan struct{}, 3)
+			submitted    sync.WaitGroup
+		)
+		for i := 0; i < 6; i++ {
+			submitted.Add(1)
+			go func() {
+				defer submitted.Done()
+				assert.NoError(t, p.Submit(context.Background(), func(context.Context) error {
+					mu.Lock()
+					active++
+					peak = max(peak, active)
+					mu.Unlock()
+					select {
+					case started <- struct{}{}:
+					default:
+					}
+					<-release
+					mu.Lock()
+					active--
+					mu.Unlock()
+					return nil
+				}))
+			}()
+		}
+		for i := 0; i < 3; i++ {
+			<-started
+		}
+		close(release)
+		submitted.Wait()
+		require.NoError(t, p.Stop(context.Background()))
+
+		assert.Equal(t, 3, peak)
+		assert.Equal(t, int64(6), p.Stats().Succeeded)
+	})
+
+	t.Run("OnJobDone", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mu      sync.Mutex
+			results []JobResult
+		)
+		p := New(Concurrency(1), OnJobDone(func(r JobResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, r)
+		}))
+		require.NoError(t, p.Start(context.Background()))
+
+		ctx := context.Background()
+		require.NoError(t, p.Submit(ctx, failingJob))
+		require.NoError(t, p.Submit(ctx, func(context.Context) error { panic("great sadness") }))
+		require.NoError(t, p.Stop(ctx))
+
+		require.Len(t, results, 2)
+		assert.Equal(t, "go.uber.org/fx/fxworker.failingJob()", results[0].Name)
+		assert.EqualError(t, results[0].Err, "failed")
+		assert.EqualError(t, results[1].Err, "fxworker: job panicked: great sadness")
+		assert.Equal(t, Stats{Failed: 2}, p.Stats())
+	})
+
+	t.Run("NoDrain", func(t *testing.T) {
+		t.Parallel()
+
+		p := New(Concurrency(1), QueueSize(2), DrainOnStop(false))
+		require.NoError(t, p.Start(context.Background()))
+
+		ctx := context.Background()
+		running := make(chan struct{})
+		require.NoError(t, p.Submit(ctx, func(ctx context.Context) error {
+			close(running)
+			<-ctx.Done()
+			return ctx.Err()
+		}))
+		<-running
+		require.NoError(t, p.Submit(ctx, func(context.Context) error { return nil }))
+		require.NoError(t, p.Submit(ctx, func(context.Context) error { return nil }))
+		require.NoError(t, p.Stop(ctx))
+
+		assert.Equal(t, Stats{Failed: 1, Discarded: 2}, p.Stats())
+	})
+
+	t.Run("StopTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		p := New(Concurrency(1))
+		require.NoError(t, p.Start(context.Background()))
+
+		running := make(chan struct{})
+		var canceled atomic.Bool
+		require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) error {
+			close(running)
+			<-ctx.Done()
+			canceled.Store(true)
+			return ctx.Err()
+		}))
+		<-running
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, p.Stop(ctx), context.DeadlineExceeded)
+		assert.Eventually(t, canceled.Load, time.Second, time.Millisecond)
+	})
+
+	t.Run("SubmitContext", func(t *testing.T) {
+		t.Parallel()
+
+		p := New(Concurrency(1))
+		require.NoError(t, p.Start(context.Background()))
+		defer p.Stop(context.Background())
+
+		release := make(chan struct{})
+		defer close(release)
+		require.NoError(t, p.Submit(context.Background(), func(context.Context) error {
+			<-release
+			return nil
+		}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := p.Submit(ctx, func(context.Context) error { return nil })
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func failingJob(context.Context) error {
+	return errors.New("failed")
+}
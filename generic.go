@@ -0,0 +1,103 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// ProvideGeneric provides an instantiation of a generic constructor,
+// applying the given annotations to it.
+//
+//	func NewCache[T any](cfg Config) *Cache[T]
+//
+//	fx.ProvideGeneric(NewCache[User], fx.As(new(Store[User])))
+//
+// This is equivalent to the following, but rejects functions that are not
+// instantiations of generic functions.
+//
+//	fx.Provide(fx.Annotate(NewCache[User], fx.As(new(Store[User]))))
+//
+// The runtime doesn't record the type arguments of instantiations,
+// so events and errors report the constructor with its instantiated type
+// instead, e.g. "NewCache[...]() (func(Config) *Cache[User])".
+func ProvideGeneric(ctor interface{}, anns ...Annotation) Option {
+	return provideGenericOption{
+		Target:      ctor,
+		Annotations: anns,
+		Stack:       fxreflect.CallerStack(1, 0),
+	}
+}
+
+type provideGenericOption struct {
+	Target      interface{}
+	Annotations []Annotation
+	Stack       fxreflect.Stack
+}
+
+func (o provideGenericOption) apply(m *module) {
+	if err := o.validate(); err != nil {
+		m.app.err = fmt.Errorf("fx.ProvideGeneric(%v) from:\n%+vFailed: %w",
+			o.name(), o.Stack, err)
+		return
+	}
+
+	target := o.Target
+	if len(o.Annotations) > 0 {
+		target = Annotate(o.Target, o.Annotations...)
+	}
+	m.provides = append(m.provides, provide{
+		Target: target,
+		Stack:  o.Stack,
+		Name:   o.name(),
+	})
+}
+
+// name names the constructor with its instantiated type,
+// which identifies the instantiation.
+func (o provideGenericOption) name() string {
+	name := fxreflect.FuncName(o.Target)
+	if t := reflect.TypeOf(o.Target); t != nil && t.Kind() == reflect.Func {
+		name = fmt.Sprintf("%v (%v)", name, t)
+	}
+	return name
+}
+
+func (o provideGenericOption) validate() error {
+	fv := reflect.ValueOf(o.Target)
+	if fv.Kind() != reflect.Func {
+		return fmt.Errorf("must provide constructor function, got %v (%T)", o.Target, o.Target)
+	}
+	if !strings.Contains(runtime.FuncForPC(fv.Pointer()).Name(), "[...]") {
+		return errors.New("constructor must be an instantiated generic function")
+	}
+	return nil
+}
+
+func (o provideGenericOption) String() string {
+	return fmt.Sprintf("fx.ProvideGeneric(%v)", o.name())
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+type genericStore[T any] interface {
+	Get() T
+}
+
+type genericCache[T any] struct{ v T }
+
+func (c *genericCache[T]) Get() T { return c.v }
+
+func newGenericCache[T any](v T) *genericCache[T] {
+	return &genericCache[T]{v: v}
+}
+
+func TestProvideGeneric(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		var got *genericCache[string]
+		app := fxtest.New(t,
+			fx.Supply("hello"),
+			fx.ProvideGeneric(newGenericCache[string]),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Equal(t, "hello", got.Get())
+	})
+
+	t.Run("annotated", func(t *testing.T) {
+		t.Parallel()
+
+		var got genericStore[int]
+		app := fxtest.New(t,
+			fx.Supply(42),
+			fx.ProvideGeneric(newGenericCache[int], fx.As(new(genericStore[int]))),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Equal(t, 42, got.Get())
+	})
+
+	t.Run("reports the instantiation", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return &spy }),
+			fx.Supply("hello"),
+			fx.ProvideGeneric(newGenericCache[string]),
+			fx.Invoke(func(*genericCache[string]) {}),
+		)
+		require.NoError(t, app.Err())
+
+		var names []string
+		for _, e := range spy.Events().SelectByTypeName("Provided") {
+			names = append(names, e.(*fxevent.Provided).ConstructorName)
+		}
+		assert.Contains(t, names,
+			"go.uber.org/fx_test.newGenericCache[...]() (func(string) *fx_test.genericCache[string])")
+	})
+
+	t.Run("not generic", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.ProvideGeneric(func() int { return 0 }),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.ProvideGeneric(")
+		assert.Contains(t, err.Error(), "constructor must be an instantiated generic function")
+	})
+
+	t.Run("not a function", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.ProvideGeneric(42),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must provide constructor function, got 42 (int)")
+	})
+}
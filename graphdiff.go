@@ -0,0 +1,247 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// GraphDiff describes how the dependency graph of an application changed
+// between two configurations, as reported by [DiffGraphs].
+type GraphDiff struct {
+	// Providers present only in the second graph.
+	AddedProviders []GraphProvider
+
+	// Providers present only in the first graph.
+	RemovedProviders []GraphProvider
+
+	// Dependencies present only in the second graph,
+	// of providers present in both.
+	AddedEdges []GraphEdge
+
+	// Dependencies present only in the first graph,
+	// of providers present in both.
+	RemovedEdges []GraphEdge
+}
+
+// GraphProvider is a constructor in the dependency graph of an application.
+type GraphProvider struct {
+	// Name of the constructor, qualified by its package.
+	Name string
+
+	// Types provided by the constructor, sorted.
+	Types []string
+}
+
+func (p GraphProvider) String() string {
+	return fmt.Sprintf("%v (%v)", p.Name, strings.Join(p.Types, ", "))
+}
+
+// GraphEdge is a dependency of a constructor on a type.
+type GraphEdge struct {
+	Provider GraphProvider
+	Type     string
+}
+
+func (e GraphEdge) String() string {
+	return fmt.Sprintf("%v depends on %v", e.Provider, e.Type)
+}
+
+// Empty reports whether the graphs were the same.
+func (d GraphDiff) Empty() bool {
+	return len(d.AddedProviders) == 0 && len(d.RemovedProviders) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// String formats the diff with one change per line,
+// prefixed with + for additions and - for removals.
+func (d GraphDiff) String() string {
+	var sb strings.Builder
+	for _, p := range d.RemovedProviders {
+		fmt.Fprintf(&sb, "- %v\n", p)
+	}
+	for _, p := range d.AddedProviders {
+		fmt.Fprintf(&sb, "+ %v\n", p)
+	}
+	for _, e := range d.RemovedEdges {
+		fmt.Fprintf(&sb, "- %v\n", e)
+	}
+	for _, e := range d.AddedEdges {
+		fmt.Fprintf(&sb, "+ %v\n", e)
+	}
+	return sb.String()
+}
+
+// DiffGraphs compares the dependency graphs of two applications,
+// for example before and after a change, and reports the constructors
+// added and removed, and the dependencies that changed.
+//
+//	var before, after fx.DotGraph
+//	fx.New(oldOptions, fx.Populate(&before))
+//	fx.New(newOptions, fx.Populate(&after))
+//	diff, err := fx.DiffGraphs(before, after)
+//
+// Constructors are identified by their name and the types they provide.
+// A constructor that provides different types in the two graphs
+// is reported as removed and added.
+//
+// DiffGraphs returns an error if either graph is not a DotGraph
+// built by Fx.
+func DiffGraphs(a, b DotGraph) (GraphDiff, error) {
+	ga, err := parseDotGraph(a)
+	if err != nil {
+		return GraphDiff{}, fmt.Errorf("first graph: %w", err)
+	}
+	gb, err := parseDotGraph(b)
+	if err != nil {
+		return GraphDiff{}, fmt.Errorf("second graph: %w", err)
+	}
+
+	var diff GraphDiff
+	for _, key := range sortedKeys(ga) {
+		pa := ga[key]
+		pb, ok := gb[key]
+		if !ok {
+			diff.RemovedProviders = append(diff.RemovedProviders, pa.GraphProvider)
+			continue
+		}
+		for _, dep := range pa.deps {
+			if _, ok := pb.depSet[dep]; !ok {
+				diff.RemovedEdges = append(diff.RemovedEdges, GraphEdge{Provider: pa.GraphProvider, Type: dep})
+			}
+		}
+		for _, dep := range pb.deps {
+			if _, ok := pa.depSet[dep]; !ok {
+				diff.AddedEdges = append(diff.AddedEdges, GraphEdge{Provider: pb.GraphProvider, Type: dep})
+			}
+		}
+	}
+	for _, key := range sortedKeys(gb) {
+		if _, ok := ga[key]; !ok {
+			diff.AddedProviders = append(diff.AddedProviders, gb[key].GraphProvider)
+		}
+	}
+	return diff, nil
+}
+
+// dotProvider is a constructor parsed from a DotGraph.
+type dotProvider struct {
+	GraphProvider
+
+	deps   []string // sorted
+	depSet map[string]struct{}
+}
+
+// key identifies the provider across graphs.
+func (p *dotProvider) key() string {
+	return p.Name + "\x00" + strings.Join(p.Types, "\x00")
+}
+
+var (
+	_dotCluster     = regexp.MustCompile(`^subgraph cluster_(\d+) \{$`)
+	_dotLabel       = regexp.MustCompile(`^label = "(.*)";$`)
+	_dotConstructor = regexp.MustCompile(`^constructor_(\d+) \[.*\blabel="(.*?)"`)
+	_dotNode        = regexp.MustCompile(`^"((?:[^"\\]|\\.)*)" \[`)
+	_dotEdge        = regexp.MustCompile(`^constructor_(\d+) -> "((?:[^"\\]|\\.)*)"`)
+
+	// Values of a value group are numbered in the graph.
+	// The numbers depend on the order constructors were provided in,
+	// so they're dropped to compare graphs.
+	_dotGroupIndex = regexp.MustCompile(`\](\d+)$`)
+)
+
+// parseDotGraph parses the constructors of a graph built by dig.Visualize,
+// by the key that identifies them across graphs.
+func parseDotGraph(g DotGraph) (map[string]*dotProvider, error) {
+	s := bufio.NewScanner(strings.NewReader(string(g)))
+	if !s.Scan() || strings.TrimSpace(s.Text()) != "digraph {" {
+		return nil, errors.New("not a DOT graph built by Fx")
+	}
+
+	var (
+		providers = make(map[string]*dotProvider) // by constructor ID
+		cluster   *dotProvider
+		pkg       string
+	)
+	provider := func(id string) *dotProvider {
+		p, ok := providers[id]
+		if !ok {
+			p = &dotProvider{depSet: make(map[string]struct{})}
+			providers[id] = p
+		}
+		return p
+	}
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if cluster == nil {
+			if m := _dotCluster.FindStringSubmatch(line); m != nil {
+				cluster, pkg = provider(m[1]), ""
+			} else if m := _dotEdge.FindStringSubmatch(line); m != nil {
+				p := provider(m[1])
+				dep := normalizeDotNode(m[2])
+				if _, ok := p.depSet[dep]; !ok {
+					p.depSet[dep] = struct{}{}
+					p.deps = append(p.deps, dep)
+				}
+			}
+			continue
+		}
+
+		if line == "}" {
+			cluster = nil
+		} else if m := _dotLabel.FindStringSubmatch(line); m != nil {
+			pkg = m[1]
+		} else if m := _dotConstructor.FindStringSubmatch(line); m != nil {
+			cluster.Name = pkg + "." + m[2]
+		} else if m := _dotNode.FindStringSubmatch(line); m != nil {
+			cluster.Types = append(cluster.Types, normalizeDotNode(m[1]))
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*dotProvider, len(providers))
+	for _, p := range providers {
+		sort.Strings(p.Types)
+		sort.Strings(p.deps)
+		byKey[p.key()] = p
+	}
+	return byKey, nil
+}
+
+func normalizeDotNode(id string) string {
+	id = strings.ReplaceAll(id, `\"`, `"`)
+	return _dotGroupIndex.ReplaceAllString(id, "]")
+}
+
+func sortedKeys(m map[string]*dotProvider) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type (
+	diffConfig  struct{}
+	diffLogger  struct{}
+	diffServer  struct{}
+	diffMetrics struct{}
+)
+
+func newDiffConfig() *diffConfig                                 { return &diffConfig{} }
+func newDiffLogger(*diffConfig) *diffLogger                      { return &diffLogger{} }
+func newDiffServer(*diffLogger) *diffServer                      { return &diffServer{} }
+func newDiffMetrics() *diffMetrics                               { return &diffMetrics{} }
+func newDiffServerMetrics(*diffLogger, *diffMetrics) *diffServer { return &diffServer{} }
+
+func graphOf(t *testing.T, opts ...fx.Option) fx.DotGraph {
+	t.Helper()
+
+	var g fx.DotGraph
+	app := fx.New(fx.NopLogger, fx.Options(opts...), fx.Populate(&g))
+	require.NoError(t, app.Err())
+	return g
+}
+
+func TestDiffGraphs(t *testing.T) {
+	t.Parallel()
+
+	base := graphOf(t, fx.Provide(newDiffConfig, newDiffLogger, newDiffServer))
+
+	t.Run("same", func(t *testing.T) {
+		t.Parallel()
+
+		diff, err := fx.DiffGraphs(base, graphOf(t,
+			// Order of provides doesn't matter.
+			fx.Provide(newDiffServer, newDiffLogger, newDiffConfig),
+		))
+		require.NoError(t, err)
+		assert.True(t, diff.Empty(), "unexpected diff:\n%v", diff)
+		assert.Empty(t, diff.String())
+	})
+
+	t.Run("providers", func(t *testing.T) {
+		t.Parallel()
+
+		diff, err := fx.DiffGraphs(base, graphOf(t,
+			fx.Provide(newDiffConfig, newDiffLogger, newDiffServer, newDiffMetrics),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, []fx.GraphProvider{{
+			Name:  "go.uber.org/fx_test.newDiffMetrics",
+			Types: []string{"*fx_test.diffMetrics"},
+		}}, diff.AddedProviders)
+		assert.Empty(t, diff.RemovedProviders)
+		assert.Empty(t, diff.AddedEdges)
+		assert.Empty(t, diff.RemovedEdges)
+
+		reverse, err := fx.DiffGraphs(graphOf(t,
+			fx.Provide(newDiffConfig, newDiffLogger, newDiffServer, newDiffMetrics),
+		), base)
+		require.NoError(t, err)
+		assert.Equal(t, diff.AddedProviders, reverse.RemovedProviders)
+		assert.Empty(t, reverse.AddedProviders)
+	})
+
+	t.Run("replaced provider", func(t *testing.T) {
+		t.Parallel()
+
+		diff, err := fx.DiffGraphs(base, graphOf(t,
+			fx.Provide(newDiffConfig, newDiffLogger, newDiffMetrics, newDiffServerMetrics),
+		))
+		require.NoError(t, err)
+		assert.Equal(t,
+			"- go.uber.org/fx_test.newDiffServer (*fx_test.diffServer)\n"+
+				"+ go.uber.org/fx_test.newDiffMetrics (*fx_test.diffMetrics)\n"+
+				"+ go.uber.org/fx_test.newDiffServerMetrics (*fx_test.diffServer)\n",
+			diff.String())
+	})
+
+	t.Run("edges", func(t *testing.T) {
+		t.Parallel()
+
+		configs := fx.Provide(
+			fx.Annotate(newDiffConfig, fx.ResultTags(`name:"old"`)),
+			fx.Annotate(newDiffConfig, fx.ResultTags(`name:"new"`)),
+		)
+		before := graphOf(t, configs, fx.Provide(fx.Annotate(newDiffLogger, fx.ParamTags(`name:"old"`))))
+		after := graphOf(t, configs, fx.Provide(fx.Annotate(newDiffLogger, fx.ParamTags(`name:"new"`))))
+
+		diff, err := fx.DiffGraphs(before, after)
+		require.NoError(t, err)
+		assert.Empty(t, diff.AddedProviders)
+		assert.Empty(t, diff.RemovedProviders)
+		require.Len(t, diff.RemovedEdges, 1)
+		require.Len(t, diff.AddedEdges, 1)
+		assert.Equal(t, "*fx_test.diffConfig[name=old]", diff.RemovedEdges[0].Type)
+		assert.Equal(t, "*fx_test.diffConfig[name=new]", diff.AddedEdges[0].Type)
+		assert.Equal(t, []string{"*fx_test.diffLogger"}, diff.AddedEdges[0].Provider.Types)
+		assert.Contains(t, diff.AddedEdges[0].String(), "depends on *fx_test.diffConfig[name=new]")
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		t.Parallel()
+
+		one := func() int { return 1 }
+		two := func() int { return 2 }
+		group := fx.ResultTags(`group:"ints"`)
+		a := graphOf(t, fx.Provide(fx.Annotate(one, group), fx.Annotate(two, group)))
+		b := graphOf(t, fx.Provide(fx.Annotate(two, group), fx.Annotate(one, group)))
+
+		diff, err := fx.DiffGraphs(a, b)
+		require.NoError(t, err)
+		assert.True(t, diff.Empty(), "unexpected diff:\n%v", diff)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := fx.DiffGraphs(base, "not a graph")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "second graph: not a DOT graph built by Fx")
+	})
+}
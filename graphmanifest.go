@@ -0,0 +1,178 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/dig"
+)
+
+// GraphManifest is a canonical description of the dependency graph of an
+// application, built by [RecordGraphManifest].
+// It lists the constructors, supplied values, and decorators of the
+// application, with the types they provide and depend on, their tags,
+// and the modules they belong to.
+//
+// The manifest is sorted, so it's the same for the same graph regardless of
+// the order options were passed in. Store it, or its [GraphManifest.Hash],
+// to detect unintended wiring changes between commits:
+//
+//	func TestGraphManifest(t *testing.T) {
+//		var m fx.GraphManifest
+//		app := fxtest.New(t, app.Module, fx.RecordGraphManifest(&m))
+//		defer app.RequireStart().RequireStop()
+//
+//		want, _ := os.ReadFile("testdata/graph.manifest")
+//		assert.Equal(t, string(want), string(m), "the dependency graph changed")
+//	}
+//
+// Constructors are identified by their names,
+// which include the names of the functions that closures are declared in.
+type GraphManifest string
+
+// Hash returns the SHA-256 hash of the manifest, hex-encoded.
+func (m GraphManifest) Hash() string {
+	sum := sha256.Sum256([]byte(m))
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordGraphManifest causes Fx to describe the dependency graph of the
+// application in *m once [New] built it successfully.
+// [App.Extend] updates *m as well.
+// See [GraphManifest] for details.
+//
+// The types that Fx provides itself, such as [Lifecycle], are not listed.
+//
+// RecordGraphManifest may only be passed to New.
+func RecordGraphManifest(m *GraphManifest) Option {
+	return recordGraphManifestOption{m}
+}
+
+type recordGraphManifestOption struct {
+	dst *GraphManifest
+}
+
+func (o recordGraphManifestOption) apply(m *module) {
+	switch {
+	case m.parent != nil:
+		m.app.err = fmt.Errorf("fx.RecordGraphManifest Option should be passed to top-level " +
+			"App, not to fx.Module")
+	case o.dst == nil:
+		m.app.err = fmt.Errorf("fx.RecordGraphManifest: manifest must not be nil")
+	default:
+		m.app.manifest = &graphManifest{dst: o.dst}
+	}
+}
+
+func (o recordGraphManifestOption) String() string {
+	return fmt.Sprintf("fx.RecordGraphManifest(%p)", o.dst)
+}
+
+// graphManifest collects the entries of a GraphManifest
+// as constructors are provided.
+type graphManifest struct {
+	dst     *GraphManifest
+	entries []manifestEntry
+}
+
+// manifestEntry is a constructor, supplied value,
+// or decorator in a GraphManifest.
+type manifestEntry struct {
+	kind    string // "provide", "supply", or "decorate"
+	name    string
+	module  string // path of the module, e.g. "server/http"
+	private bool
+	outputs []string // sorted
+	inputs  []string // sorted
+}
+
+func (e manifestEntry) key() string {
+	return strings.Join([]string{
+		e.module, e.kind, e.name,
+		strings.Join(e.outputs, "\x00"),
+		strings.Join(e.inputs, "\x00"),
+	}, "\x01")
+}
+
+// modulePath returns the names of m and the modules it's nested in,
+// outermost first, separated by slashes.
+func modulePath(m *module) string {
+	var names []string
+	for ; m != nil && m.parent != nil; m = m.parent {
+		names = append(names, m.name)
+	}
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return strings.Join(names, "/")
+}
+
+// add records a node of the graph added by module m.
+func (gm *graphManifest) add(m *module, kind, name string, private bool, inputs []*dig.Input, outputs []*dig.Output) {
+	e := manifestEntry{
+		kind:    kind,
+		name:    name,
+		module:  modulePath(m),
+		private: private,
+	}
+	for _, o := range outputs {
+		e.outputs = append(e.outputs, o.String())
+	}
+	for _, in := range inputs {
+		e.inputs = append(e.inputs, in.String())
+	}
+	sort.Strings(e.outputs)
+	sort.Strings(e.inputs)
+	gm.entries = append(gm.entries, e)
+}
+
+// write formats the manifest into its destination.
+func (gm *graphManifest) write() {
+	entries := make([]manifestEntry, len(gm.entries))
+	copy(entries, gm.entries)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].key() < entries[j].key()
+	})
+
+	var sb strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&sb, "%v %v", e.kind, e.name)
+		if e.module != "" {
+			fmt.Fprintf(&sb, " module=%v", e.module)
+		}
+		if e.private {
+			sb.WriteString(" private")
+		}
+		sb.WriteString("\n")
+		for _, o := range e.outputs {
+			fmt.Fprintf(&sb, "\tout %v\n", o)
+		}
+		for _, in := range e.inputs {
+			fmt.Fprintf(&sb, "\tin %v\n", in)
+		}
+	}
+	*gm.dst = GraphManifest(sb.String())
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type manifestA struct{}
+
+type manifestB struct{}
+
+func newManifestA() *manifestA { return &manifestA{} }
+
+func newManifestB(*manifestA, *manifestA) *manifestB { return &manifestB{} }
+
+func decorateManifestA(a *manifestA) *manifestA { return a }
+
+func TestRecordGraphManifest(t *testing.T) {
+	t.Parallel()
+
+	build := func(t *testing.T, opts ...fx.Option) fx.GraphManifest {
+		var m fx.GraphManifest
+		app := fx.New(append([]fx.Option{fx.NopLogger, fx.RecordGraphManifest(&m)}, opts...)...)
+		require.NoError(t, app.Err())
+		return m
+	}
+
+	t.Run("manifest", func(t *testing.T) {
+		t.Parallel()
+
+		m := build(t,
+			fx.Module("server",
+				fx.Module("http",
+					fx.Provide(newManifestB, fx.Private),
+					fx.Invoke(func(*manifestB) {}),
+				),
+				fx.Decorate(decorateManifestA),
+			),
+			fx.Provide(fx.Annotate(newManifestA, fx.ResultTags(`name:"a"`))),
+			fx.Provide(newManifestA),
+			fx.Supply(42),
+		)
+		assert.Equal(t, `provide fx.Annotate(go.uber.org/fx_test.newManifestA(), fx.ResultTags(["name:\"a\""])
+	out *fx_test.manifestA[name = "a"]
+provide go.uber.org/fx_test.newManifestA()
+	out *fx_test.manifestA
+supply fx.Supply(int)
+	out int
+decorate go.uber.org/fx_test.decorateManifestA() module=server
+	out *fx_test.manifestA
+	in *fx_test.manifestA
+provide go.uber.org/fx_test.newManifestB() module=server/http private
+	out *fx_test.manifestB
+	in *fx_test.manifestA
+	in *fx_test.manifestA
+`, string(m))
+	})
+
+	t.Run("order does not matter", func(t *testing.T) {
+		t.Parallel()
+
+		a := build(t, fx.Provide(newManifestA), fx.Provide(newManifestB))
+		b := build(t, fx.Provide(newManifestB, newManifestA))
+		assert.Equal(t, a, b)
+		assert.Equal(t, a.Hash(), b.Hash())
+		assert.Len(t, a.Hash(), 64)
+	})
+
+	t.Run("drift", func(t *testing.T) {
+		t.Parallel()
+
+		a := build(t, fx.Provide(newManifestA, newManifestB))
+		b := build(t, fx.Module("mod", fx.Provide(newManifestA)), fx.Provide(newManifestB))
+		assert.NotEqual(t, a.Hash(), b.Hash())
+	})
+
+	t.Run("extend", func(t *testing.T) {
+		t.Parallel()
+
+		var m fx.GraphManifest
+		app := fx.New(fx.NopLogger, fx.RecordGraphManifest(&m), fx.Provide(newManifestA))
+		require.NoError(t, app.Err())
+		before := m
+
+		require.NoError(t, app.Extend(fx.Provide(newManifestB)))
+		assert.NotEqual(t, before, m)
+		assert.Contains(t, string(m), "provide go.uber.org/fx_test.newManifestB()")
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		var m fx.GraphManifest
+		app := fx.New(fx.NopLogger, fx.Module("mod", fx.RecordGraphManifest(&m)))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.RecordGraphManifest Option should be passed to top-level App")
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(fx.NopLogger, fx.RecordGraphManifest(nil))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "manifest must not be nil")
+	})
+}
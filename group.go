@@ -0,0 +1,177 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// RequireGroupNonEmpty fails the application if no constructor
+// contributes a value of type T to the given value group.
+// Without it, consumers of an empty group silently receive an empty slice,
+// which often fails obscurely when the application runs.
+//
+//	fx.New(
+//		fx.RequireGroupNonEmpty[http.Handler]("routes"),
+//		...
+//	)
+//
+// This is equivalent to RequireGroupMin[T](group, 1).
+func RequireGroupNonEmpty[T any](group string) Option {
+	return requireGroupOption{
+		Group:    group,
+		Type:     reflect.TypeOf((*T)(nil)).Elem(),
+		Min:      1,
+		Stack:    fxreflect.CallerStack(1, 0),
+		NonEmpty: true,
+	}
+}
+
+// RequireGroupMin fails the application if fewer than min values of type T
+// are contributed to the given value group.
+//
+//	fx.RequireGroupMin[Replica]("replicas", 3)
+//
+// Like an [Invoke], the constraint is checked when the application is
+// built, but it doesn't construct the values of the group:
+// it counts the constructors and supplied values that provide them.
+// A constructor counts as one value even if it provides a flattened slice.
+// It applies to the values visible from the module it is passed to.
+func RequireGroupMin[T any](group string, min int) Option {
+	return requireGroupOption{
+		Group: group,
+		Type:  reflect.TypeOf((*T)(nil)).Elem(),
+		Min:   min,
+		Stack: fxreflect.CallerStack(1, 0),
+	}
+}
+
+type requireGroupOption struct {
+	Group string
+	Type  reflect.Type
+	Min   int
+	Stack fxreflect.Stack
+
+	NonEmpty bool // whether this is an fx.RequireGroupNonEmpty
+}
+
+func (o requireGroupOption) apply(m *module) {
+	if err := o.validate(); err != nil {
+		m.app.err = fmt.Errorf("%v from:\n%+vFailed: %w", o, o.Stack, err)
+		return
+	}
+
+	g := &groupMin{
+		module: m,
+		key:    fmt.Sprintf("%v[group = %q]", o.Type, o.Group),
+	}
+	m.app.groupMins = append(m.app.groupMins, g)
+	m.invokes = append(m.invokes, invoke{
+		Target: func() error { return o.check(g) },
+		Stack:  o.Stack,
+		Name:   o.String(),
+	})
+}
+
+func (o requireGroupOption) validate() error {
+	if o.Group == "" || strings.Contains(o.Group, ",") {
+		return fmt.Errorf("invalid value group name %q", o.Group)
+	}
+	if o.Min < 0 {
+		return errors.New("minimum number of values cannot be negative")
+	}
+	return nil
+}
+
+// check fails if fewer than the minimum number of values
+// visible from the module of g were provided to the group.
+func (o requireGroupOption) check(g *groupMin) error {
+	var n int
+	for _, c := range g.contributors {
+		if c.visibleFrom(g.module) {
+			n++
+		}
+	}
+	if n < o.Min {
+		return fmt.Errorf("value group %q of %v has %d values, but requires at least %d",
+			o.Group, o.Type, n, o.Min)
+	}
+	return nil
+}
+
+func (o requireGroupOption) String() string {
+	if o.NonEmpty {
+		return fmt.Sprintf("fx.RequireGroupNonEmpty[%v](%q)", o.Type, o.Group)
+	}
+	return fmt.Sprintf("fx.RequireGroupMin[%v](%q, %d)", o.Type, o.Group, o.Min)
+}
+
+// groupMin is a value group passed to fx.RequireGroupMin
+// or fx.RequireGroupNonEmpty,
+// along with the constructors that provide values to it.
+type groupMin struct {
+	module       *module // module the option was passed to
+	key          string  // as reported by dig.Output.String
+	contributors []groupMinContributor
+}
+
+// groupMinContributor is a constructor or supplied value
+// that provides a value to a group passed to fx.RequireGroupMin.
+type groupMinContributor struct {
+	module  *module
+	private bool
+}
+
+// visibleFrom reports whether the value provided by c
+// can be consumed from m.
+func (c groupMinContributor) visibleFrom(m *module) bool {
+	if !c.private {
+		return true
+	}
+	for ; m != nil; m = m.parent {
+		if m == c.module {
+			return true
+		}
+	}
+	return false
+}
+
+// countGroupMins records p, provided by m, as a contributor
+// to the groups passed to fx.RequireGroupMin among outputs.
+func (m *module) countGroupMins(p provide, outputs []*dig.Output) {
+	for _, g := range m.app.groupMins {
+		for _, o := range outputs {
+			if o.String() == g.key {
+				g.contributors = append(g.contributors, groupMinContributor{
+					module:  m,
+					private: p.Private,
+				})
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+func TestRequireGroup(t *testing.T) {
+	t.Parallel()
+
+	type handler struct{ name string }
+	newHandler := func(name string) interface{} {
+		return fx.Annotate(
+			func() *handler { return &handler{name: name} },
+			fx.ResultTags(`group:"handlers"`),
+		)
+	}
+
+	t.Run("non-empty", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Provide(newHandler("a")),
+			fx.RequireGroupNonEmpty[*handler]("handlers"),
+		)
+		app.RequireStart().RequireStop()
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.RequireGroupNonEmpty[*handler]("handlers"),
+		)
+		assert.ErrorContains(t, app.Err(),
+			`value group "handlers" of *fx_test.handler has 0 values, but requires at least 1`)
+	})
+
+	t.Run("minimum", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(newHandler("a"), newHandler("b")),
+			fx.RequireGroupMin[*handler]("handlers", 3),
+		)
+		assert.ErrorContains(t, app.Err(),
+			`value group "handlers" of *fx_test.handler has 2 values, but requires at least 3`)
+
+		fxtest.New(t,
+			fx.Provide(newHandler("a"), newHandler("b"), newHandler("c")),
+			fx.RequireGroupMin[*handler]("handlers", 3),
+		).RequireStart().RequireStop()
+	})
+
+	t.Run("scoped to module", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Module("child",
+				fx.Provide(newHandler("a")),
+				fx.RequireGroupNonEmpty[*handler]("handlers"),
+			),
+			fx.RequireGroupMin[*handler]("handlers", 2),
+		)
+		assert.ErrorContains(t, app.Err(), "has 1 values, but requires at least 2")
+	})
+
+	t.Run("values are not built", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Provide(fx.Annotate(
+				func() *handler { panic("constructors must not run") },
+				fx.ResultTags(`group:"handlers"`),
+			)),
+			fx.Supply(fx.Annotate(&handler{name: "b"}, fx.ResultTags(`group:"handlers"`))),
+			fx.RequireGroupMin[*handler]("handlers", 2),
+		)
+		app.RequireStart().RequireStop()
+	})
+
+	t.Run("private values", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return &spy }),
+			fx.Module("child",
+				fx.Provide(newHandler("a"), fx.Private),
+				fx.Module("grandchild",
+					fx.RequireGroupNonEmpty[*handler]("handlers"),
+				),
+			),
+			fx.RequireGroupNonEmpty[*handler]("handlers"),
+		)
+		assert.ErrorContains(t, app.Err(), "has 0 values, but requires at least 1")
+
+		invoked := spy.Events().SelectByTypeName("Invoked")
+		require.Len(t, invoked, 2)
+		assert.Equal(t, "grandchild", invoked[0].(*fxevent.Invoked).ModuleName)
+		assert.NoError(t, invoked[0].(*fxevent.Invoked).Err, "private values are visible to submodules")
+		assert.Error(t, invoked[1].(*fxevent.Invoked).Err, "private values are not visible to parents")
+	})
+
+	t.Run("reported as invoke", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return &spy }),
+			fx.Provide(newHandler("a")),
+			fx.RequireGroupNonEmpty[*handler]("handlers"),
+		)
+		require.NoError(t, app.Err())
+
+		invoked := spy.Events().SelectByTypeName("Invoked")
+		require.Len(t, invoked, 1)
+		assert.Equal(t, `fx.RequireGroupNonEmpty[*fx_test.handler]("handlers")`,
+			invoked[0].(*fxevent.Invoked).FunctionName)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc string
+			give fx.Option
+			want string
+		}{
+			{
+				desc: "empty group name",
+				give: fx.RequireGroupNonEmpty[*handler](""),
+				want: `invalid value group name ""`,
+			},
+			{
+				desc: "group options",
+				give: fx.RequireGroupNonEmpty[*handler]("handlers,soft"),
+				want: `invalid value group name "handlers,soft"`,
+			},
+			{
+				desc: "negative minimum",
+				give: fx.RequireGroupMin[*handler]("handlers", -1),
+				want: "minimum number of values cannot be negative",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := fx.New(fx.NopLogger, tt.give)
+				assert.ErrorContains(t, app.Err(), tt.want)
+			})
+		}
+	})
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/internal/fxreflect"
+	"go.uber.org/multierr"
+)
+
+// RequireGroupConsumed fails the application if some of the values of type T
+// provided to the named value group are never built.
+//
+// Constructors that contribute to a value group only run if the group is
+// consumed. With soft value groups (`group:"routes,soft"`), only the values
+// of constructors that already ran for other reasons are consumed,
+// and the others are silently dropped. RequireGroupConsumed reports these:
+//
+//	fx.Provide(
+//		fx.Annotate(NewHealthRoute, fx.ResultTags(`group:"routes"`)),
+//		fx.Annotate(NewUserRoute, fx.ResultTags(`group:"routes"`)),
+//	),
+//	fx.Invoke(fx.Annotate(RegisterRoutes, fx.ParamTags(`group:"routes,soft"`))),
+//	fx.RequireGroupConsumed[Route]("routes"),
+//
+// The check runs once the functions passed to [Invoke] ran,
+// or, if the application uses [InvokeAtStart],
+// when those ran as the application starts.
+// It runs again after [App.Extend].
+// It's skipped when the application is only validated with [ValidateApp].
+func RequireGroupConsumed[T any](group string) Option {
+	return requireGroupConsumedOption{
+		Group: group,
+		Type:  reflect.TypeOf((*T)(nil)).Elem(),
+		Stack: fxreflect.CallerStack(1, 0),
+	}
+}
+
+type requireGroupConsumedOption struct {
+	Group string
+	Type  reflect.Type
+	Stack fxreflect.Stack
+}
+
+func (o requireGroupConsumedOption) apply(m *module) {
+	m.app.requiredGroups = append(m.app.requiredGroups, &requiredGroup{
+		option: o,
+		key:    fmt.Sprintf("%v[group = %q]", o.Type, o.Group),
+	})
+}
+
+func (o requireGroupConsumedOption) String() string {
+	return fmt.Sprintf("fx.RequireGroupConsumed[%v](%q)", o.Type, o.Group)
+}
+
+// requiredGroup is a value group passed to fx.RequireGroupConsumed,
+// along with the constructors that contribute to it.
+type requiredGroup struct {
+	option       requireGroupConsumedOption
+	key          string // as reported by dig.Output.String
+	contributors []*groupContributor
+}
+
+// groupContributor is a constructor that provides values to a required group.
+type groupContributor struct {
+	info ProviderInfo
+	ran  atomic.Bool
+}
+
+// groupContributors records the constructor p provided by m under the given
+// name as a contributor to the required groups among outputs.
+// It returns the records to mark once the constructor ran.
+func (m *module) groupContributors(name string, p provide, outputs []*dig.Output) []*groupContributor {
+	var contributors []*groupContributor
+	for _, g := range m.app.requiredGroups {
+		for _, o := range outputs {
+			if o.String() != g.key {
+				continue
+			}
+			c := &groupContributor{info: m.providerInfo(name, p)}
+			g.contributors = append(g.contributors, c)
+			contributors = append(contributors, c)
+			break
+		}
+	}
+	return contributors
+}
+
+// checkGroupsConsumed returns an error if the constructors
+// that contribute to a required group did not all run.
+func (app *App) checkGroupsConsumed() error {
+	if app.validate {
+		return nil
+	}
+
+	var errs []error
+	for _, g := range app.requiredGroups {
+		var dropped []string
+		for _, c := range g.contributors {
+			if !c.ran.Load() {
+				dropped = append(dropped, c.info.describe())
+			}
+		}
+		if len(dropped) == 0 {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("%v from:\n%+vFailed: "+
+			"values provided to the group by %d constructors were never built:\n\t%v",
+			g.option, g.option.Stack, len(dropped), strings.Join(dropped, "\n\t")))
+	}
+	return multierr.Combine(errs...)
+}
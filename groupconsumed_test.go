@@ -0,0 +1,147 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type consumedRoute string
+
+func newHealthRoute() consumedRoute { return "health" }
+
+func newUserRoute() consumedRoute { return "user" }
+
+func TestRequireGroupConsumed(t *testing.T) {
+	t.Parallel()
+
+	routes := fx.Provide(
+		fx.Annotate(newHealthRoute, fx.ResultTags(`group:"routes"`)),
+		fx.Annotate(newUserRoute, fx.ResultTags(`group:"routes"`)),
+	)
+
+	t.Run("consumed", func(t *testing.T) {
+		t.Parallel()
+
+		var got []consumedRoute
+		app := fxtest.New(t,
+			routes,
+			fx.Invoke(fx.Annotate(func(rs []consumedRoute) { got = rs }, fx.ParamTags(`group:"routes"`))),
+			fx.RequireGroupConsumed[consumedRoute]("routes"),
+		)
+		app.RequireStart().RequireStop()
+		assert.Len(t, got, 2)
+	})
+
+	t.Run("dropped by soft group", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			routes,
+			fx.Invoke(fx.Annotate(func([]consumedRoute) {}, fx.ParamTags(`group:"routes,soft"`))),
+			fx.RequireGroupConsumed[consumedRoute]("routes"),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `fx.RequireGroupConsumed[fx_test.consumedRoute]("routes") from:`)
+		assert.Contains(t, err.Error(), "values provided to the group by 2 constructors were never built")
+		assert.Contains(t, err.Error(), "newHealthRoute")
+		assert.Contains(t, err.Error(), "newUserRoute")
+	})
+
+	t.Run("other groups", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Provide(fx.Annotate(newHealthRoute, fx.ResultTags(`group:"other"`))),
+			fx.RequireGroupConsumed[consumedRoute]("routes"),
+		)
+		app.RequireStart().RequireStop()
+	})
+
+	t.Run("supplied", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Supply(fx.Annotated{Group: "routes", Target: consumedRoute("static")}),
+			fx.RequireGroupConsumed[consumedRoute]("routes"),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.Supply(fx_test.consumedRoute)")
+	})
+
+	t.Run("consumed at start", func(t *testing.T) {
+		t.Parallel()
+
+		var got []consumedRoute
+		app := fx.New(
+			fx.NopLogger,
+			routes,
+			fx.InvokeAtStart(fx.Annotate(func(rs []consumedRoute) { got = rs }, fx.ParamTags(`group:"routes"`))),
+			fx.RequireGroupConsumed[consumedRoute]("routes"),
+		)
+		require.NoError(t, app.Err())
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+		assert.Len(t, got, 2)
+	})
+
+	t.Run("not consumed at start", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			routes,
+			fx.InvokeAtStart(func() {}),
+			fx.RequireGroupConsumed[consumedRoute]("routes"),
+		)
+		require.NoError(t, app.Err())
+		err := app.Start(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "were never built")
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		t.Parallel()
+
+		err := fx.ValidateApp(
+			routes,
+			fx.RequireGroupConsumed[consumedRoute]("routes"),
+		)
+		assert.NoError(t, err)
+	})
+
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, `fx.RequireGroupConsumed[fx_test.consumedRoute]("routes")`,
+			fx.RequireGroupConsumed[consumedRoute]("routes").String())
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"log/slog"
+	"reflect"
+
+	"go.uber.org/zap"
+)
+
+var (
+	_typeOfZapLogger  = reflect.TypeOf((*zap.Logger)(nil))
+	_typeOfSlogLogger = reflect.TypeOf((*slog.Logger)(nil))
+)
+
+// hookZapLogger returns log with fields identifying
+// the hook function funcName described by info.
+func hookZapLogger(log *zap.Logger, info HookInfo, funcName string) *zap.Logger {
+	fields := []zap.Field{
+		zap.String("hook", info.Hook),
+		zap.String("callee", funcName),
+		zap.String("caller", info.Target),
+	}
+	if info.Module != "" {
+		fields = append(fields, zap.String("module", info.Module))
+	}
+	return log.With(fields...)
+}
+
+// hookSlogLogger is the [slog.Logger] counterpart of hookZapLogger.
+func hookSlogLogger(log *slog.Logger, info HookInfo, funcName string) *slog.Logger {
+	args := []any{
+		slog.String("hook", info.Hook),
+		slog.String("callee", funcName),
+		slog.String("caller", info.Target),
+	}
+	if info.Module != "" {
+		args = append(args, slog.String("module", info.Module))
+	}
+	return log.With(args...)
+}
+
+// hookModule returns the name of the module appending hooks to lc,
+// if any.
+func hookModule(lc Lifecycle) string {
+	lw, ok := lc.(*lifecycleWrapper)
+	if !ok {
+		return ""
+	}
+	if m := lw.modules.current(); m != nil {
+		return m.Name
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/fxclock"
+)
+
+// OnStartRetry is an Annotation that retries the OnStart hook appended by an
+// [OnStart] annotation on the same function per the given policy when it
+// returns an error. This is intended for hooks that wait for external
+// services, which may not be available yet when the application starts.
+//
+//	fx.Provide(
+//		fx.Annotate(
+//			NewClient,
+//			fx.OnStart(func(ctx context.Context, c *Client) error {
+//				return c.Connect(ctx)
+//			}),
+//			fx.OnStartRetry(fx.Retry(5, time.Second)),
+//		),
+//	)
+//
+// Each failed attempt that is retried is reported as an
+// [fxevent.OnStartRetrying] event. If all attempts fail, or the start
+// context expires while waiting for the next attempt, the application fails
+// to start with the error returned by the last attempt.
+func OnStartRetry(policy RetryPolicy) Annotation {
+	return onStartRetryAnnotation{policy}
+}
+
+type onStartRetryAnnotation struct {
+	policy RetryPolicy
+}
+
+func (a onStartRetryAnnotation) String() string {
+	return fmt.Sprintf("fx.OnStartRetry(%v)", a.policy)
+}
+
+func (a onStartRetryAnnotation) apply(ann *annotated) error {
+	if a.policy.attempts < 1 {
+		return fmt.Errorf("cannot apply %v: must make at least one attempt, got %d", a, a.policy.attempts)
+	}
+	if ann.StartRetry != nil {
+		return fmt.Errorf("cannot apply more than one %q annotation", "OnStartRetry")
+	}
+	policy := a.policy
+	ann.StartRetry = &policy
+	return nil
+}
+
+func (a onStartRetryAnnotation) build(ann *annotated) (interface{}, error) {
+	for _, h := range ann.Hooks {
+		if h.Type == _onStartHookType {
+			return ann.Target, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot apply %v: function has no %q hook annotation", a, _onStartHook)
+}
+
+// retryHook wraps an OnStart hook appended to lc to retry it per the given
+// policy, reporting retries to the application's logger.
+func retryHook(
+	lc Lifecycle,
+	policy RetryPolicy,
+	funcName, callerName string,
+	fn func(context.Context) error,
+) func(context.Context) error {
+	var (
+		logger fxevent.Logger = fxevent.NopLogger
+		clock  fxclock.Clock  = fxclock.System
+	)
+	if lw, ok := lc.(*lifecycleWrapper); ok && lw.logger != nil {
+		logger, clock = lw.logger, lw.clock
+	}
+
+	return func(ctx context.Context) error {
+		delay := policy.backoff
+		for attempt := 1; ; attempt++ {
+			err := fn(ctx)
+			if err == nil {
+				return nil
+			}
+			if attempt >= policy.attempts {
+				if attempt > 1 {
+					err = fmt.Errorf("failed after %d attempts: %w", attempt, err)
+				}
+				return err
+			}
+
+			logger.LogEvent(&fxevent.OnStartRetrying{
+				FunctionName: funcName,
+				CallerName:   callerName,
+				Attempt:      attempt,
+				Attempts:     policy.attempts,
+				Delay:        delay,
+				Err:          err,
+			})
+			if werr := waitFor(ctx, clock, delay); werr != nil {
+				return fmt.Errorf("stopped retrying after %d attempts: %v: %w", attempt, werr, err)
+			}
+			delay = nextBackoff(delay)
+		}
+	}
+}
+
+// waitFor waits for d to pass on the given clock,
+// returning early with the context's error if it's done first.
+func waitFor(ctx context.Context, clock fxclock.Clock, d time.Duration) error {
+	timer, cancel := clock.WithTimeout(ctx, d)
+	defer cancel()
+	select {
+	case <-ctx.Done():
+	case <-timer.Done():
+	}
+	return ctx.Err()
+}
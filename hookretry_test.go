@@ -0,0 +1,199 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/fxclock"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+type retriedClient struct{ connects int }
+
+func newRetriedClient() *retriedClient { return &retriedClient{} }
+
+func TestOnStartRetry(t *testing.T) {
+	t.Parallel()
+
+	errRefused := errors.New("connection refused")
+
+	// connect returns an OnStart hook that fails the given number of times.
+	connect := func(failures int) func(*retriedClient) error {
+		return func(c *retriedClient) error {
+			c.connects++
+			if c.connects <= failures {
+				return errRefused
+			}
+			return nil
+		}
+	}
+
+	// newApp builds an application with the given options.
+	newApp := func(t *testing.T, clock *fxclock.Mock, opts ...fx.Option) (*fx.App, *fxlog.Spy, *retriedClient) {
+		spy := new(fxlog.Spy)
+		var c *retriedClient
+		opts = append(opts,
+			fx.WithClock(clock),
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.Populate(&c),
+		)
+		app := fx.New(opts...)
+		require.NoError(t, app.Err())
+		return app, spy, c
+	}
+
+	// start starts the application in the background,
+	// advancing the clock past the given delays.
+	start := func(ctx context.Context, app *fx.App, clock *fxclock.Mock, delays ...time.Duration) error {
+		done := make(chan error)
+		go func() { done <- app.Start(ctx) }()
+		for _, d := range delays {
+			clock.AwaitScheduled(1)
+			clock.Add(d)
+		}
+		return <-done
+	}
+
+	t.Run("Succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		app, spy, c := newApp(t, clock, fx.Provide(
+			fx.Annotate(newRetriedClient,
+				fx.OnStartRetry(fx.Retry(3, time.Second)),
+				fx.OnStart(connect(2)),
+			),
+		))
+		require.NoError(t, start(context.Background(), app, clock, time.Second, 2*time.Second))
+		assert.Equal(t, 3, c.connects)
+
+		events := spy.Events().SelectByTypeName("OnStartRetrying")
+		require.Len(t, events, 2)
+		for i, e := range events {
+			e := e.(*fxevent.OnStartRetrying)
+			assert.Equal(t, i+1, e.Attempt)
+			assert.Equal(t, 3, e.Attempts)
+			assert.Equal(t, time.Second<<i, e.Delay)
+			assert.Contains(t, e.CallerName, "newRetriedClient")
+			assert.ErrorIs(t, e.Err, errRefused)
+		}
+		require.NoError(t, app.Stop(context.Background()))
+	})
+
+	t.Run("Exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		app, spy, c := newApp(t, clock, fx.Provide(
+			fx.Annotate(newRetriedClient,
+				fx.OnStart(connect(5)),
+				fx.OnStartRetry(fx.Retry(2, time.Second)),
+			),
+		))
+		err := start(context.Background(), app, clock, time.Second)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errRefused)
+		assert.Contains(t, err.Error(), "failed after 2 attempts")
+		assert.Equal(t, 2, c.connects)
+		assert.Len(t, spy.Events().SelectByTypeName("OnStartRetrying"), 1)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		app, _, c := newApp(t, clock, fx.Provide(
+			fx.Annotate(newRetriedClient,
+				fx.OnStart(connect(5)),
+				fx.OnStartRetry(fx.Retry(5, time.Minute)),
+			),
+		))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- app.Start(ctx) }()
+		clock.AwaitScheduled(1)
+		cancel()
+
+		assert.ErrorIs(t, <-done, context.Canceled)
+		assert.Equal(t, 1, c.connects)
+	})
+
+	t.Run("Annotation errors", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    interface{}
+			wantErr string
+		}{
+			{
+				desc: "no OnStart hook",
+				give: fx.Annotate(newRetriedClient,
+					fx.OnStop(func() {}),
+					fx.OnStartRetry(fx.Retry(3, time.Second)),
+				),
+				wantErr: `cannot apply fx.OnStartRetry(fx.Retry(3, 1s)): function has no "OnStart" hook annotation`,
+			},
+			{
+				desc: "no attempts",
+				give: fx.Annotate(newRetriedClient,
+					fx.OnStart(func() {}),
+					fx.OnStartRetry(fx.Retry(0, time.Second)),
+				),
+				wantErr: "must make at least one attempt, got 0",
+			},
+			{
+				desc: "duplicate",
+				give: fx.Annotate(newRetriedClient,
+					fx.OnStart(func() {}),
+					fx.OnStartRetry(fx.Retry(3, time.Second)),
+					fx.OnStartRetry(fx.Retry(3, time.Second)),
+				),
+				wantErr: `cannot apply more than one "OnStartRetry" annotation`,
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := fx.New(
+					fx.NopLogger,
+					fx.Provide(tt.give),
+					fx.Invoke(func(*retriedClient) {}),
+				)
+				err := app.Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
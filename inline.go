@@ -0,0 +1,300 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// _inlineTag marks an embedded struct in an fx.In struct
+// whose fields are injected as if they were declared in the fx.In struct.
+const _inlineTag = "inline"
+
+// inlineField is a field of a flattened fx.In or fx.Out struct,
+// along with the index sequence of the field in the original struct.
+type inlineField struct {
+	field reflect.StructField
+	index []int
+}
+
+// flattenStructs returns a function equivalent to fn
+// whose fx.In parameters have their fields from embedded structs tagged
+// `inline:"true"` declared directly on them instead.
+// This allows parameter structs to share fields through embedding:
+//
+//	type Common struct {
+//		Logger *zap.Logger
+//		Config *Config
+//	}
+//
+//	type Params struct {
+//		fx.In
+//		Common `inline:"true"`
+//
+//		Handler http.Handler
+//	}
+//
+// Likewise, fx.Out results have the fields of embedded fx.Out structs
+// and of embedded structs tagged `inline:"true"` declared directly on them,
+// so that result structs can be composed from others:
+//
+//	type ObservabilityResult struct {
+//		fx.Out
+//
+//		Metrics *Metrics
+//		Tracer  *Tracer
+//	}
+//
+//	type ServerResult struct {
+//		fx.Out
+//		ObservabilityResult
+//
+//		Server *http.Server
+//	}
+//
+// The returned function also fills in the defaults of optional fields
+// tagged `default:"..."` that c does not provide, as described in paramDefaults,
+// and supports value groups with keys, as described in keyGroups.
+//
+// It reports whether fn had any such parameters or results.
+// If it didn't, fn is returned unchanged.
+func flattenStructs(c container, fn interface{}) (interface{}, bool, error) {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func {
+		return fn, false, nil
+	}
+
+	ft := fv.Type()
+	var (
+		changed    bool
+		ins        = make([]reflect.Type, ft.NumIn())
+		unflattens = make([]func(reflect.Value) reflect.Value, ft.NumIn())
+		outs       = make([]reflect.Type, ft.NumOut())
+		flattens   = make([]func(reflect.Value) reflect.Value, ft.NumOut())
+	)
+	for i := range ins {
+		ins[i] = ft.In(i)
+		if !isIn(ins[i]) {
+			continue
+		}
+
+		pt := ins[i]
+		flat, unflatten, ok, err := flattenParamStruct(pt)
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			ins[i], unflattens[i] = flat, unflatten
+			changed = true
+		}
+
+		fill, err := paramDefaults(pt)
+		if err != nil {
+			return nil, false, err
+		}
+		if fill != nil {
+			unflattens[i] = func(v reflect.Value) reflect.Value {
+				if unflatten != nil {
+					v = unflatten(v)
+				}
+				out := reflect.New(pt).Elem()
+				out.Set(v)
+				fill(out, c)
+				return out
+			}
+			changed = true
+		}
+	}
+	for i := range outs {
+		outs[i] = ft.Out(i)
+		if !isOut(outs[i]) {
+			continue
+		}
+
+		flat, flatten, ok, err := flattenResultStruct(outs[i])
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			outs[i], flattens[i] = flat, flatten
+			changed = true
+		}
+	}
+	if !changed {
+		return keyGroups(fn)
+	}
+
+	call := fv.Call
+	if ft.IsVariadic() {
+		call = fv.CallSlice
+	}
+	newFt := reflect.FuncOf(ins, outs, ft.IsVariadic())
+	flat := reflect.MakeFunc(newFt, func(args []reflect.Value) []reflect.Value {
+		for i, unflatten := range unflattens {
+			if unflatten != nil {
+				args[i] = unflatten(args[i])
+			}
+		}
+		results := call(args)
+		for i, flatten := range flattens {
+			if flatten != nil {
+				results[i] = flatten(results[i])
+			}
+		}
+		return results
+	}).Interface()
+
+	flat, _, err := keyGroups(flat)
+	return flat, true, err
+}
+
+// flattenParamStruct builds a flattened version of the fx.In struct t,
+// along with a function to convert values of it back into t.
+// It reports whether t had any inline fields.
+func flattenParamStruct(t reflect.Type) (reflect.Type, func(reflect.Value) reflect.Value, bool, error) {
+	fields, inlined, err := inlineFields(t, func(f reflect.StructField) bool {
+		return f.Anonymous && f.Tag.Get(_inlineTag) == "true"
+	})
+	if err != nil || !inlined {
+		return t, nil, false, err
+	}
+
+	// Unexported fields cannot be declared on the flattened struct.
+	// Drop them if the fx.In opted into ignoring them.
+	var ignoreUnexported bool
+	if in, ok := t.FieldByName(_inAnnotationField.Name); ok && in.Type == _inAnnotationField.Type {
+		ignoreUnexported = in.Tag.Get("ignore-unexported") == "true"
+	}
+
+	exported := fields[:0]
+	for _, f := range fields {
+		if f.field.PkgPath == "" {
+			exported = append(exported, f)
+		} else if !ignoreUnexported {
+			return nil, nil, false, fmt.Errorf(
+				"unexported field %v of %v cannot be injected: "+
+					"use the ignore-unexported tag on fx.In to ignore it",
+				f.field.Name, t)
+		}
+	}
+	fields = exported
+
+	structFields := make([]reflect.StructField, 0, len(fields)+1)
+	structFields = append(structFields, _inAnnotationField)
+	for _, f := range fields {
+		structFields = append(structFields, f.field)
+	}
+
+	flat := reflect.StructOf(structFields)
+	unflatten := func(v reflect.Value) reflect.Value {
+		out := reflect.New(t).Elem()
+		for i, f := range fields {
+			out.FieldByIndex(f.index).Set(v.Field(i + 1))
+		}
+		return out
+	}
+	return flat, unflatten, true, nil
+}
+
+// flattenResultStruct builds a flattened version of the fx.Out struct t,
+// along with a function to convert values of t into it.
+// It reports whether t had any embedded fx.Out or inline fields.
+func flattenResultStruct(t reflect.Type) (reflect.Type, func(reflect.Value) reflect.Value, bool, error) {
+	fields, inlined, err := inlineFields(t, func(f reflect.StructField) bool {
+		return f.Anonymous && (f.Tag.Get(_inlineTag) == "true" || isOut(f.Type))
+	})
+	if err != nil || !inlined {
+		return t, nil, false, err
+	}
+
+	structFields := make([]reflect.StructField, 0, len(fields)+1)
+	structFields = append(structFields, _outAnnotationField)
+	for _, f := range fields {
+		if f.field.PkgPath != "" {
+			return nil, nil, false, fmt.Errorf(
+				"unexported field %v of %v cannot be provided", f.field.Name, t)
+		}
+		structFields = append(structFields, f.field)
+	}
+
+	flat := reflect.StructOf(structFields)
+	flatten := func(v reflect.Value) reflect.Value {
+		out := reflect.New(flat).Elem()
+		for i, f := range fields {
+			out.Field(i + 1).Set(v.FieldByIndex(f.index))
+		}
+		return out
+	}
+	return flat, flatten, true, nil
+}
+
+// inlineFields collects the fields of the fx.In or fx.Out struct t,
+// declaring the fields of the embedded structs for which nested returns
+// true in their place, recursively.
+// It reports whether t had any such embedded structs.
+func inlineFields(t reflect.Type, nested func(reflect.StructField) bool) ([]inlineField, bool, error) {
+	var (
+		fields   []inlineField
+		inlined  bool
+		declared = make(map[string]reflect.Type) // field name => declaring type
+	)
+
+	var collect func(t reflect.Type, index []int) error
+	collect = func(t reflect.Type, index []int) error {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			idx := append(index[:len(index):len(index)], i)
+
+			if f.Type == _inAnnotationField.Type || f.Type == _outAnnotationField.Type {
+				continue
+			}
+
+			if nested(f) {
+				if f.Type.Kind() != reflect.Struct {
+					return fmt.Errorf("inline field %v of %v must be a struct, got %v", f.Name, t, f.Type)
+				}
+				inlined = true
+				if err := collect(f.Type, idx); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if prev, ok := declared[f.Name]; ok {
+				return fmt.Errorf("field %v of %v conflicts with field %v of %v", f.Name, t, f.Name, prev)
+			}
+			declared[f.Name] = t
+
+			// Embedded fields are declared by name in the flattened struct
+			// because reflect.StructOf does not support promoted methods.
+			f.Anonymous = false
+			f.Index = nil
+			f.Offset = 0
+			fields = append(fields, inlineField{field: f, index: idx})
+		}
+		return nil
+	}
+	if err := collect(t, nil); err != nil {
+		return nil, false, err
+	}
+	return fields, inlined, nil
+}
@@ -0,0 +1,446 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestInlineParams(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ name string }
+	type B struct{ name string }
+	type C struct{ name string }
+
+	type Common struct {
+		A *A
+		B *B `name:"b"`
+	}
+
+	type Params struct {
+		fx.In
+		Common `inline:"true"`
+
+		C *C `optional:"true"`
+	}
+
+	type CommonParams struct {
+		fx.In
+		Common `inline:"true"`
+	}
+
+	provideAll := fx.Provide(
+		func() *A { return &A{"a"} },
+		fx.Annotate(func() *B { return &B{"b"} }, fx.ResultTags(`name:"b"`)),
+	)
+
+	t.Run("invoke", func(t *testing.T) {
+		t.Parallel()
+
+		var got Params
+		app := fxtest.New(t,
+			provideAll,
+			fx.Invoke(func(p Params) { got = p }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "a", got.A.name)
+		assert.Equal(t, "b", got.B.name)
+		assert.Nil(t, got.C)
+	})
+
+	t.Run("provide", func(t *testing.T) {
+		t.Parallel()
+
+		var got *C
+		app := fxtest.New(t,
+			provideAll,
+			fx.Provide(
+				func(p CommonParams) *C { return &C{p.A.name + p.B.name} },
+				fx.Annotated{
+					Name:   "c",
+					Target: func(p CommonParams) *C { return &C{"annotated"} },
+				},
+			),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "ab", got.name)
+	})
+
+	t.Run("annotate", func(t *testing.T) {
+		t.Parallel()
+
+		var got *C
+		app := fxtest.New(t,
+			provideAll,
+			fx.Provide(
+				fx.Annotate(
+					func(p CommonParams) *C { return &C{p.A.name} },
+					fx.ResultTags(`name:"c"`),
+				),
+			),
+			fx.Populate(fx.Annotate(&got, fx.ParamTags(`name:"c"`))),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "a", got.name)
+	})
+
+	t.Run("decorate", func(t *testing.T) {
+		t.Parallel()
+
+		var got *A
+		app := fxtest.New(t,
+			provideAll,
+			fx.Decorate(func(p Params) *A { return &A{p.A.name + "!"} }),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "a!", got.name)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		t.Parallel()
+
+		type Outer struct {
+			Common `inline:"true"`
+
+			C *C
+		}
+		type NestedParams struct {
+			fx.In
+			Outer `inline:"true"`
+		}
+
+		var got NestedParams
+		app := fxtest.New(t,
+			provideAll,
+			fx.Provide(func() *C { return &C{"c"} }),
+			fx.Invoke(func(p NestedParams) { got = p }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "a", got.A.name)
+		assert.Equal(t, "b", got.B.name)
+		assert.Equal(t, "c", got.C.name)
+	})
+
+	t.Run("variadic", func(t *testing.T) {
+		t.Parallel()
+
+		var got *A
+		app := fxtest.New(t,
+			provideAll,
+			fx.Invoke(func(p Params, opts ...string) { got = p.A }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "a", got.name)
+	})
+
+	t.Run("without tag", func(t *testing.T) {
+		t.Parallel()
+
+		type NotInline struct {
+			fx.In
+			Common
+		}
+
+		var got NotInline
+		app := fxtest.New(t,
+			fx.Supply(Common{A: &A{"supplied"}}),
+			fx.Invoke(func(p NotInline) { got = p }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "supplied", got.A.name)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		t.Parallel()
+
+		type Conflict struct {
+			fx.In
+			Common `inline:"true"`
+
+			A *A
+		}
+
+		app := fx.New(
+			fx.NopLogger,
+			provideAll,
+			fx.Invoke(func(Conflict) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field A of fx_test.Conflict conflicts with field A of fx_test.Common")
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		t.Parallel()
+
+		type Ptr struct {
+			fx.In
+			*Common `inline:"true"`
+		}
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(func(Ptr) *C { return &C{} }),
+			fx.Invoke(func(*C) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "inline field Common of fx_test.Ptr must be a struct")
+	})
+
+	t.Run("unexported field", func(t *testing.T) {
+		t.Parallel()
+
+		type hidden struct {
+			a *A
+		}
+		type Hidden struct {
+			fx.In
+			hidden `inline:"true"`
+		}
+
+		app := fx.New(
+			fx.NopLogger,
+			provideAll,
+			fx.Decorate(func(Hidden) *A { return &A{} }),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unexported field a of fx_test.Hidden cannot be injected")
+	})
+
+	t.Run("ignore unexported", func(t *testing.T) {
+		t.Parallel()
+
+		type hidden struct {
+			A *A
+			b *B
+		}
+		type Hidden struct {
+			fx.In  `ignore-unexported:"true"`
+			hidden `inline:"true"`
+		}
+
+		var got Hidden
+		app := fxtest.New(t,
+			provideAll,
+			fx.Invoke(func(p Hidden) { got = p }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "a", got.A.name)
+		assert.Nil(t, got.b)
+	})
+}
+
+func TestInlineResults(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ name string }
+	type B struct{ name string }
+	type C struct{ name string }
+
+	type ObservabilityResult struct {
+		fx.Out
+
+		A *A
+		B *B `name:"b"`
+	}
+
+	type ServerResult struct {
+		fx.Out
+		ObservabilityResult
+
+		C *C
+	}
+
+	type Params struct {
+		fx.In
+
+		A *A
+		B *B `name:"b"`
+		C *C
+	}
+
+	newServer := func() ServerResult {
+		return ServerResult{
+			ObservabilityResult: ObservabilityResult{A: &A{"a"}, B: &B{"b"}},
+			C:                   &C{"c"},
+		}
+	}
+
+	t.Run("embedded out", func(t *testing.T) {
+		t.Parallel()
+
+		var got Params
+		app := fxtest.New(t,
+			fx.Provide(newServer),
+			fx.Invoke(func(p Params) { got = p }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "a", got.A.name)
+		assert.Equal(t, "b", got.B.name)
+		assert.Equal(t, "c", got.C.name)
+	})
+
+	t.Run("unexported embedded out", func(t *testing.T) {
+		t.Parallel()
+
+		type observability struct {
+			fx.Out
+
+			A *A
+		}
+		type Result struct {
+			fx.Out
+			observability
+
+			C *C
+		}
+
+		var got Params
+		app := fxtest.New(t,
+			fx.Provide(
+				func() Result {
+					return Result{observability: observability{A: &A{"a"}}, C: &C{"c"}}
+				},
+				fx.Annotate(func() *B { return &B{"b"} }, fx.ResultTags(`name:"b"`)),
+			),
+			fx.Invoke(func(p Params) { got = p }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "a", got.A.name)
+		assert.Equal(t, "c", got.C.name)
+	})
+
+	t.Run("inline", func(t *testing.T) {
+		t.Parallel()
+
+		type Common struct {
+			A *A
+			B *B `name:"b"`
+		}
+		type Result struct {
+			fx.Out
+			Common `inline:"true"`
+
+			C *C
+		}
+
+		var got Params
+		app := fxtest.New(t,
+			fx.Provide(func() Result {
+				return Result{Common: Common{A: &A{"a"}, B: &B{"b"}}, C: &C{"c"}}
+			}),
+			fx.Invoke(func(p Params) { got = p }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "a", got.A.name)
+		assert.Equal(t, "b", got.B.name)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		t.Parallel()
+
+		type AppResult struct {
+			fx.Out
+			ServerResult
+		}
+
+		var got Params
+		app := fxtest.New(t,
+			fx.Provide(func() AppResult { return AppResult{ServerResult: newServer()} }),
+			fx.Invoke(func(p Params) { got = p }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "b", got.B.name)
+		assert.Equal(t, "c", got.C.name)
+	})
+
+	t.Run("keyed group", func(t *testing.T) {
+		t.Parallel()
+
+		type CodecResult struct {
+			fx.Out
+
+			Codec *A `group:"codecs" key:"a"`
+		}
+		type Result struct {
+			fx.Out
+			CodecResult
+
+			C *C
+		}
+
+		var got map[string]*A
+		app := fxtest.New(t,
+			fx.Provide(func() Result {
+				return Result{CodecResult: CodecResult{Codec: &A{"a"}}, C: &C{"c"}}
+			}),
+			fx.Invoke(fx.Annotate(
+				func(m map[string]*A, _ *C) { got = m },
+				fx.ParamTags(`group:"codecs"`),
+			)),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "a", got["a"].name)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		t.Parallel()
+
+		type Conflict struct {
+			fx.Out
+			ObservabilityResult
+
+			A *A
+		}
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(func() Conflict { return Conflict{} }),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"field A of fx_test.Conflict conflicts with field A of fx_test.ObservabilityResult")
+	})
+}
@@ -57,6 +57,75 @@ func (systemClock) WithTimeout(ctx context.Context, d time.Duration) (context.Co
 	return context.WithTimeout(ctx, d)
 }
 
+// Monotonic wraps a Clock so that the contexts returned by its WithTimeout
+// method are cancelled by a timer of the wrapped clock,
+// and do not add a deadline to that of their parent.
+//
+// Deadlines are absolute wall-clock times, so code that forwards them to
+// other processes, e.g. as the deadline of an RPC, relies on their clocks
+// agreeing with this one.
+func Monotonic(c Clock) Clock {
+	return monotonicClock{c}
+}
+
+type monotonicClock struct{ Clock }
+
+func (c monotonicClock) WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	inner, cancelInner := context.WithCancel(ctx)
+	tctx := &timerCtx{
+		inner:       inner,
+		cancelInner: cancelInner,
+		done:        make(chan struct{}),
+	}
+
+	timeout, cancelTimeout := c.Clock.WithTimeout(context.Background(), d)
+	go func() {
+		defer cancelTimeout()
+
+		select {
+		case <-timeout.Done():
+			tctx.cancel(context.DeadlineExceeded)
+		case <-inner.Done():
+			tctx.cancel(inner.Err())
+		}
+	}()
+	return tctx, func() { tctx.cancel(context.Canceled) }
+}
+
+// timerCtx is a context that is cancelled by a timer
+// but only reports the deadline of its parent.
+type timerCtx struct {
+	inner       context.Context
+	cancelInner func()
+
+	done chan struct{}
+
+	mu  sync.Mutex // guards err; the rest is immutable
+	err error
+}
+
+var _ context.Context = (*timerCtx)(nil)
+
+func (c *timerCtx) Deadline() (deadline time.Time, ok bool) { return c.inner.Deadline() }
+func (c *timerCtx) Done() <-chan struct{}                   { return c.done }
+func (c *timerCtx) Value(key any) any                       { return c.inner.Value(key) }
+
+func (c *timerCtx) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *timerCtx) cancel(err error) {
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = err
+		close(c.done)
+		c.cancelInner()
+	}
+	c.mu.Unlock()
+}
+
 // Mock adapted from
 // https://github.com/uber-go/zap/blob/7db06bc9b095571d3dc3d4eebdfbe4dd9bd20405/internal/ztest/clock.go.
 
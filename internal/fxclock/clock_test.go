@@ -121,6 +121,85 @@ func testClock(t *testing.T, clock Clock, advance func(d time.Duration)) {
 	})
 }
 
+func TestMonotonicClock(t *testing.T) {
+	awaitDone := func(t *testing.T, ctx context.Context) {
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Fatal("expected context to be done")
+		}
+	}
+
+	t.Run("NoDeadline", func(t *testing.T) {
+		clock := Monotonic(NewMock())
+		ctx, cancel := clock.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, ok := ctx.Deadline()
+		assert.False(t, ok, "must not have a deadline")
+	})
+
+	t.Run("ParentDeadline", func(t *testing.T) {
+		want := time.Now().Add(time.Hour)
+		parent, cancelParent := context.WithDeadline(context.Background(), want)
+		defer cancelParent()
+
+		ctx, cancel := Monotonic(NewMock()).WithTimeout(parent, time.Second)
+		defer cancel()
+
+		got, ok := ctx.Deadline()
+		assert.True(t, ok, "must keep the parent's deadline")
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("Expires", func(t *testing.T) {
+		mock := NewMock()
+		ctx, cancel := Monotonic(mock).WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		mock.Add(999 * time.Millisecond)
+		assert.NoError(t, ctx.Err(), "must not expire early")
+
+		mock.Add(time.Millisecond)
+		awaitDone(t, ctx)
+		assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		mock := NewMock()
+		ctx, cancel := Monotonic(mock).WithTimeout(context.Background(), time.Second)
+		cancel()
+
+		awaitDone(t, ctx)
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+		// Expiring after cancellation must not change the error.
+		mock.Add(time.Second)
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
+
+	t.Run("ParentCanceled", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := Monotonic(NewMock()).WithTimeout(parent, time.Second)
+		defer cancel()
+
+		cancelParent()
+		awaitDone(t, ctx)
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
+
+	t.Run("Value", func(t *testing.T) {
+		type contextKey string
+		key := contextKey("foo")
+
+		ctx, cancel := Monotonic(NewMock()).WithTimeout(
+			context.WithValue(context.Background(), key, "bar"), time.Second)
+		defer cancel()
+
+		assert.Equal(t, "bar", ctx.Value(key), "value must be preserved")
+	})
+}
+
 func TestMock_Sleep(t *testing.T) {
 	clock := NewMock()
 
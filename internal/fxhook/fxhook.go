@@ -0,0 +1,40 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fxhook lets other packages of Fx wrap the functions of an
+// fx.Hook without changing how Fx reports them.
+package fxhook
+
+import "context"
+
+// Func is the type of the functions of an fx.Hook.
+type Func = func(context.Context) error
+
+// Appender is implemented by the fx.Lifecycle of applications.
+type Appender interface {
+	// AppendWrapped appends a copy of hook, which must be an fx.Hook,
+	// with each of its functions fn replaced by wrap(kind, name, fn),
+	// where kind is the kind of hook, like "OnStart",
+	// and name is the name Fx reports for fn.
+	// The copy is reported with the same names as hook,
+	// and as appended by the function skip frames above
+	// the caller of AppendWrapped.
+	AppendWrapped(hook any, skip int, wrap func(kind, name string, fn Func) Func)
+}
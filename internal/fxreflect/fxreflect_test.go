@@ -35,6 +35,12 @@ func TestCaller(t *testing.T) {
 
 func someFunc() {}
 
+type cache[T any] struct{}
+
+// newCache can't be told apart from a newCache[T any]() *cache[T]
+// instantiated with int.
+func newCache[T any]() *cache[int] { return nil }
+
 func TestFuncName(t *testing.T) {
 	t.Parallel()
 
@@ -48,6 +54,11 @@ func TestFuncName(t *testing.T) {
 			give: someFunc,
 			want: "go.uber.org/fx/internal/fxreflect.someFunc()",
 		},
+		{
+			desc: "generic function",
+			give: newCache[string],
+			want: "go.uber.org/fx/internal/fxreflect.newCache[...]()",
+		},
 		{
 			desc: "not a function",
 			give: 42,
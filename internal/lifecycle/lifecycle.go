@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -114,13 +115,27 @@ func Wrap[T Callable](x T) (ContextErrorFunc, string) {
 
 // A Hook is a pair of start and stop callbacks, either of which can be nil,
 // plus a string identifying the supplier of the hook.
+// OnPause and OnResume are optional callbacks run by Pause and Resume.
 type Hook struct {
-	OnStart     func(context.Context) error
-	OnStop      func(context.Context) error
-	OnStartName string
-	OnStopName  string
+	OnStart      func(context.Context) error
+	OnStop       func(context.Context) error
+	OnPause      func(context.Context) error
+	OnResume     func(context.Context) error
+	OnStartName  string
+	OnStopName   string
+	OnPauseName  string
+	OnResumeName string
+
+	// Module is the module that appended the hook, if any.
+	// Hooks appended while another hook runs default to its module.
+	Module *Module
+
+	// CallerFrame is the function that appended the hook, if known.
+	// It defaults to the caller of Append.
+	CallerFrame fxreflect.Frame
 
 	callerFrame fxreflect.Frame
+	id          uint64 // identifies the hook for AppendRemovable
 }
 
 type appState int
@@ -131,6 +146,9 @@ const (
 	incompleteStart
 	started
 	stopping
+	pausing
+	paused
+	resuming
 )
 
 func (as appState) String() string {
@@ -145,6 +163,12 @@ func (as appState) String() string {
 		return "started"
 	case stopping:
 		return "stopping"
+	case pausing:
+		return "pausing"
+	case paused:
+		return "paused"
+	case resuming:
+		return "resuming"
 	default:
 		return "invalidState"
 	}
@@ -156,29 +180,175 @@ type Lifecycle struct {
 	logger       fxevent.Logger
 	state        appState
 	hooks        []Hook
+	lastID       uint64 // ID of the last hook appended
 	numStarted   int
+	startRuns    int // OnStart hooks run by the last Start
+	stopRuns     int // OnStop hooks run by the last Stop
 	startRecords HookRecords
 	stopRecords  HookRecords
 	runningHook  Hook
+	running      string // name of the hook function currently executing
+	stopPolicy   StopPolicy
+	startCalled  bool          // whether Start was ever called
+	fairStart    bool          // whether OnStart hooks get a fair share of the timeout
+	slowAfter    time.Duration // when to report OnStart hooks as slow, if set
+	modules      []*Module
+	startModules *moduleProgress // modules left to start during Start
 	mu           sync.Mutex
 }
 
+// StopPolicy controls how Stop handles errors returned by OnStop hooks.
+type StopPolicy int
+
+const (
+	// StopAggregate runs every OnStop hook and returns all their errors.
+	StopAggregate StopPolicy = iota
+
+	// StopFailFast returns the first OnStop error without running the
+	// remaining hooks. It is ignored when rolling back a failed or
+	// incomplete Start so that every started hook is still stopped.
+	StopFailFast
+
+	// StopLogOnly runs every OnStop hook and only logs their errors.
+	StopLogOnly
+)
+
 // New constructs a new Lifecycle.
 func New(logger fxevent.Logger, clock fxclock.Clock) *Lifecycle {
 	return &Lifecycle{logger: logger, clock: clock}
 }
 
+// SetStopPolicy changes how Stop handles errors from OnStop hooks.
+func (l *Lifecycle) SetStopPolicy(p StopPolicy) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stopPolicy = p
+}
+
+// SetFairStartBudget changes whether each OnStart hook run by Start
+// is bounded by an equal share of the time left before the deadline
+// of the context passed to Start, among the OnStart hooks left to run.
+func (l *Lifecycle) SetFairStartBudget(fair bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fairStart = fair
+}
+
+// SetSlowThreshold makes Start emit an OnStartSlow event every d
+// while an OnStart hook is still running. A zero d disables the events.
+func (l *Lifecycle) SetSlowThreshold(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.slowAfter = d
+}
+
+// AddModule registers a module whose hooks may be appended, so that
+// ModuleStarted and ModuleStopped events are emitted for it.
+// Modules without hooks are reported in the order they were added,
+// so modules should be added after the modules they contain.
+func (l *Lifecycle) AddModule(m *Module) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.modules = append(l.modules, m)
+}
+
 // Append adds a Hook to the lifecycle.
 func (l *Lifecycle) Append(hook Hook) {
+	l.append(hook)
+}
+
+// AppendRemovable adds a Hook to the lifecycle,
+// and returns a function that removes it.
+// The function reports whether the hook was removed:
+// hooks can only be removed before the lifecycle is first started.
+func (l *Lifecycle) AppendRemovable(hook Hook) (remove func() bool) {
+	id := l.append(hook)
+	return func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if l.startCalled {
+			return false
+		}
+		for i, h := range l.hooks {
+			if h.id == id {
+				l.hooks = append(l.hooks[:i:i], l.hooks[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (l *Lifecycle) append(hook Hook) (id uint64) {
 	// Save the caller's stack frame to report file/line number.
-	if f := fxreflect.CallerStack(2, 0); len(f) > 0 {
+	if hook.CallerFrame != (fxreflect.Frame{}) {
+		hook.callerFrame = hook.CallerFrame
+	} else if f := fxreflect.CallerStack(3, 0); len(f) > 0 {
 		hook.callerFrame = f[0]
 	}
+
+	l.mu.Lock()
+	l.lastID++
+	hook.id = l.lastID
+	if hook.Module == nil && l.running != "" {
+		hook.Module = l.runningHook.Module
+	}
 	l.hooks = append(l.hooks, hook)
+	dynamic := l.state == starting
+	if dynamic && hook.OnStart != nil {
+		l.startModules.add(hook)
+	}
+	l.mu.Unlock()
+
+	if dynamic {
+		l.logger.LogEvent(&fxevent.DynamicHookAppended{
+			CallerName:  hook.callerFrame.Function,
+			OnStartName: funcName(hook.OnStartName, hook.OnStart),
+			OnStopName:  funcName(hook.OnStopName, hook.OnStop),
+		})
+	}
+	return hook.id
+}
+
+// moduleEvents builds the events reporting that the given modules
+// of p started, or stopped. It must be called with l.mu held.
+func (l *Lifecycle) moduleEvents(p *moduleProgress, done []*Module, stopped bool) []fxevent.Event {
+	events := make([]fxevent.Event, len(done))
+	for i, m := range done {
+		if stopped {
+			events[i] = &fxevent.ModuleStopped{ModuleName: m.Name, Runtime: p.runtime(m)}
+		} else {
+			events[i] = &fxevent.ModuleStarted{ModuleName: m.Name, Runtime: p.runtime(m)}
+		}
+	}
+	return events
+}
+
+func (l *Lifecycle) logEvents(events []fxevent.Event) {
+	for _, e := range events {
+		l.logger.LogEvent(e)
+	}
+}
+
+// funcName returns name, or the name of fn if name is empty.
+// It returns an empty string if fn is nil.
+func funcName(name string, fn func(context.Context) error) string {
+	if fn == nil {
+		return ""
+	}
+	if len(name) == 0 {
+		return fxreflect.FuncName(fn)
+	}
+	return name
 }
 
 // Start runs all OnStart hooks, returning immediately if it encounters an
 // error.
+//
+// Hooks appended while Start is running, e.g. from an OnStart hook,
+// run after the hooks that were already appended,
+// and their OnStop hooks run on Stop like any other.
 func (l *Lifecycle) Start(ctx context.Context) error {
 	if ctx == nil {
 		return errors.New("called OnStart with nil context")
@@ -190,10 +360,21 @@ func (l *Lifecycle) Start(ctx context.Context) error {
 		return fmt.Errorf("attempted to start lifecycle when in state: %v", l.state)
 	}
 	l.numStarted = 0
+	l.startRuns = 0
 	l.state = starting
+	l.startCalled = true
 
 	l.startRecords = make(HookRecords, 0, len(l.hooks))
+	var startHooks []Hook
+	for _, h := range l.hooks {
+		if h.OnStart != nil {
+			startHooks = append(startHooks, h)
+		}
+	}
+	l.startModules = newModuleProgress(l.modules, startHooks)
+	events := l.moduleEvents(l.startModules, l.startModules.idle(), false)
 	l.mu.Unlock()
+	l.logEvents(events)
 
 	returnState := incompleteStart
 	defer func() {
@@ -202,7 +383,17 @@ func (l *Lifecycle) Start(ctx context.Context) error {
 		l.mu.Unlock()
 	}()
 
-	for _, hook := range l.hooks {
+	for i := 0; ; i++ {
+		// Check the length on each iteration
+		// to pick up hooks appended by OnStart hooks.
+		l.mu.Lock()
+		if i >= len(l.hooks) {
+			l.mu.Unlock()
+			break
+		}
+		hook := l.hooks[i]
+		l.mu.Unlock()
+
 		// if ctx has cancelled, bail out of the loop.
 		if err := ctx.Err(); err != nil {
 			return err
@@ -211,20 +402,28 @@ func (l *Lifecycle) Start(ctx context.Context) error {
 		if hook.OnStart != nil {
 			l.mu.Lock()
 			l.runningHook = hook
+			share := l.startShare(ctx, i)
 			l.mu.Unlock()
 
-			runtime, err := l.runStartHook(ctx, hook)
-			if err != nil {
-				return err
-			}
-
+			runtime, err := l.runStartHook(ctx, hook, share)
 			l.mu.Lock()
+			l.startRuns++
 			l.startRecords = append(l.startRecords, HookRecord{
 				CallerFrame: hook.callerFrame,
 				Func:        hook.OnStart,
+				Name:        funcName(hook.OnStartName, hook.OnStart),
 				Runtime:     runtime,
+				Err:         err,
 			})
 			l.mu.Unlock()
+			if err != nil {
+				return err
+			}
+
+			l.mu.Lock()
+			events := l.moduleEvents(l.startModules, l.startModules.ran(hook, runtime), false)
+			l.mu.Unlock()
+			l.logEvents(events)
 		}
 		l.numStarted++
 	}
@@ -233,11 +432,41 @@ func (l *Lifecycle) Start(ctx context.Context) error {
 	return nil
 }
 
-func (l *Lifecycle) runStartHook(ctx context.Context, hook Hook) (runtime time.Duration, err error) {
+// startShare returns the share of the time left before the deadline of ctx
+// that the OnStart hook at index i gets, if fair start budgets are enabled.
+// It returns zero if hooks aren't bounded by a share.
+// It must be called with l.mu held.
+func (l *Lifecycle) startShare(ctx context.Context, i int) time.Duration {
+	if !l.fairStart {
+		return 0
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0
+	}
+
+	var pending int64
+	for _, h := range l.hooks[i:] {
+		if h.OnStart != nil {
+			pending++
+		}
+	}
+	remaining := deadline.Sub(l.clock.Now())
+	if remaining <= 0 {
+		return 0
+	}
+	return remaining / time.Duration(pending)
+}
+
+// runStartHook runs the OnStart hook of hook.
+// If share is non-zero, the hook fails if it runs for longer than that.
+func (l *Lifecycle) runStartHook(ctx context.Context, hook Hook, share time.Duration) (runtime time.Duration, err error) {
 	funcName := hook.OnStartName
 	if len(funcName) == 0 {
 		funcName = fxreflect.FuncName(hook.OnStart)
 	}
+	l.setRunning(funcName)
+	defer l.setRunning("")
 
 	l.logger.LogEvent(&fxevent.OnStartExecuting{
 		CallerName:   hook.callerFrame.Function,
@@ -252,11 +481,88 @@ func (l *Lifecycle) runStartHook(ctx context.Context, hook Hook) (runtime time.D
 		})
 	}()
 
+	if share > 0 {
+		parent := ctx
+		var cancel context.CancelFunc
+		ctx, cancel = l.clock.WithTimeout(ctx, share)
+		defer func() {
+			if shareExpiredFirst(parent, ctx) {
+				if err == nil {
+					err = ctx.Err()
+				}
+				err = fmt.Errorf("OnStart hook %v exceeded its fair share of the start timeout (%v): %w",
+					funcName, share, err)
+			}
+			cancel()
+		}()
+	}
+
 	begin := l.clock.Now()
+	l.mu.Lock()
+	slowAfter := l.slowAfter
+	l.mu.Unlock()
+	if slowAfter > 0 {
+		stop := l.watchSlowHook(funcName, hook.callerFrame.Function, begin, slowAfter)
+		defer stop()
+	}
+
 	err = hook.OnStart(ctx)
 	return l.clock.Since(begin), err
 }
 
+// shareExpiredFirst reports whether ctx, limited to the share of the start
+// timeout of a hook, expired because of its own deadline rather than because
+// parent did.
+func shareExpiredFirst(parent, ctx context.Context) bool {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+	if !errors.Is(parent.Err(), context.DeadlineExceeded) {
+		return true
+	}
+
+	// Both expired: the share is only to blame
+	// if it ended before the start timeout.
+	shareDeadline, _ := ctx.Deadline()
+	deadline, ok := parent.Deadline()
+	return ok && shareDeadline.Before(deadline)
+}
+
+// watchSlowHook emits an OnStartSlow event for a hook that began running at
+// begin every threshold, until the returned function is called.
+// That function waits for the watchdog to exit,
+// so that no event is emitted once it returns.
+func (l *Lifecycle) watchSlowHook(funcName, callerName string, begin time.Time, threshold time.Duration) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		for {
+			timer, cancel := l.clock.WithTimeout(context.Background(), threshold)
+			select {
+			case <-done:
+			case <-timer.Done():
+			}
+			cancel()
+
+			select {
+			case <-done:
+				return
+			default:
+			}
+			l.logger.LogEvent(&fxevent.OnStartSlow{
+				FunctionName: funcName,
+				CallerName:   callerName,
+				Elapsed:      l.clock.Since(begin),
+			})
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+	}
+}
+
 // Stop runs any OnStop hooks whose OnStart counterpart succeeded. OnStop
 // hooks run in reverse order.
 func (l *Lifecycle) Stop(ctx context.Context) error {
@@ -265,8 +571,11 @@ func (l *Lifecycle) Stop(ctx context.Context) error {
 	}
 
 	l.mu.Lock()
-	if l.state != started && l.state != incompleteStart && l.state != starting {
-		defer l.mu.Unlock()
+	prevState := l.state
+	switch prevState {
+	case started, incompleteStart, starting, paused:
+	default:
+		l.mu.Unlock()
 		return nil
 	}
 	l.state = stopping
@@ -280,10 +589,26 @@ func (l *Lifecycle) Stop(ctx context.Context) error {
 
 	l.mu.Lock()
 	l.stopRecords = make(HookRecords, 0, l.numStarted)
+	l.stopRuns = 0
 	// Take a snapshot of hook state to avoid races.
 	allHooks := l.hooks[:]
 	numStarted := l.numStarted
+	policy := l.stopPolicy
+	if policy == StopFailFast && (prevState == incompleteStart || prevState == starting) {
+		// Rolling back a start that did not complete: stopping early
+		// would leak the resources of the hooks that already started.
+		policy = StopAggregate
+	}
+	var stopHooks []Hook
+	for _, h := range allHooks[:numStarted] {
+		if h.OnStop != nil {
+			stopHooks = append(stopHooks, h)
+		}
+	}
+	stopModules := newModuleProgress(l.modules, stopHooks)
+	events := l.moduleEvents(stopModules, stopModules.idle(), true)
 	l.mu.Unlock()
+	l.logEvents(events)
 
 	// Run backward from last successful OnStart.
 	var errs []error
@@ -301,20 +626,33 @@ func (l *Lifecycle) Stop(ctx context.Context) error {
 		l.mu.Unlock()
 
 		runtime, err := l.runStopHook(ctx, hook)
-		if err != nil {
-			// For best-effort cleanup, keep going after errors.
-			errs = append(errs, err)
-		}
 
 		l.mu.Lock()
 		l.stopRecords = append(l.stopRecords, HookRecord{
 			CallerFrame: hook.callerFrame,
 			Func:        hook.OnStop,
+			Name:        funcName(hook.OnStopName, hook.OnStop),
 			Runtime:     runtime,
+			Err:         err,
 		})
+		l.stopRuns++
+		events := l.moduleEvents(stopModules, stopModules.ran(hook, runtime), true)
 		l.mu.Unlock()
+		l.logEvents(events)
+
+		if err != nil {
+			if policy == StopFailFast {
+				return err
+			}
+			// For best-effort cleanup, keep going after errors.
+			// The error has already been logged with OnStopExecuted.
+			errs = append(errs, err)
+		}
 	}
 
+	if policy == StopLogOnly {
+		return nil
+	}
 	return multierr.Combine(errs...)
 }
 
@@ -323,6 +661,8 @@ func (l *Lifecycle) runStopHook(ctx context.Context, hook Hook) (runtime time.Du
 	if len(funcName) == 0 {
 		funcName = fxreflect.FuncName(hook.OnStop)
 	}
+	l.setRunning(funcName)
+	defer l.setRunning("")
 
 	l.logger.LogEvent(&fxevent.OnStopExecuting{
 		CallerName:   hook.callerFrame.Function,
@@ -342,6 +682,171 @@ func (l *Lifecycle) runStopHook(ctx context.Context, hook Hook) (runtime time.Du
 	return l.clock.Since(begin), err
 }
 
+// Pause runs the OnPause hooks of a started lifecycle in reverse order.
+// If an OnPause hook fails, the hooks that were already paused are resumed,
+// and the lifecycle stays started.
+func (l *Lifecycle) Pause(ctx context.Context) error {
+	if ctx == nil {
+		return errors.New("called OnPause with nil context")
+	}
+
+	l.mu.Lock()
+	if l.state != started {
+		defer l.mu.Unlock()
+		return fmt.Errorf("attempted to pause lifecycle when in state: %v", l.state)
+	}
+	l.state = pausing
+	hooks := l.hooks[:l.numStarted]
+	l.mu.Unlock()
+
+	returnState := started
+	defer func() {
+		l.mu.Lock()
+		l.state = returnState
+		l.mu.Unlock()
+	}()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := l.runPauseHook(ctx, hooks[i]); err != nil {
+			// Resume the hooks paused so far, in the order they started.
+			return multierr.Append(err, l.resumeHooks(ctx, hooks[i+1:]))
+		}
+	}
+
+	returnState = paused
+	return nil
+}
+
+// Resume runs the OnResume hooks of a paused lifecycle in order.
+// If an OnResume hook fails, the hooks that were already resumed are paused
+// again, and the lifecycle stays paused.
+func (l *Lifecycle) Resume(ctx context.Context) error {
+	if ctx == nil {
+		return errors.New("called OnResume with nil context")
+	}
+
+	l.mu.Lock()
+	if l.state != paused {
+		defer l.mu.Unlock()
+		return fmt.Errorf("attempted to resume lifecycle when in state: %v", l.state)
+	}
+	l.state = resuming
+	hooks := l.hooks[:l.numStarted]
+	l.mu.Unlock()
+
+	returnState := paused
+	defer func() {
+		l.mu.Lock()
+		l.state = returnState
+		l.mu.Unlock()
+	}()
+
+	for i, hook := range hooks {
+		if err := l.runResumeHook(ctx, hook); err != nil {
+			// Pause the hooks resumed so far, in reverse order.
+			errs := []error{err}
+			for j := i - 1; j >= 0; j-- {
+				errs = append(errs, l.runPauseHook(ctx, hooks[j]))
+			}
+			return multierr.Combine(errs...)
+		}
+	}
+
+	returnState = started
+	return nil
+}
+
+// resumeHooks runs the OnResume hooks of hooks in order,
+// returning all their errors.
+func (l *Lifecycle) resumeHooks(ctx context.Context, hooks []Hook) error {
+	var errs []error
+	for _, hook := range hooks {
+		errs = append(errs, l.runResumeHook(ctx, hook))
+	}
+	return multierr.Combine(errs...)
+}
+
+func (l *Lifecycle) runPauseHook(ctx context.Context, hook Hook) (err error) {
+	if hook.OnPause == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	name := funcName(hook.OnPauseName, hook.OnPause)
+
+	l.logger.LogEvent(&fxevent.OnPauseExecuting{
+		CallerName:   hook.callerFrame.Function,
+		FunctionName: name,
+	})
+	begin := l.clock.Now()
+	defer func() {
+		l.logger.LogEvent(&fxevent.OnPauseExecuted{
+			CallerName:   hook.callerFrame.Function,
+			FunctionName: name,
+			Runtime:      l.clock.Since(begin),
+			Err:          err,
+		})
+	}()
+
+	return l.runHook(ctx, hook, hook.OnPause, name)
+}
+
+func (l *Lifecycle) runResumeHook(ctx context.Context, hook Hook) (err error) {
+	if hook.OnResume == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	name := funcName(hook.OnResumeName, hook.OnResume)
+
+	l.logger.LogEvent(&fxevent.OnResumeExecuting{
+		CallerName:   hook.callerFrame.Function,
+		FunctionName: name,
+	})
+	begin := l.clock.Now()
+	defer func() {
+		l.logger.LogEvent(&fxevent.OnResumeExecuted{
+			CallerName:   hook.callerFrame.Function,
+			FunctionName: name,
+			Runtime:      l.clock.Since(begin),
+			Err:          err,
+		})
+	}()
+
+	return l.runHook(ctx, hook, hook.OnResume, name)
+}
+
+// runHook runs fn, a callback of hook named funcName,
+// tracking it as the running hook.
+func (l *Lifecycle) runHook(ctx context.Context, hook Hook, fn func(context.Context) error, funcName string) error {
+	l.mu.Lock()
+	l.runningHook = hook
+	l.mu.Unlock()
+	l.setRunning(funcName)
+	defer l.setRunning("")
+
+	return fn(ctx)
+}
+
+// HookRuns reports the number of OnStart hooks run by the last Start,
+// and the number of OnStop hooks run by the last Stop,
+// including hooks that failed.
+func (l *Lifecycle) HookRuns() (onStart, onStop int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.startRuns, l.stopRuns
+}
+
+// HookRecords returns records of the OnStart hooks run by the last Start,
+// and of the OnStop hooks run by the last Stop, in the order they ran.
+func (l *Lifecycle) HookRecords() (onStart, onStop HookRecords) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append(HookRecords(nil), l.startRecords...), append(HookRecords(nil), l.stopRecords...)
+}
+
 // RunningHookCaller returns the name of the hook that was running when a Start/Stop
 // hook timed out.
 func (l *Lifecycle) RunningHookCaller() string {
@@ -350,11 +855,63 @@ func (l *Lifecycle) RunningHookCaller() string {
 	return l.runningHook.callerFrame.Function
 }
 
+func (l *Lifecycle) setRunning(funcName string) {
+	l.mu.Lock()
+	l.running = funcName
+	l.mu.Unlock()
+}
+
+// RunningHook reports the name of the hook function that is currently
+// executing, and the name of the function that appended it.
+// ok is false if no hook is running.
+func (l *Lifecycle) RunningHook() (funcName, callerName string, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.running) == 0 {
+		return "", "", false
+	}
+	return l.running, l.runningHook.callerFrame.Function, true
+}
+
+// GoroutineStacks returns the stacks of all goroutines in the process,
+// as well as the subset of those stacks that belong to goroutines
+// currently executing a lifecycle hook.
+func GoroutineStacks() (all string, hooks []string) {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true /* all */)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	all = string(buf)
+	for _, g := range strings.Split(all, "\n\n") {
+		if strings.Contains(g, _runStartHookFunc) ||
+			strings.Contains(g, _runStopHookFunc) ||
+			strings.Contains(g, _runHookFunc) {
+			hooks = append(hooks, g)
+		}
+	}
+	return all, hooks
+}
+
+// Function names that identify a goroutine running a hook in a stack dump.
+const (
+	_runStartHookFunc = "go.uber.org/fx/internal/lifecycle.(*Lifecycle).runStartHook"
+	_runStopHookFunc  = "go.uber.org/fx/internal/lifecycle.(*Lifecycle).runStopHook"
+	_runHookFunc      = "go.uber.org/fx/internal/lifecycle.(*Lifecycle).runHook"
+)
+
 // HookRecord keeps track of each Hook's execution time, the caller that appended the Hook, and function that ran as the Hook.
 type HookRecord struct {
 	CallerFrame fxreflect.Frame             // stack frame of the caller
 	Func        func(context.Context) error // function that ran as sanitized name
+	Name        string                      // name of the function that ran
 	Runtime     time.Duration               // how long the hook ran
+	Err         error                       // error returned by the hook, if any
 }
 
 // HookRecords is a Stringer wrapper of HookRecord slice.
@@ -145,6 +145,180 @@ func TestLifecycleStart(t *testing.T) {
 		require.NoError(t, l.Stop(ctx))
 	})
 
+	t.Run("FairStartBudget", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		l := New(testLogger(t), clock)
+		l.SetFairStartBudget(true)
+
+		var shares []time.Duration
+		hook := func(name string, elapse time.Duration) Hook {
+			return Hook{
+				OnStartName: name,
+				OnStart: func(ctx context.Context) error {
+					deadline, ok := ctx.Deadline()
+					require.True(t, ok, "hook context must have a deadline")
+					shares = append(shares, deadline.Sub(clock.Now()))
+					clock.Add(elapse)
+					return nil
+				},
+			}
+		}
+		l.Append(hook("first", 4*time.Second))
+		l.Append(Hook{OnStop: func(context.Context) error { return nil }})
+		l.Append(hook("second", 20*time.Second))
+		l.Append(hook("third", 0))
+
+		ctx, cancel := clock.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		err := l.Start(ctx)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, "OnStart hook second exceeded its fair share of the start timeout (13s): "+
+			"context deadline exceeded", err.Error())
+		assert.Equal(t, []time.Duration{10 * time.Second, 13 * time.Second}, shares,
+			"hooks without OnStart must not get a share, and unused time must be shared")
+	})
+
+	t.Run("FairStartBudgetStartTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		l := New(testLogger(t), clock)
+		l.SetFairStartBudget(true)
+		l.Append(Hook{
+			OnStartName: "last",
+			OnStart: func(ctx context.Context) error {
+				// The only hook left gets all the remaining time,
+				// so the start timeout expires along with its share.
+				clock.Add(time.Minute)
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		})
+
+		ctx, cancel := clock.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		err := l.Start(ctx)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NotContains(t, err.Error(), "fair share",
+			"hooks must not be blamed for the start timeout")
+	})
+
+	t.Run("FairStartBudgetWithoutDeadline", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		l.SetFairStartBudget(true)
+		l.Append(Hook{
+			OnStart: func(ctx context.Context) error {
+				_, ok := ctx.Deadline()
+				assert.False(t, ok, "hook must not get a share without a deadline")
+				return nil
+			},
+		})
+		require.NoError(t, l.Start(context.Background()))
+	})
+
+	t.Run("SlowThreshold", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		clock := fxclock.NewMock()
+		l := New(&spy, clock)
+		l.SetSlowThreshold(10 * time.Second)
+		l.Append(Hook{
+			OnStartName: "slow",
+			OnStart: func(context.Context) error {
+				for i := 0; i < 2; i++ {
+					clock.AwaitScheduled(1)
+					clock.Add(10 * time.Second)
+				}
+				// Wait for the watchdog to report the last interval.
+				clock.AwaitScheduled(1)
+				return nil
+			},
+		})
+		l.Append(Hook{
+			OnStartName: "fast",
+			OnStart:     func(context.Context) error { return nil },
+		})
+		require.NoError(t, l.Start(context.Background()))
+
+		events := spy.Events().SelectByTypeName("OnStartSlow")
+		require.Len(t, events, 2)
+		for i, e := range events {
+			slow := e.(*fxevent.OnStartSlow)
+			assert.Equal(t, "slow", slow.FunctionName)
+			assert.Equal(t, time.Duration(i+1)*10*time.Second, slow.Elapsed)
+		}
+		assert.Equal(t, []string{
+			"OnStartExecuting", "OnStartSlow", "OnStartSlow", "OnStartExecuted",
+			"OnStartExecuting", "OnStartExecuted",
+		}, spy.EventTypes(), "slow events must stop once the hook returns")
+	})
+
+	t.Run("RunsHooksAppendedDuringStart", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			spy   fxlog.Spy
+			calls []string
+		)
+		l := New(&spy, fxclock.System)
+		l.Append(Hook{
+			OnStart: func(context.Context) error {
+				calls = append(calls, "start 1")
+				l.Append(Hook{
+					OnStart: func(context.Context) error {
+						calls = append(calls, "start 3")
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						calls = append(calls, "stop 3")
+						return nil
+					},
+				})
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				calls = append(calls, "stop 1")
+				return nil
+			},
+		})
+		l.Append(Hook{
+			OnStart: func(context.Context) error {
+				calls = append(calls, "start 2")
+				return nil
+			},
+		})
+
+		require.NoError(t, l.Start(context.Background()))
+		require.NoError(t, l.Stop(context.Background()))
+		assert.Equal(t, []string{"start 1", "start 2", "start 3", "stop 3", "stop 1"}, calls)
+
+		events := spy.Events().SelectByTypeName("DynamicHookAppended")
+		require.Len(t, events, 1)
+		e := events[0].(*fxevent.DynamicHookAppended)
+		assert.Contains(t, e.OnStartName, "TestLifecycleStart")
+		assert.NotEmpty(t, e.OnStopName)
+		assert.NotEmpty(t, e.CallerName)
+	})
+
+	t.Run("NoEventOutsideStart", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		l := New(&spy, fxclock.System)
+		l.Append(Hook{})
+		require.NoError(t, l.Start(context.Background()))
+		l.Append(Hook{})
+		require.NoError(t, l.Stop(context.Background()))
+		assert.Empty(t, spy.Events().SelectByTypeName("DynamicHookAppended"))
+	})
+
 	t.Run("StartWhileStartedErrors", func(t *testing.T) {
 		t.Parallel()
 
@@ -255,6 +429,80 @@ func TestLifecycleStop(t *testing.T) {
 		assert.NoError(t, l.Start(context.Background()))
 		assert.Equal(t, multierr.Combine(err, err2), l.Stop(context.Background()))
 	})
+	t.Run("StopFailFast", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		l.SetStopPolicy(StopFailFast)
+
+		err := errors.New("some stop error")
+		l.Append(Hook{
+			OnStop: func(context.Context) error {
+				assert.Fail(t, "OnStop should not be called after an earlier failure")
+				return nil
+			},
+		})
+		l.Append(Hook{
+			OnStop: func(context.Context) error {
+				return err
+			},
+		})
+
+		assert.NoError(t, l.Start(context.Background()))
+		assert.Equal(t, err, l.Stop(context.Background()))
+		assert.Len(t, l.stopRecords, 1)
+	})
+	t.Run("StopFailFastDuringRollback", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		l.SetStopPolicy(StopFailFast)
+
+		startErr := errors.New("some start error")
+		stopErr := errors.New("some stop error")
+		var stopped bool
+		l.Append(Hook{
+			OnStop: func(context.Context) error {
+				stopped = true
+				return nil
+			},
+		})
+		l.Append(Hook{
+			OnStop: func(context.Context) error {
+				return stopErr
+			},
+		})
+		l.Append(Hook{
+			OnStart: func(context.Context) error {
+				return startErr
+			},
+		})
+
+		assert.Equal(t, startErr, l.Start(context.Background()))
+		assert.Equal(t, stopErr, l.Stop(context.Background()))
+		assert.True(t, stopped, "all started hooks must be rolled back")
+		assert.Len(t, l.stopRecords, 2)
+	})
+	t.Run("StopLogOnly", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		l.SetStopPolicy(StopLogOnly)
+
+		var count int
+		for i := 0; i < 2; i++ {
+			l.Append(Hook{
+				OnStop: func(context.Context) error {
+					count++
+					return errors.New("some stop error")
+				},
+			})
+		}
+
+		assert.NoError(t, l.Start(context.Background()))
+		assert.NoError(t, l.Stop(context.Background()))
+		assert.Equal(t, 2, count)
+	})
 	t.Run("AllowEmptyHooks", func(t *testing.T) {
 		t.Parallel()
 
@@ -330,6 +578,334 @@ func TestLifecycleStop(t *testing.T) {
 	})
 }
 
+func TestLifecyclePauseResume(t *testing.T) {
+	t.Parallel()
+
+	// record returns a hook that appends its pause and resume calls to calls.
+	record := func(calls *[]string, name string) Hook {
+		return Hook{
+			OnPause: func(context.Context) error {
+				*calls = append(*calls, "pause "+name)
+				return nil
+			},
+			OnResume: func(context.Context) error {
+				*calls = append(*calls, "resume "+name)
+				return nil
+			},
+		}
+	}
+
+	t.Run("RunsHooks", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		l := New(testLogger(t), fxclock.System)
+		l.Append(record(&calls, "a"))
+		l.Append(Hook{OnStart: func(context.Context) error { return nil }})
+		l.Append(record(&calls, "b"))
+
+		ctx := context.Background()
+		require.NoError(t, l.Start(ctx))
+		require.NoError(t, l.Pause(ctx))
+		assert.Equal(t, "paused", l.state.String())
+		require.NoError(t, l.Resume(ctx))
+		assert.Equal(t, "started", l.state.String())
+		require.NoError(t, l.Stop(ctx))
+
+		assert.Equal(t, []string{"pause b", "pause a", "resume a", "resume b"}, calls)
+	})
+
+	t.Run("PauseErrResumesPausedHooks", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		l := New(testLogger(t), fxclock.System)
+		l.Append(record(&calls, "a"))
+		l.Append(Hook{OnPause: func(context.Context) error {
+			return errors.New("great sadness")
+		}})
+		l.Append(record(&calls, "b"))
+		l.Append(record(&calls, "c"))
+
+		ctx := context.Background()
+		require.NoError(t, l.Start(ctx))
+		err := l.Pause(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.Equal(t, []string{"pause c", "pause b", "resume b", "resume c"}, calls)
+
+		// The lifecycle is still started, so it can be paused again.
+		assert.Equal(t, "started", l.state.String())
+	})
+
+	t.Run("ResumeErrPausesResumedHooks", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		l := New(testLogger(t), fxclock.System)
+		l.Append(record(&calls, "a"))
+		l.Append(record(&calls, "b"))
+		l.Append(Hook{OnResume: func(context.Context) error {
+			return errors.New("great sadness")
+		}})
+
+		ctx := context.Background()
+		require.NoError(t, l.Start(ctx))
+		require.NoError(t, l.Pause(ctx))
+		calls = nil
+
+		err := l.Resume(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.Equal(t, []string{"resume a", "resume b", "pause b", "pause a"}, calls)
+		assert.Equal(t, "paused", l.state.String())
+	})
+
+	t.Run("InvalidStates", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		ctx := context.Background()
+
+		err := l.Pause(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "attempted to pause lifecycle when in state: stopped")
+
+		require.NoError(t, l.Start(ctx))
+		err = l.Resume(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "attempted to resume lifecycle when in state: started")
+	})
+
+	t.Run("StopWhilePaused", func(t *testing.T) {
+		t.Parallel()
+
+		var stopped bool
+		l := New(testLogger(t), fxclock.System)
+		l.Append(Hook{
+			OnPause: func(context.Context) error { return nil },
+			OnStop: func(context.Context) error {
+				stopped = true
+				return nil
+			},
+		})
+
+		ctx := context.Background()
+		require.NoError(t, l.Start(ctx))
+		require.NoError(t, l.Pause(ctx))
+		require.NoError(t, l.Stop(ctx))
+		assert.True(t, stopped)
+		assert.Equal(t, "stopped", l.state.String())
+	})
+
+	t.Run("OnlyStartedHooksArePaused", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		l := New(testLogger(t), fxclock.System)
+		l.Append(record(&calls, "a"))
+
+		ctx := context.Background()
+		require.NoError(t, l.Start(ctx))
+		l.Append(record(&calls, "late"))
+		require.NoError(t, l.Pause(ctx))
+		assert.Equal(t, []string{"pause a"}, calls)
+	})
+
+	t.Run("LogsEvents", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			spy   fxlog.Spy
+			calls []string
+		)
+		l := New(&spy, fxclock.System)
+		l.Append(record(&calls, "a"))
+		l.Append(Hook{OnStart: func(context.Context) error { return nil }})
+		l.Append(Hook{OnResume: func(context.Context) error {
+			return errors.New("great sadness")
+		}})
+
+		ctx := context.Background()
+		require.NoError(t, l.Start(ctx))
+		require.NoError(t, l.Pause(ctx))
+		require.Error(t, l.Resume(ctx))
+
+		assert.Equal(t, []string{
+			"OnStartExecuting", "OnStartExecuted",
+			"OnPauseExecuting", "OnPauseExecuted",
+			"OnResumeExecuting", "OnResumeExecuted",
+			"OnResumeExecuting", "OnResumeExecuted",
+			"OnPauseExecuting", "OnPauseExecuted",
+		}, spy.EventTypes())
+
+		failed := spy.Events().SelectByTypeName("OnResumeExecuted")[1].(*fxevent.OnResumeExecuted)
+		assert.ErrorContains(t, failed.Err, "great sadness")
+	})
+}
+
+func TestModuleEvents(t *testing.T) {
+	t.Parallel()
+
+	var spy fxlog.Spy
+	l := New(&spy, fxclock.System)
+
+	parent := &Module{Name: "parent"}
+	child := &Module{Name: "child", Parent: parent}
+	empty := &Module{Name: "empty"}
+	l.AddModule(child)
+	l.AddModule(parent)
+	l.AddModule(empty)
+
+	noop := func(context.Context) error { return nil }
+	l.Append(Hook{OnStart: noop, OnStop: noop, Module: child})
+	l.Append(Hook{OnStart: noop, Module: parent})
+	l.Append(Hook{OnStop: noop})
+
+	modules := func(events fxlog.Events) []string {
+		var names []string
+		for _, e := range events {
+			switch e := e.(type) {
+			case *fxevent.ModuleStarted:
+				names = append(names, e.ModuleName)
+			case *fxevent.ModuleStopped:
+				names = append(names, e.ModuleName)
+			}
+		}
+		return names
+	}
+
+	require.NoError(t, l.Start(context.Background()))
+	assert.Equal(t, []string{
+		"ModuleStarted",
+		"OnStartExecuting", "OnStartExecuted", "ModuleStarted",
+		"OnStartExecuting", "OnStartExecuted", "ModuleStarted",
+	}, spy.EventTypes())
+	assert.Equal(t, []string{"empty", "child", "parent"}, modules(spy.Events()))
+
+	spy.Reset()
+	require.NoError(t, l.Stop(context.Background()))
+	assert.Equal(t, []string{
+		"ModuleStopped",
+		"OnStopExecuting", "OnStopExecuted",
+		"OnStopExecuting", "OnStopExecuted", "ModuleStopped", "ModuleStopped",
+	}, spy.EventTypes())
+	assert.Equal(t, []string{"empty", "child", "parent"}, modules(spy.Events()))
+}
+
+func TestRunningHook(t *testing.T) {
+	t.Parallel()
+
+	l := New(testLogger(t), fxclock.System)
+	_, _, ok := l.RunningHook()
+	assert.False(t, ok, "no hook should be running before Start")
+
+	running := make(chan struct{})
+	release := make(chan struct{})
+	l.Append(Hook{
+		OnStart: func(context.Context) error {
+			close(running)
+			<-release
+			return nil
+		},
+		OnStartName: "blockingHook",
+	})
+
+	errc := make(chan error, 1)
+	go func() { errc <- l.Start(context.Background()) }()
+	<-running
+
+	funcName, callerName, ok := l.RunningHook()
+	require.True(t, ok, "hook should be running")
+	assert.Equal(t, "blockingHook", funcName)
+	assert.NotEmpty(t, callerName)
+
+	all, hooks := GoroutineStacks()
+	assert.Contains(t, all, "TestRunningHook")
+	// Other tests running in parallel may also be executing hooks.
+	require.NotEmpty(t, hooks)
+	for _, h := range hooks {
+		assert.Contains(t, h, "runStartHook")
+	}
+
+	close(release)
+	require.NoError(t, <-errc)
+
+	_, _, ok = l.RunningHook()
+	assert.False(t, ok, "no hook should be running after Start")
+	require.NoError(t, l.Stop(context.Background()))
+}
+
+func TestHookRuns(t *testing.T) {
+	t.Parallel()
+
+	l := New(testLogger(t), fxclock.System)
+	noop := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return errors.New("great sadness") }
+	l.Append(Hook{OnStart: noop, OnStop: noop})
+	l.Append(Hook{OnStop: noop})
+	l.Append(Hook{OnStart: fail, OnStop: noop})
+	l.Append(Hook{OnStart: noop})
+
+	require.Error(t, l.Start(context.Background()))
+	onStart, onStop := l.HookRuns()
+	assert.Equal(t, 2, onStart, "the failed hook must be counted")
+	assert.Equal(t, 0, onStop)
+
+	require.NoError(t, l.Stop(context.Background()))
+	onStart, onStop = l.HookRuns()
+	assert.Equal(t, 2, onStart)
+	assert.Equal(t, 2, onStop, "only hooks that started must be stopped")
+}
+
+func TestHookRecords(t *testing.T) {
+	t.Parallel()
+
+	l := New(testLogger(t), fxclock.System)
+	noop := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return errors.New("great sadness") }
+	l.Append(Hook{OnStart: noop, OnStartName: "start1", OnStop: noop, OnStopName: "stop1"})
+	l.Append(Hook{OnStart: fail, OnStartName: "start2", OnStop: noop, OnStopName: "stop2"})
+
+	require.Error(t, l.Start(context.Background()))
+	require.NoError(t, l.Stop(context.Background()))
+
+	onStart, onStop := l.HookRecords()
+	require.Len(t, onStart, 2)
+	assert.Equal(t, "start1", onStart[0].Name)
+	assert.NoError(t, onStart[0].Err)
+	assert.Equal(t, "start2", onStart[1].Name)
+	assert.EqualError(t, onStart[1].Err, "great sadness")
+
+	require.Len(t, onStop, 1, "only hooks that started must be stopped")
+	assert.Equal(t, "stop1", onStop[0].Name)
+}
+
+func TestAppendRemovable(t *testing.T) {
+	t.Parallel()
+
+	l := New(testLogger(t), fxclock.System)
+	var ran []string
+	hook := func(name string) Hook {
+		return Hook{OnStart: func(context.Context) error {
+			ran = append(ran, name)
+			return nil
+		}}
+	}
+	l.Append(hook("a"))
+	removeB := l.AppendRemovable(hook("b"))
+	removeC := l.AppendRemovable(hook("c"))
+
+	assert.True(t, removeB())
+	assert.False(t, removeB(), "hook must only be removed once")
+	require.NoError(t, l.Start(context.Background()))
+	assert.False(t, removeC(), "hook must not be removed once started")
+	require.NoError(t, l.Stop(context.Background()))
+	assert.Equal(t, []string{"a", "c"}, ran)
+
+	assert.False(t, removeC(), "hook must not be removed once stopped")
+}
+
 func TestHookRecordsFormat(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,110 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package lifecycle
+
+import "time"
+
+// Module identifies the Fx module that appended a hook,
+// so that the lifecycle can report when all hooks of a module ran.
+type Module struct {
+	Name   string
+	Parent *Module // nil for modules of the root module
+}
+
+// moduleRun is the state of a module during a single Start or Stop.
+type moduleRun struct {
+	pending  int           // hooks left to run
+	runtime  time.Duration // spent running hooks so far
+	reported bool
+}
+
+// moduleProgress tracks the hooks of each module left to run
+// during a single Start or Stop.
+// It's guarded by Lifecycle.mu.
+type moduleProgress struct {
+	modules []*Module // in the order they're reported if they have no hooks
+	runs    map[*Module]*moduleRun
+}
+
+// newModuleProgress starts tracking the given hooks of the given modules.
+func newModuleProgress(modules []*Module, hooks []Hook) *moduleProgress {
+	p := &moduleProgress{
+		modules: modules,
+		runs:    make(map[*Module]*moduleRun, len(modules)),
+	}
+	for _, h := range hooks {
+		p.add(h)
+	}
+	return p
+}
+
+func (p *moduleProgress) run(m *Module) *moduleRun {
+	r, ok := p.runs[m]
+	if !ok {
+		r = &moduleRun{}
+		p.runs[m] = r
+	}
+	return r
+}
+
+// add tracks hook, which must run before its module
+// and the modules containing it are reported.
+func (p *moduleProgress) add(hook Hook) {
+	for m := hook.Module; m != nil; m = m.Parent {
+		if r := p.run(m); !r.reported {
+			r.pending++
+		}
+	}
+}
+
+// idle returns the modules that have no hooks to run,
+// marking them reported.
+func (p *moduleProgress) idle() (done []*Module) {
+	for _, m := range p.modules {
+		if r := p.run(m); !r.reported && r.pending == 0 {
+			r.reported = true
+			done = append(done, m)
+		}
+	}
+	return done
+}
+
+// ran records that hook ran in the given time.
+// It returns the modules that have no hooks left to run as a result,
+// innermost first, marking them reported.
+func (p *moduleProgress) ran(hook Hook, runtime time.Duration) (done []*Module) {
+	for m := hook.Module; m != nil; m = m.Parent {
+		r := p.run(m)
+		if r.reported {
+			continue
+		}
+		r.runtime += runtime
+		if r.pending--; r.pending == 0 {
+			r.reported = true
+			done = append(done, m)
+		}
+	}
+	return done
+}
+
+// runtime returns the time spent running the hooks of m.
+func (p *moduleProgress) runtime(m *Module) time.Duration {
+	return p.run(m).runtime
+}
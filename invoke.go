@@ -68,9 +68,30 @@ func Invoke(funcs ...interface{}) Option {
 	}
 }
 
+// TryInvoke registers functions that are invoked like [Invoke],
+// except that their errors don't stop the application:
+// they're reported to the handlers registered with [ErrorHook]
+// and logged as warnings in the [fxevent.Invoked] event,
+// and the application continues with the next function.
+// This suits best-effort registrations, such as optional telemetry
+// or warming up non-critical caches.
+//
+//	fx.TryInvoke(telemetry.Register)
+//
+// Errors resolving the parameters of the functions, such as missing
+// dependencies, are tolerated the same way.
+func TryInvoke(funcs ...interface{}) Option {
+	return invokeOption{
+		Targets: funcs,
+		Stack:   fxreflect.CallerStack(1, 0),
+		Try:     true,
+	}
+}
+
 type invokeOption struct {
 	Targets []interface{}
 	Stack   fxreflect.Stack
+	Try     bool
 }
 
 func (o invokeOption) apply(mod *module) {
@@ -78,6 +99,7 @@ func (o invokeOption) apply(mod *module) {
 		mod.invokes = append(mod.invokes, invoke{
 			Target: target,
 			Stack:  o.Stack,
+			Try:    o.Try,
 		})
 	}
 }
@@ -87,7 +109,79 @@ func (o invokeOption) String() string {
 	for i, f := range o.Targets {
 		items[i] = fxreflect.FuncName(f)
 	}
-	return fmt.Sprintf("fx.Invoke(%s)", strings.Join(items, ", "))
+	name := "fx.Invoke"
+	if o.Try {
+		name = "fx.TryInvoke"
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(items, ", "))
+}
+
+// InvokeWith registers a function that is invoked like [Invoke],
+// with its parameters resolved using additional options
+// that only apply to this function.
+// This is useful for administrative or maintenance functions
+// that need a slightly different configuration
+// without changing it for the rest of the application.
+//
+//	fx.InvokeWith(runMigrations,
+//		fx.Decorate(func(cfg db.Config) db.Config {
+//			cfg.Timeout = time.Hour
+//			return cfg
+//		}),
+//		fx.Supply(migrations.Force(true)),
+//	)
+//
+// The options are applied to an anonymous [Module] holding the function:
+// values passed to [Supply] and constructors passed to [Provide]
+// are private to it, and [Decorate] and [Replace] only affect the values
+// it receives. Constructors provided to the rest of the application
+// keep using the undecorated values,
+// since they may have already run for other functions.
+// To override a dependency of such a constructor,
+// pass the constructor to InvokeWith as well with [Provide]
+// so that it's built again for this function.
+//
+// The function runs in the same order as if it were passed to [Invoke].
+func InvokeWith(function interface{}, opts ...Option) Option {
+	return invokeWithOption{
+		Target:  function,
+		Options: opts,
+		Stack:   fxreflect.CallerStack(1, 0),
+	}
+}
+
+type invokeWithOption struct {
+	Target  interface{}
+	Options []Option
+	Stack   fxreflect.Stack
+}
+
+func (o invokeWithOption) apply(mod *module) {
+	// The options apply to a child module, so that they're scoped to it,
+	// but the function is invoked by mod to run in order.
+	scoped := &module{
+		name:          mod.name,
+		parent:        mod,
+		trace:         mod.trace,
+		app:           mod.app,
+		inheritsHooks: true,
+	}
+	for _, opt := range o.Options {
+		opt.apply(scoped)
+	}
+	for i := range scoped.provides {
+		scoped.provides[i].Private = true
+	}
+	mod.modules = append(mod.modules, scoped)
+	mod.invokes = append(mod.invokes, invoke{
+		Target: o.Target,
+		Stack:  o.Stack,
+		Module: scoped,
+	})
+}
+
+func (o invokeWithOption) String() string {
+	return fmt.Sprintf("fx.InvokeWith(%s, %v)", fxreflect.FuncName(o.Target), o.Options)
 }
 
 func runInvoke(c container, i invoke) error {
@@ -104,8 +198,18 @@ func runInvoke(c container, i invoke) error {
 			return err
 		}
 
+		af, _, err = flattenStructs(c, af)
+		if err != nil {
+			return err
+		}
+
 		return c.Invoke(af)
 	default:
-		return c.Invoke(fn)
+		f, _, err := flattenStructs(c, fn)
+		if err != nil {
+			return err
+		}
+
+		return c.Invoke(f)
 	}
 }
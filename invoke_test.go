@@ -0,0 +1,188 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+func TestInvokeWith(t *testing.T) {
+	t.Parallel()
+
+	type config struct{ Name string }
+	type server struct{ Config config }
+	newServer := func(c config) *server { return &server{Config: c} }
+	rename := func(name string) fx.Option {
+		return fx.Decorate(func(c config) config {
+			c.Name = name
+			return c
+		})
+	}
+
+	t.Run("Decorate", func(t *testing.T) {
+		t.Parallel()
+
+		var got []string
+		record := func(c config) { got = append(got, c.Name) }
+		fxtest.New(t,
+			fx.Supply(config{Name: "global"}),
+			fx.Invoke(record),
+			fx.InvokeWith(record, rename("scoped")),
+			fx.Invoke(record),
+		)
+		assert.Equal(t, []string{"global", "scoped", "global"}, got)
+	})
+
+	t.Run("SupplyIsPrivate", func(t *testing.T) {
+		t.Parallel()
+
+		var got int
+		fxtest.New(t,
+			fx.InvokeWith(func(i int) { got = i }, fx.Supply(42)),
+		)
+		assert.Equal(t, 42, got)
+
+		err := fx.New(
+			fx.NopLogger,
+			fx.InvokeWith(func(int) {}, fx.Supply(42)),
+			fx.Invoke(func(int) {}),
+		).Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: int")
+	})
+
+	t.Run("ProvideRebuildsConstructor", func(t *testing.T) {
+		t.Parallel()
+
+		var global, scoped *server
+		fxtest.New(t,
+			fx.Supply(config{Name: "global"}),
+			fx.Provide(newServer),
+			fx.Invoke(func(s *server) { global = s }),
+			fx.InvokeWith(func(s *server) { scoped = s },
+				fx.Provide(newServer),
+				rename("scoped"),
+			),
+		)
+		assert.Equal(t, "global", global.Config.Name)
+		assert.Equal(t, "scoped", scoped.Config.Name)
+	})
+
+	t.Run("InModule", func(t *testing.T) {
+		t.Parallel()
+
+		var got []string
+		fxtest.New(t,
+			fx.Supply(config{Name: "global"}),
+			fx.Module("admin",
+				fx.Invoke(func(c config) { got = append(got, "admin "+c.Name) }),
+				fx.InvokeWith(func(c config) { got = append(got, "admin "+c.Name) },
+					rename("scoped"),
+				),
+			),
+			fx.Invoke(func(c config) { got = append(got, "root "+c.Name) }),
+		)
+		assert.Equal(t, []string{"admin global", "admin scoped", "root global"}, got)
+	})
+}
+
+func TestTryInvoke(t *testing.T) {
+	t.Parallel()
+
+	type telemetry struct{}
+
+	t.Run("ErrorsDontStopTheApp", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			got     []string
+			handled []error
+		)
+		spy := new(fxlog.Spy)
+		app := fxtest.New(t,
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.ErrorHook(errHandlerFunc(func(err error) { handled = append(handled, err) })),
+			fx.TryInvoke(func() error {
+				got = append(got, "try")
+				return errors.New("great sadness")
+			}),
+			fx.Invoke(func() { got = append(got, "invoke") }),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{"try", "invoke"}, got)
+		require.Len(t, handled, 1)
+		assert.ErrorContains(t, handled[0], "fx.TryInvoke(go.uber.org/fx_test.TestTryInvoke")
+		assert.ErrorContains(t, handled[0], "great sadness")
+
+		invoked := spy.Events().SelectByTypeName("Invoked")
+		require.Len(t, invoked, 2)
+		assert.True(t, invoked[0].(*fxevent.Invoked).Try)
+		assert.EqualError(t, invoked[0].(*fxevent.Invoked).Err, "great sadness")
+		assert.False(t, invoked[1].(*fxevent.Invoked).Try)
+	})
+
+	t.Run("MissingDependencies", func(t *testing.T) {
+		t.Parallel()
+
+		var handled error
+		app := fxtest.New(t,
+			fx.ErrorHook(errHandlerFunc(func(err error) { handled = err })),
+			fx.TryInvoke(func(*telemetry) {}),
+		)
+		app.RequireStart().RequireStop()
+		assert.ErrorContains(t, handled, "missing type: *fx_test.telemetry")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		var called bool
+		app := fxtest.New(t,
+			fx.ErrorHook(errHandlerFunc(func(err error) { t.Errorf("unexpected error: %v", err) })),
+			fx.Supply(&telemetry{}),
+			fx.TryInvoke(func(*telemetry) { called = true }),
+		)
+		app.RequireStart().RequireStop()
+		assert.True(t, called)
+	})
+
+	t.Run("OptionsAreFatal", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, fx.TryInvoke(fx.Supply(&telemetry{})))
+		assert.ErrorContains(t, app.Err(), "fx.Option should be passed to fx.New directly")
+	})
+
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+
+		opt := fx.TryInvoke(func() {})
+		assert.Contains(t, opt.String(), "fx.TryInvoke(go.uber.org/fx_test.TestTryInvoke")
+	})
+}
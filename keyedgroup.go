@@ -0,0 +1,341 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// _keyTag holds the key of a value contributed to a value group,
+// by which it can be looked up when the group is consumed as a map.
+const _keyTag = "key"
+
+var _typeOfString = reflect.TypeOf("")
+
+// keyedEntryType returns the type that values of type t
+// contributed to a value group with a key are provided to dig as.
+// reflect.StructOf returns identical types for identical fields,
+// so contributors and consumers of the same group agree on it.
+func keyedEntryType(t reflect.Type) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "Key", Type: _typeOfString},
+		{Name: "Value", Type: t},
+	})
+}
+
+// keyGroups returns a function equivalent to fn
+// that supports value groups with keys.
+//
+// Fields of fx.Out results tagged with both a group and a key contribute
+// their value to the group under that key:
+//
+//	type Result struct {
+//		fx.Out
+//
+//		Codec Codec `group:"codecs" key:"json"`
+//	}
+//
+// Fields of fx.In parameters of type map[string]T tagged with a group
+// receive the values contributed to that group with a key, by key:
+//
+//	type Params struct {
+//		fx.In
+//
+//		Codecs map[string]Codec `group:"codecs"`
+//	}
+//
+// Values contributed without a key are not included in the map,
+// and values contributed with a key are not included in []T.
+// If two values of a group share a key, the function fails.
+//
+// The returned function also supports lazy value groups,
+// as described in lazyGroups.
+//
+// It reports whether fn had any such parameters or results.
+// If it didn't, fn is returned unchanged.
+func keyGroups(fn interface{}) (interface{}, bool, error) {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func {
+		return fn, false, nil
+	}
+
+	ft := fv.Type()
+	var (
+		changed  bool
+		ins      = make([]reflect.Type, ft.NumIn())
+		fromKeys = make([]func(reflect.Value) (reflect.Value, error), ft.NumIn())
+		outs     = make([]reflect.Type, ft.NumOut())
+		toKeys   = make([]func(reflect.Value) reflect.Value, ft.NumOut())
+		paramErr bool // whether converting parameters may fail
+	)
+	for i := range ins {
+		ins[i] = ft.In(i)
+		if !isIn(ins[i]) {
+			continue
+		}
+		t, from, ok, err := keyedParamStruct(ins[i])
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			ins[i], fromKeys[i] = t, from
+			changed, paramErr = true, true
+		}
+	}
+	for i := range outs {
+		outs[i] = ft.Out(i)
+		if !isOut(outs[i]) {
+			continue
+		}
+		t, to, ok, err := keyedResultStruct(outs[i])
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			outs[i], toKeys[i] = t, to
+			changed = true
+		}
+	}
+	if !changed {
+		return lazyGroups(fn)
+	}
+
+	// Duplicate keys are reported through an error result,
+	// which is added if fn does not have one.
+	hasErr := len(outs) > 0 && outs[len(outs)-1] == _typeOfError
+	addErr := paramErr && !hasErr
+	if addErr {
+		outs = append(outs, _typeOfError)
+	}
+
+	call := fv.Call
+	if ft.IsVariadic() {
+		call = fv.CallSlice
+	}
+	newFt := reflect.FuncOf(ins, outs, ft.IsVariadic())
+	keyed := reflect.MakeFunc(newFt, func(args []reflect.Value) []reflect.Value {
+		for i, from := range fromKeys {
+			if from == nil {
+				continue
+			}
+			v, err := from(args[i])
+			if err != nil {
+				results := make([]reflect.Value, len(outs))
+				for j, t := range outs {
+					results[j] = reflect.Zero(t)
+				}
+				results[len(results)-1] = reflect.ValueOf(&err).Elem()
+				return results
+			}
+			args[i] = v
+		}
+
+		results := call(args)
+		for i, to := range toKeys {
+			if to != nil {
+				results[i] = to(results[i])
+			}
+		}
+		if addErr {
+			results = append(results, reflect.Zero(_typeOfError))
+		}
+		return results
+	}).Interface()
+
+	keyed, _, err := lazyGroups(keyed)
+	return keyed, true, err
+}
+
+// keyedParamStruct builds a version of the fx.In struct t whose value group
+// fields of map types are slices of keyed entries instead, along with
+// a function to convert values of it back into t.
+// It reports whether t had any such fields.
+func keyedParamStruct(t reflect.Type) (reflect.Type, func(reflect.Value) (reflect.Value, error), bool, error) {
+	fields, keyed, err := keyedStructFields(t, func(f reflect.StructField) (bool, error) {
+		if f.Type.Kind() != reflect.Map || f.Tag.Get(_groupTag) == "" {
+			return false, nil
+		}
+		if f.Type.Key().Kind() != reflect.String {
+			return false, fmt.Errorf(
+				"value group field %v of %v must be a map with string keys, got %v",
+				f.Name, t, f.Type)
+		}
+		return true, nil
+	})
+	if err != nil || len(keyed) == 0 {
+		return t, nil, false, err
+	}
+
+	groups := make(map[int]string, len(keyed))
+	for i := range keyed {
+		groups[i] = groupName(fields[i].Tag.Get(_groupTag))
+		fields[i].Type = reflect.SliceOf(keyedEntryType(fields[i].Type.Elem()))
+	}
+
+	newT := reflect.StructOf(fields)
+	from := func(v reflect.Value) (reflect.Value, error) {
+		out := reflect.New(t).Elem()
+		for i, f := range fields {
+			if f.Type == _inAnnotationField.Type {
+				continue
+			}
+			fv := v.Field(i)
+			mapType, ok := keyed[i]
+			if !ok {
+				out.Field(i).Set(fv)
+				continue
+			}
+
+			m := reflect.MakeMapWithSize(mapType, fv.Len())
+			for j := 0; j < fv.Len(); j++ {
+				entry := fv.Index(j)
+				key := entry.Field(0).Convert(mapType.Key())
+				if m.MapIndex(key).IsValid() {
+					return reflect.Value{}, fmt.Errorf(
+						"value group %q has more than one value with key %q",
+						groups[i], entry.Field(0).String())
+				}
+				m.SetMapIndex(key, entry.Field(1))
+			}
+			out.Field(i).Set(m)
+		}
+		return out, nil
+	}
+	return newT, from, true, nil
+}
+
+// keyedResultStruct builds a version of the fx.Out struct t whose value group
+// fields tagged with a key are keyed entries instead, along with
+// a function to convert values of t into it.
+// It reports whether t had any such fields.
+func keyedResultStruct(t reflect.Type) (reflect.Type, func(reflect.Value) reflect.Value, bool, error) {
+	fields, keyed, err := keyedStructFields(t, func(f reflect.StructField) (bool, error) {
+		key, ok := f.Tag.Lookup(_keyTag)
+		if !ok {
+			return false, nil
+		}
+		group := f.Tag.Get(_groupTag)
+		switch {
+		case group == "":
+			return false, fmt.Errorf("field %v of %v has a key but is not in a value group", f.Name, t)
+		case strings.Contains(group, ",flatten"):
+			return false, fmt.Errorf("field %v of %v has a key and cannot be flattened", f.Name, t)
+		case key == "":
+			return false, fmt.Errorf("field %v of %v has an empty key", f.Name, t)
+		}
+		return true, nil
+	})
+	if err != nil || len(keyed) == 0 {
+		return t, nil, false, err
+	}
+
+	keys := make(map[int]string, len(keyed))
+	for i := range keyed {
+		keys[i] = fields[i].Tag.Get(_keyTag)
+		fields[i].Type = keyedEntryType(fields[i].Type)
+	}
+
+	newT := reflect.StructOf(fields)
+	to := func(v reflect.Value) reflect.Value {
+		out := reflect.New(newT).Elem()
+		for i, f := range fields {
+			if f.Type == _outAnnotationField.Type {
+				continue
+			}
+			fv := v.Field(i)
+			if key, ok := keys[i]; ok {
+				entry := out.Field(i)
+				entry.Field(0).SetString(key)
+				entry.Field(1).Set(fv)
+				continue
+			}
+			out.Field(i).Set(fv)
+		}
+		return out
+	}
+	return newT, to, true, nil
+}
+
+// keyedStructFields returns the fields of the fx.In or fx.Out struct t
+// to declare on a version of it built with reflect.StructOf,
+// in the same order as in t,
+// along with the original types of the fields for which match
+// returned true, by index.
+func keyedStructFields(
+	t reflect.Type,
+	match func(reflect.StructField) (bool, error),
+) ([]reflect.StructField, map[int]reflect.Type, error) {
+	var (
+		fields []reflect.StructField
+		keyed  map[int]reflect.Type
+	)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		switch {
+		case f.Type == _inAnnotationField.Type:
+			fields = append(fields, _inAnnotationField)
+			continue
+		case f.Type == _outAnnotationField.Type:
+			fields = append(fields, _outAnnotationField)
+			continue
+		}
+
+		ok, err := match(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			if keyed == nil {
+				keyed = make(map[int]reflect.Type)
+			}
+			keyed[len(fields)] = f.Type
+		}
+		fields = append(fields, f)
+	}
+	if len(keyed) == 0 {
+		return nil, nil, nil
+	}
+
+	for i, f := range fields {
+		if f.PkgPath != "" {
+			return nil, nil, fmt.Errorf(
+				"unexported field %v of %v cannot be used with keyed value groups",
+				f.Name, t)
+		}
+		// Embedded fields are declared by name
+		// because reflect.StructOf does not support promoted methods.
+		if f.Type != _inAnnotationField.Type && f.Type != _outAnnotationField.Type {
+			f.Anonymous = false
+		}
+		f.Index = nil
+		f.Offset = 0
+		fields[i] = f
+	}
+	return fields, keyed, nil
+}
+
+// groupName returns the name of the value group in the group tag value tag,
+// without its options.
+func groupName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestKeyedGroups(t *testing.T) {
+	t.Parallel()
+
+	type Codec struct{ name string }
+
+	type CodecResult struct {
+		fx.Out
+
+		Codec *Codec `group:"codecs" key:"json"`
+	}
+
+	type Params struct {
+		fx.In
+
+		Codecs map[string]*Codec `group:"codecs"`
+	}
+
+	provideCodecs := fx.Provide(
+		func() CodecResult { return CodecResult{Codec: &Codec{"json"}} },
+		fx.Annotate(
+			func() *Codec { return &Codec{"yaml"} },
+			fx.ResultTags(`group:"codecs" key:"yaml"`),
+		),
+		fx.Annotate(
+			func() *Codec { return &Codec{"proto"} },
+			fx.ResultTags(fx.Tag().Group("codecs").GroupKey("proto").Build()),
+		),
+	)
+
+	t.Run("invoke", func(t *testing.T) {
+		t.Parallel()
+
+		var got map[string]*Codec
+		app := fxtest.New(t,
+			provideCodecs,
+			fx.Invoke(func(p Params) { got = p.Codecs }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		require.Len(t, got, 3)
+		for key, codec := range got {
+			assert.Equal(t, key, codec.name)
+		}
+	})
+
+	t.Run("annotated consumer", func(t *testing.T) {
+		t.Parallel()
+
+		var got map[string]*Codec
+		app := fxtest.New(t,
+			provideCodecs,
+			fx.Invoke(fx.Annotate(
+				func(codecs map[string]*Codec) { got = codecs },
+				fx.ParamTags(`group:"codecs"`),
+			)),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Len(t, got, 3)
+	})
+
+	t.Run("provide", func(t *testing.T) {
+		t.Parallel()
+
+		type Registry struct{ codecs map[string]*Codec }
+
+		var got *Registry
+		app := fxtest.New(t,
+			provideCodecs,
+			fx.Provide(func(p Params) *Registry { return &Registry{p.Codecs} }),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "yaml", got.codecs["yaml"].name)
+	})
+
+	t.Run("unkeyed values are separate", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			byKey map[string]*Codec
+			all   []*Codec
+		)
+		app := fxtest.New(t,
+			provideCodecs,
+			fx.Provide(fx.Annotate(
+				func() *Codec { return &Codec{"gob"} },
+				fx.ResultTags(`group:"codecs"`),
+			)),
+			fx.Invoke(fx.Annotate(
+				func(m map[string]*Codec, s []*Codec) { byKey, all = m, s },
+				fx.ParamTags(`group:"codecs"`, `group:"codecs"`),
+			)),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Len(t, byKey, 3)
+		require.Len(t, all, 1)
+		assert.Equal(t, "gob", all[0].name)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		var got map[string]*Codec
+		app := fxtest.New(t,
+			fx.Invoke(func(p Params) { got = p.Codecs }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+	})
+
+	t.Run("duplicate key", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			provideCodecs,
+			fx.Provide(func() CodecResult { return CodecResult{Codec: &Codec{"json2"}} }),
+			fx.Invoke(func(Params) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `value group "codecs" has more than one value with key "json"`)
+	})
+
+	t.Run("duplicate key with error result", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			provideCodecs,
+			fx.Provide(func() CodecResult { return CodecResult{Codec: &Codec{"json2"}} }),
+			fx.Invoke(func(Params) error { return errors.New("not called") }),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `more than one value with key "json"`)
+		assert.NotContains(t, err.Error(), "not called")
+	})
+
+	t.Run("key without group", func(t *testing.T) {
+		t.Parallel()
+
+		type Result struct {
+			fx.Out
+
+			Codec *Codec `key:"json"`
+		}
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(func() Result { return Result{} }),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has a key but is not in a value group")
+	})
+
+	t.Run("non-string keys", func(t *testing.T) {
+		t.Parallel()
+
+		type BadParams struct {
+			fx.In
+
+			Codecs map[int]*Codec `group:"codecs"`
+		}
+		app := fx.New(
+			fx.NopLogger,
+			fx.Invoke(func(BadParams) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be a map with string keys")
+	})
+}
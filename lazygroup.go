@@ -0,0 +1,477 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"go.uber.org/dig"
+)
+
+// _lazyOption is the option of the group tag of fx.In fields
+// that consume a value group lazily.
+const _lazyOption = "lazy"
+
+// LazyGroup holds the values of a value group of type T
+// without building them, so that they are built only if they're used.
+// It's consumed with the lazy option of the group tag:
+//
+//	type Params struct {
+//		fx.In
+//
+//		Plugins fx.LazyGroup[Plugin] `group:"plugins,lazy"`
+//	}
+//
+// Values contributed to the group are built on first access, by [LazyGroup.Get]
+// or [LazyGroup.All], along with their dependencies.
+// A constructor contributing to the group runs at most once,
+// whether its values are consumed lazily, as a []T,
+// or through its other results.
+//
+// Values contributed with a key are not members of lazy groups,
+// and flattened values fail to load.
+// Lazy groups consumed by functions passed to [App.Extend] don't include
+// values contributed before the application was extended,
+// unless a LazyGroup of the same type was already consumed.
+// Values loaded lazily are not decorated.
+//
+// Loading a value runs its constructor from the container,
+// which is not safe for concurrent use:
+// don't load values from multiple goroutines at once.
+type LazyGroup[T any] struct {
+	loads []func() (T, error)
+}
+
+// Len returns the number of values in the group.
+func (g LazyGroup[T]) Len() int {
+	return len(g.loads)
+}
+
+// Get returns the i-th value of the group, building it if needed.
+// The order of values is unspecified.
+// Get fails if the constructor of the value fails.
+func (g LazyGroup[T]) Get(i int) (T, error) {
+	return g.loads[i]()
+}
+
+// All returns all values of the group, building them if needed.
+func (g LazyGroup[T]) All() ([]T, error) {
+	vs := make([]T, len(g.loads))
+	for i, load := range g.loads {
+		v, err := load()
+		if err != nil {
+			return nil, err
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+func (LazyGroup[T]) elemType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (g *LazyGroup[T]) setMembers(members reflect.Value) {
+	g.loads = make([]func() (T, error), members.Len())
+	for i := range g.loads {
+		g.loads[i] = members.Index(i).Field(0).Interface().(func() (T, error))
+	}
+}
+
+// lazyGroup is implemented by pointers to LazyGroup types.
+type lazyGroup interface {
+	elemType() reflect.Type
+	setMembers(reflect.Value)
+}
+
+var _typeOfLazyGroup = reflect.TypeOf((*lazyGroup)(nil)).Elem()
+
+// lazyMemberType returns the type that loaders of values of type t
+// are provided to dig as, so that lazy consumers of a group
+// receive them instead of the values.
+// reflect.StructOf returns identical types for identical fields,
+// so contributors and consumers of the same group agree on it.
+func lazyMemberType(t reflect.Type) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "Load", Type: reflect.FuncOf(nil, []reflect.Type{t, _typeOfError}, false)},
+	})
+}
+
+// lazyGroups returns a function equivalent to fn
+// whose fx.In parameters support fields of type LazyGroup,
+// which receive the loaders of the values of their group
+// instead of the values.
+//
+// It reports whether fn had any such parameters.
+// If it didn't, fn is returned unchanged.
+func lazyGroups(fn interface{}) (interface{}, bool, error) {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func {
+		return fn, false, nil
+	}
+
+	ft := fv.Type()
+	var (
+		changed bool
+		ins     = make([]reflect.Type, ft.NumIn())
+		froms   = make([]func(reflect.Value) reflect.Value, ft.NumIn())
+		outs    = make([]reflect.Type, ft.NumOut())
+	)
+	for i := range ins {
+		ins[i] = ft.In(i)
+		if !isIn(ins[i]) {
+			continue
+		}
+		t, from, ok, err := lazyParamStruct(ins[i])
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			ins[i], froms[i] = t, from
+			changed = true
+		}
+	}
+	if !changed {
+		return fn, false, nil
+	}
+	for i := range outs {
+		outs[i] = ft.Out(i)
+	}
+
+	call := fv.Call
+	if ft.IsVariadic() {
+		call = fv.CallSlice
+	}
+	newFt := reflect.FuncOf(ins, outs, ft.IsVariadic())
+	return reflect.MakeFunc(newFt, func(args []reflect.Value) []reflect.Value {
+		for i, from := range froms {
+			if from != nil {
+				args[i] = from(args[i])
+			}
+		}
+		return call(args)
+	}).Interface(), true, nil
+}
+
+// lazyParamStruct builds a version of the fx.In struct t whose LazyGroup
+// fields are slices of loaders instead, along with a function to convert
+// values of it back into t.
+// It reports whether t had any such fields.
+func lazyParamStruct(t reflect.Type) (reflect.Type, func(reflect.Value) reflect.Value, bool, error) {
+	fields, lazy, err := keyedStructFields(t, func(f reflect.StructField) (bool, error) {
+		isLazy := reflect.PointerTo(f.Type).Implements(_typeOfLazyGroup)
+		name, opts, _ := strings.Cut(f.Tag.Get(_groupTag), ",")
+		hasOpt := opts == _lazyOption || strings.Contains(","+opts+",", ","+_lazyOption+",")
+		switch {
+		case !isLazy && !hasOpt:
+			return false, nil
+		case !isLazy:
+			return false, fmt.Errorf(
+				"lazy value group field %v of %v must be an fx.LazyGroup, got %v",
+				f.Name, t, f.Type)
+		case name == "" || opts != _lazyOption:
+			return false, fmt.Errorf(
+				"field %v of %v must be tagged `group:\"<name>,lazy\"`, got %q",
+				f.Name, t, f.Tag.Get(_groupTag))
+		}
+		return true, nil
+	})
+	if err != nil || len(lazy) == 0 {
+		return t, nil, false, err
+	}
+
+	for i, ft := range lazy {
+		elem := reflect.New(ft).Interface().(lazyGroup).elemType()
+		fields[i].Type = reflect.SliceOf(lazyMemberType(elem))
+		fields[i].Tag = reflect.StructTag(strings.Replace(
+			string(fields[i].Tag), ","+_lazyOption+`"`, `"`, 1))
+	}
+
+	newT := reflect.StructOf(fields)
+	from := func(v reflect.Value) reflect.Value {
+		out := reflect.New(t).Elem()
+		for i, f := range fields {
+			if f.Type == _inAnnotationField.Type {
+				continue
+			}
+			if _, ok := lazy[i]; ok {
+				out.Field(i).Addr().Interface().(lazyGroup).setMembers(v.Field(i))
+				continue
+			}
+			out.Field(i).Set(v.Field(i))
+		}
+		return out
+	}
+	return newT, from, true, nil
+}
+
+// lazyGroupContainer is a container that provides loaders
+// for the values its constructors contribute to value groups,
+// so that they can be consumed by LazyGroup fields.
+//
+// Constructors with such values are memoized,
+// so that they run at most once whether they are called by dig
+// or by a loader.
+type lazyGroupContainer struct {
+	container
+
+	export bool                      // whether the constructor is visible outside its module
+	group  string                    // group of the results, set by fx.Annotated
+	types  map[reflect.Type]struct{} // types of values consumed lazily
+}
+
+var _ container = lazyGroupContainer{}
+
+// lazyResult is a value contributed to a value group by a constructor.
+type lazyResult struct {
+	group   string
+	typ     reflect.Type
+	result  int // index of the result
+	field   int // index of the field in the fx.Out result, or -1
+	flatten bool
+}
+
+func (c lazyGroupContainer) Provide(constructor interface{}, opts ...dig.ProvideOption) error {
+	fn := reflect.ValueOf(constructor)
+	if fn.Kind() != reflect.Func {
+		// Let dig report the error.
+		return c.container.Provide(constructor, opts...)
+	}
+
+	ft := fn.Type()
+	members := c.groupResults(ft)
+	if len(members) == 0 {
+		return c.container.Provide(constructor, opts...)
+	}
+
+	var memoized constructorFunc
+	err := provideWrapped(c.container, constructor, opts, false, func(_ reflect.Type, call constructorFunc) constructorFunc {
+		var (
+			mu      sync.Mutex
+			results []reflect.Value // set once the constructor succeeds
+		)
+		memoized = func(args []reflect.Value) []reflect.Value {
+			mu.Lock()
+			defer mu.Unlock()
+			if results != nil {
+				return results
+			}
+
+			rs := call(args)
+			if n := len(rs); n == 0 || rs[n-1].Type() != _typeOfError || rs[n-1].IsNil() {
+				results = rs
+			}
+			return rs
+		}
+		return memoized
+	})
+	if err != nil {
+		return err
+	}
+
+	// Results are loaded by invoking a function with the same parameters
+	// as the constructor, which dig can resolve from the same scope.
+	in := make([]reflect.Type, ft.NumIn())
+	for i := range in {
+		in[i] = ft.In(i)
+	}
+	load := func() ([]reflect.Value, error) {
+		var rs []reflect.Value
+		invoke := reflect.MakeFunc(
+			reflect.FuncOf(in, []reflect.Type{_typeOfError}, ft.IsVariadic()),
+			func(args []reflect.Value) []reflect.Value {
+				rs = memoized(args)
+				if n := len(rs); n > 0 && rs[n-1].Type() == _typeOfError {
+					return rs[n-1:]
+				}
+				return []reflect.Value{_nilError}
+			},
+		)
+		if err := c.container.Invoke(invoke.Interface()); err != nil {
+			return nil, err
+		}
+		return rs, nil
+	}
+
+	fields := make([]reflect.StructField, 0, len(members)+1)
+	fields = append(fields, _outAnnotationField)
+	for i, m := range members {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("Field%d", i),
+			Type: lazyMemberType(m.typ),
+			Tag:  reflect.StructTag(fmt.Sprintf(`group:"%v"`, m.group)),
+		})
+	}
+	outT := reflect.StructOf(fields)
+	out := reflect.New(outT).Elem()
+	for i, m := range members {
+		out.Field(i + 1).Field(0).Set(m.loader(load))
+	}
+
+	loaders := reflect.MakeFunc(
+		reflect.FuncOf(nil, []reflect.Type{outT}, false),
+		func([]reflect.Value) []reflect.Value { return []reflect.Value{out} },
+	)
+	return c.container.Provide(loaders.Interface(), dig.Export(c.export))
+}
+
+// groupResults returns the values contributed to value groups
+// by constructors of type ft.
+func (c lazyGroupContainer) groupResults(ft reflect.Type) []lazyResult {
+	var members []lazyResult
+	for i := 0; i < ft.NumOut(); i++ {
+		rt := ft.Out(i)
+		switch {
+		case rt == _typeOfError:
+		case isOut(rt):
+			for j := 0; j < rt.NumField(); j++ {
+				f := rt.Field(j)
+				tag, ok := f.Tag.Lookup(_groupTag)
+				if !ok || f.Type == _outAnnotationField.Type {
+					continue
+				}
+				if _, keyed := f.Tag.Lookup(_keyTag); keyed {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				m := lazyResult{group: name, typ: f.Type, result: i, field: j}
+				if opts == "flatten" && f.Type.Kind() == reflect.Slice {
+					m.typ, m.flatten = f.Type.Elem(), true
+				}
+				if _, ok := c.types[m.typ]; ok {
+					members = append(members, m)
+				}
+			}
+		case c.group != "":
+			name, opts, _ := strings.Cut(c.group, ",")
+			m := lazyResult{group: name, typ: rt, result: i, field: -1}
+			if opts == "flatten" && rt.Kind() == reflect.Slice {
+				m.typ, m.flatten = rt.Elem(), true
+			}
+			if _, ok := c.types[m.typ]; ok {
+				members = append(members, m)
+			}
+		}
+	}
+	return members
+}
+
+// loader builds the function that loads the value of r
+// from the results returned by load.
+func (r lazyResult) loader(load func() ([]reflect.Value, error)) reflect.Value {
+	ft := reflect.FuncOf(nil, []reflect.Type{r.typ, _typeOfError}, false)
+	return reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+		fail := func(err error) []reflect.Value {
+			return []reflect.Value{reflect.Zero(r.typ), reflect.ValueOf(&err).Elem()}
+		}
+		if r.flatten {
+			return fail(fmt.Errorf(
+				"value group %q has flattened values, which cannot be loaded lazily", r.group))
+		}
+
+		rs, err := load()
+		if err != nil {
+			return fail(err)
+		}
+		v := rs[r.result]
+		if r.field >= 0 {
+			v = v.Field(r.field)
+		}
+		return []reflect.Value{v, _nilError}
+	})
+}
+
+// collectLazyGroupTypes records the types of the values consumed by the
+// LazyGroup parameters of the functions of m and its submodules,
+// so that constructors contributing values of these types get loaders.
+// Constructors that contribute to no lazily consumed group
+// are provided unchanged.
+func (app *App) collectLazyGroupTypes(m *module) {
+	var targets []interface{}
+	var walk func(*module)
+	walk = func(m *module) {
+		for _, p := range m.provides {
+			targets = append(targets, p.Target)
+		}
+		for _, i := range m.invokes {
+			targets = append(targets, i.Target)
+		}
+		for _, d := range m.decorators {
+			targets = append(targets, d.Target)
+		}
+		for _, c := range m.conditionals {
+			for _, p := range c.Provides {
+				targets = append(targets, p.Target)
+			}
+		}
+		for _, sub := range m.modules {
+			walk(sub)
+		}
+	}
+	walk(m)
+	for _, si := range app.startInvokes {
+		targets = append(targets, si.invoke.Target)
+	}
+
+	for _, target := range targets {
+		ft := reflect.TypeOf(unwrapTarget(target))
+		if ft == nil || ft.Kind() != reflect.Func {
+			continue
+		}
+		for i := 0; i < ft.NumIn(); i++ {
+			app.addLazyGroupTypes(ft.In(i))
+		}
+	}
+}
+
+// addLazyGroupTypes records the types of the values consumed
+// by t if it's a LazyGroup, or by its fields if it's an fx.In struct.
+func (app *App) addLazyGroupTypes(t reflect.Type) {
+	if reflect.PointerTo(t).Implements(_typeOfLazyGroup) {
+		if app.lazyGroupTypes == nil {
+			app.lazyGroupTypes = make(map[reflect.Type]struct{})
+		}
+		elem := reflect.New(t).Interface().(lazyGroup).elemType()
+		app.lazyGroupTypes[elem] = struct{}{}
+		return
+	}
+	if !isIn(t) {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		app.addLazyGroupTypes(t.Field(i).Type)
+	}
+}
+
+// unwrapTarget returns the function annotated by fx.Annotate
+// or fx.Annotated, or target itself.
+func unwrapTarget(target interface{}) interface{} {
+	switch t := target.(type) {
+	case annotated:
+		return unwrapTarget(t.Target)
+	case Annotated:
+		return unwrapTarget(t.Target)
+	}
+	return target
+}
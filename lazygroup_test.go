@@ -0,0 +1,300 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestLazyGroups(t *testing.T) {
+	t.Parallel()
+
+	type Plugin struct{ name string }
+
+	type Params struct {
+		fx.In
+
+		Plugins fx.LazyGroup[*Plugin] `group:"plugins,lazy"`
+	}
+
+	// provide returns an option that provides a plugin
+	// and records in built when it's built.
+	provide := func(built map[string]int, name string) fx.Option {
+		return fx.Provide(fx.Annotate(
+			func() *Plugin {
+				built[name]++
+				return &Plugin{name}
+			},
+			fx.ResultTags(`group:"plugins"`),
+		))
+	}
+
+	t.Run("built on first use", func(t *testing.T) {
+		t.Parallel()
+
+		built := make(map[string]int)
+		var plugins fx.LazyGroup[*Plugin]
+		app := fxtest.New(t,
+			provide(built, "a"),
+			provide(built, "b"),
+			fx.Invoke(func(p Params) { plugins = p.Plugins }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		require.Equal(t, 2, plugins.Len())
+		assert.Empty(t, built, "plugins must not be built until used")
+
+		p, err := plugins.Get(0)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{p.name: 1}, built)
+
+		p2, err := plugins.Get(0)
+		require.NoError(t, err)
+		assert.Same(t, p, p2, "plugins must be built once")
+
+		all, err := plugins.All()
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+		assert.Equal(t, map[string]int{"a": 1, "b": 1}, built)
+	})
+
+	t.Run("shared with eager consumers", func(t *testing.T) {
+		t.Parallel()
+
+		built := make(map[string]int)
+		var (
+			lazy  []*Plugin
+			eager []*Plugin
+		)
+		app := fxtest.New(t,
+			provide(built, "a"),
+			fx.Invoke(func(p Params) {
+				var err error
+				lazy, err = p.Plugins.All()
+				require.NoError(t, err)
+			}),
+			fx.Invoke(fx.Annotate(
+				func(ps []*Plugin) { eager = ps },
+				fx.ParamTags(`group:"plugins"`),
+			)),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, map[string]int{"a": 1}, built)
+		assert.Equal(t, eager, lazy)
+	})
+
+	t.Run("annotated consumer", func(t *testing.T) {
+		t.Parallel()
+
+		built := make(map[string]int)
+		var plugins fx.LazyGroup[*Plugin]
+		app := fxtest.New(t,
+			provide(built, "a"),
+			fx.Invoke(fx.Annotate(
+				func(ps fx.LazyGroup[*Plugin]) { plugins = ps },
+				fx.ParamTags(`group:"plugins,lazy"`),
+			)),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, 1, plugins.Len())
+		assert.Empty(t, built)
+	})
+
+	t.Run("contributors", func(t *testing.T) {
+		t.Parallel()
+
+		type Result struct {
+			fx.Out
+
+			Plugin *Plugin `group:"plugins"`
+			Keyed  *Plugin `group:"plugins" key:"keyed"`
+		}
+
+		var names []string
+		app := fxtest.New(t,
+			fx.Provide(
+				func() Result { return Result{Plugin: &Plugin{"out"}, Keyed: &Plugin{"keyed"}} },
+				fx.Annotated{Group: "plugins", Target: func() *Plugin { return &Plugin{"annotated"} }},
+			),
+			fx.Supply(fx.Annotate(&Plugin{"supplied"}, fx.ResultTags(`group:"plugins"`))),
+			fx.Invoke(func(p Params) {
+				all, err := p.Plugins.All()
+				require.NoError(t, err)
+				for _, p := range all {
+					names = append(names, p.name)
+				}
+			}),
+		)
+		defer app.RequireStart().RequireStop()
+
+		sort.Strings(names)
+		assert.Equal(t, []string{"annotated", "out", "supplied"}, names)
+	})
+
+	t.Run("dependencies", func(t *testing.T) {
+		t.Parallel()
+
+		type Config struct{ prefix string }
+
+		var configs int
+		var plugins fx.LazyGroup[*Plugin]
+		app := fxtest.New(t,
+			fx.Provide(
+				func() *Config {
+					configs++
+					return &Config{"plugin-"}
+				},
+				fx.Annotate(
+					func(c *Config) *Plugin { return &Plugin{c.prefix + "a"} },
+					fx.ResultTags(`group:"plugins"`),
+				),
+			),
+			fx.Invoke(func(p Params) { plugins = p.Plugins }),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.Zero(t, configs, "dependencies must not be built until used")
+
+		p, err := plugins.Get(0)
+		require.NoError(t, err)
+		assert.Equal(t, "plugin-a", p.name)
+		assert.Equal(t, 1, configs)
+	})
+
+	t.Run("constructor error", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		var plugins fx.LazyGroup[*Plugin]
+		app := fxtest.New(t,
+			fx.Provide(fx.Annotate(
+				func() (*Plugin, error) {
+					calls++
+					return nil, errors.New("great sadness")
+				},
+				fx.ResultTags(`group:"plugins"`),
+			)),
+			fx.Invoke(func(p Params) { plugins = p.Plugins }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		_, err := plugins.Get(0)
+		assert.ErrorContains(t, err, "great sadness")
+		_, err = plugins.All()
+		assert.ErrorContains(t, err, "great sadness")
+		assert.Equal(t, 2, calls, "failed constructors must run again")
+	})
+
+	t.Run("flattened", func(t *testing.T) {
+		t.Parallel()
+
+		var plugins fx.LazyGroup[*Plugin]
+		app := fxtest.New(t,
+			fx.Provide(fx.Annotate(
+				func() []*Plugin { return []*Plugin{{"a"}, {"b"}} },
+				fx.ResultTags(`group:"plugins,flatten"`),
+			)),
+			fx.Invoke(func(p Params) { plugins = p.Plugins }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		require.Equal(t, 1, plugins.Len())
+		_, err := plugins.Get(0)
+		assert.ErrorContains(t, err, `value group "plugins" has flattened values`)
+	})
+
+	t.Run("lazy field must be a LazyGroup", func(t *testing.T) {
+		t.Parallel()
+
+		type BadParams struct {
+			fx.In
+
+			Plugins []*Plugin `group:"plugins,lazy"`
+		}
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Invoke(func(BadParams) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "lazy value group field Plugins")
+		assert.ErrorContains(t, err, "must be an fx.LazyGroup")
+	})
+
+	t.Run("LazyGroup must be lazy", func(t *testing.T) {
+		t.Parallel()
+
+		type BadParams struct {
+			fx.In
+
+			Plugins fx.LazyGroup[*Plugin] `group:"plugins,soft"`
+		}
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Invoke(func(BadParams) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "field Plugins")
+		assert.ErrorContains(t, err, `must be tagged`)
+	})
+
+	t.Run("contributors keep their location", func(t *testing.T) {
+		t.Parallel()
+
+		type Out struct {
+			fx.Out
+
+			Plugin *Plugin `group:"plugins"`
+		}
+		type EagerParams struct {
+			fx.In
+
+			Plugins []*Plugin `group:"plugins"`
+		}
+		newPlugin := func() (Out, error) { return Out{}, errors.New("great sadness") }
+
+		for _, lazy := range []bool{false, true} {
+			opts := []fx.Option{
+				fx.NopLogger,
+				fx.Provide(newPlugin),
+				fx.Invoke(func(EagerParams) {}),
+			}
+			if lazy {
+				opts = append(opts, fx.Invoke(func(Params) {}))
+			}
+			err := fx.New(opts...).Err()
+			require.Error(t, err)
+			assert.ErrorContains(t, err, "TestLazyGroups", "lazy consumer: %v", lazy)
+			assert.NotContains(t, err.Error(), "makeFuncStub", "lazy consumer: %v", lazy)
+		}
+	})
+}
@@ -22,7 +22,12 @@ package fx
 
 import (
 	"context"
+	"sync"
 
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/fxclock"
+	"go.uber.org/fx/internal/fxhook"
+	"go.uber.org/fx/internal/fxreflect"
 	"go.uber.org/fx/internal/lifecycle"
 )
 
@@ -34,20 +39,57 @@ type HookFunc interface {
 // Lifecycle allows constructors to register callbacks that are executed on
 // application start and stop. See the documentation for App for details on Fx
 // applications' initialization, startup, and shutdown logic.
+//
+// Hooks may also be appended while the application is starting,
+// for example from an OnStart hook. These run after the hooks
+// that were already appended, and their OnStop callbacks run on shutdown.
 type Lifecycle interface {
 	Append(Hook)
 }
 
+type rollbackKey struct{}
+
+// IsRollback reports whether ctx was passed to an OnStop hook
+// because the application failed to start,
+// to roll back the hooks that already started,
+// rather than because the application is stopping after it ran.
+//
+// Use it for cleanups that differ in these cases. For example,
+// a server may skip draining connections that it never accepted:
+//
+//	OnStop: func(ctx context.Context) error {
+//		if fx.IsRollback(ctx) {
+//			return srv.Close()
+//		}
+//		return srv.Shutdown(ctx)
+//	},
+func IsRollback(ctx context.Context) bool {
+	rollback, _ := ctx.Value(rollbackKey{}).(bool)
+	return rollback
+}
+
 // A Hook is a pair of start and stop callbacks, either of which can be nil.
 // If a Hook's OnStart callback isn't executed (because a previous OnStart
 // failure short-circuited application startup), its OnStop callback won't be
 // executed.
+//
+// A Hook may also have OnPause and OnResume callbacks, which are run by
+// [App.Pause] and [App.Resume] to temporarily quiesce a started application
+// without tearing it down.
 type Hook struct {
-	OnStart func(context.Context) error
-	OnStop  func(context.Context) error
+	OnStart  func(context.Context) error
+	OnStop   func(context.Context) error
+	OnPause  func(context.Context) error
+	OnResume func(context.Context) error
+
+	onStartName  string
+	onStopName   string
+	onPauseName  string
+	onResumeName string
 
-	onStartName string
-	onStopName  string
+	// callerFrame, if set, is reported as the function
+	// that appended the hook, instead of the caller of Append.
+	callerFrame fxreflect.Frame
 }
 
 // StartHook returns a new Hook with start as its [Hook.OnStart] function,
@@ -133,15 +175,219 @@ func StartStopHook[T1 HookFunc, T2 HookFunc](start T1, stop T2) Hook {
 	}
 }
 
+// PauseHook returns a new Hook with pause as its [Hook.OnPause] function,
+// wrapping its signature as needed like [StartHook].
+// To pair it with other callbacks, construct a Hook directly.
+func PauseHook[T HookFunc](pause T) Hook {
+	onpause, pausename := lifecycle.Wrap(pause)
+
+	return Hook{
+		OnPause:     onpause,
+		onPauseName: pausename,
+	}
+}
+
+// ResumeHook returns a new Hook with resume as its [Hook.OnResume] function,
+// wrapping its signature as needed like [StartHook].
+func ResumeHook[T HookFunc](resume T) Hook {
+	onresume, resumename := lifecycle.Wrap(resume)
+
+	return Hook{
+		OnResume:     onresume,
+		onResumeName: resumename,
+	}
+}
+
+// HookHandle identifies a hook appended with [AppendHook].
+type HookHandle struct {
+	remove func() bool
+}
+
+// Remove removes the hook from the lifecycle it was appended to,
+// so that neither of its callbacks runs.
+// It reports whether the hook was removed:
+// hooks cannot be removed once the application has started,
+// nor removed twice.
+func (h HookHandle) Remove() bool {
+	return h.remove()
+}
+
+// AppendHook appends a hook to a [Lifecycle] like [Lifecycle.Append],
+// returning a handle that can remove it before the application starts.
+// This allows frameworks to conditionally drop hooks
+// registered by the modules they build on,
+// for example to disable a background refresher in tests.
+//
+//	type Refresher struct{ Hook fx.HookHandle }
+//
+//	func NewRefresher(lc fx.Lifecycle) *Refresher {
+//		r := &Refresher{}
+//		r.Hook = fx.AppendHook(lc, fx.StartStopHook(r.start, r.stop))
+//		return r
+//	}
+//
+//	fx.Invoke(func(r *refresh.Refresher) { r.Hook.Remove() })
+func AppendHook(lc Lifecycle, hook Hook) HookHandle {
+	if lw, ok := lc.(*lifecycleWrapper); ok {
+		return HookHandle{remove: lw.AppendRemovable(lw.convert(hook))}
+	}
+
+	// For other implementations of Lifecycle,
+	// disable the callbacks of the hook when it's removed.
+	var (
+		mu               sync.Mutex
+		started, removed bool
+	)
+	wrapped := hook
+	if hook.OnStart != nil {
+		wrapped.OnStart = func(ctx context.Context) error {
+			mu.Lock()
+			started = true
+			skip := removed
+			mu.Unlock()
+			if skip {
+				return nil
+			}
+			return hook.OnStart(ctx)
+		}
+		if wrapped.onStartName == "" {
+			wrapped.onStartName = fxreflect.FuncName(hook.OnStart)
+		}
+	}
+	if hook.OnStop != nil {
+		wrapped.OnStop = func(ctx context.Context) error {
+			mu.Lock()
+			skip := removed
+			mu.Unlock()
+			if skip {
+				return nil
+			}
+			return hook.OnStop(ctx)
+		}
+		if wrapped.onStopName == "" {
+			wrapped.onStopName = fxreflect.FuncName(hook.OnStop)
+		}
+	}
+	wrapped.OnPause = skipRemoved(&mu, &removed, hook.OnPause)
+	wrapped.OnResume = skipRemoved(&mu, &removed, hook.OnResume)
+	if hook.OnPause != nil && wrapped.onPauseName == "" {
+		wrapped.onPauseName = fxreflect.FuncName(hook.OnPause)
+	}
+	if hook.OnResume != nil && wrapped.onResumeName == "" {
+		wrapped.onResumeName = fxreflect.FuncName(hook.OnResume)
+	}
+	lc.Append(wrapped)
+
+	return HookHandle{remove: func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if started || removed {
+			return false
+		}
+		removed = true
+		return true
+	}}
+}
+
+// wrapHook wraps the callbacks of h as requested by the app's options.
+func (app *App) wrapHook(h Hook) Hook {
+	if app.recoverHookPanics {
+		h = app.recoverHook(h)
+	}
+	if app.tracer != nil {
+		h = app.traceHook(h)
+	}
+	return h
+}
+
+// wrapHookFuncs returns a copy of h with its functions wrapped,
+// as described by fxhook.Appender.
+func wrapHookFuncs(h Hook, skip int, wrap func(kind, name string, fn fxhook.Func) fxhook.Func) Hook {
+	// Keep the caller found by an earlier wrapper, which is closer to
+	// the function that appended the hook.
+	if h.callerFrame == (fxreflect.Frame{}) {
+		if f := fxreflect.CallerStack(skip+1, 0); len(f) > 0 {
+			h.callerFrame = f[0]
+		}
+	}
+	wrapFunc := func(kind string, name *string, fn fxhook.Func) fxhook.Func {
+		if fn == nil {
+			return nil
+		}
+		if *name == "" {
+			*name = fxreflect.FuncName(fn)
+		}
+		return wrap(kind, *name, fn)
+	}
+	h.OnStart = wrapFunc(_onStartHook, &h.onStartName, h.OnStart)
+	h.OnStop = wrapFunc(_onStopHook, &h.onStopName, h.OnStop)
+	h.OnPause = wrapFunc(_onPauseHook, &h.onPauseName, h.OnPause)
+	h.OnResume = wrapFunc(_onResumeHook, &h.onResumeName, h.OnResume)
+	return h
+}
+
+// skipRemoved wraps fn to do nothing once *removed is set.
+func skipRemoved(mu *sync.Mutex, removed *bool, fn func(context.Context) error) func(context.Context) error {
+	if fn == nil {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		mu.Lock()
+		skip := *removed
+		mu.Unlock()
+		if skip {
+			return nil
+		}
+		return fn(ctx)
+	}
+}
+
 type lifecycleWrapper struct {
 	*lifecycle.Lifecycle
+
+	// onAppend, if set, is called with every hook appended.
+	onAppend func(Hook)
+
+	// wrap, if set, replaces every hook appended.
+	wrap func(Hook) Hook
+
+	// modules, if set, attributes hooks to the module appending them.
+	modules *runningModules
+
+	// logger and clock are used by hooks retried with fx.OnStartRetry.
+	logger fxevent.Logger
+	clock  fxclock.Clock
 }
 
 func (l *lifecycleWrapper) Append(h Hook) {
-	l.Lifecycle.Append(lifecycle.Hook{
-		OnStart:     h.OnStart,
-		OnStop:      h.OnStop,
-		OnStartName: h.onStartName,
-		OnStopName:  h.onStopName,
-	})
+	l.Lifecycle.Append(l.convert(h))
+}
+
+var _ fxhook.Appender = (*lifecycleWrapper)(nil)
+
+// AppendWrapped implements fxhook.Appender.
+func (l *lifecycleWrapper) AppendWrapped(hook any, skip int, wrap func(kind, name string, fn fxhook.Func) fxhook.Func) {
+	l.Append(wrapHookFuncs(hook.(Hook), skip+1, wrap))
+}
+
+// convert adapts an appended fx.Hook into a lifecycle.Hook.
+func (l *lifecycleWrapper) convert(h Hook) lifecycle.Hook {
+	if l.onAppend != nil {
+		l.onAppend(h)
+	}
+	if l.wrap != nil {
+		h = l.wrap(h)
+	}
+	return lifecycle.Hook{
+		OnStart:      h.OnStart,
+		OnStop:       h.OnStop,
+		OnPause:      h.OnPause,
+		OnResume:     h.OnResume,
+		OnStartName:  h.onStartName,
+		OnStopName:   h.onStopName,
+		OnPauseName:  h.onPauseName,
+		OnResumeName: h.onResumeName,
+		Module:       l.modules.current(),
+		CallerFrame:  h.callerFrame,
+	}
 }
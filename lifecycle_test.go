@@ -0,0 +1,300 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+func TestAppendHook(t *testing.T) {
+	t.Parallel()
+
+	t.Run("removed before start", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ran    []string
+			handle fx.HookHandle
+			spy    fxlog.Spy
+		)
+		app := fxtest.New(t,
+			fx.WithLogger(func() fxevent.Logger { return &spy }),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() { ran = append(ran, "kept") }))
+				handle = fx.AppendHook(lc, fx.StartStopHook(
+					func() { ran = append(ran, "removed start") },
+					func() { ran = append(ran, "removed stop") },
+				))
+			}),
+			fx.Invoke(func() {
+				assert.True(t, handle.Remove())
+				assert.False(t, handle.Remove(), "hook must only be removed once")
+			}),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{"kept"}, ran)
+		assert.Len(t, spy.Events().SelectByTypeName("OnStartExecuting"), 1)
+	})
+
+	t.Run("kept", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ran    []string
+			handle fx.HookHandle
+		)
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				handle = fx.AppendHook(lc, fx.StartStopHook(
+					func() { ran = append(ran, "start") },
+					func() { ran = append(ran, "stop") },
+				))
+			}),
+		)
+		app.RequireStart()
+		assert.False(t, handle.Remove(), "hook must not be removed once started")
+		app.RequireStop()
+		assert.False(t, handle.Remove(), "hook must not be removed once stopped")
+
+		assert.Equal(t, []string{"start", "stop"}, ran)
+	})
+
+	t.Run("other lifecycles", func(t *testing.T) {
+		t.Parallel()
+
+		var ran []string
+		lc := fxtest.NewLifecycle(t)
+		removed := fx.AppendHook(lc, fx.StartStopHook(
+			func() { ran = append(ran, "removed start") },
+			func() { ran = append(ran, "removed stop") },
+		))
+		kept := fx.AppendHook(lc, fx.StartStopHook(
+			func() { ran = append(ran, "kept start") },
+			func() { ran = append(ran, "kept stop") },
+		))
+
+		require.True(t, removed.Remove())
+		assert.False(t, removed.Remove(), "hook must only be removed once")
+		lc.RequireStart()
+		assert.False(t, kept.Remove(), "hook must not be removed once started")
+		lc.RequireStop()
+		assert.False(t, kept.Remove(), "hook must not be removed once stopped")
+
+		assert.Equal(t, []string{"kept start", "kept stop"}, ran)
+	})
+}
+
+func TestPauseResume(t *testing.T) {
+	t.Parallel()
+
+	t.Run("App", func(t *testing.T) {
+		t.Parallel()
+
+		var ran []string
+		record := func(s string) func() {
+			return func() { ran = append(ran, s) }
+		}
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(record("start a")))
+				lc.Append(fx.PauseHook(record("pause a")))
+				lc.Append(fx.Hook{
+					OnPause: func(context.Context) error {
+						ran = append(ran, "pause b")
+						return nil
+					},
+					OnResume: func(context.Context) error {
+						ran = append(ran, "resume b")
+						return nil
+					},
+				})
+				lc.Append(fx.ResumeHook(record("resume c")))
+				lc.Append(fx.StopHook(record("stop d")))
+			}),
+		)
+		app.RequireStart()
+
+		ctx := context.Background()
+		require.NoError(t, app.Pause(ctx))
+		assert.Equal(t, fx.AppPaused, app.State())
+		require.NoError(t, app.Resume(ctx))
+		assert.Equal(t, fx.AppStarted, app.State())
+		app.RequireStop()
+
+		assert.Equal(t, []string{
+			"start a",
+			"pause b", "pause a",
+			"resume b", "resume c",
+			"stop d",
+		}, ran)
+	})
+
+	t.Run("NotStarted", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t)
+		err := app.Pause(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "attempted to pause lifecycle when in state: stopped")
+		assert.Equal(t, fx.AppCreated, app.State())
+	})
+
+	t.Run("PauseError", func(t *testing.T) {
+		t.Parallel()
+
+		var resumed bool
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.PauseHook(func() error { return errors.New("great sadness") }))
+				lc.Append(fx.ResumeHook(func() { resumed = true }))
+			}),
+		)
+		app.RequireStart()
+		defer app.RequireStop()
+
+		err := app.Pause(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.True(t, resumed, "hooks paused before the failure must be resumed")
+		assert.Equal(t, fx.AppStarted, app.State())
+	})
+
+	t.Run("StopWhilePaused", func(t *testing.T) {
+		t.Parallel()
+
+		var stopped bool
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StopHook(func() { stopped = true }))
+			}),
+		)
+		app.RequireStart()
+		require.NoError(t, app.Pause(context.Background()))
+		app.RequireStop()
+		assert.True(t, stopped)
+	})
+
+	t.Run("fxtest.Lifecycle", func(t *testing.T) {
+		t.Parallel()
+
+		var ran []string
+		lc := fxtest.NewLifecycle(t)
+		lc.Append(fx.PauseHook(func() { ran = append(ran, "pause") }))
+		lc.Append(fx.ResumeHook(func() { ran = append(ran, "resume") }))
+		lc.RequireStart()
+		require.NoError(t, lc.Pause(context.Background()))
+		require.NoError(t, lc.Resume(context.Background()))
+		lc.RequireStop()
+
+		assert.Equal(t, []string{"pause", "resume"}, ran)
+	})
+}
+
+func TestHooksAppendedDuringStart(t *testing.T) {
+	t.Parallel()
+
+	var (
+		spy fxlog.Spy
+		ran []string
+	)
+	app := fxtest.New(t,
+		fx.WithLogger(func() fxevent.Logger { return &spy }),
+		fx.Invoke(func(lc fx.Lifecycle) {
+			lc.Append(fx.StartHook(func() {
+				ran = append(ran, "start")
+				lc.Append(fx.StartStopHook(
+					func() { ran = append(ran, "dynamic start") },
+					func() { ran = append(ran, "dynamic stop") },
+				))
+			}))
+		}),
+		fx.Invoke(func(lc fx.Lifecycle) {
+			lc.Append(fx.StopHook(func() { ran = append(ran, "stop") }))
+		}),
+	)
+	app.RequireStart().RequireStop()
+
+	assert.Equal(t, []string{"start", "dynamic start", "dynamic stop", "stop"}, ran)
+	assert.Len(t, spy.Events().SelectByTypeName("DynamicHookAppended"), 1)
+}
+
+func TestIsRollback(t *testing.T) {
+	t.Parallel()
+
+	// newApp builds an application with an OnStop hook
+	// that records whether it ran as part of a rollback,
+	// and a later OnStart hook that fails if failStart is set.
+	newApp := func(failStart bool, rollbacks *[]bool) *fx.App {
+		return fx.New(
+			fx.NopLogger,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error { return nil },
+					OnStop: func(ctx context.Context) error {
+						*rollbacks = append(*rollbacks, fx.IsRollback(ctx))
+						return nil
+					},
+				})
+				lc.Append(fx.StartHook(func() error {
+					if failStart {
+						return errors.New("great sadness")
+					}
+					return nil
+				}))
+			}),
+		)
+	}
+
+	t.Run("rollback", func(t *testing.T) {
+		t.Parallel()
+
+		var rollbacks []bool
+		app := newApp(true, &rollbacks)
+		require.Error(t, app.Start(context.Background()))
+		assert.Equal(t, []bool{true}, rollbacks)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		t.Parallel()
+
+		var rollbacks []bool
+		app := newApp(false, &rollbacks)
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+		assert.Equal(t, []bool{false}, rollbacks)
+	})
+
+	t.Run("background", func(t *testing.T) {
+		t.Parallel()
+
+		assert.False(t, fx.IsRollback(context.Background()))
+	})
+}
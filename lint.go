@@ -0,0 +1,219 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// _lintMaxParams is the number of parameters at which a constructor is
+// reported by fx.Lint.
+const _lintMaxParams = 10
+
+// Lint enables checks for suspicious patterns in the application.
+// Problems found are reported to the [fxevent.Logger] as
+// [fxevent.LintWarning] events, and do not fail the application.
+//
+// The following are reported:
+//
+//   - constructors that take 10 or more parameters ("too-many-params")
+//   - optional named parameters for which no value with that name is
+//     provided, which usually indicates a typo in the name
+//     ("optional-name-not-provided")
+//   - constructors that return structs holding a context.Context
+//     ("context-in-struct")
+//   - hooks appended to the Lifecycle after the application was built,
+//     for example, from inside another hook ("late-hook")
+//
+// More checks may be added in the future.
+func Lint() Option {
+	return lintOption{}
+}
+
+type lintOption struct{}
+
+func (o lintOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.Lint Option should be passed to top-level " +
+			"App, not to fx.Module")
+	} else {
+		m.app.linter = new(linter)
+	}
+}
+
+func (o lintOption) String() string {
+	return "fx.Lint()"
+}
+
+// linter holds the state for checks enabled by fx.Lint.
+type linter struct {
+	// Values provided to the container, as reported by dig.
+	outputs map[string]struct{}
+
+	// Constructors with optional named parameters.
+	optionals []lintOptional
+
+	// Set once the App has been built.
+	built atomic.Bool
+}
+
+type lintOptional struct {
+	funcName   string
+	moduleName string
+	input      string // as reported by dig, e.g. `T[optional, name = "foo"]`
+}
+
+// checkProvide checks a constructor after it was provided.
+func (l *linter) checkProvide(m *module, funcName string, p provide, info dig.ProvideInfo) {
+	if l.outputs == nil {
+		l.outputs = make(map[string]struct{})
+	}
+	for _, o := range info.Outputs {
+		l.outputs[o.String()] = struct{}{}
+	}
+
+	if n := len(info.Inputs); n >= _lintMaxParams {
+		m.log.LogEvent(&fxevent.LintWarning{
+			Rule:         "too-many-params",
+			Message:      fmt.Sprintf("%v has %d parameters; consider splitting it up", funcName, n),
+			FunctionName: funcName,
+			ModuleName:   m.name,
+		})
+	}
+
+	for _, in := range info.Inputs {
+		s := in.String()
+		if strings.Contains(s, "[optional, name = ") {
+			l.optionals = append(l.optionals, lintOptional{
+				funcName:   funcName,
+				moduleName: m.name,
+				input:      s,
+			})
+		}
+	}
+
+	if t, ok := lintFuncType(p.Target); ok {
+		for i := 0; i < t.NumOut(); i++ {
+			if name, ok := structWithContext(t.Out(i)); ok {
+				m.log.LogEvent(&fxevent.LintWarning{
+					Rule: "context-in-struct",
+					Message: fmt.Sprintf("%v returns %v which stores a context.Context; "+
+						"pass contexts to functions instead", funcName, name),
+					FunctionName: funcName,
+					ModuleName:   m.name,
+				})
+			}
+		}
+	}
+}
+
+// checkOptionals reports optional named parameters for which no value
+// was provided. This must be called after all constructors were provided.
+func (l *linter) checkOptionals(log fxevent.Logger) {
+	for _, o := range l.optionals {
+		want := strings.Replace(o.input, "optional, ", "", 1)
+		if _, ok := l.outputs[want]; ok {
+			continue
+		}
+		log.LogEvent(&fxevent.LintWarning{
+			Rule: "optional-name-not-provided",
+			Message: fmt.Sprintf("%v depends on optional %v but no such value is provided; "+
+				"check the name for typos", o.funcName, want),
+			FunctionName: o.funcName,
+			ModuleName:   o.moduleName,
+		})
+	}
+}
+
+// checkHook checks a hook appended to the application's Lifecycle.
+func (l *linter) checkHook(log fxevent.Logger, h Hook) {
+	if !l.built.Load() {
+		return
+	}
+
+	name := h.onStartName
+	switch {
+	case name != "":
+	case h.OnStart != nil:
+		name = fxreflect.FuncName(h.OnStart)
+	case h.onStopName != "":
+		name = h.onStopName
+	case h.OnStop != nil:
+		name = fxreflect.FuncName(h.OnStop)
+	}
+	log.LogEvent(&fxevent.LintWarning{
+		Rule: "late-hook",
+		Message: fmt.Sprintf("hook %v was appended after the application was built; "+
+			"append hooks from constructors or invoked functions instead", name),
+		FunctionName: name,
+	})
+}
+
+// lintFuncType returns the type of the user-provided constructor function
+// behind target.
+func lintFuncType(target interface{}) (reflect.Type, bool) {
+	switch c := target.(type) {
+	case annotated:
+		target = c.Target
+	case Annotated:
+		target = c.Target
+	}
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Func {
+		return nil, false
+	}
+	return t, true
+}
+
+// structWithContext reports whether t, or a struct pointed to by t, has a
+// context.Context field. For fx.Out structs, their fields are checked
+// instead.
+func structWithContext(t reflect.Type) (string, bool) {
+	if isOut(t) {
+		for i := 1; i < t.NumField(); i++ {
+			if name, ok := structWithContext(t.Field(i).Type); ok {
+				return name, true
+			}
+		}
+		return "", false
+	}
+
+	st := t
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		return "", false
+	}
+	for i := 0; i < st.NumField(); i++ {
+		if st.Field(i).Type == _typeOfContext {
+			return t.String(), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+
+	lintWarnings := func(spy *fxlog.Spy) []*fxevent.LintWarning {
+		var warnings []*fxevent.LintWarning
+		for _, e := range spy.Events().SelectByTypeName("LintWarning") {
+			warnings = append(warnings, e.(*fxevent.LintWarning))
+		}
+		return warnings
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		app := fxtest.New(t,
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.Provide(func(int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64) *A {
+				return &A{}
+			}),
+		)
+		require.NoError(t, app.Err())
+		assert.Empty(t, lintWarnings(spy))
+	})
+
+	t.Run("too many params", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		app := fxtest.New(t,
+			fx.Lint(),
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.Module("mod",
+				fx.Provide(func(int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64) *A {
+					return &A{}
+				}),
+				fx.Provide(func(int, int8) string { return "" }),
+			),
+		)
+		require.NoError(t, app.Err())
+
+		warnings := lintWarnings(spy)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, "too-many-params", warnings[0].Rule)
+		assert.Equal(t, "mod", warnings[0].ModuleName)
+		assert.Contains(t, warnings[0].FunctionName, "TestLint")
+		assert.Contains(t, warnings[0].Message, "has 10 parameters")
+	})
+
+	t.Run("optional name not provided", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			fx.In
+
+			Found   string `name:"found" optional:"true"`
+			Missing string `name:"mising" optional:"true"`
+		}
+
+		spy := new(fxlog.Spy)
+		app := fxtest.New(t,
+			fx.Lint(),
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.Provide(
+				func(params) *A { return &A{} },
+				fx.Annotate(func() string { return "" }, fx.ResultTags(`name:"found"`)),
+			),
+		)
+		require.NoError(t, app.Err())
+
+		warnings := lintWarnings(spy)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, "optional-name-not-provided", warnings[0].Rule)
+		assert.Contains(t, warnings[0].Message, `string[name = "mising"]`)
+	})
+
+	t.Run("context in struct", func(t *testing.T) {
+		t.Parallel()
+
+		type withContext struct {
+			ctx context.Context
+		}
+
+		type out struct {
+			fx.Out
+
+			W *withContext `name:"w"`
+		}
+
+		spy := new(fxlog.Spy)
+		app := fxtest.New(t,
+			fx.Lint(),
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.Provide(
+				func() *withContext { return &withContext{ctx: context.Background()} },
+				func() out { return out{} },
+			),
+			fx.Provide(func() A { return A{} }),
+		)
+		require.NoError(t, app.Err())
+
+		warnings := lintWarnings(spy)
+		require.Len(t, warnings, 2)
+		for _, w := range warnings {
+			assert.Equal(t, "context-in-struct", w.Rule)
+			assert.Contains(t, w.Message, "returns *fx_test.withContext")
+		}
+	})
+
+	t.Run("late hook", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		app := fxtest.New(t,
+			fx.Lint(),
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() {
+					lc.Append(fx.StopHook(func() {}))
+				}))
+			}),
+		)
+		require.NoError(t, app.Err())
+		assert.Empty(t, lintWarnings(spy), "hooks appended from invokes are fine")
+
+		app.RequireStart().RequireStop()
+
+		warnings := lintWarnings(spy)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, "late-hook", warnings[0].Rule)
+		assert.Contains(t, warnings[0].FunctionName, "TestLint")
+	})
+
+	t.Run("only top-level", func(t *testing.T) {
+		t.Parallel()
+
+		err := fx.New(fx.NopLogger, fx.Module("mod", fx.Lint())).Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.Lint Option should be passed to top-level App")
+	})
+}
@@ -29,10 +29,14 @@ import (
 type logBuffer struct {
 	events []fxevent.Event
 	logger fxevent.Logger
+	stamp  func(fxevent.Event) // stamps events as they're buffered, if set
 }
 
 // LogEvent buffers or logs an event.
 func (l *logBuffer) LogEvent(event fxevent.Event) {
+	if l.stamp != nil {
+		l.stamp(event)
+	}
 	if l.logger == nil {
 		l.events = append(l.events, event)
 	} else {
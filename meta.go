@@ -0,0 +1,182 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Meta is an option that can be passed as an argument to [Provide] or
+// [Supply] to attach metadata to the constructors being provided,
+// such as the team that owns them:
+//
+//	fx.Provide(NewLedger, NewBiller, fx.Meta("owner", "team-payments"))
+//
+// Fx doesn't interpret metadata. It travels with the constructors,
+// and is reported in the errors that involve them,
+// such as missing dependencies and dependency cycles:
+//
+//	missing dependencies for function "example.com/payments".NewLedger (...): missing type: *sql.DB
+//		example.com/payments.NewLedger() in module "payments": provided by owner=team-payments
+//
+// These errors may be inspected with errors.As, e.g. in an [ErrorHook],
+// to retrieve the metadata; see [ProviderMetaError].
+// Metadata is also reported in the [ProviderInfo] of a
+// [DuplicateProvideError].
+//
+// Meta may be passed several times to attach several keys.
+// A later value for a key replaces an earlier one.
+func Meta(key, value string) interface{} {
+	return metaOption{key: key, value: value}
+}
+
+type metaOption struct {
+	key, value string
+}
+
+// addMeta adds the metadata set by o to meta,
+// creating it if it's nil.
+func (o metaOption) addMeta(meta map[string]string) map[string]string {
+	if meta == nil {
+		meta = make(map[string]string)
+	}
+	meta[o.key] = o.value
+	return meta
+}
+
+// formatMeta formats metadata as space-separated key=value pairs,
+// sorted by key.
+func formatMeta(meta map[string]string) string {
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%v=%v", k, meta[k])
+	}
+	return strings.Join(pairs, " ")
+}
+
+// ProviderMetaError is the error an application fails with
+// when an error reported by the container involves constructors
+// that were provided with [Meta].
+// It reports their metadata after the original error.
+// Use errors.As to inspect it:
+//
+//	var metaErr *fx.ProviderMetaError
+//	if errors.As(app.Err(), &metaErr) {
+//		for _, p := range metaErr.Providers {
+//			log.Printf("%v is owned by %v", p.ConstructorName, p.Meta["owner"])
+//		}
+//	}
+type ProviderMetaError struct {
+	// Providers are the constructors with metadata
+	// that the error involves, in the order they were provided.
+	Providers []ProviderInfo
+
+	err error
+}
+
+func (e *ProviderMetaError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.err.Error())
+	for _, p := range e.Providers {
+		fmt.Fprintf(&sb, "\n\t%v: provided by %v", p.describe(), formatMeta(p.Meta))
+	}
+	return sb.String()
+}
+
+// Unwrap returns the original error.
+func (e *ProviderMetaError) Unwrap() error {
+	return e.err
+}
+
+// metaProvider is a constructor provided with metadata,
+// along with where the container reports it to be.
+type metaProvider struct {
+	info     ProviderInfo
+	location string // as in "(path/to/file.go:42)"
+}
+
+// recordMeta records the constructor p provided by m under the given name
+// if it has metadata, so that errors that involve it report its metadata.
+func (m *module) recordMeta(name string, p provide) {
+	if len(p.Meta) == 0 {
+		return
+	}
+	pc := providePC(p.Target)
+	if p.FuncPtr != 0 {
+		pc = p.FuncPtr
+	}
+	f := runtime.FuncForPC(pc)
+	if f == nil {
+		return
+	}
+
+	// Match the format of the locations reported by dig.
+	file, line := f.FileLine(pc)
+	m.app.metaProviders = append(m.app.metaProviders, metaProvider{
+		info:     m.providerInfo(name, p),
+		location: fmt.Sprintf("(%v:%v)", file, line),
+	})
+}
+
+// providePC returns the entry of the function that the container reports
+// as the location of target, or 0 if it isn't a function.
+func providePC(target interface{}) uintptr {
+	switch t := target.(type) {
+	case annotated:
+		return providePC(t.Target)
+	case Annotated:
+		return providePC(t.Target)
+	}
+	if v := reflect.ValueOf(target); v.Kind() == reflect.Func {
+		return v.Pointer()
+	}
+	return 0
+}
+
+// withMeta wraps err in a ProviderMetaError
+// if it involves constructors provided with metadata.
+func (app *App) withMeta(err error) error {
+	if err == nil || len(app.metaProviders) == 0 {
+		return err
+	}
+
+	msg := err.Error()
+	var providers []ProviderInfo
+	for _, mp := range app.metaProviders {
+		if strings.Contains(msg, mp.location) {
+			providers = append(providers, mp.info)
+		}
+	}
+	if len(providers) == 0 {
+		return err
+	}
+	return &ProviderMetaError{Providers: providers, err: err}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type metaErrorHook struct{ err error }
+
+func (h *metaErrorHook) HandleError(err error) { h.err = err }
+
+func TestMeta(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type Missing struct{}
+
+	t.Run("missing dependency", func(t *testing.T) {
+		t.Parallel()
+
+		hook := new(metaErrorHook)
+		app := fx.New(
+			fx.NopLogger,
+			fx.ErrorHook(hook),
+			fx.Module("payments",
+				fx.Provide(
+					func(*Missing) *A { return &A{} },
+					fx.Meta("owner", "team-payments"),
+					fx.Meta("oncall", "payments-oncall"),
+				),
+			),
+			fx.Provide(func() *B { return &B{} }, fx.Meta("owner", "team-other")),
+			fx.Invoke(func(*A, *B) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *fx_test.Missing")
+		assert.Contains(t, err.Error(),
+			`in module "payments": provided by oncall=payments-oncall owner=team-payments`)
+		assert.NotContains(t, err.Error(), "team-other")
+
+		var metaErr *fx.ProviderMetaError
+		require.True(t, errors.As(hook.err, &metaErr), "ErrorHook should receive the metadata")
+		require.Len(t, metaErr.Providers, 1)
+		assert.Equal(t, "payments", metaErr.Providers[0].ModuleName)
+		assert.Equal(t, map[string]string{
+			"owner":  "team-payments",
+			"oncall": "payments-oncall",
+		}, metaErr.Providers[0].Meta)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(func(*B) *A { return &A{} }, fx.Meta("owner", "team-a")),
+			fx.Provide(func(*A) *B { return &B{} }, fx.Meta("owner", "team-b")),
+			fx.Invoke(func(*A) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cycle detected")
+		assert.Contains(t, err.Error(), "provided by owner=team-a")
+		assert.Contains(t, err.Error(), "provided by owner=team-b")
+	})
+
+	t.Run("annotated", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(
+				fx.Annotate(func(*Missing) *A { return &A{} }, fx.ResultTags(`name:"a"`)),
+				fx.Meta("owner", "team-payments"),
+			),
+			fx.Invoke(fx.Annotate(func(*A) {}, fx.ParamTags(`name:"a"`))),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "provided by owner=team-payments")
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(func() *A { return &A{} }, fx.Meta("owner", "team-a")),
+			fx.Provide(func() *A { return &A{} }),
+		)
+		var dup *fx.DuplicateProvideError
+		require.True(t, errors.As(app.Err(), &dup))
+		assert.Equal(t, map[string]string{"owner": "team-a"}, dup.First.Meta)
+		assert.Nil(t, dup.Second.Meta)
+	})
+
+	t.Run("supply", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Supply(&A{}, fx.Meta("owner", "team-a")),
+			fx.Supply(&A{}),
+		)
+		var dup *fx.DuplicateProvideError
+		require.True(t, errors.As(app.Err(), &dup))
+		assert.Equal(t, map[string]string{"owner": "team-a"}, dup.First.Meta)
+	})
+
+	t.Run("no metadata", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(func(*Missing) *A { return &A{} }),
+			fx.Invoke(func(*A) {}),
+		)
+		var metaErr *fx.ProviderMetaError
+		assert.False(t, errors.As(app.Err(), &metaErr))
+	})
+
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+
+		opt := fx.Provide(fx.Meta("owner", "team-a"))
+		assert.Equal(t, `fx.Provide(fx.Meta("owner", "team-a"))`, opt.String())
+	})
+}
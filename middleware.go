@@ -0,0 +1,212 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// ConstructorInfo describes a constructor called by Fx,
+// for [ConstructorMiddleware].
+type ConstructorInfo struct {
+	// FunctionName is the name of the constructor.
+	FunctionName string
+
+	// ModuleName is the name of the module that provided the constructor,
+	// if any.
+	ModuleName string
+
+	// Results are the types of the non-error results of the constructor.
+	Results []reflect.Type
+}
+
+// ConstructorCall calls a constructor with its arguments,
+// and returns its non-error results or its error.
+type ConstructorCall func(info ConstructorInfo, args []interface{}) ([]interface{}, error)
+
+// ConstructorMiddleware wraps the calls to constructors, and may run code
+// before and after them, replace their results, or fail them.
+// It calls next to call the constructor, or the next middleware.
+//
+//	func TimeConstructors(next fx.ConstructorCall) fx.ConstructorCall {
+//		return func(info fx.ConstructorInfo, args []interface{}) ([]interface{}, error) {
+//			start := time.Now()
+//			defer func() {
+//				metrics.Observe(info.FunctionName, time.Since(start))
+//			}()
+//			return next(info, args)
+//		}
+//	}
+//
+// Results returned by a middleware must be assignable to the corresponding
+// [ConstructorInfo.Results]. A nil result stands for the zero value.
+type ConstructorMiddleware func(next ConstructorCall) ConstructorCall
+
+// WithConstructorMiddleware wraps the calls to all constructors provided to
+// the application in the given middleware, for cross-cutting behavior such
+// as metrics, caching, or injecting construction failures in tests.
+//
+//	fx.New(
+//		fx.WithConstructorMiddleware(TimeConstructors),
+//		...
+//	)
+//
+// Middleware applies in the order given: the first middleware is the
+// outermost one. WithConstructorMiddleware may be passed more than once,
+// adding to the middleware passed before.
+// Constructors that fail through a middleware but don't return an error
+// fail the application as if they did.
+//
+// WithConstructorMiddleware is an advanced option: it applies to
+// constructors passed to [Provide] and its variants, not to decorators,
+// invoked functions, values passed to [Supply],
+// or the types that Fx provides itself, such as [Lifecycle].
+//
+// WithConstructorMiddleware may only be passed to [New].
+func WithConstructorMiddleware(middleware ...ConstructorMiddleware) Option {
+	return constructorMiddlewareOption(middleware)
+}
+
+type constructorMiddlewareOption []ConstructorMiddleware
+
+func (o constructorMiddlewareOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.WithConstructorMiddleware Option should be passed to top-level " +
+			"App, not to fx.Module")
+	} else {
+		m.app.constructorMiddleware = append(m.app.constructorMiddleware, o...)
+	}
+}
+
+func (o constructorMiddlewareOption) String() string {
+	items := make([]string, len(o))
+	for i, mw := range o {
+		items[i] = fxreflect.FuncName(mw)
+	}
+	return fmt.Sprintf("fx.WithConstructorMiddleware(%s)", strings.Join(items, ", "))
+}
+
+// middlewareContainer is a container whose constructors are called
+// through constructor middleware.
+type middlewareContainer struct {
+	container
+
+	middleware []ConstructorMiddleware
+	info       ConstructorInfo // FunctionName and ModuleName of the constructor
+}
+
+var _ container = middlewareContainer{}
+
+func (c middlewareContainer) Provide(constructor interface{}, opts ...dig.ProvideOption) error {
+	// Report the failure through an error result,
+	// adding one if the constructor doesn't have one.
+	return provideWrapped(c.container, constructor, opts, true, func(ft reflect.Type, call constructorFunc) constructorFunc {
+		in := make([]reflect.Type, ft.NumIn())
+		for i := range in {
+			in[i] = ft.In(i)
+		}
+		out := make([]reflect.Type, ft.NumOut()-1) // without the error
+		for i := range out {
+			out[i] = ft.Out(i)
+		}
+
+		info := c.info
+		info.Results = out
+
+		var next ConstructorCall = func(_ ConstructorInfo, args []interface{}) ([]interface{}, error) {
+			if len(args) != len(in) {
+				return nil, fmt.Errorf("constructor middleware passed %d arguments to %v, which takes %d",
+					len(args), info.FunctionName, len(in))
+			}
+			argVals := make([]reflect.Value, len(args))
+			for i, arg := range args {
+				if arg != nil && !reflect.TypeOf(arg).AssignableTo(in[i]) {
+					return nil, fmt.Errorf("constructor middleware passed %T as argument %d of %v, which is a %v",
+						arg, i, info.FunctionName, in[i])
+				}
+				argVals[i] = valueOf(in[i], arg)
+			}
+			resultVals := call(argVals)
+			if err, _ := resultVals[len(resultVals)-1].Interface().(error); err != nil {
+				return nil, err
+			}
+			resultVals = resultVals[:len(resultVals)-1]
+			results := make([]interface{}, len(resultVals))
+			for i, v := range resultVals {
+				results[i] = v.Interface()
+			}
+			return results, nil
+		}
+		for i := len(c.middleware) - 1; i >= 0; i-- {
+			next = c.middleware[i](next)
+		}
+
+		return func(args []reflect.Value) []reflect.Value {
+			fail := func(err error) []reflect.Value {
+				results := make([]reflect.Value, len(out)+1)
+				for i, t := range out {
+					results[i] = reflect.Zero(t)
+				}
+				results[len(out)] = reflect.ValueOf(&err).Elem()
+				return results
+			}
+
+			argVals := make([]interface{}, len(args))
+			for i, arg := range args {
+				argVals[i] = arg.Interface()
+			}
+			results, err := next(info, argVals)
+			if err != nil {
+				return fail(err)
+			}
+			if len(results) != len(out) {
+				return fail(fmt.Errorf("constructor middleware returned %d results for %v, which has %d",
+					len(results), info.FunctionName, len(out)))
+			}
+
+			resultVals := make([]reflect.Value, len(out)+1)
+			for i, t := range out {
+				if r := results[i]; r != nil && !reflect.TypeOf(r).AssignableTo(t) {
+					return fail(fmt.Errorf("constructor middleware returned %T as result %d of %v, which is a %v",
+						r, i, info.FunctionName, t))
+				}
+				resultVals[i] = valueOf(t, results[i])
+			}
+			resultVals[len(out)] = _nilError
+			return resultVals
+		}
+	})
+}
+
+// valueOf returns v as a value of type t,
+// or the zero value of t if v is nil.
+func valueOf(t reflect.Type, v interface{}) reflect.Value {
+	val := reflect.New(t).Elem()
+	if v != nil {
+		val.Set(reflect.ValueOf(v))
+	}
+	return val
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestWithConstructorMiddleware(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ name string }
+	type B struct{ a *A }
+
+	newA := func() *A { return &A{name: "real"} }
+	newB := func(a *A) (*B, error) { return &B{a: a}, nil }
+
+	t.Run("order", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		record := func(name string) fx.ConstructorMiddleware {
+			return func(next fx.ConstructorCall) fx.ConstructorCall {
+				return func(info fx.ConstructorInfo, args []interface{}) ([]interface{}, error) {
+					calls = append(calls, name+" "+info.ModuleName)
+					return next(info, args)
+				}
+			}
+		}
+
+		var b *B
+		app := fxtest.New(t,
+			fx.WithConstructorMiddleware(record("outer"), record("inner")),
+			fx.Module("mod", fx.Provide(newA)),
+			fx.Provide(newB),
+			fx.Populate(&b),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "real", b.a.name)
+		// Dependencies are built before the constructors that need them.
+		assert.Equal(t, []string{"outer mod", "inner mod", "outer ", "inner "}, calls)
+	})
+
+	t.Run("info", func(t *testing.T) {
+		t.Parallel()
+
+		var infos []fx.ConstructorInfo
+		app := fxtest.New(t,
+			fx.WithConstructorMiddleware(func(next fx.ConstructorCall) fx.ConstructorCall {
+				return func(info fx.ConstructorInfo, args []interface{}) ([]interface{}, error) {
+					infos = append(infos, info)
+					return next(info, args)
+				}
+			}),
+			fx.Provide(newA, newB),
+			fx.Invoke(func(*B) {}),
+		)
+		defer app.RequireStart().RequireStop()
+
+		require.Len(t, infos, 2)
+		assert.Contains(t, infos[0].FunctionName, "TestWithConstructorMiddleware")
+		assert.Equal(t, []reflect.Type{reflect.TypeOf(&A{})}, infos[0].Results)
+		assert.Equal(t, []reflect.Type{reflect.TypeOf(&B{})}, infos[1].Results)
+	})
+
+	t.Run("replace results", func(t *testing.T) {
+		t.Parallel()
+
+		var b *B
+		app := fxtest.New(t,
+			fx.WithConstructorMiddleware(func(next fx.ConstructorCall) fx.ConstructorCall {
+				return func(info fx.ConstructorInfo, args []interface{}) ([]interface{}, error) {
+					if info.Results[0] == reflect.TypeOf(&A{}) {
+						return []interface{}{&A{name: "fake"}}, nil
+					}
+					return next(info, args)
+				}
+			}),
+			fx.Provide(newA, newB),
+			fx.Populate(&b),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "fake", b.a.name)
+	})
+
+	t.Run("inject failure", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.WithConstructorMiddleware(func(fx.ConstructorCall) fx.ConstructorCall {
+				return func(fx.ConstructorInfo, []interface{}) ([]interface{}, error) {
+					return nil, errors.New("great sadness")
+				}
+			}),
+			fx.Provide(newA),
+			fx.Invoke(func(*A) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+	})
+
+	t.Run("wrong result type", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.WithConstructorMiddleware(func(fx.ConstructorCall) fx.ConstructorCall {
+				return func(fx.ConstructorInfo, []interface{}) ([]interface{}, error) {
+					return []interface{}{"not an A"}, nil
+				}
+			}),
+			fx.Provide(newA),
+			fx.Invoke(func(*A) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "constructor middleware returned string as result 0")
+	})
+
+	t.Run("nil result", func(t *testing.T) {
+		t.Parallel()
+
+		var a *A
+		app := fxtest.New(t,
+			fx.WithConstructorMiddleware(func(fx.ConstructorCall) fx.ConstructorCall {
+				return func(fx.ConstructorInfo, []interface{}) ([]interface{}, error) {
+					return []interface{}{nil}, nil
+				}
+			}),
+			fx.Provide(newA),
+			fx.Populate(&a),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Nil(t, a)
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Module("mod", fx.WithConstructorMiddleware()),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.WithConstructorMiddleware Option should be passed to top-level App")
+	})
+}
@@ -21,11 +21,15 @@
 package fx
 
 import (
+	"flag"
 	"fmt"
+	"reflect"
+	"sort"
 
 	"go.uber.org/dig"
 	"go.uber.org/fx/fxevent"
 	"go.uber.org/fx/internal/fxreflect"
+	"go.uber.org/fx/internal/lifecycle"
 	"go.uber.org/multierr"
 )
 
@@ -40,6 +44,59 @@ type container interface {
 	Decorate(interface{}, ...dig.DecorateOption) error
 }
 
+// constructorFunc is the implementation of a function built with
+// reflect.MakeFunc.
+type constructorFunc = func(args []reflect.Value) []reflect.Value
+
+// provideWrapped provides constructor to c through a function of the same
+// type, implemented by wrap. wrap receives the type of that function and
+// a function calling constructor, variadic or not.
+//
+// If withError is set, the function ends with an error result, which is
+// added if constructor doesn't have one: call then returns a nil error.
+//
+// dig reports the function at the location of constructor,
+// unless opts set the location themselves (e.g. for fx.Annotate).
+// Values that aren't functions are provided as-is for dig to reject.
+func provideWrapped(
+	c container,
+	constructor interface{},
+	opts []dig.ProvideOption,
+	withError bool,
+	wrap func(ft reflect.Type, call constructorFunc) constructorFunc,
+) error {
+	fn := reflect.ValueOf(constructor)
+	if fn.Kind() != reflect.Func {
+		return c.Provide(constructor, opts...)
+	}
+
+	ft := fn.Type()
+	call := fn.Call
+	if ft.IsVariadic() {
+		call = fn.CallSlice
+	}
+	if n := ft.NumOut(); withError && (n == 0 || ft.Out(n-1) != _typeOfError) {
+		in := make([]reflect.Type, ft.NumIn())
+		for i := range in {
+			in[i] = ft.In(i)
+		}
+		out := make([]reflect.Type, n, n+1)
+		for i := range out {
+			out[i] = ft.Out(i)
+		}
+		ft = reflect.FuncOf(in, append(out, _typeOfError), ft.IsVariadic())
+
+		callFn := call
+		call = func(args []reflect.Value) []reflect.Value {
+			return append(callFn(args), _nilError)
+		}
+	}
+
+	wrapped := reflect.MakeFunc(ft, wrap(ft, call))
+	opts = append([]dig.ProvideOption{dig.LocationForPC(fn.Pointer())}, opts...)
+	return c.Provide(wrapped.Interface(), opts...)
+}
+
 // Module is a named group of zero or more fx.Options.
 //
 // A Module scopes the effect of certain operations to within the module.
@@ -84,13 +141,46 @@ func Module(name string, opts ...Option) Option {
 	return mo
 }
 
+// TestOnlyModule is a [Module] that may only be used in tests.
+// Use it for modules of fakes and stubs
+// to prevent them from accidentally shipping in production binaries.
+//
+//	var FakeStorageModule = fx.TestOnlyModule("fakestorage",
+//		fx.Provide(NewFakeStorage),
+//	)
+//
+// Applications that include a test-only module
+// fail to build with an error
+// unless the binary is running under "go test".
+func TestOnlyModule(name string, opts ...Option) Option {
+	return moduleOption{
+		name:     name,
+		location: fxreflect.CallerStack(1, 2)[0],
+		options:  opts,
+		testOnly: true,
+	}
+}
+
+// isTesting reports whether the binary is running under "go test".
+// Tests override it to check test-only modules.
+//
+// Importing package testing would link it into every binary that uses Fx,
+// so this relies on the flags that the testing package registers instead.
+var isTesting = func() bool {
+	return flag.Lookup("test.v") != nil
+}
+
 type moduleOption struct {
 	name     string
 	location fxreflect.Frame
 	options  []Option
+	testOnly bool // whether this is an fx.TestOnlyModule
 }
 
 func (o moduleOption) String() string {
+	if o.testOnly {
+		return fmt.Sprintf("fx.TestOnlyModule(%q, %v)", o.name, o.options)
+	}
 	return fmt.Sprintf("fx.Module(%q, %v)", o.name, o.options)
 }
 
@@ -98,6 +188,11 @@ func (o moduleOption) apply(mod *module) {
 	// This get called on any submodules' that are declared
 	// as part of another module.
 
+	if o.testOnly && !isTesting() {
+		mod.app.err = fmt.Errorf("fx.TestOnlyModule(%q) from %v can only be used in tests", o.name, o.location)
+		return
+	}
+
 	// 1. Create a new module with the parent being the specified
 	// module.
 	// 2. Apply child Options on the new module.
@@ -106,30 +201,46 @@ func (o moduleOption) apply(mod *module) {
 	// Create trace as parent's trace with this module's location pre-pended.
 	trace := append([]string{fmt.Sprintf("%v (%v)", o.location, o.name)}, mod.trace...)
 	newModule := &module{
-		name:   o.name,
-		parent: mod,
-		trace:  trace,
-		app:    mod.app,
+		name:     o.name,
+		location: o.location,
+		parent:   mod,
+		trace:    trace,
+		app:      mod.app,
 	}
 	for _, opt := range o.options {
 		opt.apply(newModule)
 	}
 	mod.modules = append(mod.modules, newModule)
+	mod.app.namedModules = append(mod.app.namedModules, newModule)
 }
 
 type module struct {
 	parent         *module
 	name           string
+	location       fxreflect.Frame // where fx.Module was called; unset for the root module
+	version        string          // set by fx.ModuleVersion
 	trace          []string
 	scope          scope
 	provides       []provide
 	invokes        []invoke
 	decorators     []decorator
+	conditionals   []conditional
+	scopeOptions   []dig.ScopeOption
 	modules        []*module
 	app            *App
 	log            fxevent.Logger
 	fallbackLogger fxevent.Logger
 	logConstructor *provide
+	logDecorators  []func(fxevent.Logger) fxevent.Logger
+	duplicates     []onDuplicateOption
+	decorated      map[string]string // type name => decorator name
+
+	// Identifies the module to the lifecycle, which reports when all hooks
+	// appended by the module ran. Nil for the root module.
+	lifecycleModule *lifecycle.Module
+	// Whether the module reports its hooks as its parent's,
+	// for the modules created by fx.InvokeWith.
+	inheritsHooks bool
 }
 
 // scope is a private wrapper interface for dig.Container and dig.Scope.
@@ -152,22 +263,36 @@ func (m *module) build(app *App, root *dig.Container) {
 		m.scope = root
 	} else {
 		parentScope := m.parent.scope
-		m.scope = parentScope.Scope(m.name)
+		m.scope = parentScope.Scope(m.name, m.scopeOptions...)
 		// use parent module's logger by default
 		m.log = m.parent.log
 	}
 
-	if m.logConstructor != nil {
+	if m.logConstructor != nil || len(m.logDecorators) > 0 {
 		// Since user supplied a custom logger, use a buffered logger
 		// to hold all messages until user supplied logger is
 		// instantiated. Then we flush those messages after fully
 		// constructing the custom logger.
-		m.fallbackLogger, m.log = m.log, new(logBuffer)
+		m.fallbackLogger, m.log = m.log, &logBuffer{stamp: app.stampEvent}
+	}
+
+	switch {
+	case m.parent == nil:
+	case m.inheritsHooks:
+		m.lifecycleModule = m.parent.lifecycleModule
+	default:
+		m.lifecycleModule = &lifecycle.Module{Name: m.name, Parent: m.parent.lifecycleModule}
 	}
 
 	for _, mod := range m.modules {
 		mod.build(app, root)
 	}
+
+	// Register modules after the modules they contain,
+	// which are reported first.
+	if m.parent != nil && !m.inheritsHooks {
+		app.lifecycle.AddModule(m.lifecycleModule)
+	}
 }
 
 func (m *module) provideAll() {
@@ -181,7 +306,7 @@ func (m *module) provideAll() {
 }
 
 func (m *module) provide(p provide) {
-	if m.app.err != nil {
+	if m.app.err != nil && (p.Bulk == nil || m.app.err != p.Bulk.err) {
 		return
 	}
 
@@ -190,29 +315,138 @@ func (m *module) provide(p provide) {
 		return
 	}
 
-	funcName := fxreflect.FuncName(p.Target)
-	var info dig.ProvideInfo
+	funcName := p.Name
+	if funcName == "" {
+		funcName = fxreflect.FuncName(p.Target)
+	}
+	var (
+		info   dig.ProvideInfo
+		demand *demandNode // set once provided if recording demand paths
+
+		// set once provided if contributing to groups
+		// passed to fx.RequireGroupConsumed
+		contributors []*groupContributor
+	)
 	opts := []dig.ProvideOption{
 		dig.FillProvideInfo(&info),
 		dig.Export(!p.Private),
 		dig.WithProviderCallback(func(ci dig.CallbackInfo) {
+			m.app.constructorsRun.Add(1)
+			for _, c := range contributors {
+				c.ran.Store(true)
+			}
+			var demandPath []string
+			if m.app.demand != nil {
+				demandPath = m.app.demand.Path(demand)
+			}
 			m.log.LogEvent(&fxevent.Run{
 				Name:       funcName,
 				Kind:       "provide",
 				ModuleName: m.name,
 				Runtime:    ci.Runtime,
 				Err:        ci.Error,
+				DemandPath: demandPath,
 			})
+			m.app.traceRun("fx.Provide", ci.Runtime, ci.Error, m.spanAttributes(funcName)...)
 		}),
 	}
 
-	if err := runProvide(m.scope, p, opts...); err != nil {
+	if p.FuncPtr != 0 {
+		opts = append(opts, dig.LocationForPC(p.FuncPtr))
+	}
+
+	var c container = m.scope
+	if m.app.hasModules {
+		c = moduleContainer{container: c, module: m}
+	}
+	if len(m.app.lazyGroupTypes) > 0 {
+		lc := lazyGroupContainer{container: c, export: !p.Private, types: m.app.lazyGroupTypes}
+		if ann, ok := p.Target.(Annotated); ok {
+			lc.group = ann.Group
+		}
+		c = lc
+	}
+	if m.app.profileLabels {
+		c = labeledContainer{container: c, labels: constructorLabels(m, funcName)}
+	}
+	if m.app.allocs != nil {
+		c = allocContainer{
+			container: c,
+			recorder:  m.app.allocs,
+			info:      fxevent.ConstructorAllocations{Name: funcName, ModuleName: m.name},
+		}
+	}
+	if p.AtStart {
+		c = atStartContainer{container: c, app: m.app, name: funcName}
+	}
+	if len(m.app.constructorMiddleware) > 0 && !p.Builtin {
+		c = middlewareContainer{
+			container:  c,
+			middleware: m.app.constructorMiddleware,
+			info:       ConstructorInfo{FunctionName: funcName, ModuleName: m.name},
+		}
+	}
+	if m.app.detectNilResults {
+		_, annotated := p.Target.(annotated)
+		c = nilCheckContainer{container: c, name: funcName, annotated: annotated}
+	}
+	if m.app.recoverFromPanics && len(m.app.panicHandlers) > 0 {
+		c = panicContainer{container: c, module: m, funcName: funcName}
+	}
+	if p.Retry != nil {
+		c = retryContainer{container: c, module: m, funcName: funcName, policy: *p.Retry}
+	}
+
+	var err error
+	if m.app.strict {
+		if err = checkStrict(p.Target); err != nil {
+			err = fmt.Errorf("fx.Provide(%v) from:\n%+vFailed: %w", funcName, p.Stack, err)
+		}
+	}
+	if err == nil {
+		m.recordMeta(funcName, p)
+		err = runProvide(c, p, opts...)
+		if err != nil {
+			if dup := m.duplicateError(funcName, p, err); dup != nil {
+				err = fmt.Errorf("fx.Provide(%v) from:\n%+vFailed: %w", funcName, p.Stack, dup)
+			}
+		}
+	}
+	switch {
+	case err != nil && p.Bulk != nil:
+		// Keep providing the other constructors of the same
+		// fx.ProvideAll or fx.ProvideMap to report all of their errors.
+		err = p.Bulk.wrap(p.BulkKey, err)
+		m.app.err = multierr.Append(m.app.err, err)
+		p.Bulk.err = m.app.err
+	case err != nil:
 		m.app.err = err
+	default:
+		m.recordProvider(funcName, p, info)
+		if len(m.app.requiredGroups) > 0 {
+			contributors = m.groupContributors(funcName, p, info.Outputs)
+		}
+		if len(m.app.groupMins) > 0 {
+			m.countGroupMins(p, info.Outputs)
+		}
+		if m.app.manifest != nil && !p.Builtin {
+			m.app.manifest.add(m, "provide", funcName, p.Private, info.Inputs, info.Outputs)
+		}
+		if m.app.demand != nil {
+			demand = m.app.demand.Provided(funcName, info)
+		}
+		if m.app.linter != nil {
+			m.app.linter.checkProvide(m, funcName, p, info)
+		}
 	}
 	outputNames := make([]string, len(info.Outputs))
 	for i, o := range info.Outputs {
 		outputNames[i] = o.String()
 	}
+	var inputNames []string
+	for _, in := range info.Inputs {
+		inputNames = append(inputNames, in.String())
+	}
 
 	m.log.LogEvent(&fxevent.Provided{
 		ConstructorName: funcName,
@@ -220,16 +454,25 @@ func (m *module) provide(p provide) {
 		ModuleTrace:     append([]string{p.Stack[0].String()}, m.trace...),
 		ModuleName:      m.name,
 		OutputTypeNames: outputNames,
-		Err:             m.app.err,
+		InputTypeNames:  inputNames,
+		Err:             err,
 		Private:         p.Private,
 	})
 }
 
 func (m *module) supply(p provide) {
 	typeName := p.SupplyType.String()
+	var (
+		info         dig.ProvideInfo
+		contributors []*groupContributor // set once provided
+	)
 	opts := []dig.ProvideOption{
+		dig.FillProvideInfo(&info),
 		dig.Export(!p.Private),
 		dig.WithProviderCallback(func(ci dig.CallbackInfo) {
+			for _, c := range contributors {
+				c.ran.Store(true)
+			}
 			m.log.LogEvent(&fxevent.Run{
 				Name:       fmt.Sprintf("stub(%v)", typeName),
 				Kind:       "supply",
@@ -239,8 +482,30 @@ func (m *module) supply(p provide) {
 		}),
 	}
 
-	if err := runProvide(m.scope, p, opts...); err != nil {
+	name := fmt.Sprintf("fx.Supply(%v)", typeName)
+	var c container = m.scope
+	if len(m.app.lazyGroupTypes) > 0 {
+		c = lazyGroupContainer{container: c, export: !p.Private, types: m.app.lazyGroupTypes}
+	}
+	if err := runProvide(c, p, opts...); err != nil {
+		if dup := m.duplicateError(name, p, err); dup != nil {
+			err = fmt.Errorf("fx.Supply(%v) from:\n%+vFailed: %w", typeName, p.Stack, dup)
+		}
 		m.app.err = err
+	} else {
+		m.recordProvider(name, p, info)
+		if len(m.app.requiredGroups) > 0 {
+			contributors = m.groupContributors(name, p, info.Outputs)
+		}
+		if len(m.app.groupMins) > 0 {
+			m.countGroupMins(p, info.Outputs)
+		}
+		if m.app.manifest != nil {
+			m.app.manifest.add(m, "supply", name, p.Private, nil, info.Outputs)
+		}
+		if m.app.linter != nil {
+			m.app.linter.checkProvide(m, name, p, info)
+		}
 	}
 
 	m.log.LogEvent(&fxevent.Supplied{
@@ -258,12 +523,22 @@ func (m *module) installAllEventLoggers() {
 		if buffer, ok := m.log.(*logBuffer); ok {
 			// default to parent's logger if custom logger constructor fails
 			if err := m.installEventLogger(buffer); err != nil {
-				m.app.err = multierr.Append(m.app.err, err)
-				m.log = m.fallbackLogger
+				m.app.err = multierr.Append(m.app.err, m.app.withMeta(err))
+				m.log = m.decorateEventLogger(m.fallbackLogger)
 				buffer.Connect(m.log)
 			}
 		}
 		m.fallbackLogger = nil
+	} else if buffer, ok := m.log.(*logBuffer); ok && len(m.logDecorators) > 0 {
+		// Replay the events buffered since this module was built
+		// through the decorated logger.
+		log := m.fallbackLogger
+		if m.parent != nil {
+			log = m.parent.log
+		}
+		m.log = m.decorateEventLogger(log)
+		buffer.Connect(m.log)
+		m.fallbackLogger = nil
 	} else if m.parent != nil {
 		m.log = m.parent.log
 	}
@@ -290,10 +565,31 @@ func (m *module) installEventLogger(buffer *logBuffer) (err error) {
 			fname, p.Stack, m.name, err)
 	}
 
-	return m.scope.Invoke(func(log fxevent.Logger) {
-		m.log = log
-		buffer.Connect(log)
+	err = m.scope.Invoke(func(log fxevent.Logger) {
+		m.log = m.decorateEventLogger(m.app.stampLogger(log))
+		buffer.Connect(m.log)
 	})
+	if err == nil && m.log == buffer {
+		// Dry runs (e.g. fx.ValidateApp) don't build the logger,
+		// so use the fallback logger instead of buffering forever.
+		m.log = m.decorateEventLogger(m.fallbackLogger)
+		buffer.Connect(m.log)
+	}
+	return err
+}
+
+// decorateEventLogger wraps log with the functions passed to
+// fx.DecorateLogger in this module, in the order they were passed.
+func (m *module) decorateEventLogger(log fxevent.Logger) fxevent.Logger {
+	if len(m.logDecorators) == 0 {
+		return log
+	}
+	for _, decorate := range m.logDecorators {
+		if l := decorate(log); l != nil {
+			log = l
+		}
+	}
+	return m.app.stampLogger(log)
 }
 
 func (m *module) invokeAll() error {
@@ -313,18 +609,53 @@ func (m *module) invokeAll() error {
 }
 
 func (m *module) invoke(i invoke) (err error) {
-	fnName := fxreflect.FuncName(i.Target)
+	fnName := i.Name
+	if fnName == "" {
+		fnName = fxreflect.FuncName(i.Target)
+	}
 	m.log.LogEvent(&fxevent.Invoking{
 		FunctionName: fnName,
 		ModuleName:   m.name,
 	})
-	err = runInvoke(m.scope, i)
+
+	if m.app.demand != nil {
+		defer m.app.demand.Invoking(fnName, i)()
+	}
+
+	parent := m.app.traceCtx
+	var endSpan func(error)
+	m.app.traceCtx, endSpan = m.app.startSpan(m.app.spanParent(), "fx.Invoke", m.spanAttributes(fnName)...)
+	var c container = m.scope
+	if i.Module != nil {
+		c = i.Module.scope
+	}
+	if m.app.hasModules {
+		pop := m.app.runningModules.push(m)
+		err = runInvoke(c, i)
+		pop()
+	} else {
+		err = runInvoke(c, i)
+	}
+	m.onDigPanic(err, "fx.Invoke", fnName)
+	endSpan(err)
+	m.app.traceCtx = parent
+
+	// Options passed to fx.TryInvoke by mistake are still fatal.
+	_, isOption := i.Target.(Option)
+	try := i.Try && !isOption
+
 	m.log.LogEvent(&fxevent.Invoked{
 		FunctionName: fnName,
 		ModuleName:   m.name,
 		Err:          err,
 		Trace:        fmt.Sprintf("%+v", i.Stack), // format stack trace as multi-line
+		Try:          try,
 	})
+	if err != nil && try {
+		errorHandlerList(m.app.errorHooks).HandleError(
+			fmt.Errorf("fx.TryInvoke(%v) failed: %w", fnName, err))
+		return nil
+	}
 	return err
 }
 
@@ -348,11 +679,15 @@ func (m *module) decorate(d decorator) (err error) {
 		return m.replace(d)
 	}
 
-	funcName := fxreflect.FuncName(d.Target)
+	funcName := d.Name
+	if funcName == "" {
+		funcName = fxreflect.FuncName(d.Target)
+	}
 	var info dig.DecorateInfo
 	opts := []dig.DecorateOption{
 		dig.FillDecorateInfo(&info),
 		dig.WithDecoratorCallback(func(ci dig.CallbackInfo) {
+			m.onDigPanic(ci.Error, "fx.Decorate", funcName)
 			m.log.LogEvent(&fxevent.Run{
 				Name:       funcName,
 				Kind:       "decorate",
@@ -360,15 +695,29 @@ func (m *module) decorate(d decorator) (err error) {
 				Runtime:    ci.Runtime,
 				Err:        ci.Error,
 			})
+			m.app.traceRun("fx.Decorate", ci.Runtime, ci.Error, m.spanAttributes(funcName)...)
 		}),
 	}
 
-	err = runDecorator(m.scope, d, opts...)
+	if d.PreserveAs != "" {
+		err = m.decoratePreservingOriginal(d, opts...)
+	} else {
+		err = runDecorator(m.scope, d, opts...)
+	}
 	outputNames := make([]string, len(info.Outputs))
 	for i, o := range info.Outputs {
 		outputNames[i] = o.String()
 	}
 
+	if err == nil {
+		for _, name := range outputNames {
+			m.recordDecoration(name, funcName)
+		}
+		if m.app.manifest != nil {
+			m.app.manifest.add(m, "decorate", funcName, false, info.Inputs, info.Outputs)
+		}
+	}
+
 	m.log.LogEvent(&fxevent.Decorated{
 		DecoratorName:   funcName,
 		StackTrace:      d.Stack.Strings(),
@@ -381,6 +730,71 @@ func (m *module) decorate(d decorator) (err error) {
 	return err
 }
 
+// decoratePreservingOriginal runs the decorator and provides the values it
+// decorates under the name requested with fx.DecoratePreserveOriginal.
+func (m *module) decoratePreservingOriginal(d decorator, opts ...dig.DecorateOption) error {
+	wrapped, ctor, err := preserveOriginal(d.Target, d.PreserveAs)
+	if err != nil {
+		return fmt.Errorf("fx.Decorate(%v) from:\n%+vFailed: %w",
+			fxreflect.FuncName(d.Target), d.Stack, err)
+	}
+
+	target := d.Target
+	d.Target = wrapped
+	if err := runDecorator(m.scope, d, opts...); err != nil {
+		return err
+	}
+
+	if err := m.scope.Provide(ctor, dig.Export(false)); err != nil {
+		return fmt.Errorf("fx.Decorate(%v) from:\n%+vFailed: %w",
+			fxreflect.FuncName(target), d.Stack, err)
+	}
+	return nil
+}
+
+func (m *module) recordDecoration(typeName, decoratorName string) {
+	if m.decorated == nil {
+		m.decorated = make(map[string]string)
+	}
+	m.decorated[typeName] = decoratorName
+}
+
+// logDecoratorChains logs the decorators applied to each type that is
+// decorated more than once by this module and the modules containing it,
+// and does the same for its submodules.
+//
+// Decorators of a type compose from the outermost module inwards:
+// a module's decorator receives the value decorated by its parent modules.
+func (m *module) logDecoratorChains() {
+	typeNames := make([]string, 0, len(m.decorated))
+	for typeName := range m.decorated {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	for _, typeName := range typeNames {
+		var chain []string
+		for mod := m; mod != nil; mod = mod.parent {
+			if name, ok := mod.decorated[typeName]; ok {
+				chain = append([]string{name}, chain...)
+			}
+		}
+		if len(chain) < 2 {
+			continue
+		}
+
+		m.log.LogEvent(&fxevent.DecoratorChain{
+			TypeName:       typeName,
+			ModuleName:     m.name,
+			DecoratorNames: chain,
+		})
+	}
+
+	for _, mod := range m.modules {
+		mod.logDecoratorChains()
+	}
+}
+
 func (m *module) replace(d decorator) error {
 	typeName := d.ReplaceType.String()
 	opts := []dig.DecorateOption{
@@ -396,6 +810,10 @@ func (m *module) replace(d decorator) error {
 	}
 
 	err := runDecorator(m.scope, d, opts...)
+	if err == nil {
+		m.recordDecoration(typeName, fmt.Sprintf("fx.Replace(%v)", typeName))
+	}
+
 	m.log.LogEvent(&fxevent.Replaced{
 		ModuleName:      m.name,
 		StackTrace:      d.Stack.Strings(),
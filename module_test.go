@@ -261,6 +261,7 @@ func TestModuleSuccess(t *testing.T) {
 				desc:           "custom logger for module",
 				giveWithLogger: fx.NopLogger,
 				wantEvents: []string{
+					"Configured",
 					"Provided", "Provided", "Provided", "Supplied",
 					"Run", "LoggerInitialized", "Invoking", "Invoked",
 				},
@@ -269,6 +270,7 @@ func TestModuleSuccess(t *testing.T) {
 				desc:           "Not using a custom logger for module defaults to app logger",
 				giveWithLogger: fx.Options(),
 				wantEvents: []string{
+					"Configured",
 					"Provided", "Provided", "Provided", "Supplied", "Provided", "Run",
 					"LoggerInitialized", "Invoking", "Run", "Invoked", "Invoking", "Invoked",
 				},
@@ -310,7 +312,7 @@ func TestModuleSuccess(t *testing.T) {
 
 				require.NoError(t, app.Err())
 
-				assert.Equal(t, []string{"Started", "Stopped"}, spy.EventTypes())
+				assert.Equal(t, []string{"ModuleStarted", "Started", "ModuleStopped", "Stopped"}, spy.EventTypes())
 			})
 		}
 	})
@@ -358,6 +360,7 @@ func TestModuleSuccess(t *testing.T) {
 		}, moduleSpy.EventTypes())
 
 		assert.Equal(t, []string{
+			"Configured",
 			"Provided", "Provided", "Provided",
 			"LoggerInitialized", "Invoking", "Invoked",
 		}, appSpy.EventTypes())
@@ -369,7 +372,7 @@ func TestModuleSuccess(t *testing.T) {
 
 		require.NoError(t, app.Err())
 
-		assert.Equal(t, []string{"Started", "Stopped"}, appSpy.EventTypes())
+		assert.Equal(t, []string{"ModuleStarted", "Started", "ModuleStopped", "Stopped"}, appSpy.EventTypes())
 		assert.Empty(t, moduleSpy.EventTypes())
 	})
 
@@ -419,6 +422,7 @@ func TestModuleSuccess(t *testing.T) {
 		}, childSpy.EventTypes(), "events from grandchild also logged in child logger")
 
 		assert.Equal(t, []string{
+			"Configured",
 			"Provided", "Provided", "Provided",
 			"LoggerInitialized", "Invoking", "Invoked",
 		}, appSpy.EventTypes(), "events from modules do not appear in app logger")
@@ -430,7 +434,10 @@ func TestModuleSuccess(t *testing.T) {
 
 		require.NoError(t, app.Err())
 
-		assert.Equal(t, []string{"Started", "Stopped"}, appSpy.EventTypes())
+		assert.Equal(t, []string{
+			"ModuleStarted", "ModuleStarted", "Started",
+			"ModuleStopped", "ModuleStopped", "Stopped",
+		}, appSpy.EventTypes())
 		assert.Empty(t, childSpy.EventTypes())
 	})
 }
@@ -660,6 +667,7 @@ func TestModuleFailures(t *testing.T) {
 				giveAppOpts:     spyAsLogger,
 				wantErrContains: []string{"error building logger"},
 				wantEvents: []string{
+					"Configured",
 					"Provided", "Provided", "Provided", "Supplied", "Run",
 					"LoggerInitialized", "Provided", "LoggerInitialized",
 				},
@@ -678,6 +686,7 @@ func TestModuleFailures(t *testing.T) {
 				giveAppOpts:     spyAsLogger,
 				wantErrContains: []string{"error building logger dependency"},
 				wantEvents: []string{
+					"Configured",
 					"Provided", "Provided", "Provided", "Supplied", "Run",
 					"LoggerInitialized", "Provided", "Provided", "Run", "LoggerInitialized",
 				},
@@ -690,6 +699,7 @@ func TestModuleFailures(t *testing.T) {
 					"fx.WithLogger", "from:", "Failed",
 				},
 				wantEvents: []string{
+					"Configured",
 					"Provided", "Provided", "Provided", "Supplied", "Run",
 					"LoggerInitialized", "Provided", "LoggerInitialized",
 				},
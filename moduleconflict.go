@@ -0,0 +1,179 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/multierr"
+)
+
+// ModuleConflictPolicy specifies how Fx handles an application
+// that includes several modules with the same name.
+// See [OnModuleConflict].
+type ModuleConflictPolicy int
+
+const (
+	// IgnoreModuleConflicts composes modules with the same name
+	// like any other modules. This is the default behavior.
+	IgnoreModuleConflicts ModuleConflictPolicy = iota
+
+	// WarnOnModuleConflict composes modules with the same name,
+	// and reports them with an [fxevent.ModuleConflict] event.
+	WarnOnModuleConflict
+
+	// FailOnModuleConflict fails the application
+	// if it includes several modules with the same name.
+	FailOnModuleConflict
+)
+
+func (p ModuleConflictPolicy) String() string {
+	switch p {
+	case IgnoreModuleConflicts:
+		return "fx.IgnoreModuleConflicts"
+	case WarnOnModuleConflict:
+		return "fx.WarnOnModuleConflict"
+	case FailOnModuleConflict:
+		return "fx.FailOnModuleConflict"
+	default:
+		return fmt.Sprintf("ModuleConflictPolicy(%d)", int(p))
+	}
+}
+
+// OnModuleConflict specifies how Fx handles modules with the same name,
+// such as two versions of a logical module bundled by different libraries.
+// By default, Fx composes them silently, which may provide,
+// decorate, or invoke things twice.
+//
+//	fx.New(
+//		fx.OnModuleConflict(fx.FailOnModuleConflict),
+//		payments.Module, // includes logging.Module v1
+//		users.Module,    // includes logging.Module v2
+//	)
+//
+// Conflicts are reported with the versions set by [ModuleVersion]
+// and the locations where the modules were declared.
+// Including the same module value twice is a conflict as well.
+//
+// OnModuleConflict may only be passed to [New].
+func OnModuleConflict(policy ModuleConflictPolicy) Option {
+	return onModuleConflictOption{Policy: policy}
+}
+
+type onModuleConflictOption struct {
+	Policy ModuleConflictPolicy
+}
+
+func (o onModuleConflictOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.OnModuleConflict Option should be passed to top-level " +
+			"App, not to fx.Module")
+	} else {
+		m.app.moduleConflictPolicy = o.Policy
+	}
+}
+
+func (o onModuleConflictOption) String() string {
+	return fmt.Sprintf("fx.OnModuleConflict(%v)", o.Policy)
+}
+
+// ModuleVersion sets the version of the [Module] it's passed to,
+// which identifies the module when it conflicts with another module
+// of the same name. See [OnModuleConflict].
+//
+//	var Module = fx.Module("logging",
+//		fx.ModuleVersion("v1.4.0"),
+//		fx.Provide(New),
+//	)
+func ModuleVersion(version string) Option {
+	return moduleVersionOption(version)
+}
+
+type moduleVersionOption string
+
+func (o moduleVersionOption) apply(m *module) {
+	if m.parent == nil {
+		m.app.err = fmt.Errorf("fx.ModuleVersion Option should be passed to fx.Module, " +
+			"not to the top-level App")
+	} else {
+		m.version = string(o)
+	}
+}
+
+func (o moduleVersionOption) String() string {
+	return fmt.Sprintf("fx.ModuleVersion(%q)", string(o))
+}
+
+// identity describes the module for diagnostics,
+// with its name, version, and the location it was declared at.
+func (m *module) identity() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%q", m.name)
+	if m.version != "" {
+		fmt.Fprintf(&sb, " %v", m.version)
+	}
+	fmt.Fprintf(&sb, " from %v", m.location)
+	return sb.String()
+}
+
+// checkModuleConflicts reports the modules with the same name as an earlier
+// module that were included since the last call,
+// according to the module conflict policy.
+func (app *App) checkModuleConflicts() {
+	mods := app.namedModules
+	checked := app.checkedModules
+	app.checkedModules = len(mods)
+	if app.moduleConflictPolicy == IgnoreModuleConflicts {
+		return
+	}
+
+	// Report each name once, with all the modules sharing it.
+	reported := make(map[string]struct{})
+	for _, m := range mods[checked:] {
+		if _, ok := reported[m.name]; ok {
+			continue
+		}
+		var idents []string
+		for _, other := range mods {
+			if other.name == m.name {
+				idents = append(idents, other.identity())
+			}
+		}
+		if len(idents) < 2 {
+			continue
+		}
+		reported[m.name] = struct{}{}
+
+		switch app.moduleConflictPolicy {
+		case WarnOnModuleConflict:
+			app.log().LogEvent(&fxevent.ModuleConflict{
+				ModuleName: m.name,
+				Modules:    idents,
+			})
+		case FailOnModuleConflict:
+			app.err = multierr.Append(app.err, fmt.Errorf(
+				"fx.OnModuleConflict: %d modules are named %q:\n\t%v",
+				len(idents), m.name, strings.Join(idents, "\n\t")))
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+func TestOnModuleConflict(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+
+	loggingV1 := fx.Module("logging",
+		fx.ModuleVersion("v1"),
+		fx.Provide(func() *A { return &A{} }),
+	)
+	loggingV2 := fx.Module("logging",
+		fx.ModuleVersion("v2"),
+		fx.Invoke(func() {}),
+	)
+
+	conflicts := func(spy *fxlog.Spy) []*fxevent.ModuleConflict {
+		var events []*fxevent.ModuleConflict
+		for _, e := range spy.Events().SelectByTypeName("ModuleConflict") {
+			events = append(events, e.(*fxevent.ModuleConflict))
+		}
+		return events
+	}
+
+	t.Run("ignored by default", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		app := fxtest.New(t,
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			loggingV1,
+			loggingV2,
+		)
+		require.NoError(t, app.Err())
+		assert.Empty(t, conflicts(spy))
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		app := fxtest.New(t,
+			fx.OnModuleConflict(fx.WarnOnModuleConflict),
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.Module("payments", loggingV1),
+			fx.Module("users", loggingV2),
+			fx.Module("unrelated"),
+		)
+		require.NoError(t, app.Err())
+
+		events := conflicts(spy)
+		require.Len(t, events, 1)
+		assert.Equal(t, "logging", events[0].ModuleName)
+		require.Len(t, events[0].Modules, 2)
+		assert.Contains(t, events[0].Modules[0], `"logging" v1 from `)
+		assert.Contains(t, events[0].Modules[0], "moduleconflict_test.go")
+		assert.Contains(t, events[0].Modules[1], `"logging" v2 from `)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.OnModuleConflict(fx.FailOnModuleConflict),
+			loggingV1,
+			loggingV2,
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `fx.OnModuleConflict: 2 modules are named "logging":`)
+		assert.Contains(t, err.Error(), `"logging" v1 from `)
+		assert.Contains(t, err.Error(), `"logging" v2 from `)
+	})
+
+	t.Run("same module twice", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.OnModuleConflict(fx.FailOnModuleConflict),
+			fx.Module("foo"),
+			fx.Module("foo"),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `2 modules are named "foo"`)
+	})
+
+	t.Run("extend", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.OnModuleConflict(fx.FailOnModuleConflict),
+			loggingV1,
+		)
+		require.NoError(t, app.Err())
+
+		err := app.Extend(loggingV2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `2 modules are named "logging"`)
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Module("foo", fx.OnModuleConflict(fx.FailOnModuleConflict)),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.OnModuleConflict Option should be passed to top-level App")
+	})
+
+	t.Run("version at top level", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(fx.NopLogger, fx.ModuleVersion("v1"))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.ModuleVersion Option should be passed to fx.Module")
+	})
+
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "fx.OnModuleConflict(fx.WarnOnModuleConflict)",
+			fx.OnModuleConflict(fx.WarnOnModuleConflict).String())
+		assert.Equal(t, `fx.ModuleVersion("v1")`, fx.ModuleVersion("v1").String())
+	})
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx
+
+import (
+	"reflect"
+	"sync"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/internal/lifecycle"
+)
+
+// runningModules tracks the modules whose constructors
+// or invoked functions are running, innermost last,
+// to attribute the hooks they append to them.
+// Hooks appended by the root module aren't attributed to any module.
+type runningModules struct {
+	mu   sync.Mutex
+	mods []*module
+}
+
+// push records that a function of m is running until pop is called.
+func (r *runningModules) push(m *module) (pop func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mods = append(r.mods, m)
+	n := len(r.mods)
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.mods = r.mods[:n-1]
+	}
+}
+
+// current returns the lifecycle module of the innermost running module,
+// or nil if none is running.
+func (r *runningModules) current() *lifecycle.Module {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.mods) == 0 {
+		return nil
+	}
+	return r.mods[len(r.mods)-1].lifecycleModule
+}
+
+// moduleContainer is a container that records the constructors
+// provided to it as functions of module while they run.
+// Only constructors that take a Lifecycle,
+// directly or in a parameter object, can append hooks,
+// so only those are recorded.
+type moduleContainer struct {
+	container
+
+	module *module
+}
+
+var _ container = moduleContainer{}
+
+func (c moduleContainer) Provide(constructor interface{}, opts ...dig.ProvideOption) error {
+	if !takesLifecycle(constructor) {
+		return c.container.Provide(constructor, opts...)
+	}
+
+	running := &c.module.app.runningModules
+	return provideWrapped(c.container, constructor, opts, false, func(_ reflect.Type, call constructorFunc) constructorFunc {
+		return func(args []reflect.Value) []reflect.Value {
+			defer running.push(c.module)()
+			return call(args)
+		}
+	})
+}
+
+// takesLifecycle reports whether fn is a function
+// that takes a Lifecycle.
+func takesLifecycle(fn interface{}) bool {
+	ft := reflect.TypeOf(fn)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return false
+	}
+	in := make([]reflect.Type, ft.NumIn())
+	for i := range in {
+		in[i] = ft.In(i)
+	}
+	return lifecycleExists(in)
+}
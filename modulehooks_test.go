@@ -0,0 +1,209 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+)
+
+// moduleRecorder records the hooks it builds as they run,
+// and the modules reported started or stopped, in order.
+type moduleRecorder struct {
+	mu  sync.Mutex
+	got []string
+}
+
+var _ fxevent.Logger = (*moduleRecorder)(nil)
+
+func (r *moduleRecorder) record(s string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.got = append(r.got, s)
+}
+
+func (r *moduleRecorder) events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.got...)
+}
+
+func (r *moduleRecorder) LogEvent(e fxevent.Event) {
+	switch e := e.(type) {
+	case *fxevent.ModuleStarted:
+		r.record("module started: " + e.ModuleName)
+	case *fxevent.ModuleStopped:
+		r.record("module stopped: " + e.ModuleName)
+	}
+}
+
+func (r *moduleRecorder) hook(name string) fx.Hook {
+	return fx.StartStopHook(
+		func() { r.record("start " + name) },
+		func() { r.record("stop " + name) },
+	)
+}
+
+func (r *moduleRecorder) appendHook(name string) func(fx.Lifecycle) {
+	return func(lc fx.Lifecycle) { lc.Append(r.hook(name)) }
+}
+
+func TestModuleHookEvents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("start and stop", func(t *testing.T) {
+		t.Parallel()
+
+		type (
+			Server     struct{}
+			GRPC       struct{}
+			GRPCParams struct {
+				fx.In
+
+				Lifecycle fx.Lifecycle
+			}
+		)
+
+		var rec moduleRecorder
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return &rec }),
+			fx.Module("empty"),
+			fx.Module("db", fx.Invoke(rec.appendHook("db"))),
+			fx.Module("server",
+				fx.Provide(func(lc fx.Lifecycle) *Server {
+					lc.Append(rec.hook("server"))
+					return &Server{}
+				}),
+				fx.Module("http", fx.Invoke(rec.appendHook("http"))),
+				fx.Module("grpc", fx.Provide(func(p GRPCParams) *GRPC {
+					p.Lifecycle.Append(rec.hook("grpc"))
+					return &GRPC{}
+				})),
+			),
+			// The constructor of *Server runs for an invoke of the root module,
+			// but its hook belongs to the server module.
+			fx.Invoke(func(*Server, *GRPC) {}),
+			fx.Invoke(rec.appendHook("root")),
+		)
+		require.NoError(t, app.Err())
+
+		require.NoError(t, app.Start(context.Background()))
+		assert.Equal(t, []string{
+			"module started: empty",
+			"start db",
+			"module started: db",
+			"start http",
+			"module started: http",
+			"start server",
+			"start grpc",
+			"module started: grpc",
+			"module started: server",
+			"start root",
+		}, rec.events())
+
+		rec.got = nil
+		require.NoError(t, app.Stop(context.Background()))
+		assert.Equal(t, []string{
+			"module stopped: empty",
+			"stop root",
+			"stop grpc",
+			"module stopped: grpc",
+			"stop server",
+			"stop http",
+			"module stopped: http",
+			"module stopped: server",
+			"stop db",
+			"module stopped: db",
+		}, rec.events())
+	})
+
+	t.Run("hooks appended by hooks", func(t *testing.T) {
+		t.Parallel()
+
+		var rec moduleRecorder
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return &rec }),
+			fx.Module("dynamic",
+				fx.Invoke(func(lc fx.Lifecycle) {
+					lc.Append(fx.StartHook(func() {
+						rec.record("start outer")
+						lc.Append(rec.hook("inner"))
+					}))
+				}),
+			),
+		)
+		require.NoError(t, app.Start(context.Background()))
+		defer func() { assert.NoError(t, app.Stop(context.Background())) }()
+
+		assert.Equal(t, []string{
+			"start outer",
+			"start inner",
+			"module started: dynamic",
+		}, rec.events())
+	})
+
+	t.Run("failed start", func(t *testing.T) {
+		t.Parallel()
+
+		var rec moduleRecorder
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return &rec }),
+			fx.Module("ok", fx.Invoke(rec.appendHook("ok"))),
+			fx.Module("broken",
+				fx.Invoke(func(lc fx.Lifecycle) {
+					lc.Append(fx.StartHook(func() error {
+						return errors.New("great sadness")
+					}))
+				}),
+			),
+		)
+		require.Error(t, app.Start(context.Background()))
+
+		assert.Equal(t, []string{
+			"start ok",
+			"module started: ok",
+			// rollback
+			"module stopped: broken",
+			"stop ok",
+			"module stopped: ok",
+		}, rec.events())
+	})
+
+	t.Run("no modules", func(t *testing.T) {
+		t.Parallel()
+
+		var rec moduleRecorder
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return &rec }),
+			fx.Invoke(rec.appendHook("root")),
+		)
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+
+		assert.Equal(t, []string{"start root", "stop root"}, rec.events())
+	})
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/fx/internal/fxreflect"
+	"go.uber.org/fx/internal/lifecycle"
+	"go.uber.org/multierr"
+)
+
+// NamedLifecycle is an auxiliary lifecycle declared with [DeclareLifecycle].
+// Its hooks do not run when the application starts and stops,
+// but when it's run explicitly with [App.RunLifecycle].
+//
+// Auxiliary lifecycles are provided to the application as named values:
+// depend on one with a `name` tag.
+//
+//	fx.Invoke(fx.Annotate(
+//		func(lc fx.NamedLifecycle, c *Cache) {
+//			lc.Append(fx.StartHook(c.Warm))
+//		},
+//		fx.ParamTags(`name:"warmup"`),
+//	))
+type NamedLifecycle interface {
+	Lifecycle
+}
+
+// DeclareLifecycle declares an auxiliary lifecycle with the given name,
+// for operational procedures like warming up caches or running maintenance
+// that are best modeled in the container with injected dependencies.
+//
+// Constructors and invoked functions append hooks to it like to [Lifecycle],
+// through a [NamedLifecycle] with the lifecycle's name.
+// The application runs these hooks on demand with [App.RunLifecycle].
+//
+//	app := fx.New(
+//		fx.DeclareLifecycle("warmup"),
+//		...
+//	)
+//	if err := app.RunLifecycle(ctx, "warmup"); err != nil {
+//		...
+//	}
+//
+// DeclareLifecycle may only be passed to the top-level App,
+// and each name may only be declared once.
+func DeclareLifecycle(name string) Option {
+	return declareLifecycleOption{
+		Name:  name,
+		Stack: fxreflect.CallerStack(1, 0),
+	}
+}
+
+type declareLifecycleOption struct {
+	Name  string
+	Stack fxreflect.Stack
+}
+
+func (o declareLifecycleOption) apply(m *module) {
+	switch {
+	case m.parent != nil:
+		m.app.err = fmt.Errorf("fx.DeclareLifecycle Option should be passed to top-level App, " +
+			"not to fx.Module")
+	case o.Name == "":
+		m.app.err = fmt.Errorf("fx.DeclareLifecycle from:\n%+vFailed: lifecycle name must not be empty", o.Stack)
+	default:
+		for _, name := range m.app.lifecycleNames {
+			if name == o.Name {
+				m.app.err = fmt.Errorf("fx.DeclareLifecycle(%q) from:\n%+vFailed: lifecycle already declared",
+					o.Name, o.Stack)
+				return
+			}
+		}
+		m.app.lifecycleNames = append(m.app.lifecycleNames, o.Name)
+	}
+}
+
+func (o declareLifecycleOption) String() string {
+	return fmt.Sprintf("fx.DeclareLifecycle(%q)", o.Name)
+}
+
+// provideNamedLifecycles builds the lifecycles declared with
+// DeclareLifecycle, and provides them to the root module.
+func (app *App) provideNamedLifecycles(stack fxreflect.Stack) {
+	if len(app.lifecycleNames) == 0 {
+		return
+	}
+
+	app.namedLifecycles = make(map[string]*lifecycleWrapper, len(app.lifecycleNames))
+	for _, name := range app.lifecycleNames {
+		lc := &lifecycleWrapper{
+			Lifecycle: lifecycle.New(appLogger{app}, app.clock),
+			logger:    appLogger{app},
+			clock:     app.clock,
+			wrap:      app.lifecycle.wrap,
+		}
+		app.namedLifecycles[name] = lc
+		app.root.provide(provide{
+			Target: Annotated{
+				Name:   name,
+				Target: func() NamedLifecycle { return lc },
+			},
+			Stack:   stack,
+			Builtin: true,
+		})
+	}
+}
+
+// RunLifecycle runs the hooks of the auxiliary lifecycle with the given name,
+// declared with [DeclareLifecycle].
+// It executes their OnStart hooks in order, and then the OnStop hooks
+// of the hooks whose OnStart succeeded in reverse order, like [App.Start]
+// followed by [App.Stop]. OnStop hooks run even if an OnStart hook fails.
+// RunLifecycle returns the errors of the hooks that failed, if any.
+//
+// Auxiliary lifecycles may be run whether the application is started or not,
+// and may be run any number of times, but not concurrently with themselves.
+func (app *App) RunLifecycle(ctx context.Context, name string) error {
+	if app.err != nil {
+		return app.err
+	}
+	lc, ok := app.namedLifecycles[name]
+	if !ok {
+		return fmt.Errorf("unknown lifecycle %q: declare it with fx.DeclareLifecycle", name)
+	}
+	if ctx == nil {
+		return errors.New("called RunLifecycle with nil context")
+	}
+
+	startErr := lc.Start(ctx)
+	return multierr.Append(startErr, lc.Stop(ctx))
+}
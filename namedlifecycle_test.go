@@ -0,0 +1,161 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestNamedLifecycle(t *testing.T) {
+	t.Parallel()
+
+	type cache struct{ warm bool }
+
+	t.Run("RunLifecycle", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		record := func(s string) func() { return func() { calls = append(calls, s) } }
+		app := fxtest.New(t,
+			fx.DeclareLifecycle("warmup"),
+			fx.DeclareLifecycle("maintenance"),
+			fx.Provide(func() *cache { return &cache{} }),
+			fx.Invoke(fx.Annotate(
+				func(lc fx.NamedLifecycle, c *cache) {
+					lc.Append(fx.StartStopHook(
+						func() {
+							c.warm = true
+							calls = append(calls, "warm")
+						},
+						record("warmed"),
+					))
+					lc.Append(fx.StartHook(record("check")))
+				},
+				fx.ParamTags(`name:"warmup"`),
+			)),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartStopHook(record("start"), record("stop")))
+			}),
+		)
+
+		require.NoError(t, app.RunLifecycle(context.Background(), "warmup"))
+		assert.Equal(t, []string{"warm", "check", "warmed"}, calls,
+			"auxiliary hooks must not run main lifecycle hooks")
+
+		calls = nil
+		app.RequireStart()
+		require.NoError(t, app.RunLifecycle(context.Background(), "warmup"))
+		require.NoError(t, app.RunLifecycle(context.Background(), "maintenance"))
+		app.RequireStop()
+		assert.Equal(t, []string{"start", "warm", "check", "warmed", "stop"}, calls)
+	})
+
+	t.Run("hook failure", func(t *testing.T) {
+		t.Parallel()
+
+		var stopped bool
+		app := fxtest.New(t,
+			fx.DeclareLifecycle("warmup"),
+			fx.Invoke(fx.Annotate(
+				func(lc fx.NamedLifecycle) {
+					lc.Append(fx.StopHook(func() { stopped = true }))
+					lc.Append(fx.StartHook(func() error { return errors.New("great sadness") }))
+				},
+				fx.ParamTags(`name:"warmup"`),
+			)),
+		)
+		err := app.RunLifecycle(context.Background(), "warmup")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.True(t, stopped, "OnStop hooks must run after a failed OnStart")
+	})
+
+	t.Run("unknown lifecycle", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t)
+		err := app.RunLifecycle(context.Background(), "warmup")
+		assert.EqualError(t, err, `unknown lifecycle "warmup": declare it with fx.DeclareLifecycle`)
+	})
+
+	t.Run("undeclared dependency", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Invoke(fx.Annotate(func(fx.NamedLifecycle) {}, fx.ParamTags(`name:"warmup"`))),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `missing type: fx.NamedLifecycle[name="warmup"]`)
+		assert.Equal(t, err, app.RunLifecycle(context.Background(), "warmup"))
+	})
+
+	t.Run("declaration errors", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    fx.Option
+			wantErr string
+		}{
+			{
+				desc:    "duplicate",
+				give:    fx.Options(fx.DeclareLifecycle("warmup"), fx.DeclareLifecycle("warmup")),
+				wantErr: `fx.DeclareLifecycle("warmup") from:`,
+			},
+			{
+				desc:    "empty name",
+				give:    fx.DeclareLifecycle(""),
+				wantErr: "lifecycle name must not be empty",
+			},
+			{
+				desc:    "module",
+				give:    fx.Module("mod", fx.DeclareLifecycle("warmup")),
+				wantErr: "fx.DeclareLifecycle Option should be passed to top-level App",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				err := fx.New(fx.NopLogger, tt.give).Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, `fx.DeclareLifecycle("warmup")`, fx.DeclareLifecycle("warmup").String())
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.uber.org/dig"
+)
+
+// DetectNilResults causes constructors to fail when they return
+// an interface that holds a nil pointer,
+// such as an io.Reader holding a nil *bytes.Buffer.
+//
+// Such values are not equal to nil,
+// so they're injected into the functions that depend on them,
+// which then panic far from the constructor that returned them.
+// With DetectNilResults, the constructor fails instead,
+// with an error that names it and the result that held the nil pointer.
+// Interfaces in [Out] structs are checked as well.
+//
+// DetectNilResults may only be passed to [New].
+func DetectNilResults() Option {
+	return detectNilResultsOption{}
+}
+
+type detectNilResultsOption struct{}
+
+func (o detectNilResultsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.DetectNilResults Option should be passed to top-level " +
+			"App, not to fx.Module")
+	} else {
+		m.app.detectNilResults = true
+	}
+}
+
+func (o detectNilResultsOption) String() string {
+	return "fx.DetectNilResults()"
+}
+
+// nilCheckContainer is a container whose constructors fail
+// if they return interfaces holding nil pointers.
+type nilCheckContainer struct {
+	container
+
+	name      string // name of the constructor
+	annotated bool   // whether the constructor was built by fx.Annotate
+}
+
+var _ container = nilCheckContainer{}
+
+func (c nilCheckContainer) Provide(constructor interface{}, opts ...dig.ProvideOption) error {
+	// Report the failure through an error result,
+	// adding one if the constructor doesn't have one.
+	return provideWrapped(c.container, constructor, opts, true, func(_ reflect.Type, call constructorFunc) constructorFunc {
+		return func(args []reflect.Value) []reflect.Value {
+			results := call(args)
+			if !results[len(results)-1].IsNil() {
+				return results // the constructor failed on its own
+			}
+
+			for i, v := range results[:len(results)-1] {
+				np, ok := findNilPointer(v)
+				if !ok {
+					continue
+				}
+
+				where := fmt.Sprintf("result %d", i)
+				switch {
+				case c.annotated:
+					// Results of fx.Annotate are fields of generated
+					// fx.Out structs, which users don't know about.
+					where = "its results"
+				case np.Field != "":
+					where += ", field " + np.Field
+				}
+				err := fmt.Errorf("fx.DetectNilResults: %v returned a nil %v as %v in %v",
+					c.name, np.Type, np.As, where)
+				results[len(results)-1] = reflect.ValueOf(&err).Elem()
+				return results
+			}
+			return results
+		}
+	})
+}
+
+// nilPointer describes a nil pointer held by an interface
+// in the results of a constructor.
+type nilPointer struct {
+	Field string       // field of the fx.Out struct holding it, if any
+	Type  reflect.Type // type of the pointer
+	As    reflect.Type // type of the interface
+}
+
+// findNilPointer searches v, a result of a constructor,
+// for an interface holding a nil pointer.
+// The fields of fx.Out structs are searched recursively.
+func findNilPointer(v reflect.Value) (nilPointer, bool) {
+	switch {
+	case v.Kind() == reflect.Interface:
+		if !v.IsNil() && v.Elem().Kind() == reflect.Ptr && v.Elem().IsNil() {
+			return nilPointer{Type: v.Elem().Type(), As: v.Type()}, true
+		}
+	case v.Kind() == reflect.Struct && isOut(v.Type()):
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if np, ok := findNilPointer(v.Field(i)); ok {
+				if np.Field == "" {
+					np.Field = f.Name
+				}
+				return np, true
+			}
+		}
+	}
+	return nilPointer{}, false
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+)
+
+func TestDetectNilResults(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc    string
+		give    interface{}
+		wantErr string // empty if the constructor succeeds
+	}{
+		{
+			desc: "NonNil",
+			give: func() io.Reader { return &bytes.Buffer{} },
+		},
+		{
+			desc: "UntypedNil",
+			give: func() io.Reader { return nil },
+		},
+		{
+			desc: "NilPointer",
+			give: func() io.Reader {
+				var buf *bytes.Buffer
+				return buf
+			},
+			wantErr: "returned a nil *bytes.Buffer as io.Reader in result 0",
+		},
+		{
+			desc: "NilPointerWithError",
+			give: func() (io.Reader, error) {
+				var buf *bytes.Buffer
+				return buf, nil
+			},
+			wantErr: "returned a nil *bytes.Buffer as io.Reader in result 0",
+		},
+		{
+			desc: "ConstructorError",
+			give: func() (io.Reader, error) {
+				var buf *bytes.Buffer
+				return buf, errors.New("great sadness")
+			},
+			wantErr: "great sadness",
+		},
+		{
+			desc: "OutField",
+			give: func() struct {
+				fx.Out
+
+				Reader io.Reader
+			} {
+				var buf *bytes.Buffer
+				return struct {
+					fx.Out
+
+					Reader io.Reader
+				}{Reader: buf}
+			},
+			wantErr: "returned a nil *bytes.Buffer as io.Reader in result 0, field Reader",
+		},
+		{
+			desc: "Annotated",
+			give: fx.Annotate(
+				func() *bytes.Buffer { return nil },
+				fx.As(new(io.Reader)),
+			),
+			wantErr: "returned a nil *bytes.Buffer as io.Reader in its results",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			app := fx.New(
+				fx.NopLogger,
+				fx.DetectNilResults(),
+				fx.Provide(tt.give),
+				fx.Invoke(func(io.Reader) {}),
+			)
+			if tt.wantErr == "" {
+				assert.NoError(t, app.Err())
+				return
+			}
+			assert.ErrorContains(t, app.Err(), tt.wantErr)
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		var got io.Reader
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(func() io.Reader {
+				var buf *bytes.Buffer
+				return buf
+			}),
+			fx.Populate(&got),
+		)
+		assert.NoError(t, app.Err())
+		assert.IsType(t, (*bytes.Buffer)(nil), got)
+	})
+
+	t.Run("NotTopLevel", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Module("child", fx.DetectNilResults()),
+		)
+		assert.ErrorContains(t, app.Err(),
+			"fx.DetectNilResults Option should be passed to top-level App, not to fx.Module")
+	})
+}
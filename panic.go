@@ -0,0 +1,243 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// A PanicPhase identifies a kind of function that Fx runs,
+// to select which panics Fx recovers from with [RecoverPanicsIn].
+type PanicPhase int
+
+const (
+	// PanicsInConstructors are panics in functions passed to [Provide],
+	// [Decorate], and [Invoke]. [RecoverFromPanics] recovers from these.
+	PanicsInConstructors PanicPhase = iota + 1
+
+	// PanicsInHooks are panics in the callbacks of lifecycle hooks.
+	PanicsInHooks
+)
+
+func (p PanicPhase) String() string {
+	switch p {
+	case PanicsInConstructors:
+		return "constructors"
+	case PanicsInHooks:
+		return "hooks"
+	default:
+		return fmt.Sprintf("PanicPhase(%d)", int(p))
+	}
+}
+
+// RecoverPanicsIn causes Fx to recover from panics in the given phases,
+// and turn them into errors.
+// For example, to recover from panics in hooks only,
+// so that a failing OnStop hook doesn't prevent the others from running:
+//
+//	fx.RecoverPanicsIn(fx.PanicsInHooks)
+//
+// A recovered panic in a constructor is reported by [App.Err],
+// and one in a hook is returned by the Start or Stop call that ran it.
+// Panics in other phases crash the application as usual.
+// Use [OnPanic] to report recovered panics.
+func RecoverPanicsIn(phases ...PanicPhase) Option {
+	return recoverPanicsInOption(phases)
+}
+
+type recoverPanicsInOption []PanicPhase
+
+func (o recoverPanicsInOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.RecoverPanicsIn Option should be passed to top-level " +
+			"App, not to fx.Module")
+		return
+	}
+	for _, phase := range o {
+		switch phase {
+		case PanicsInConstructors:
+			m.app.recoverFromPanics = true
+		case PanicsInHooks:
+			m.app.recoverHookPanics = true
+		default:
+			m.app.err = fmt.Errorf("fx.RecoverPanicsIn received unknown phase %v", phase)
+		}
+	}
+}
+
+func (o recoverPanicsInOption) String() string {
+	return fmt.Sprintf("fx.RecoverPanicsIn(%v)", []PanicPhase(o))
+}
+
+// PanicInfo describes a panic that Fx recovered from.
+type PanicInfo struct {
+	// Phase is the kind of function that panicked.
+	Phase PanicPhase
+
+	// Kind describes how the function was passed to Fx:
+	// "fx.Provide", "fx.Decorate", or "fx.Invoke" for constructors,
+	// and "OnStart", "OnStop", "OnPause", or "OnResume" for hooks.
+	Kind string
+
+	// FunctionName is the name of the function that panicked.
+	FunctionName string
+
+	// ModuleName is the name of the module the function was passed to,
+	// if any. It's empty for hooks.
+	ModuleName string
+
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack of the goroutine that panicked,
+	// formatted by [runtime/debug.Stack].
+	// It's nil for decorators and invoked functions,
+	// which Fx only learns about after the stack unwound.
+	Stack []byte
+}
+
+// OnPanic registers functions that Fx calls with every panic it recovers
+// from, before turning the panic into an error. Use it to report panics to
+// an error tracking service with their stack trace:
+//
+//	fx.RecoverFromPanics(),
+//	fx.OnPanic(func(p fx.PanicInfo) {
+//		sentry.CurrentHub().Recover(p.Value)
+//	}),
+//
+// OnPanic does not recover from panics by itself:
+// combine it with [RecoverFromPanics] or [RecoverPanicsIn].
+func OnPanic(funcs ...func(PanicInfo)) Option {
+	return onPanicOption(funcs)
+}
+
+type onPanicOption []func(PanicInfo)
+
+func (o onPanicOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.OnPanic Option should be passed to top-level " +
+			"App, not to fx.Module")
+		return
+	}
+	m.app.panicHandlers = append(m.app.panicHandlers, o...)
+}
+
+func (o onPanicOption) String() string {
+	names := make([]string, len(o))
+	for i, f := range o {
+		names[i] = fxreflect.FuncName(f)
+	}
+	return fmt.Sprintf("fx.OnPanic(%v)", names)
+}
+
+// onPanic reports a recovered panic to the OnPanic functions.
+func (app *App) onPanic(info PanicInfo) {
+	for _, f := range app.panicHandlers {
+		f(info)
+	}
+}
+
+// onDigPanic reports err to the OnPanic functions if it's a panic
+// that dig recovered from in the given function itself.
+func (m *module) onDigPanic(err error, kind, funcName string) {
+	// dig returns panics in the function itself unwrapped;
+	// wrapped panics happened in its dependencies, which report them.
+	if pe, ok := err.(dig.PanicError); ok && len(m.app.panicHandlers) > 0 {
+		m.app.onPanic(PanicInfo{
+			Phase:        PanicsInConstructors,
+			Kind:         kind,
+			FunctionName: funcName,
+			ModuleName:   m.name,
+			Value:        pe.Panic,
+		})
+	}
+}
+
+// panicContainer is a container that reports panics in constructors
+// provided to it with their stack, before dig recovers from them.
+type panicContainer struct {
+	container
+
+	module   *module
+	funcName string
+}
+
+var _ container = panicContainer{}
+
+func (c panicContainer) Provide(constructor interface{}, opts ...dig.ProvideOption) error {
+	return provideWrapped(c.container, constructor, opts, false, func(_ reflect.Type, call constructorFunc) constructorFunc {
+		return func(args []reflect.Value) []reflect.Value {
+			defer func() {
+				if p := recover(); p != nil {
+					c.module.app.onPanic(PanicInfo{
+						Phase:        PanicsInConstructors,
+						Kind:         "fx.Provide",
+						FunctionName: c.funcName,
+						ModuleName:   c.module.name,
+						Value:        p,
+						Stack:        debug.Stack(),
+					})
+					panic(p) // for dig to recover from
+				}
+			}()
+			return call(args)
+		}
+	})
+}
+
+// recoverHook returns a copy of h whose callbacks recover from panics,
+// reporting them to the OnPanic functions and returning them as errors.
+func (app *App) recoverHook(h Hook) Hook {
+	wrap := func(kind string, name *string, f func(context.Context) error) func(context.Context) error {
+		if f == nil {
+			return nil
+		}
+		if *name == "" {
+			*name = fxreflect.FuncName(f)
+		}
+		funcName := *name
+		return func(ctx context.Context) (err error) {
+			defer func() {
+				if p := recover(); p != nil {
+					app.onPanic(PanicInfo{
+						Phase:        PanicsInHooks,
+						Kind:         kind,
+						FunctionName: funcName,
+						Value:        p,
+						Stack:        debug.Stack(),
+					})
+					err = fmt.Errorf("panic: %v in %v hook: %q", p, kind, funcName)
+				}
+			}()
+			return f(ctx)
+		}
+	}
+	h.OnStart = wrap(_onStartHook, &h.onStartName, h.OnStart)
+	h.OnStop = wrap(_onStopHook, &h.onStopName, h.OnStop)
+	h.OnPause = wrap(_onPauseHook, &h.onPauseName, h.OnPause)
+	h.OnResume = wrap(_onResumeHook, &h.onResumeName, h.OnResume)
+	return h
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type panicky struct{}
+
+func newPanicky() *panicky { panic("great sadness") }
+
+func reportPanic(fx.PanicInfo) {}
+
+func TestOnPanic(t *testing.T) {
+	t.Parallel()
+
+	newApp := func(opts ...fx.Option) (*fx.App, *[]fx.PanicInfo) {
+		var panics []fx.PanicInfo
+		app := fx.New(
+			fx.NopLogger,
+			fx.RecoverFromPanics(),
+			fx.OnPanic(func(p fx.PanicInfo) { panics = append(panics, p) }),
+			fx.Options(opts...),
+		)
+		return app, &panics
+	}
+
+	t.Run("Provide", func(t *testing.T) {
+		t.Parallel()
+
+		app, panics := newApp(
+			fx.Module("sad",
+				fx.Provide(newPanicky),
+			),
+			fx.Invoke(func(*panicky) {}),
+		)
+		require.Error(t, app.Err())
+		assert.Contains(t, app.Err().Error(), `panic: "great sadness" in func: "go.uber.org/fx_test".newPanicky`)
+
+		require.Len(t, *panics, 1, "panic must be reported once")
+		p := (*panics)[0]
+		assert.Equal(t, fx.PanicsInConstructors, p.Phase)
+		assert.Equal(t, "fx.Provide", p.Kind)
+		assert.Equal(t, "go.uber.org/fx_test.newPanicky()", p.FunctionName)
+		assert.Equal(t, "sad", p.ModuleName)
+		assert.Equal(t, "great sadness", p.Value)
+		assert.Contains(t, string(p.Stack), "fx_test.newPanicky")
+	})
+
+	t.Run("Decorate", func(t *testing.T) {
+		t.Parallel()
+
+		app, panics := newApp(
+			fx.Supply(&panicky{}),
+			fx.Decorate(func(*panicky) *panicky { panic("great sadness") }),
+			fx.Invoke(func(*panicky) {}),
+		)
+		require.Error(t, app.Err())
+		require.Len(t, *panics, 1)
+		assert.Equal(t, "fx.Decorate", (*panics)[0].Kind)
+		assert.Equal(t, "great sadness", (*panics)[0].Value)
+	})
+
+	t.Run("Invoke", func(t *testing.T) {
+		t.Parallel()
+
+		app, panics := newApp(
+			fx.Invoke(func() { panic("great sadness") }),
+		)
+		require.Error(t, app.Err())
+		require.Len(t, *panics, 1)
+		p := (*panics)[0]
+		assert.Equal(t, "fx.Invoke", p.Kind)
+		assert.Equal(t, "great sadness", p.Value)
+		assert.Nil(t, p.Stack)
+	})
+}
+
+func TestRecoverPanicsIn(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Hooks", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			panics  []fx.PanicInfo
+			stopped bool
+		)
+		app := fxtest.New(t,
+			fx.RecoverPanicsIn(fx.PanicsInHooks),
+			fx.OnPanic(func(p fx.PanicInfo) { panics = append(panics, p) }),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StopHook(func() { stopped = true }))
+				lc.Append(fx.StopHook(func() { panic("great sadness") }))
+			}),
+		)
+		app.RequireStart()
+
+		err := app.Stop(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `panic: great sadness in OnStop hook`)
+		assert.True(t, stopped, "other hooks must still run")
+
+		require.Len(t, panics, 1)
+		assert.Equal(t, fx.PanicsInHooks, panics[0].Phase)
+		assert.Equal(t, "OnStop", panics[0].Kind)
+		assert.Contains(t, panics[0].FunctionName, "TestRecoverPanicsIn")
+		assert.NotEmpty(t, panics[0].Stack)
+	})
+
+	t.Run("StartHook", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.RecoverPanicsIn(fx.PanicsInHooks),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() error { panic(errors.New("great sadness")) }))
+			}),
+		)
+		err := app.Start(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "panic: great sadness in OnStart hook")
+	})
+
+	t.Run("PauseAndResumeHooks", func(t *testing.T) {
+		t.Parallel()
+
+		var panics []fx.PanicInfo
+		app := fxtest.New(t,
+			fx.RecoverPanicsIn(fx.PanicsInHooks),
+			fx.OnPanic(func(p fx.PanicInfo) { panics = append(panics, p) }),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.ResumeHook(func() { panic("great sadness") }))
+			}),
+		)
+		app.RequireStart()
+		defer app.RequireStop()
+
+		ctx := context.Background()
+		require.NoError(t, app.Pause(ctx))
+		err := app.Resume(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "panic: great sadness in OnResume hook")
+
+		require.Len(t, panics, 1)
+		assert.Equal(t, "OnResume", panics[0].Kind)
+	})
+
+	t.Run("HooksOnly", func(t *testing.T) {
+		t.Parallel()
+
+		assert.PanicsWithValue(t, "great sadness", func() {
+			fx.New(
+				fx.NopLogger,
+				fx.RecoverPanicsIn(fx.PanicsInHooks),
+				fx.Provide(newPanicky),
+				fx.Invoke(func(*panicky) {}),
+			)
+		}, "constructor panics must not be recovered")
+	})
+
+	t.Run("Constructors", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.RecoverPanicsIn(fx.PanicsInConstructors),
+			fx.Provide(newPanicky),
+			fx.Invoke(func(*panicky) {}),
+		)
+		require.Error(t, app.Err())
+		assert.Contains(t, app.Err().Error(), "great sadness")
+	})
+
+	t.Run("UnknownPhase", func(t *testing.T) {
+		t.Parallel()
+
+		err := fx.New(fx.NopLogger, fx.RecoverPanicsIn(fx.PanicPhase(42))).Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown phase PanicPhase(42)")
+	})
+
+	t.Run("InModule", func(t *testing.T) {
+		t.Parallel()
+
+		err := fx.New(fx.NopLogger,
+			fx.Module("mod",
+				fx.RecoverPanicsIn(fx.PanicsInHooks),
+			),
+		).Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.RecoverPanicsIn Option should be passed to top-level App")
+	})
+}
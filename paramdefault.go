@@ -0,0 +1,196 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"go.uber.org/dig"
+)
+
+// _defaultTag holds the value of an optional fx.In field
+// that is used when the field is not provided.
+const _defaultTag = "default"
+
+var (
+	_durationType        = reflect.TypeOf(time.Duration(0))
+	_textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	_digInType           = reflect.TypeOf(dig.In{})
+)
+
+// paramDefaults returns a function that fills in the fields of values of
+// the fx.In struct t that are tagged with a default value,
+// if the container c that the values were built from
+// does not provide them. For example:
+//
+//	type Params struct {
+//		fx.In
+//
+//		Port    int           `name:"port" optional:"true" default:"8080"`
+//		Timeout time.Duration `optional:"true" default:"5s"`
+//	}
+//
+// Defaults are supported for strings, booleans, numbers, durations,
+// and types that implement encoding.TextUnmarshaler.
+// Fields with a default must be optional.
+// Provided values are kept even if they are the zero value.
+//
+// It returns nil if t does not have any fields with a default.
+func paramDefaults(t reflect.Type) (func(v reflect.Value, c container), error) {
+	type fieldDefault struct {
+		index []int
+		parse func() (reflect.Value, error)
+		// Invoking probe succeeds only if the field's value is provided.
+		probe interface{}
+	}
+	var defaults []fieldDefault
+
+	var collect func(t reflect.Type, index []int) error
+	collect = func(t reflect.Type, index []int) error {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			idx := append(index[:len(index):len(index)], i)
+
+			if f.Type == _inAnnotationField.Type {
+				continue
+			}
+			if (f.Anonymous && f.Tag.Get(_inlineTag) == "true" && f.Type.Kind() == reflect.Struct) || isIn(f.Type) {
+				if err := collect(f.Type, idx); err != nil {
+					return err
+				}
+				continue
+			}
+
+			value, ok := f.Tag.Lookup(_defaultTag)
+			if !ok {
+				continue
+			}
+			if optional, _ := strconv.ParseBool(f.Tag.Get("optional")); !optional {
+				return fmt.Errorf(`field %v of %v has a default but is not optional: add optional:"true"`, f.Name, t)
+			}
+			if f.Tag.Get("group") != "" {
+				return fmt.Errorf("field %v of %v cannot have a default: value groups cannot have defaults", f.Name, t)
+			}
+
+			parse, err := defaultParser(f.Type, value)
+			if err != nil {
+				return fmt.Errorf("invalid default for field %v of %v: %w", f.Name, t, err)
+			}
+			// Report bad defaults when the function is provided,
+			// not when it is called.
+			if _, err := parse(); err != nil {
+				return fmt.Errorf("invalid default for field %v of %v: %w", f.Name, t, err)
+			}
+			defaults = append(defaults, fieldDefault{
+				index: idx,
+				parse: parse,
+				probe: presenceProbe(f),
+			})
+		}
+		return nil
+	}
+	if err := collect(t, nil); err != nil {
+		return nil, err
+	}
+	if len(defaults) == 0 {
+		return nil, nil
+	}
+
+	return func(v reflect.Value, c container) {
+		for _, d := range defaults {
+			if c.Invoke(d.probe) == nil {
+				continue
+			}
+			// The default was validated up front.
+			value, _ := d.parse()
+			v.FieldByIndex(d.index).Set(value)
+		}
+	}, nil
+}
+
+// presenceProbe returns a function that requires the value of the
+// optional field f, without marking it optional.
+// Invoking it fails if the container does not provide the value.
+func presenceProbe(f reflect.StructField) interface{} {
+	var tag reflect.StructTag
+	if name := f.Tag.Get("name"); name != "" {
+		tag = reflect.StructTag(fmt.Sprintf("name:%q", name))
+	}
+	in := reflect.StructOf([]reflect.StructField{
+		{Name: "In", Type: _digInType, Anonymous: true},
+		{Name: "Value", Type: f.Type, Tag: tag},
+	})
+	ft := reflect.FuncOf([]reflect.Type{in}, nil, false)
+	return reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+		return nil
+	}).Interface()
+}
+
+// defaultParser returns a function that parses s into a value of type t.
+// Values are parsed anew on each call so that pointers are not shared.
+func defaultParser(t reflect.Type, s string) (func() (reflect.Value, error), error) {
+	switch {
+	case reflect.PointerTo(t).Implements(_textUnmarshalerType):
+		return func() (reflect.Value, error) {
+			v := reflect.New(t)
+			err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+			return v.Elem(), err
+		}, nil
+	case t.Kind() == reflect.Pointer && t.Implements(_textUnmarshalerType):
+		return func() (reflect.Value, error) {
+			v := reflect.New(t.Elem())
+			err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+			return v, err
+		}, nil
+	case t == _durationType:
+		return func() (reflect.Value, error) {
+			d, err := time.ParseDuration(s)
+			return reflect.ValueOf(d), err
+		}, nil
+	}
+
+	var parse func() (interface{}, error)
+	switch t.Kind() {
+	case reflect.String:
+		parse = func() (interface{}, error) { return s, nil }
+	case reflect.Bool:
+		parse = func() (interface{}, error) { return strconv.ParseBool(s) }
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parse = func() (interface{}, error) { return strconv.ParseInt(s, 0, t.Bits()) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		parse = func() (interface{}, error) { return strconv.ParseUint(s, 0, t.Bits()) }
+	case reflect.Float32, reflect.Float64:
+		parse = func() (interface{}, error) { return strconv.ParseFloat(s, t.Bits()) }
+	default:
+		return nil, fmt.Errorf("defaults are not supported for %v", t)
+	}
+	return func() (reflect.Value, error) {
+		x, err := parse()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(x).Convert(t), nil
+	}, nil
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestParamDefaults(t *testing.T) {
+	t.Parallel()
+
+	type Params struct {
+		fx.In
+
+		Port    int           `name:"port" optional:"true" default:"8080"`
+		Host    string        `name:"host" optional:"true" default:"localhost"`
+		Debug   bool          `name:"debug" optional:"true" default:"true"`
+		Ratio   float64       `name:"ratio" optional:"true" default:"0.5"`
+		Timeout time.Duration `optional:"true" default:"5s"`
+		IP      net.IP        `optional:"true" default:"127.0.0.1"`
+	}
+
+	t.Run("missing values use defaults", func(t *testing.T) {
+		t.Parallel()
+
+		var got Params
+		app := fxtest.New(t, fx.Invoke(func(p Params) { got = p }))
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, 8080, got.Port)
+		assert.Equal(t, "localhost", got.Host)
+		assert.True(t, got.Debug)
+		assert.Equal(t, 0.5, got.Ratio)
+		assert.Equal(t, 5*time.Second, got.Timeout)
+		assert.Equal(t, "127.0.0.1", got.IP.String())
+	})
+
+	t.Run("provided values win", func(t *testing.T) {
+		t.Parallel()
+
+		var got Params
+		app := fxtest.New(t,
+			fx.Supply(
+				fx.Annotated{Name: "port", Target: 9090},
+				fx.Annotated{Name: "host", Target: "example.com"},
+				time.Second,
+			),
+			fx.Invoke(func(p Params) { got = p }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, 9090, got.Port)
+		assert.Equal(t, "example.com", got.Host)
+		assert.Equal(t, time.Second, got.Timeout)
+	})
+
+	t.Run("provided zero values win", func(t *testing.T) {
+		t.Parallel()
+
+		var got Params
+		app := fxtest.New(t,
+			fx.Supply(
+				fx.Annotated{Name: "port", Target: 0},
+				fx.Annotated{Name: "debug", Target: false},
+			),
+			fx.Module("server",
+				fx.Provide(
+					fx.Private,
+					func() time.Duration { return 0 },
+				),
+				fx.Invoke(func(p Params) { got = p }),
+			),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, 0, got.Port)
+		assert.False(t, got.Debug)
+		assert.Zero(t, got.Timeout)
+		assert.Equal(t, "localhost", got.Host, "missing values still use defaults")
+	})
+
+	t.Run("inline and nested structs", func(t *testing.T) {
+		t.Parallel()
+
+		type Common struct {
+			Host string `name:"host" optional:"true" default:"localhost"`
+		}
+		type Nested struct {
+			fx.In
+
+			Port int `name:"port" optional:"true" default:"8080"`
+		}
+		type Outer struct {
+			fx.In
+			Common `inline:"true"`
+
+			Nested Nested
+		}
+
+		var got Outer
+		app := fxtest.New(t, fx.Invoke(func(p Outer) { got = p }))
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "localhost", got.Host)
+		assert.Equal(t, 8080, got.Nested.Port)
+	})
+
+	t.Run("param tags", func(t *testing.T) {
+		t.Parallel()
+
+		var got int
+		app := fxtest.New(t,
+			fx.Invoke(fx.Annotate(
+				func(port int) { got = port },
+				fx.ParamTags(`name:"port" optional:"true" default:"8080"`),
+			)),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, 8080, got)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		type NotOptional struct {
+			fx.In
+
+			Port int `name:"port" default:"8080"`
+		}
+		type BadValue struct {
+			fx.In
+
+			Port int `name:"port" optional:"true" default:"eighty"`
+		}
+		type Unsupported struct {
+			fx.In
+
+			Ports []int `name:"ports" optional:"true" default:"80"`
+		}
+		type Group struct {
+			fx.In
+
+			Ports []int `group:"ports" optional:"true" default:"80"`
+		}
+
+		tests := []struct {
+			desc    string
+			give    interface{}
+			wantErr string
+		}{
+			{
+				desc:    "not optional",
+				give:    func(NotOptional) {},
+				wantErr: "field Port of fx_test.NotOptional has a default but is not optional",
+			},
+			{
+				desc:    "bad value",
+				give:    func(BadValue) {},
+				wantErr: `invalid default for field Port of fx_test.BadValue: strconv.ParseInt: parsing "eighty": invalid syntax`,
+			},
+			{
+				desc:    "unsupported type",
+				give:    func(Unsupported) {},
+				wantErr: "invalid default for field Ports of fx_test.Unsupported: defaults are not supported for []int",
+			},
+			{
+				desc:    "value group",
+				give:    func(Group) {},
+				wantErr: "field Ports of fx_test.Group cannot have a default",
+			},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, fx.Invoke(tt.give))
+				require.Error(t, app.Err())
+				assert.ErrorContains(t, app.Err(), tt.wantErr)
+			})
+		}
+	})
+
+	t.Run("result tags", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.Provide(fx.Annotate(func() int { return 0 }, fx.ResultTags(`default:"1"`))),
+		)
+		assert.ErrorContains(t, app.Err(), "default tags can only be used with fx.ParamTags")
+	})
+}
@@ -58,43 +58,78 @@ import (
 //	...
 //	fx.Populate(&target)
 //
+// Targets may also be functions, optionally annotated with [Annotate],
+// which are called eagerly with their parameters like [Invoke]
+// after the pointer targets are populated.
+// This extracts several values without declaring a pointer for each one:
+//
+//	var (
+//		db  *sql.DB
+//		cfg Config
+//	)
+//	fx.Populate(func(d *sql.DB, c Config) {
+//		db, cfg = d, c
+//	})
+//
 // This is most helpful in unit tests: it lets tests leverage Fx's automatic
 // constructor wiring to build a few structs, but then extract those structs
 // for further testing.
 func Populate(targets ...interface{}) Option {
-	// Validate all targets are non-nil pointers.
-	fields := make([]reflect.StructField, len(targets)+1)
-	fields[0] = reflect.StructField{
+	// Validate all targets are non-nil pointers or functions.
+	fields := []reflect.StructField{{
 		Name:      "In",
 		Type:      reflect.TypeOf(In{}),
 		Anonymous: true,
-	}
+	}}
+	var ptrs, funcs []interface{}
 	for i, t := range targets {
 		if t == nil {
 			return Error(fmt.Errorf("failed to Populate: target %v is nil", i+1))
 		}
 		var (
-			rt  reflect.Type
-			tag reflect.StructTag
+			rt     reflect.Type
+			tag    reflect.StructTag
+			target = t
 		)
 		switch t := t.(type) {
 		case annotated:
 			rt = reflect.TypeOf(t.Target)
-			tag = reflect.StructTag(t.ParamTags[0])
-			targets[i] = t.Target
+			if rt.Kind() == reflect.Ptr {
+				if len(t.ParamTags) > 0 {
+					tag = reflect.StructTag(t.ParamTags[0])
+				}
+				target = t.Target
+			}
 		default:
 			rt = reflect.TypeOf(t)
 		}
-		if rt.Kind() != reflect.Ptr {
-			return Error(fmt.Errorf("failed to Populate: target %v is not a pointer type, got %T", i+1, t))
+		switch rt.Kind() {
+		case reflect.Ptr:
+			ptrs = append(ptrs, target)
+		case reflect.Func:
+			funcs = append(funcs, target)
+			continue
+		default:
+			return Error(fmt.Errorf("failed to Populate: target %v is not a pointer or function type, got %T", i+1, t))
 		}
-		fields[i+1] = reflect.StructField{
+		fields = append(fields, reflect.StructField{
 			Name: fmt.Sprintf("Field%d", i),
 			Type: rt.Elem(),
 			Tag:  tag,
+		})
+	}
+	if len(funcs) > 0 {
+		if len(ptrs) == 0 {
+			return Invoke(funcs...)
 		}
+		return Options(populate(fields, ptrs), Invoke(funcs...))
 	}
+	return populate(fields, ptrs)
+}
 
+// populate returns an Invoke that sets each of targets
+// to the value of the corresponding field of an fx.In struct.
+func populate(fields []reflect.StructField, targets []interface{}) Option {
 	// Build a function that looks like:
 	//
 	// func(t1 T1, t2 T2, ...) {
@@ -21,6 +21,7 @@
 package fx_test
 
 import (
+	"errors"
 	"io"
 	"strings"
 	"testing"
@@ -224,6 +225,61 @@ func TestPopulate(t *testing.T) {
 		assert.False(t, v1 == v2, "values should be different")
 	})
 
+	t.Run("populate function", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			v1 *t1
+			v2 *t2
+		)
+		app := fxtest.New(t,
+			Provide(func() *t1 { return &t1{} }),
+			Provide(func() *t2 { return &t2{} }),
+			Populate(func(a *t1, b *t2) {
+				v1, v2 = a, b
+			}),
+		)
+		app.RequireStart().RequireStop()
+		require.NotNil(t, v1, "did not populate value")
+		require.NotNil(t, v2, "did not populate value")
+	})
+
+	t.Run("populate pointers and functions", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			v1, fromFunc *t1
+			named        *t2
+		)
+		app := fxtest.New(t,
+			Provide(func() *t1 { return &t1{} }),
+			Provide(Annotate(func() *t2 { return &t2{} }, ResultTags(`name:"n"`))),
+			Populate(
+				func(v *t1) {
+					require.NotNil(t, v1, "pointers must be populated first")
+					fromFunc = v
+				},
+				&v1,
+				Annotate(func(v *t2) { named = v }, ParamTags(`name:"n"`)),
+			),
+		)
+		app.RequireStart().RequireStop()
+		assert.Same(t, v1, fromFunc)
+		require.NotNil(t, named, "did not populate named value")
+	})
+
+	t.Run("populate function error", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			NopLogger,
+			Provide(func() *t1 { return &t1{} }),
+			Populate(func(*t1) error { return errors.New("great sadness") }),
+		)
+		require.Error(t, app.Err())
+		assert.Contains(t, app.Err().Error(), "great sadness")
+	})
+
 	t.Run("populate group", func(t *testing.T) {
 		t.Parallel()
 
@@ -296,9 +352,9 @@ func TestPopulateErrors(t *testing.T) {
 			wantErr: "missing type: fx_test.containerNoIn",
 		},
 		{
-			msg:     "function",
-			opt:     Populate(fn),
-			wantErr: "not a pointer",
+			msg:     "function with missing dependency",
+			opt:     Populate(func(string) {}),
+			wantErr: "missing type: string",
 		},
 		{
 			msg:     "function pointer",
@@ -308,7 +364,7 @@ func TestPopulateErrors(t *testing.T) {
 		{
 			msg:     "invalid last argument",
 			opt:     Populate(&v, t1{}),
-			wantErr: "target 2 is not a pointer type",
+			wantErr: "target 2 is not a pointer or function type",
 		},
 		{
 			msg:     "nil argument",
@@ -365,9 +421,9 @@ func TestPopulateValidateApp(t *testing.T) {
 			wantErr: "missing type: fx_test.containerNoIn",
 		},
 		{
-			msg:     "function",
-			opts:    []interface{}{fn},
-			wantErr: "not a pointer",
+			msg:     "function with missing dependency",
+			opts:    []interface{}{func(string) {}},
+			wantErr: "missing type: string",
 		},
 		{
 			msg:     "function pointer",
@@ -377,7 +433,7 @@ func TestPopulateValidateApp(t *testing.T) {
 		{
 			msg:     "invalid last argument",
 			opts:    []interface{}{&v, t1{}},
-			wantErr: "target 2 is not a pointer type",
+			wantErr: "target 2 is not a pointer or function type",
 		},
 		{
 			msg:     "nil argument",
@@ -0,0 +1,100 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/pprof"
+
+	"go.uber.org/dig"
+)
+
+// ProfileLabels causes Fx to run constructors with [runtime/pprof] labels
+// identifying them, so that CPU and goroutine profiles taken while the
+// application is starting attribute their samples to specific constructors.
+// Heap profiles don't record labels:
+// use [ReportConstructorAllocations] to attribute allocations instead.
+//
+// Constructors run with the following labels:
+//
+//	fx.constructor: fully qualified name of the constructor
+//	fx.module:      name of the fx.Module that provided it, if any
+//
+// For example, to view the CPU time spent in each constructor:
+//
+//	go tool pprof -tagfocus=fx.constructor=. -tags cpu.prof
+//
+// Go provides no way to read the labels already set on a goroutine,
+// so Fx can't restore them:
+// constructors run with the labels above only,
+// and the goroutine that calls [New] is left without labels
+// once a constructor has run.
+// Set labels again after New if the rest of the program relies on them.
+func ProfileLabels() Option {
+	return profileLabelsOption{}
+}
+
+type profileLabelsOption struct{}
+
+func (o profileLabelsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.ProfileLabels Option should be passed to top-level " +
+			"App, not to fx.Module")
+	} else {
+		m.app.profileLabels = true
+	}
+}
+
+func (o profileLabelsOption) String() string {
+	return "fx.ProfileLabels()"
+}
+
+// labeledContainer is a container that runs constructors provided to it
+// with the given pprof labels.
+type labeledContainer struct {
+	container
+
+	labels pprof.LabelSet
+}
+
+var _ container = labeledContainer{}
+
+// constructorLabels builds the pprof labels for a constructor
+// provided by the given module.
+func constructorLabels(m *module, funcName string) pprof.LabelSet {
+	if m.name == "" {
+		return pprof.Labels("fx.constructor", funcName)
+	}
+	return pprof.Labels("fx.constructor", funcName, "fx.module", m.name)
+}
+
+func (c labeledContainer) Provide(constructor interface{}, opts ...dig.ProvideOption) error {
+	return provideWrapped(c.container, constructor, opts, false, func(_ reflect.Type, call constructorFunc) constructorFunc {
+		return func(args []reflect.Value) (results []reflect.Value) {
+			pprof.Do(context.Background(), c.labels, func(context.Context) {
+				results = call(args)
+			})
+			return results
+		}
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"bytes"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+// goroutineLabels returns the goroutine profile of the running program,
+// which includes the labels of every goroutine.
+func goroutineLabels(t *testing.T) string {
+	var buf bytes.Buffer
+	require.NoError(t, pprof.Lookup("goroutine").WriteTo(&buf, 1))
+	return buf.String()
+}
+
+func TestProfileLabels(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+
+	newA := func() *A { return &A{} }
+
+	t.Run("constructors are labeled", func(t *testing.T) {
+		t.Parallel()
+
+		var profile string
+		app := fxtest.New(t,
+			fx.ProfileLabels(),
+			fx.Provide(newA),
+			fx.Module("child",
+				fx.Provide(func(*A) *B {
+					profile = goroutineLabels(t)
+					return &B{}
+				}),
+			),
+			fx.Invoke(func(*B) {}),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Contains(t, profile, `"fx.module":"child"`)
+		assert.Contains(t, profile, `"fx.constructor":"go.uber.org/fx_test.TestProfileLabels.func`)
+	})
+
+	t.Run("annotated and variadic constructors", func(t *testing.T) {
+		t.Parallel()
+
+		var annotated, variadic string
+		app := fxtest.New(t,
+			fx.ProfileLabels(),
+			fx.Provide(
+				fx.Annotate(func() *A {
+					annotated = goroutineLabels(t)
+					return &A{}
+				}),
+				func(*A, ...string) *B {
+					variadic = goroutineLabels(t)
+					return &B{}
+				},
+			),
+			fx.Invoke(func(*B) {}),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Contains(t, annotated, `"fx.constructor":`)
+		assert.Contains(t, variadic, `"fx.constructor":`)
+	})
+
+	t.Run("constructor names are preserved", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.ProfileLabels(),
+			fx.Provide(newA),
+			fx.Provide(func(*C) *B { return &B{} }),
+			fx.Invoke(func(*A, *B) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "reflect.makeFuncStub")
+
+		runs := spy.Events().SelectByTypeName("Run")
+		require.Len(t, runs, 1)
+		assert.Contains(t, runs[0].(*fxevent.Run).Name, "TestProfileLabels")
+	})
+
+	t.Run("not allowed in modules", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Module("child", fx.ProfileLabels()),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.ProfileLabels Option should be passed to top-level App")
+	})
+}
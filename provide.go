@@ -27,6 +27,7 @@ import (
 
 	"go.uber.org/dig"
 	"go.uber.org/fx/internal/fxreflect"
+	"go.uber.org/multierr"
 )
 
 // Provide registers any number of constructor functions, teaching the
@@ -44,6 +45,17 @@ import (
 //	// Constructs types *B and *C, depends on *A, and can fail.
 //	func(*A) (*B, *C, error)
 //
+// Constructors that report failures through several trailing errors,
+// or through a trailing []error, are also accepted.
+// Their errors are combined into one, which is nil if all of them are nil.
+// This eases wrapping legacy factory functions.
+//
+//	// Constructs type *C and can fail in two ways.
+//	func(*A) (*C, error, error)
+//
+//	// Constructs type *C and can fail in many ways.
+//	func(*A) (*C, []error)
+//
 // The order in which constructors are provided doesn't matter, and passing
 // multiple Provide options appends to the application's collection of
 // constructors. Constructors are called only if one or more of their returned
@@ -71,27 +83,40 @@ func Provide(constructors ...interface{}) Option {
 type provideOption struct {
 	Targets []interface{}
 	Stack   fxreflect.Stack
+	Default bool // whether this is an fx.ProvideDefault
 }
 
 func (o provideOption) apply(mod *module) {
-	var private bool
+	var (
+		private bool
+		meta    map[string]string
+	)
 
 	targets := make([]interface{}, 0, len(o.Targets))
 	for _, target := range o.Targets {
-		if _, ok := target.(privateOption); ok {
+		switch t := target.(type) {
+		case privateOption:
 			private = true
 			continue
+		case metaOption:
+			meta = t.addMeta(meta)
+			continue
 		}
 		targets = append(targets, target)
 	}
 
 	for _, target := range targets {
 		mod.provides = append(mod.provides, provide{
-			Target:  target,
-			Stack:   o.Stack,
-			Private: private,
+			Target:    target,
+			Stack:     o.Stack,
+			Private:   private,
+			Meta:      meta,
+			IsDefault: o.Default,
 		})
 	}
+	if o.Default {
+		mod.app.hasDuplicatePolicy = true
+	}
 }
 
 type privateOption struct{}
@@ -114,9 +139,17 @@ var Private = privateOption{}
 func (o provideOption) String() string {
 	items := make([]string, len(o.Targets))
 	for i, c := range o.Targets {
+		if m, ok := c.(metaOption); ok {
+			items[i] = fmt.Sprintf("fx.Meta(%q, %q)", m.key, m.value)
+			continue
+		}
 		items[i] = fxreflect.FuncName(c)
 	}
-	return fmt.Sprintf("fx.Provide(%s)", strings.Join(items, ", "))
+	name := "fx.Provide"
+	if o.Default {
+		name = "fx.ProvideDefault"
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(items, ", "))
 }
 
 func runProvide(c container, p provide, opts ...dig.ProvideOption) error {
@@ -135,12 +168,22 @@ func runProvide(c container, p provide, opts ...dig.ProvideOption) error {
 			fxreflect.FuncName(constructor.target), constructor.err)
 
 	case annotated:
+		pc := constructor.FuncPtr
+		if pc == 0 {
+			// Report the annotated function, not the one Build generates.
+			pc = providePC(constructor.Target)
+		}
 		ctor, err := constructor.Build()
 		if err != nil {
 			return fmt.Errorf("fx.Provide(%v) from:\n%+vFailed: %w", constructor, p.Stack, err)
 		}
 
-		opts = append(opts, dig.LocationForPC(constructor.FuncPtr))
+		ctor, _, err = flattenStructs(c, ctor)
+		if err != nil {
+			return fmt.Errorf("fx.Provide(%v) from:\n%+vFailed: %w", constructor, p.Stack, err)
+		}
+
+		opts = append(opts, dig.LocationForPC(pc))
 		if err := c.Provide(ctor, opts...); err != nil {
 			return fmt.Errorf("fx.Provide(%v) from:\n%+vFailed: %w", constructor, p.Stack, err)
 		}
@@ -152,18 +195,58 @@ func runProvide(c container, p provide, opts ...dig.ProvideOption) error {
 			return fmt.Errorf(
 				"fx.Annotated may specify only one of Name or Group: received %v from:\n%+v",
 				ann, p.Stack)
+		}
+
+		switch target := ann.Target.(type) {
+		case annotationError:
+			return fmt.Errorf(
+				"encountered error while applying annotation using fx.Annotate to %s: %w",
+				fxreflect.FuncName(target.target), target.err)
+
+		case annotated:
+			// Build the fx.Annotate constructor,
+			// and apply the name or group on top of its annotations.
+			pc := reflect.ValueOf(target.Target).Pointer()
+			ctor, err := target.Build()
+			if err == nil {
+				ctor, err = ann.tagResults(ctor)
+			}
+			if err == nil {
+				ctor, _, err = flattenStructs(c, ctor)
+			}
+			if err != nil {
+				return fmt.Errorf("fx.Provide(%v) from:\n%+vFailed: %w", ann, p.Stack, err)
+			}
+
+			opts = append(opts, dig.LocationForPC(pc))
+			if err := c.Provide(ctor, opts...); err != nil {
+				return fmt.Errorf("fx.Provide(%v) from:\n%+vFailed: %w", ann, p.Stack, err)
+			}
+			return nil
+		}
+
+		switch {
 		case len(ann.Name) > 0:
 			opts = append(opts, dig.Name(ann.Name))
 		case len(ann.Group) > 0:
 			opts = append(opts, dig.Group(ann.Group))
 		}
 
-		if err := c.Provide(ann.Target, opts...); err != nil {
+		target, folded := foldErrors(ann.Target)
+		target, flattened, err := flattenStructs(c, target)
+		if err != nil {
+			return fmt.Errorf("fx.Provide(%v) from:\n%+vFailed: %w", ann, p.Stack, err)
+		}
+		if folded || flattened {
+			opts = append(opts, dig.LocationForPC(reflect.ValueOf(ann.Target).Pointer()))
+		}
+
+		if err := c.Provide(target, opts...); err != nil {
 			return fmt.Errorf("fx.Provide(%v) from:\n%+vFailed: %w", ann, p.Stack, err)
 		}
 
 	default:
-		if reflect.TypeOf(constructor).Kind() == reflect.Func {
+		if constructor != nil && reflect.TypeOf(constructor).Kind() == reflect.Func {
 			ft := reflect.ValueOf(constructor).Type()
 
 			for i := 0; i < ft.NumOut(); i++ {
@@ -179,9 +262,84 @@ func runProvide(c container, p provide, opts ...dig.ProvideOption) error {
 			}
 		}
 
-		if err := c.Provide(constructor, opts...); err != nil {
+		ctor, folded := foldErrors(constructor)
+		ctor, flattened, err := flattenStructs(c, ctor)
+		if err != nil {
+			return fmt.Errorf("fx.Provide(%v) from:\n%+vFailed: %w", fxreflect.FuncName(constructor), p.Stack, err)
+		}
+		if folded || flattened {
+			opts = append(opts, dig.LocationForPC(reflect.ValueOf(constructor).Pointer()))
+		}
+
+		if err := c.Provide(ctor, opts...); err != nil {
 			return fmt.Errorf("fx.Provide(%v) from:\n%+vFailed: %w", fxreflect.FuncName(constructor), p.Stack, err)
 		}
 	}
 	return nil
 }
+
+var _typeOfErrors = reflect.TypeOf([]error(nil))
+
+// foldErrors returns a function equivalent to fn
+// whose trailing error results are combined into one.
+// It applies to functions that end with two or more error results,
+// or with a []error result.
+//
+// It reports whether fn had such results.
+// If it didn't, fn is returned unchanged.
+func foldErrors(fn interface{}) (interface{}, bool) {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func {
+		return fn, false
+	}
+
+	ft := fv.Type()
+	numOut := ft.NumOut()
+	first := numOut // index of the first folded result
+	switch {
+	case numOut > 0 && ft.Out(numOut-1) == _typeOfErrors:
+		first = numOut - 1
+	default:
+		for first > 0 && ft.Out(first-1) == _typeOfError {
+			first--
+		}
+		if numOut-first < 2 {
+			return fn, false
+		}
+	}
+
+	ins := make([]reflect.Type, ft.NumIn())
+	for i := range ins {
+		ins[i] = ft.In(i)
+	}
+	outs := make([]reflect.Type, 0, first+1)
+	for i := 0; i < first; i++ {
+		outs = append(outs, ft.Out(i))
+	}
+	outs = append(outs, _typeOfError)
+
+	call := fv.Call
+	if ft.IsVariadic() {
+		call = fv.CallSlice
+	}
+	newFt := reflect.FuncOf(ins, outs, ft.IsVariadic())
+	return reflect.MakeFunc(newFt, func(args []reflect.Value) []reflect.Value {
+		results := call(args)
+
+		var errs []error
+		for _, r := range results[first:] {
+			switch err := r.Interface().(type) {
+			case error:
+				errs = append(errs, err)
+			case []error:
+				errs = append(errs, err...)
+			}
+		}
+
+		err := reflect.Zero(_typeOfError)
+		if e := multierr.Combine(errs...); e != nil {
+			err = reflect.ValueOf(&e).Elem()
+		}
+		return append(results[:first], err)
+	}).Interface(), true
+}
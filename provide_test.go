@@ -0,0 +1,124 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestProvideFoldsErrors(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+
+	errA := errors.New("great sadness")
+	errB := errors.New("even greater sadness")
+
+	tests := []struct {
+		desc    string
+		give    interface{}
+		wantErr []error
+	}{
+		{
+			desc: "error tail/success",
+			give: func() (*A, error, error) { return &A{}, nil, nil },
+		},
+		{
+			desc:    "error tail/first fails",
+			give:    func() (*A, error, error) { return nil, errA, nil },
+			wantErr: []error{errA},
+		},
+		{
+			desc:    "error tail/both fail",
+			give:    func() (*A, error, error) { return nil, errA, errB },
+			wantErr: []error{errA, errB},
+		},
+		{
+			desc: "slice tail/success",
+			give: func() (*A, []error) { return &A{}, nil },
+		},
+		{
+			desc:    "slice tail/failure",
+			give:    func() (*A, []error) { return nil, []error{errA, nil, errB} },
+			wantErr: []error{errA, errB},
+		},
+		{
+			desc: "annotated",
+			give: fx.Annotate(
+				func() (*A, error, error) { return &A{}, nil, nil },
+				fx.ResultTags(`name:"a"`),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			app := NewForTest(t,
+				fx.Provide(tt.give),
+				fx.Invoke(fx.Annotate(func(*A) {}, fx.ParamTags(`optional:"true"`))),
+				fx.Invoke(fx.Annotate(func(*A) {}, fx.ParamTags(`name:"a" optional:"true"`))),
+			)
+			if len(tt.wantErr) == 0 {
+				require.NoError(t, app.Err())
+				return
+			}
+
+			err := app.Err()
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.ErrorIs(t, err, want)
+			}
+		})
+	}
+
+	t.Run("combined error", func(t *testing.T) {
+		t.Parallel()
+
+		var a *A
+		app := NewForTest(t,
+			fx.Provide(func() (*A, error, error) { return nil, errA, errB }),
+			fx.Populate(&a),
+		)
+		require.Error(t, app.Err())
+		assert.ErrorContains(t, app.Err(), "great sadness; even greater sadness")
+	})
+
+	t.Run("lone error is untouched", func(t *testing.T) {
+		t.Parallel()
+
+		var a *A
+		app := fxtest.New(t,
+			fx.Provide(func() (*A, error) { return &A{}, nil }),
+			fx.Populate(&a),
+		)
+		defer app.RequireStart().RequireStop()
+		assert.NotNil(t, a)
+	})
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// ProvideAll registers the constructors in the given slice, as with
+// [Provide]. It's meant for constructors gathered programmatically,
+// like the contents of a generated registry:
+//
+//	fx.ProvideAll(registry.Constructors())
+//
+// Unlike Provide, a constructor that fails to be provided does not prevent
+// the others from being provided: the application fails with the errors of
+// all of them, each naming the index of the offending constructor.
+//
+// As with Provide, the slice may contain [Private]
+// to restrict access to the constructors.
+func ProvideAll(constructors []interface{}) Option {
+	entries := make([]bulkEntry, len(constructors))
+	for i, c := range constructors {
+		entries[i] = bulkEntry{key: strconv.Itoa(i), target: c}
+	}
+	return bulkProvideOption{
+		name:    "fx.ProvideAll",
+		entries: entries,
+		stack:   fxreflect.CallerStack(1, 0),
+	}
+}
+
+// ProvideMap registers the constructors in the given map, as with
+// [ProvideAll]. Errors name the key of the offending constructor.
+//
+//	fx.ProvideMap(map[string]any{
+//		"json": codec.NewJSON,
+//		"yaml": codec.NewYAML,
+//	})
+//
+// Constructors are provided in the order of their keys.
+func ProvideMap(constructors map[string]interface{}) Option {
+	keys := make([]string, 0, len(constructors))
+	for k := range constructors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	entries := make([]bulkEntry, len(keys))
+	for i, k := range keys {
+		entries[i] = bulkEntry{key: strconv.Quote(k), target: constructors[k]}
+	}
+	return bulkProvideOption{
+		name:    "fx.ProvideMap",
+		entries: entries,
+		stack:   fxreflect.CallerStack(1, 0),
+	}
+}
+
+// bulkEntry is a constructor passed to fx.ProvideAll or fx.ProvideMap,
+// along with the key it's reported under.
+type bulkEntry struct {
+	key    string
+	target interface{}
+}
+
+type bulkProvideOption struct {
+	name    string
+	entries []bulkEntry
+	stack   fxreflect.Stack
+}
+
+func (o bulkProvideOption) apply(mod *module) {
+	var private bool
+	for _, e := range o.entries {
+		if _, ok := e.target.(privateOption); ok {
+			private = true
+		}
+	}
+
+	bulk := &bulkProvide{name: o.name}
+	for _, e := range o.entries {
+		if _, ok := e.target.(privateOption); ok {
+			continue
+		}
+		mod.provides = append(mod.provides, provide{
+			Target:  e.target,
+			Stack:   o.stack,
+			Private: private,
+			Bulk:    bulk,
+			BulkKey: e.key,
+		})
+	}
+}
+
+func (o bulkProvideOption) String() string {
+	items := make([]string, len(o.entries))
+	for i, e := range o.entries {
+		items[i] = fxreflect.FuncName(e.target)
+		if o.name == "fx.ProvideMap" {
+			items[i] = e.key + ": " + items[i]
+		}
+	}
+	return fmt.Sprintf("%s(%s)", o.name, strings.Join(items, ", "))
+}
+
+// bulkProvide tracks the constructors of a single fx.ProvideAll
+// or fx.ProvideMap, so that a failure to provide one of them
+// does not prevent the others from being provided.
+type bulkProvide struct {
+	name string
+
+	// Application error once a constructor of this option failed.
+	// Constructors of this option are still provided while the
+	// application error is this one.
+	err error
+}
+
+// wrap annotates err with the key of the constructor that caused it.
+func (b *bulkProvide) wrap(key string, err error) error {
+	return fmt.Errorf("%v entry %v: %w", b.name, key, err)
+}
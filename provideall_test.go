@@ -0,0 +1,163 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxlog"
+	"go.uber.org/multierr"
+)
+
+func TestProvideAll(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+
+	t.Run("slice", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			a *A
+			b *B
+		)
+		app := fxtest.New(t,
+			fx.ProvideAll([]any{
+				func() *A { return &A{} },
+				func() *B { return &B{} },
+			}),
+			fx.Populate(&a, &b),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.NotNil(t, a)
+		assert.NotNil(t, b)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			a *A
+			b *B
+		)
+		app := fxtest.New(t,
+			fx.ProvideMap(map[string]any{
+				"a": func() *A { return &A{} },
+				"b": fx.Annotate(func() *B { return &B{} }),
+			}),
+			fx.Populate(&a, &b),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.NotNil(t, a)
+		assert.NotNil(t, b)
+	})
+
+	t.Run("private", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.Module("child",
+				fx.ProvideAll([]any{func() *A { return &A{} }, fx.Private}),
+			),
+			fx.Invoke(func(*A) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *fx_test.A")
+	})
+
+	t.Run("reports every failure", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return &spy }),
+			fx.ProvideMap(map[string]any{
+				"a":   func() *A { return &A{} },
+				"bad": 42,
+				"dup": func() *A { return &A{} },
+				"c":   func() *C { return &C{} },
+			}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+
+		errs := multierr.Errors(err)
+		require.Len(t, errs, 2)
+		assert.Contains(t, errs[0].Error(), `fx.ProvideMap entry "bad": `)
+		assert.Contains(t, errs[1].Error(), `fx.ProvideMap entry "dup": `)
+		assert.Contains(t, errs[1].Error(), "already provided")
+
+		provided := spy.Events().SelectByTypeName("Provided")
+		var names []string
+		for _, e := range provided {
+			p := e.(*fxevent.Provided)
+			if p.Err == nil {
+				names = append(names, p.OutputTypeNames...)
+			}
+		}
+		assert.Contains(t, names, "*fx_test.C", "entries after a failure must be provided")
+	})
+
+	t.Run("slice index", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.ProvideAll([]any{
+				func() *A { return &A{} },
+				func() (*B, error) { return nil, errors.New("unused") },
+				nil,
+			}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.ProvideAll entry 2: ")
+		assert.NotContains(t, err.Error(), "entry 1")
+	})
+
+	t.Run("earlier failure", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return &spy }),
+			fx.Provide(42),
+			fx.ProvideAll([]any{func() *A { return &A{} }}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "fx.ProvideAll")
+		for _, e := range spy.Events().SelectByTypeName("Provided") {
+			assert.NotContains(t, e.(*fxevent.Provided).OutputTypeNames, "*fx_test.A",
+				"entries must not be provided after an unrelated failure")
+		}
+	})
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// RetryPolicy specifies how often a constructor provided with
+// [ProvideWithRetry], or a hook annotated with [OnStartRetry], is retried.
+// Build one with [Retry].
+type RetryPolicy struct {
+	attempts int
+	backoff  time.Duration
+}
+
+// Retry returns a policy that calls a constructor up to attempts times
+// until it succeeds.
+// Fx waits for backoff after the first failed attempt,
+// and doubles the wait after each subsequent one, up to a minute.
+//
+// The waits between attempts to call a constructor
+// are not bound by [StartTimeout]:
+// constructors run in [New], before the application starts.
+func Retry(attempts int, backoff time.Duration) RetryPolicy {
+	return RetryPolicy{attempts: attempts, backoff: backoff}
+}
+
+func (p RetryPolicy) String() string {
+	return fmt.Sprintf("fx.Retry(%d, %v)", p.attempts, p.backoff)
+}
+
+// _maxRetryBackoff is the longest wait that doubling grows to.
+const _maxRetryBackoff = time.Minute
+
+// nextBackoff returns the wait that follows a wait of delay.
+func nextBackoff(delay time.Duration) time.Duration {
+	if delay >= _maxRetryBackoff/2 {
+		return max(delay, _maxRetryBackoff)
+	}
+	return delay * 2
+}
+
+// ProvideWithRetry registers a constructor like [Provide],
+// retrying it per the given policy when it returns an error.
+// This is intended for constructors that connect to external services,
+// which may not be available yet when the application starts.
+//
+//	fx.ProvideWithRetry(db.Open, fx.Retry(5, time.Second))
+//
+// Each failed attempt that is retried is reported
+// as an [fxevent.Retrying] event.
+// If all attempts fail, the application fails
+// with the error returned by the last attempt.
+//
+// Hooks that the constructor appends to an [fx.Lifecycle] it depends on
+// are only kept for the attempt that succeeds.
+//
+// The constructor must be a function whose last result is an error.
+// Annotated constructors are not supported.
+func ProvideWithRetry(constructor interface{}, policy RetryPolicy) Option {
+	return provideWithRetryOption{
+		Target: constructor,
+		Policy: policy,
+		Stack:  fxreflect.CallerStack(1, 0),
+	}
+}
+
+type provideWithRetryOption struct {
+	Target interface{}
+	Policy RetryPolicy
+	Stack  fxreflect.Stack
+}
+
+func (o provideWithRetryOption) apply(m *module) {
+	if err := o.validate(); err != nil {
+		m.app.err = fmt.Errorf("fx.ProvideWithRetry(%v) from:\n%+vFailed: %w",
+			fxreflect.FuncName(o.Target), o.Stack, err)
+		return
+	}
+
+	policy := o.Policy
+	m.provides = append(m.provides, provide{
+		Target: o.Target,
+		Stack:  o.Stack,
+		Retry:  &policy,
+	})
+}
+
+func (o provideWithRetryOption) validate() error {
+	if o.Policy.attempts < 1 {
+		return fmt.Errorf("must make at least one attempt, got %d", o.Policy.attempts)
+	}
+
+	t := reflect.TypeOf(o.Target)
+	if t == nil || t.Kind() != reflect.Func {
+		return fmt.Errorf("must provide constructor function, got %v (%T)", o.Target, o.Target)
+	}
+	if t.NumOut() == 0 || t.Out(t.NumOut()-1) != _typeOfError {
+		return errors.New("constructor must return an error to be retried")
+	}
+	return nil
+}
+
+func (o provideWithRetryOption) String() string {
+	return fmt.Sprintf("fx.ProvideWithRetry(%v, %v)", fxreflect.FuncName(o.Target), o.Policy)
+}
+
+// retryContainer is a container that retries the constructors
+// validated by ProvideWithRetry per the given policy.
+type retryContainer struct {
+	container
+
+	module   *module
+	funcName string
+	policy   RetryPolicy
+}
+
+var _ container = retryContainer{}
+
+func (c retryContainer) Provide(constructor interface{}, opts ...dig.ProvideOption) error {
+	m, funcName, policy := c.module, c.funcName, c.policy
+	return provideWrapped(c.container, constructor, opts, false, func(_ reflect.Type, call constructorFunc) constructorFunc {
+		return func(args []reflect.Value) []reflect.Value {
+			delay := policy.backoff
+			for attempt := 1; ; attempt++ {
+				attemptArgs, lifecycles := withAttemptLifecycles(args)
+				results := call(attemptArgs)
+				last := len(results) - 1
+				err, _ := results[last].Interface().(error)
+				if err == nil {
+					for _, lc := range lifecycles {
+						lc.commit()
+					}
+					return results
+				}
+				if attempt >= policy.attempts {
+					if attempt > 1 {
+						err = fmt.Errorf("failed after %d attempts: %w", attempt, err)
+						results[last] = reflect.ValueOf(&err).Elem()
+					}
+					return results
+				}
+
+				m.log.LogEvent(&fxevent.Retrying{
+					ConstructorName: funcName,
+					ModuleName:      m.name,
+					Attempt:         attempt,
+					Attempts:        policy.attempts,
+					Delay:           delay,
+					Err:             err,
+				})
+				m.app.clock.Sleep(delay)
+				delay = nextBackoff(delay)
+			}
+		}
+	})
+}
+
+// withAttemptLifecycles returns a copy of the arguments of a constructor
+// that replaces the Lifecycles among them,
+// including those in parameter objects,
+// with ones that hold on to the hooks appended to them
+// until they're committed.
+func withAttemptLifecycles(args []reflect.Value) ([]reflect.Value, []*attemptLifecycle) {
+	var lifecycles []*attemptLifecycle
+	var replace func(v reflect.Value) reflect.Value
+	replace = func(v reflect.Value) reflect.Value {
+		switch {
+		case v.Type() == _typeOfLifecycle:
+			if v.IsNil() {
+				return v
+			}
+			lc := &attemptLifecycle{target: v.Interface().(Lifecycle)}
+			lifecycles = append(lifecycles, lc)
+			return reflect.ValueOf(lc).Convert(_typeOfLifecycle)
+		case dig.IsIn(v.Type()):
+			cp := reflect.New(v.Type()).Elem()
+			cp.Set(v)
+			for i := 0; i < cp.NumField(); i++ {
+				if f := cp.Field(i); f.CanSet() {
+					f.Set(replace(f))
+				}
+			}
+			return cp
+		default:
+			return v
+		}
+	}
+
+	replaced := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		replaced[i] = replace(arg)
+	}
+	return replaced, lifecycles
+}
+
+// attemptLifecycle is the Lifecycle given to one attempt
+// to call a constructor provided with ProvideWithRetry.
+// It appends its hooks to the application's Lifecycle
+// only if the attempt succeeds,
+// so that failed attempts leave no hooks behind.
+type attemptLifecycle struct {
+	target Lifecycle
+	hooks  []Hook
+}
+
+func (l *attemptLifecycle) Append(h Hook) {
+	if h.callerFrame == (fxreflect.Frame{}) {
+		if f := fxreflect.CallerStack(1, 0); len(f) > 0 {
+			h.callerFrame = f[0]
+		}
+	}
+	l.hooks = append(l.hooks, h)
+}
+
+func (l *attemptLifecycle) commit() {
+	for _, h := range l.hooks {
+		l.target.Append(h)
+	}
+}
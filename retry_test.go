@@ -0,0 +1,212 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/fxclock"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+func TestProvideWithRetry(t *testing.T) {
+	t.Parallel()
+
+	type DB struct{}
+	errRefused := errors.New("connection refused")
+
+	// flaky returns a constructor that fails the given number of times.
+	flaky := func(failures int) (func() (*DB, error), *int) {
+		var calls int
+		return func() (*DB, error) {
+			calls++
+			if calls <= failures {
+				return nil, errRefused
+			}
+			return &DB{}, nil
+		}, &calls
+	}
+
+	// newApp builds an application with the given options in the background,
+	// advancing the clock past the given delays.
+	newApp := func(delays []time.Duration, opts ...fx.Option) (*fx.App, *fxlog.Spy) {
+		clock := fxclock.NewMock()
+		spy := new(fxlog.Spy)
+		opts = append(opts,
+			fx.WithClock(clock),
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+		)
+
+		done := make(chan *fx.App)
+		go func() { done <- fx.New(opts...) }()
+		for _, d := range delays {
+			clock.AwaitScheduled(1)
+			clock.Add(d)
+		}
+		return <-done, spy
+	}
+
+	t.Run("Succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		ctor, calls := flaky(2)
+		var db *DB
+		app, spy := newApp([]time.Duration{time.Second, 2 * time.Second},
+			fx.ProvideWithRetry(ctor, fx.Retry(3, time.Second)),
+			fx.Populate(&db),
+		)
+		require.NoError(t, app.Err())
+		assert.NotNil(t, db)
+		assert.Equal(t, 3, *calls)
+
+		events := spy.Events().SelectByTypeName("Retrying")
+		require.Len(t, events, 2)
+		for i, e := range events {
+			e := e.(*fxevent.Retrying)
+			assert.Equal(t, i+1, e.Attempt)
+			assert.Equal(t, 3, e.Attempts)
+			assert.Equal(t, time.Second<<i, e.Delay)
+			assert.ErrorIs(t, e.Err, errRefused)
+			assert.Contains(t, e.ConstructorName, "TestProvideWithRetry")
+		}
+	})
+
+	t.Run("Fails", func(t *testing.T) {
+		t.Parallel()
+
+		ctor, calls := flaky(5)
+		app, _ := newApp([]time.Duration{time.Second},
+			fx.ProvideWithRetry(ctor, fx.Retry(2, time.Second)),
+			fx.Invoke(func(*DB) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errRefused)
+		assert.Contains(t, err.Error(), "failed after 2 attempts")
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("MaxBackoff", func(t *testing.T) {
+		t.Parallel()
+
+		ctor, _ := flaky(3)
+		delays := []time.Duration{40 * time.Second, time.Minute, time.Minute}
+		app, spy := newApp(delays,
+			fx.ProvideWithRetry(ctor, fx.Retry(4, 40*time.Second)),
+			fx.Invoke(func(*DB) {}),
+		)
+		require.NoError(t, app.Err())
+
+		events := spy.Events().SelectByTypeName("Retrying")
+		require.Len(t, events, 3)
+		for i, e := range events {
+			assert.Equal(t, delays[i], e.(*fxevent.Retrying).Delay)
+		}
+	})
+
+	t.Run("KeepsHooksOfSuccessfulAttempt", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			fx.In
+
+			Lifecycle fx.Lifecycle
+		}
+
+		var calls, stops int
+		ctor := func(lc fx.Lifecycle, p params) (*DB, error) {
+			calls++
+			lc.Append(fx.StopHook(func() { stops++ }))
+			p.Lifecycle.Append(fx.StopHook(func() { stops++ }))
+			if calls <= 2 {
+				return nil, errRefused
+			}
+			return &DB{}, nil
+		}
+		app, _ := newApp([]time.Duration{time.Second, 2 * time.Second},
+			fx.ProvideWithRetry(ctor, fx.Retry(3, time.Second)),
+			fx.Invoke(func(*DB) {}),
+		)
+		require.NoError(t, app.Err())
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+		assert.Equal(t, 2, stops, "only the hooks of the last attempt must run")
+	})
+
+	t.Run("Unused", func(t *testing.T) {
+		t.Parallel()
+
+		ctor, calls := flaky(0)
+		app, _ := newApp(nil, fx.ProvideWithRetry(ctor, fx.Retry(3, time.Second)))
+		require.NoError(t, app.Err())
+		assert.Zero(t, *calls)
+	})
+
+	t.Run("InvalidConstructor", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    interface{}
+			policy  fx.RetryPolicy
+			wantErr string
+		}{
+			{
+				desc:    "NoError",
+				give:    func() *DB { return &DB{} },
+				policy:  fx.Retry(3, time.Second),
+				wantErr: "constructor must return an error to be retried",
+			},
+			{
+				desc:    "NotFunction",
+				give:    &DB{},
+				policy:  fx.Retry(3, time.Second),
+				wantErr: "must provide constructor function",
+			},
+			{
+				desc:    "NoAttempts",
+				give:    func() (*DB, error) { return &DB{}, nil },
+				policy:  fx.Retry(0, time.Second),
+				wantErr: "must make at least one attempt, got 0",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := fx.New(fx.NopLogger, fx.ProvideWithRetry(tt.give, tt.policy))
+				err := app.Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "fx.ProvideWithRetry(")
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// BeforeRun registers a function that [App.Run] and [App.RunErr] call
+// after the application has started, and before they wait for a shutdown
+// signal. Use it for work that must happen only once the application is
+// fully up, such as writing a PID file or notifying a service manager
+// that the application is ready.
+//
+// The function receives the context used to start the application
+// and the App itself, so it can, for example, use [App.DigContainer].
+// If it returns an error, the application is stopped immediately,
+// and Run fails with that error.
+//
+// BeforeRun functions are called in the order they were registered.
+// They are not called if the application is started with [App.Start].
+// BeforeRun may only be passed to the top-level App.
+func BeforeRun(f func(context.Context, *App) error) Option {
+	return beforeRunOption{
+		Func:  f,
+		Stack: fxreflect.CallerStack(1, 0),
+	}
+}
+
+type beforeRunOption struct {
+	Func  func(context.Context, *App) error
+	Stack fxreflect.Stack
+}
+
+func (o beforeRunOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.BeforeRun Option should be passed to top-level App, " +
+			"not to fx.Module")
+		return
+	}
+	m.app.beforeRun = append(m.app.beforeRun, o)
+}
+
+func (o beforeRunOption) String() string {
+	return fmt.Sprintf("fx.BeforeRun(%v)", fxreflect.FuncName(o.Func))
+}
+
+// AfterRun registers a function that [App.Run] and [App.RunErr] call
+// with the shutdown signal that ended the application,
+// before the application is stopped. Use it to undo the work
+// of a [BeforeRun] function, or to notify a service manager
+// that the application is stopping.
+//
+// AfterRun functions are called in the reverse order they were registered,
+// mirroring how OnStop hooks are run.
+// They are not called if the application failed to start,
+// or if a BeforeRun function failed.
+// AfterRun may only be passed to the top-level App.
+func AfterRun(f func(ShutdownSignal)) Option {
+	return afterRunOption{
+		Func:  f,
+		Stack: fxreflect.CallerStack(1, 0),
+	}
+}
+
+type afterRunOption struct {
+	Func  func(ShutdownSignal)
+	Stack fxreflect.Stack
+}
+
+func (o afterRunOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.AfterRun Option should be passed to top-level App, " +
+			"not to fx.Module")
+		return
+	}
+	m.app.afterRun = append(m.app.afterRun, o)
+}
+
+func (o afterRunOption) String() string {
+	return fmt.Sprintf("fx.AfterRun(%v)", fxreflect.FuncName(o.Func))
+}
+
+// runBefore calls the BeforeRun functions in order,
+// stopping at the first failure.
+func (app *App) runBefore(ctx context.Context) error {
+	for _, o := range app.beforeRun {
+		if err := o.Func(ctx, app); err != nil {
+			return fmt.Errorf("fx.BeforeRun(%v) from:\n%+vFailed: %w",
+				fxreflect.FuncName(o.Func), o.Stack, err)
+		}
+	}
+	return nil
+}
+
+// runAfter calls the AfterRun functions in reverse order.
+func (app *App) runAfter(sig ShutdownSignal) {
+	for i := len(app.afterRun) - 1; i >= 0; i-- {
+		app.afterRun[i].Func(sig)
+	}
+}
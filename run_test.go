@@ -0,0 +1,185 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func writePIDFile(context.Context, *fx.App) error { return nil }
+
+func removePIDFile(fx.ShutdownSignal) {}
+
+func TestRunHooks(t *testing.T) {
+	t.Parallel()
+
+	// shutdown shuts down the application as soon as it starts.
+	shutdown := func(opts ...fx.ShutdownOption) fx.Option {
+		return fx.Invoke(func(sd fx.Shutdowner, lc fx.Lifecycle) {
+			lc.Append(fx.StartHook(func() error {
+				return sd.Shutdown(opts...)
+			}))
+		})
+	}
+
+	t.Run("order", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		record := func(name string) fx.Option {
+			return fx.Options(
+				fx.BeforeRun(func(context.Context, *fx.App) error {
+					calls = append(calls, "before "+name)
+					return nil
+				}),
+				fx.AfterRun(func(sig fx.ShutdownSignal) {
+					calls = append(calls, "after "+name)
+				}),
+			)
+		}
+
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error {
+						calls = append(calls, "start")
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						calls = append(calls, "stop")
+						return nil
+					},
+				})
+			}),
+			shutdown(),
+			record("a"),
+			record("b"),
+		)
+		require.NoError(t, app.RunErr())
+		assert.Equal(t, []string{
+			"start",
+			"before a", "before b",
+			"after b", "after a",
+			"stop",
+		}, calls)
+	})
+
+	t.Run("container access", func(t *testing.T) {
+		t.Parallel()
+
+		var got string
+		app := fxtest.New(t,
+			fx.Supply("hello"),
+			shutdown(),
+			fx.BeforeRun(func(_ context.Context, app *fx.App) error {
+				return app.DigContainer().Invoke(func(s string) { got = s })
+			}),
+		)
+		require.NoError(t, app.RunErr())
+		assert.Equal(t, "hello", got)
+	})
+
+	t.Run("shutdown signal", func(t *testing.T) {
+		t.Parallel()
+
+		var got fx.ShutdownSignal
+		app := fxtest.New(t,
+			fx.Invoke(func(sd fx.Shutdowner, lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() error {
+					return sd.Shutdown(fx.ExitCode(3))
+				}))
+			}),
+			fx.AfterRun(func(sig fx.ShutdownSignal) { got = sig }),
+		)
+		err := app.RunErr()
+		assert.Equal(t, 3, fx.ExitCodeOf(err))
+		assert.Equal(t, 3, got.ExitCode)
+		assert.Equal(t, syscall.SIGTERM, got.Signal)
+	})
+
+	t.Run("before failure", func(t *testing.T) {
+		t.Parallel()
+
+		var stopped, afterCalled bool
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StopHook(func() { stopped = true }))
+			}),
+			fx.BeforeRun(func(context.Context, *fx.App) error {
+				return errors.New("great sadness")
+			}),
+			fx.AfterRun(func(fx.ShutdownSignal) { afterCalled = true }),
+		)
+		err := app.RunErr()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.BeforeRun(")
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.True(t, stopped, "application must be stopped")
+		assert.False(t, afterCalled, "AfterRun must not be called")
+	})
+
+	t.Run("start failure", func(t *testing.T) {
+		t.Parallel()
+
+		var beforeCalled bool
+		app := fx.New(
+			fx.NopLogger,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() error {
+					return errors.New("great sadness")
+				}))
+			}),
+			fx.BeforeRun(func(context.Context, *fx.App) error {
+				beforeCalled = true
+				return nil
+			}),
+		)
+		require.Error(t, app.RunErr())
+		assert.False(t, beforeCalled)
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Module("foo",
+				fx.BeforeRun(func(context.Context, *fx.App) error { return nil }),
+			),
+		)
+		assert.ErrorContains(t, app.Err(),
+			"fx.BeforeRun Option should be passed to top-level App, not to fx.Module")
+
+		app = fx.New(
+			fx.NopLogger,
+			fx.Module("foo", fx.AfterRun(func(fx.ShutdownSignal) {})),
+		)
+		assert.ErrorContains(t, app.Err(),
+			"fx.AfterRun Option should be passed to top-level App, not to fx.Module")
+	})
+}
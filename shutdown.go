@@ -21,7 +21,13 @@
 package fx
 
 import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"go.uber.org/fx/fxevent"
 )
 
 // Shutdowner provides a method that can manually trigger the shutdown of the
@@ -32,6 +38,33 @@ type Shutdowner interface {
 	Shutdown(...ShutdownOption) error
 }
 
+// ShutdownScheduler provides methods to shut down the application at a
+// later time, unless the shutdown is canceled first. For example,
+// to drain an application and exit in 30 seconds:
+//
+//	cancel := scheduler.ShutdownAfter(30 * time.Second)
+//	// ...
+//	if stillNeeded {
+//		cancel()
+//	}
+//
+// Scheduling, canceling, and firing a shutdown are reported to the
+// [fxevent.Logger]. Scheduled shutdowns that have not fired yet are canceled
+// when the application stops.
+//
+// The ShutdownScheduler is provided to all Fx applications.
+type ShutdownScheduler interface {
+	// ShutdownAfter shuts down the application after the given duration,
+	// as if by [Shutdowner.Shutdown] with the given options.
+	// The returned function cancels the shutdown,
+	// and reports whether it was canceled before it fired.
+	ShutdownAfter(d time.Duration, opts ...ShutdownOption) (cancel func() bool)
+
+	// ShutdownAt is similar to ShutdownAfter,
+	// but shuts down the application at the given time.
+	ShutdownAt(deadline time.Time, opts ...ShutdownOption) (cancel func() bool)
+}
+
 // ShutdownOption provides a way to configure properties of the shutdown
 // process. Currently, no options have been implemented.
 type ShutdownOption interface {
@@ -42,6 +75,7 @@ type exitCodeOption int
 
 func (code exitCodeOption) apply(s *shutdowner) {
 	s.exitCode = int(code)
+	s.exitCodeSet = true
 }
 
 var _ ShutdownOption = exitCodeOption(0)
@@ -54,6 +88,29 @@ func ExitCode(code int) ShutdownOption {
 	return exitCodeOption(code)
 }
 
+type shutdownErrorOption struct{ err error }
+
+func (o shutdownErrorOption) apply(s *shutdowner) {
+	s.err = o.err
+}
+
+var _ ShutdownOption = shutdownErrorOption{}
+
+// ShutdownError is a [ShutdownOption] that reports the error
+// that caused the application to shut down, for example:
+//
+//	if err := srv.Serve(ln); err != nil {
+//		shutdowner.Shutdown(fx.ShutdownError(err))
+//	}
+//
+// The error is broadcast in the Err field of the [ShutdownSignal]
+// received from [App.Wait], so that supervisors can tell failures
+// from requested shutdowns, and returned by [App.RunErr].
+// The exit code of the shutdown defaults to 1 unless it's set with [ExitCode].
+func ShutdownError(err error) ShutdownOption {
+	return shutdownErrorOption{err: err}
+}
+
 type shutdownTimeoutOption time.Duration
 
 func (shutdownTimeoutOption) apply(*shutdowner) {}
@@ -73,22 +130,141 @@ func ShutdownTimeout(timeout time.Duration) ShutdownOption {
 type shutdowner struct {
 	app      *App
 	exitCode int
+	err      error
+
+	// exitCodeSet reports whether the exit code was set with ExitCode.
+	exitCodeSet bool
+}
+
+// withOptions returns a copy of the shutdowner with the given options
+// applied, so that they don't affect other shutdowns.
+func (s *shutdowner) withOptions(opts []ShutdownOption) shutdowner {
+	sd := shutdowner{app: s.app}
+	for _, opt := range opts {
+		opt.apply(&sd)
+	}
+	if sd.err != nil && !sd.exitCodeSet {
+		sd.exitCode = 1
+	}
+	return sd
 }
 
 // Shutdown broadcasts a signal to all of the application's Done channels
 // and begins the Stop process. Applications can be shut down only after they
 // have finished starting up.
 func (s *shutdowner) Shutdown(opts ...ShutdownOption) error {
-	for _, opt := range opts {
-		opt.apply(s)
-	}
+	sd := s.withOptions(opts)
 
 	return s.app.receivers.b.Broadcast(ShutdownSignal{
 		Signal:   _sigTERM,
-		ExitCode: s.exitCode,
+		ExitCode: sd.exitCode,
+		Err:      sd.err,
 	})
 }
 
-func (app *App) shutdowner() Shutdowner {
-	return &shutdowner{app: app}
+// ShutdownAfter schedules a shutdown of the application after d.
+func (s *shutdowner) ShutdownAfter(d time.Duration, opts ...ShutdownOption) func() bool {
+	return s.ShutdownAt(s.app.clock.Now().Add(d), opts...)
+}
+
+// ShutdownAt schedules a shutdown of the application at deadline.
+func (s *shutdowner) ShutdownAt(deadline time.Time, opts ...ShutdownOption) func() bool {
+	sd := s.withOptions(opts)
+
+	ctx, cancel := s.app.clock.WithTimeout(context.Background(), deadline.Sub(s.app.clock.Now()))
+	ss := &scheduledShutdown{
+		deadline: deadline,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	s.app.scheduledShutdowns.add(ss)
+
+	log := s.app.log()
+	log.LogEvent(&fxevent.ShutdownScheduled{
+		Deadline: deadline,
+		ExitCode: sd.exitCode,
+	})
+
+	go func() {
+		defer close(ss.done)
+		defer s.app.scheduledShutdowns.remove(ss)
+
+		<-ctx.Done()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.LogEvent(&fxevent.ShutdownCanceled{Deadline: deadline})
+			return
+		}
+
+		ss.fired.Store(true)
+		err := s.app.receivers.b.Broadcast(ShutdownSignal{
+			Signal:   _sigTERM,
+			ExitCode: sd.exitCode,
+			Err:      sd.err,
+		})
+		log.LogEvent(&fxevent.ShutdownFired{
+			Deadline: deadline,
+			ExitCode: sd.exitCode,
+			Err:      err,
+		})
+	}()
+
+	return ss.Cancel
+}
+
+func (app *App) shutdowner() (Shutdowner, ShutdownScheduler) {
+	s := &shutdowner{app: app}
+	return s, s
+}
+
+// scheduledShutdown is a pending shutdown scheduled with ShutdownScheduler.
+type scheduledShutdown struct {
+	deadline time.Time
+	cancel   context.CancelFunc
+	fired    atomic.Bool
+	done     chan struct{} // closed when the shutdown fires or is canceled
+}
+
+// Cancel cancels the shutdown if it has not fired yet, and waits for it to
+// be resolved. It reports whether the shutdown was canceled.
+func (ss *scheduledShutdown) Cancel() bool {
+	ss.cancel()
+	<-ss.done
+	return !ss.fired.Load()
+}
+
+// scheduledShutdowns tracks pending scheduled shutdowns of an App.
+type scheduledShutdowns struct {
+	mu      sync.Mutex
+	pending map[*scheduledShutdown]struct{}
+}
+
+func (s *scheduledShutdowns) add(ss *scheduledShutdown) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending == nil {
+		s.pending = make(map[*scheduledShutdown]struct{})
+	}
+	s.pending[ss] = struct{}{}
+}
+
+func (s *scheduledShutdowns) remove(ss *scheduledShutdown) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.pending, ss)
+}
+
+// CancelAll cancels all pending shutdowns.
+func (s *scheduledShutdowns) CancelAll() {
+	s.mu.Lock()
+	pending := make([]*scheduledShutdown, 0, len(s.pending))
+	for ss := range s.pending {
+		pending = append(pending, ss)
+	}
+	s.mu.Unlock()
+
+	for _, ss := range pending {
+		ss.Cancel()
+	}
 }
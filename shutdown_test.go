@@ -22,6 +22,7 @@ package fx_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -30,7 +31,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
 	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxclock"
+	"go.uber.org/fx/internal/fxlog"
 )
 
 func TestShutdown(t *testing.T) {
@@ -130,6 +134,126 @@ func TestShutdown(t *testing.T) {
 		assert.NoError(t, s.Shutdown(fx.ExitCode(2), fx.ShutdownTimeout(time.Second)))
 	})
 
+	t.Run("with error", func(t *testing.T) {
+		t.Parallel()
+
+		var s fx.Shutdowner
+		app := fxtest.New(t, fx.Populate(&s))
+		app.RequireStart()
+		defer app.RequireStop()
+
+		giveErr := errors.New("great sadness")
+		require.NoError(t, s.Shutdown(fx.ShutdownError(giveErr)))
+		sig := <-app.Wait()
+		assert.Equal(t, giveErr, sig.Err)
+		assert.Equal(t, 1, sig.ExitCode, "error-driven shutdowns must have a non-zero exit code")
+	})
+
+	t.Run("with error and exit code", func(t *testing.T) {
+		t.Parallel()
+
+		giveErr := errors.New("great sadness")
+		tests := []struct {
+			desc     string
+			give     []fx.ShutdownOption
+			wantCode int
+		}{
+			{"exit code first", []fx.ShutdownOption{fx.ExitCode(3), fx.ShutdownError(giveErr)}, 3},
+			{"exit code last", []fx.ShutdownOption{fx.ShutdownError(giveErr), fx.ExitCode(3)}, 3},
+			{"zero exit code first", []fx.ShutdownOption{fx.ExitCode(0), fx.ShutdownError(giveErr)}, 0},
+			{"zero exit code last", []fx.ShutdownOption{fx.ShutdownError(giveErr), fx.ExitCode(0)}, 0},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				var s fx.Shutdowner
+				app := fxtest.New(t, fx.Populate(&s))
+				app.RequireStart()
+				defer app.RequireStop()
+
+				require.NoError(t, s.Shutdown(tt.give...))
+				sig := <-app.Wait()
+				assert.Equal(t, giveErr, sig.Err)
+				assert.Equal(t, tt.wantCode, sig.ExitCode)
+			})
+		}
+	})
+
+	t.Run("options don't outlive the shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		var s fx.Shutdowner
+		app := fxtest.New(t, fx.Populate(&s))
+
+		app.RequireStart()
+		require.NoError(t, s.Shutdown(fx.ShutdownError(errors.New("great sadness"))))
+		require.Error(t, (<-app.Wait()).Err)
+		app.RequireStop()
+
+		app.RequireStart()
+		require.NoError(t, s.Shutdown())
+		sig := <-app.Wait()
+		assert.NoError(t, sig.Err)
+		assert.Zero(t, sig.ExitCode)
+		app.RequireStop()
+	})
+
+	t.Run("start failure", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() error { return errors.New("great sadness") }))
+			}),
+		)
+		wait := app.Wait()
+		// Stop the signal receivers started by Wait.
+		defer app.Stop(context.Background())
+
+		err := app.Start(context.Background())
+		require.Error(t, err)
+
+		sig := <-wait
+		assert.Nil(t, sig.Signal)
+		assert.Equal(t, 1, sig.ExitCode)
+		var startErr *fx.StartError
+		require.ErrorAs(t, sig.Err, &startErr)
+		assert.ErrorContains(t, startErr, "great sadness")
+	})
+
+	t.Run("restart after start failure", func(t *testing.T) {
+		t.Parallel()
+
+		fail := true
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() error {
+					if fail {
+						return errors.New("great sadness")
+					}
+					return nil
+				}))
+			}),
+		)
+		done := app.Done()
+		require.Error(t, app.Start(context.Background()))
+
+		fail = false
+		app.RequireStart()
+		defer app.RequireStop()
+
+		select {
+		case sig := <-done:
+			assert.Fail(t, "unexpected signal", "got %v", sig)
+		case sig := <-app.Wait():
+			assert.Fail(t, "unexpected signal", "got %v", sig)
+		default:
+		}
+	})
+
 	t.Run("from invoke", func(t *testing.T) {
 		t.Parallel()
 
@@ -171,6 +295,127 @@ func TestShutdown(t *testing.T) {
 	})
 }
 
+func TestShutdownScheduler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ShutdownAfter", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		spy := new(fxlog.Spy)
+		var scheduler fx.ShutdownScheduler
+		app := fxtest.New(t,
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.WithClock(clock),
+			fx.Populate(&scheduler),
+		)
+		defer app.RequireStart().RequireStop()
+
+		scheduler.ShutdownAfter(time.Minute, fx.ExitCode(3))
+		clock.AwaitScheduled(1)
+
+		select {
+		case <-app.Wait():
+			assert.Fail(t, "app shut down before the deadline")
+		default:
+		}
+
+		clock.Add(time.Minute)
+		assert.Equal(t, 3, (<-app.Wait()).ExitCode)
+
+		scheduled := spy.Events().SelectByTypeName("ShutdownScheduled")
+		require.Len(t, scheduled, 1)
+		assert.Equal(t, 3, scheduled[0].(*fxevent.ShutdownScheduled).ExitCode)
+	})
+
+	t.Run("ShutdownAt", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		var scheduler fx.ShutdownScheduler
+		app := fxtest.New(t,
+			fx.WithClock(clock),
+			fx.Populate(&scheduler),
+		)
+		defer app.RequireStart().RequireStop()
+
+		deadline := clock.Now().Add(time.Hour)
+		scheduler.ShutdownAt(deadline)
+		clock.AwaitScheduled(1)
+		clock.Add(time.Hour)
+
+		assert.Equal(t, 0, (<-app.Wait()).ExitCode)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		spy := new(fxlog.Spy)
+		var scheduler fx.ShutdownScheduler
+		app := fxtest.New(t,
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.WithClock(clock),
+			fx.Populate(&scheduler),
+		)
+		defer app.RequireStart().RequireStop()
+
+		cancel := scheduler.ShutdownAfter(time.Minute)
+		clock.AwaitScheduled(1)
+		assert.True(t, cancel(), "shutdown should be canceled")
+
+		clock.Add(time.Minute)
+		select {
+		case <-app.Wait():
+			assert.Fail(t, "app shut down after cancel")
+		default:
+		}
+
+		assert.Len(t, spy.Events().SelectByTypeName("ShutdownCanceled"), 1)
+		assert.Empty(t, spy.Events().SelectByTypeName("ShutdownFired"))
+	})
+
+	t.Run("CancelAfterFired", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		var scheduler fx.ShutdownScheduler
+		app := fxtest.New(t,
+			fx.WithClock(clock),
+			fx.Populate(&scheduler),
+		)
+		defer app.RequireStart().RequireStop()
+
+		cancel := scheduler.ShutdownAfter(time.Second)
+		clock.AwaitScheduled(1)
+		clock.Add(time.Second)
+		<-app.Wait()
+
+		assert.False(t, cancel(), "fired shutdown cannot be canceled")
+	})
+
+	t.Run("CanceledOnStop", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		spy := new(fxlog.Spy)
+		var scheduler fx.ShutdownScheduler
+		app := fxtest.New(t,
+			fx.WithLogger(func() fxevent.Logger { return spy }),
+			fx.WithClock(clock),
+			fx.Populate(&scheduler),
+		)
+		app.RequireStart()
+
+		scheduler.ShutdownAfter(time.Minute)
+		scheduler.ShutdownAfter(time.Hour)
+		clock.AwaitScheduled(2)
+		app.RequireStop()
+
+		assert.Len(t, spy.Events().SelectByTypeName("ShutdownCanceled"), 2)
+	})
+}
+
 func TestDataRace(t *testing.T) {
 	t.Parallel()
 
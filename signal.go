@@ -34,9 +34,17 @@ import (
 //
 // Should the application receive an operating system signal,
 // the Signal field will be populated with the received os.Signal.
+//
+// Should the application shut down because of an error,
+// such as a failure to start or a [ShutdownError] passed to Shutdown,
+// the error will be populated in the Err field,
+// and ExitCode will be non-zero.
+// A failure to start is only sent to channels returned by Wait before Start
+// was called, without a Signal, and the application may be started again.
 type ShutdownSignal struct {
 	Signal   os.Signal
 	ExitCode int
+	Err      error
 }
 
 // String will render a ShutdownSignal type as a string suitable for printing.
@@ -0,0 +1,130 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"time"
+
+	"go.uber.org/fx/internal/lifecycle"
+	"go.uber.org/multierr"
+)
+
+// StartError is returned by [App.Start] when the application fails to start
+// and is rolled back. It describes the hooks that ran before and during the
+// rollback:
+//
+//	var startErr *fx.StartError
+//	if errors.As(app.Start(ctx), &startErr) && startErr.FailedHook != nil {
+//		log.Printf("%v failed after %v", startErr.FailedHook.Name, startErr.FailedHook.Runtime)
+//	}
+//
+// Its message is that of the error that failed the start, combined with
+// that of the rollback, if it failed too.
+//
+// If the start timeout expires, App.Start returns the error of its context
+// instead, because the rollback may still be running.
+type StartError struct {
+	// Err is the error that failed the start of the application.
+	Err error
+
+	// FailedHook is the OnStart hook that failed, if any.
+	// It's nil if the application failed to start for another reason,
+	// such as a function passed to [InvokeAtStart] failing.
+	FailedHook *HookRun
+
+	// Started lists the OnStart hooks that succeeded, in the order they ran.
+	Started []HookRun
+
+	// RolledBack lists the OnStop hooks that ran to roll back the hooks in
+	// Started, in the order they ran.
+	RolledBack []HookRun
+
+	// RollbackErr is non-nil if the rollback failed.
+	RollbackErr error
+}
+
+// HookRun describes a lifecycle hook function that ran.
+type HookRun struct {
+	// Name is the name of the hook function.
+	Name string
+
+	// CallerName is the name of the function that appended the hook.
+	CallerName string
+
+	// Runtime is how long the function ran for.
+	Runtime time.Duration
+
+	// Err is the error returned by the function, if any.
+	Err error
+}
+
+func (e *StartError) Error() string {
+	return multierr.Append(e.Err, e.RollbackErr).Error()
+}
+
+// Unwrap returns the error that failed the start of the application,
+// and the error of the rollback, if any.
+func (e *StartError) Unwrap() []error {
+	if e.RollbackErr == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.RollbackErr}
+}
+
+// Errors returns the errors of the start and of the rollback,
+// as combined by go.uber.org/multierr.
+func (e *StartError) Errors() []error {
+	return multierr.Errors(multierr.Append(e.Err, e.RollbackErr))
+}
+
+// newStartError builds a StartError for err, the error that failed the start
+// of an application, and rollbackErr, the error of its rollback.
+// hooksRan reports whether the OnStart hooks of the application ran,
+// and so whether the hook records of lc describe this start.
+func newStartError(err, rollbackErr error, lc *lifecycle.Lifecycle, hooksRan bool) *StartError {
+	startErr := &StartError{Err: err, RollbackErr: rollbackErr}
+	if !hooksRan {
+		return startErr
+	}
+
+	onStart, onStop := lc.HookRecords()
+	for _, r := range onStart {
+		run := newHookRun(r)
+		if r.Err != nil {
+			startErr.FailedHook = &run
+			continue
+		}
+		startErr.Started = append(startErr.Started, run)
+	}
+	for _, r := range onStop {
+		startErr.RolledBack = append(startErr.RolledBack, newHookRun(r))
+	}
+	return startErr
+}
+
+func newHookRun(r lifecycle.HookRecord) HookRun {
+	return HookRun{
+		Name:       r.Name,
+		CallerName: r.CallerFrame.Function,
+		Runtime:    r.Runtime,
+		Err:        r.Err,
+	}
+}
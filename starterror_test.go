@@ -0,0 +1,107 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+)
+
+func startErrorOK(context.Context) error { return nil }
+
+func startErrorStopOK(context.Context) error { return nil }
+
+func TestStartError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("HookFailed", func(t *testing.T) {
+		t.Parallel()
+
+		errStart := errors.New("great sadness")
+		errStop := errors.New("rollback failed")
+		failStart := func(context.Context) error { return errStart }
+		failStop := func(context.Context) error { return errStop }
+		app, spy := NewSpied(
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{OnStart: startErrorOK, OnStop: failStop})
+				lc.Append(Hook{OnStart: startErrorOK, OnStop: startErrorStopOK})
+				lc.Append(Hook{OnStart: failStart})
+			}),
+		)
+		require.NoError(t, app.Err())
+
+		err := app.Start(context.Background())
+		assert.EqualError(t, err, "great sadness; rollback failed")
+
+		var startErr *StartError
+		require.ErrorAs(t, err, &startErr)
+		assert.Same(t, errStart, startErr.Err)
+		assert.Same(t, errStop, startErr.RollbackErr)
+
+		require.NotNil(t, startErr.FailedHook)
+		assert.Same(t, errStart, startErr.FailedHook.Err)
+		assert.Contains(t, startErr.FailedHook.CallerName, "TestStartError")
+
+		require.Len(t, startErr.Started, 2)
+		for _, run := range startErr.Started {
+			assert.Equal(t, "go.uber.org/fx_test.startErrorOK()", run.Name)
+			assert.NoError(t, run.Err)
+		}
+
+		require.Len(t, startErr.RolledBack, 2)
+		assert.Equal(t, "go.uber.org/fx_test.startErrorStopOK()", startErr.RolledBack[0].Name)
+		assert.NoError(t, startErr.RolledBack[0].Err)
+		assert.Same(t, errStop, startErr.RolledBack[1].Err)
+
+		started := spy.Events().SelectByTypeName("Started")
+		require.Len(t, started, 1)
+		assert.Same(t, startErr, started[0].(*fxevent.Started).Err,
+			"Started event must report the StartError")
+	})
+
+	t.Run("InvokeAtStartFailed", func(t *testing.T) {
+		t.Parallel()
+
+		errInvoke := errors.New("great sadness")
+		app := NewForTest(t,
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{OnStart: startErrorOK, OnStop: startErrorStopOK})
+			}),
+			InvokeAtStart(func() error { return errInvoke }),
+		)
+
+		err := app.Start(context.Background())
+		assert.ErrorIs(t, err, errInvoke)
+
+		var startErr *StartError
+		require.ErrorAs(t, err, &startErr)
+		assert.Nil(t, startErr.FailedHook)
+		assert.Empty(t, startErr.Started)
+		assert.Empty(t, startErr.RolledBack)
+		assert.NoError(t, startErr.RollbackErr)
+	})
+}
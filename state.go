@@ -0,0 +1,200 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// AppState is a phase in the lifecycle of an [App].
+//
+// An App starts out in the AppCreated state after [New].
+// Starting it moves it to AppStarting,
+// and then to AppStarted once all OnStart hooks succeed.
+// Stopping it moves it to AppStopping,
+// and then to AppStopped once all OnStop hooks have run.
+// If an OnStart hook fails, the App moves from AppStarting
+// to AppStopping while it rolls back, and then to AppStopped.
+//
+// A stopped App, including one that failed to start, may be started again,
+// which runs its OnStart hooks again; stopping it again runs its OnStop
+// hooks again. Constructors and invoked functions run only once,
+// and the [AppContext] stays canceled after the first stop,
+// so applications that depend on it can't be started again.
+// [InvokeAtStart] functions run again only if they failed.
+// Channels returned by [App.Wait] before a failed start receive its error,
+// but channels returned after it only receive later shutdowns.
+// [App.Start] fails without logging any events or running any hooks if the
+// App is in any other state than AppCreated or AppStopped, and [App.Stop]
+// does not run any hooks for an App that isn't started.
+// [App.Pause] moves a started App to AppPaused,
+// and [App.Resume] moves it back to AppStarted.
+type AppState int
+
+const (
+	// AppCreated is the state of an App that was never started.
+	AppCreated AppState = iota
+
+	// AppStarting is the state of an App that is running OnStart hooks.
+	AppStarting
+
+	// AppStarted is the state of an App whose OnStart hooks all succeeded.
+	AppStarted
+
+	// AppStopping is the state of an App that is running OnStop hooks,
+	// including while rolling back a failed start.
+	AppStopping
+
+	// AppStopped is the state of an App that finished running OnStop hooks,
+	// or whose start timed out or failed.
+	AppStopped
+
+	// AppPaused is the state of a started App whose OnPause hooks all
+	// succeeded.
+	AppPaused
+)
+
+func (s AppState) String() string {
+	switch s {
+	case AppCreated:
+		return "created"
+	case AppStarting:
+		return "starting"
+	case AppStarted:
+		return "started"
+	case AppStopping:
+		return "stopping"
+	case AppStopped:
+		return "stopped"
+	case AppPaused:
+		return "paused"
+	default:
+		return fmt.Sprintf("AppState(%d)", int(s))
+	}
+}
+
+// State reports the current state of the application.
+func (app *App) State() AppState {
+	return app.state.Get()
+}
+
+// StateChanges returns a channel that receives the state of the application
+// every time it changes, starting with the current state,
+// until ctx is done.
+//
+//	for state := range app.StateChanges(ctx) {
+//		if state == fx.AppStarted {
+//			healthy.Store(true)
+//		}
+//	}
+//
+// The channel holds at most one state.
+// If the receiver falls behind, it misses intermediate states
+// but always receives the latest one.
+// The channel is closed once ctx is done,
+// and the application stops tracking it.
+func (app *App) StateChanges(ctx context.Context) <-chan AppState {
+	return app.state.Subscribe(ctx)
+}
+
+// appStateBroadcaster tracks the state of an App
+// and broadcasts its changes to subscribers.
+// All methods on appStateBroadcaster are concurrency-safe.
+type appStateBroadcaster struct {
+	mu    sync.Mutex
+	state AppState
+	subs  []chan AppState
+}
+
+func (b *appStateBroadcaster) Get() AppState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Subscribe returns a channel that receives state changes until ctx is
+// done, at which point the channel is closed.
+func (b *appStateBroadcaster) Subscribe(ctx context.Context) <-chan AppState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan AppState, 1)
+	ch <- b.state
+	b.subs = append(b.subs, ch)
+	context.AfterFunc(ctx, func() { b.unsubscribe(ch) })
+	return ch
+}
+
+// unsubscribe stops sending state changes to ch and closes it.
+func (b *appStateBroadcaster) unsubscribe(ch chan AppState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, sub := range b.subs {
+		if sub == ch {
+			b.subs = append(b.subs[:i], b.subs[i+1:]...)
+			close(ch)
+			return
+		}
+	}
+}
+
+// Set changes the state and sends it to all subscribers without blocking,
+// replacing states they have not received yet.
+func (b *appStateBroadcaster) Set(state AppState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.set(state)
+}
+
+// Transition changes the state like Set if the current state is one of from.
+// It returns the state it found, and whether it changed it.
+func (b *appStateBroadcaster) Transition(state AppState, from ...AppState) (AppState, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cur := b.state
+	for _, s := range from {
+		if s == cur {
+			b.set(state)
+			return cur, true
+		}
+	}
+	return cur, false
+}
+
+// set changes the state and notifies subscribers.
+// It must be called with b.mu held.
+func (b *appStateBroadcaster) set(state AppState) {
+	b.state = state
+	for _, ch := range b.subs {
+		// Drop the pending state, if any.
+		// Only Set sends to the channel, so the send below cannot block.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- state
+	}
+}
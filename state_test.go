@@ -0,0 +1,293 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/fx/internal/fxlog"
+)
+
+func TestAppState(t *testing.T) {
+	t.Parallel()
+
+	t.Run("StartStop", func(t *testing.T) {
+		t.Parallel()
+
+		var app *fxtest.App
+		var states []fx.AppState
+		app = fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error {
+						states = append(states, app.State())
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						states = append(states, app.State())
+						return nil
+					},
+				})
+			}),
+		)
+		assert.Equal(t, fx.AppCreated, app.State())
+
+		app.RequireStart()
+		assert.Equal(t, fx.AppStarted, app.State())
+		app.RequireStop()
+		assert.Equal(t, fx.AppStopped, app.State())
+
+		assert.Equal(t, []fx.AppState{fx.AppStarting, fx.AppStopping}, states)
+	})
+
+	t.Run("StartFailure", func(t *testing.T) {
+		t.Parallel()
+
+		var app *fxtest.App
+		var states []fx.AppState
+		app = fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error { return nil },
+					OnStop: func(context.Context) error {
+						states = append(states, app.State())
+						return nil
+					},
+				})
+				lc.Append(fx.StartHook(func() error {
+					return errors.New("great sadness")
+				}))
+			}),
+		)
+
+		require.Error(t, app.Start(context.Background()))
+		assert.Equal(t, fx.AppStopped, app.State())
+		assert.Equal(t, []fx.AppState{fx.AppStopping}, states)
+	})
+
+	t.Run("StateChanges", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t)
+		changes := app.StateChanges(context.Background())
+		assert.Equal(t, fx.AppCreated, <-changes)
+
+		var got []fx.AppState
+		require.NoError(t, app.Start(context.Background()))
+		got = append(got, <-changes)
+		require.NoError(t, app.Stop(context.Background()))
+		got = append(got, <-changes)
+
+		// Intermediate states are replaced by the latest one.
+		assert.Equal(t, []fx.AppState{fx.AppStarted, fx.AppStopped}, got)
+	})
+
+	t.Run("StateChangesAfterStart", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t).RequireStart()
+		defer app.RequireStop()
+
+		assert.Equal(t, fx.AppStarted, <-app.StateChanges(context.Background()))
+	})
+
+	t.Run("StateChangesUntilContextDone", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		changes := app.StateChanges(ctx)
+		assert.Equal(t, fx.AppCreated, <-changes)
+
+		cancel()
+		_, ok := <-changes
+		assert.False(t, ok, "channel must be closed once the context is done")
+
+		// The application no longer tracks the channel.
+		app.RequireStart().RequireStop()
+	})
+
+	t.Run("Restart", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t)
+		for i := 0; i < 2; i++ {
+			app.RequireStart()
+			assert.Equal(t, fx.AppStarted, app.State())
+			app.RequireStop()
+			assert.Equal(t, fx.AppStopped, app.State())
+		}
+	})
+
+	t.Run("RestartRunsHooks", func(t *testing.T) {
+		t.Parallel()
+
+		var constructed, starts, stops int
+		app := fxtest.New(t,
+			fx.Provide(func(lc fx.Lifecycle) struct{} {
+				constructed++
+				lc.Append(fx.StartStopHook(
+					func() { starts++ },
+					func() { stops++ },
+				))
+				return struct{}{}
+			}),
+			fx.Invoke(func(struct{}) {}),
+		)
+		for i := 1; i <= 3; i++ {
+			app.RequireStart()
+			assert.Equal(t, i, starts)
+			app.RequireStop()
+			assert.Equal(t, i, stops)
+		}
+		assert.Equal(t, 1, constructed, "constructors run only once")
+	})
+
+	t.Run("RestartAfterFailedStart", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			failHook, failInvoke = true, true
+			invoked              int
+		)
+		app := fx.New(
+			fx.NopLogger,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() error {
+					if failHook {
+						return errors.New("great sadness")
+					}
+					return nil
+				}))
+			}),
+			fx.InvokeAtStart(func() error {
+				if failInvoke {
+					return errors.New("invoke failed")
+				}
+				invoked++
+				return nil
+			}),
+		)
+
+		wait := app.Wait()
+		require.ErrorContains(t, app.Start(context.Background()), "invoke failed")
+		assert.Equal(t, fx.AppStopped, app.State())
+		select {
+		case sig := <-wait:
+			assert.Equal(t, 1, sig.ExitCode, "existing waiters learn about the failure")
+		default:
+			assert.Fail(t, "existing waiters must be notified of the failed start")
+		}
+
+		failInvoke = false
+		require.ErrorContains(t, app.Start(context.Background()), "great sadness")
+		assert.Equal(t, fx.AppStopped, app.State())
+		assert.Equal(t, 1, invoked, "InvokeAtStart functions run again after they fail")
+
+		failHook = false
+		require.NoError(t, app.Start(context.Background()))
+		assert.Equal(t, fx.AppStarted, app.State())
+		assert.Equal(t, 1, invoked, "InvokeAtStart functions that succeeded do not run again")
+
+		select {
+		case sig := <-app.Wait():
+			assert.Fail(t, "earlier failed starts must not be reported as shutdowns", "got %v", sig)
+		default:
+		}
+		require.NoError(t, app.Stop(context.Background()))
+	})
+
+	t.Run("StartWhileRunningLogsNothing", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		app := fxtest.New(t, fx.WithLogger(func() fxevent.Logger { return &spy }))
+		app.RequireStart()
+		spy.Reset()
+
+		require.Error(t, app.Start(context.Background()))
+		assert.Empty(t, spy.Events(), "failed start must not log events")
+		app.RequireStop()
+	})
+
+	t.Run("StartWhileRunning", func(t *testing.T) {
+		t.Parallel()
+
+		var stops int
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StopHook(func() { stops++ }))
+			}),
+		)
+		app.RequireStart()
+		require.NoError(t, app.Pause(context.Background()))
+
+		for _, state := range []fx.AppState{fx.AppPaused, fx.AppStarted} {
+			err := app.Start(context.Background())
+			require.Error(t, err)
+			assert.EqualError(t, err, "cannot start application that is "+state.String())
+			assert.Equal(t, state, app.State())
+			assert.Zero(t, stops, "failed start must not stop the app")
+
+			if state == fx.AppPaused {
+				require.NoError(t, app.Resume(context.Background()))
+			}
+		}
+		app.RequireStop()
+		assert.Equal(t, 1, stops)
+	})
+
+	t.Run("StopBeforeStart", func(t *testing.T) {
+		t.Parallel()
+
+		var stops int
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StopHook(func() { stops++ }))
+			}),
+		)
+		app.RequireStop()
+		assert.Zero(t, stops)
+		assert.Equal(t, fx.AppStopped, app.State())
+
+		app.RequireStart().RequireStop()
+		assert.Equal(t, 1, stops)
+	})
+
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "created", fx.AppCreated.String())
+		assert.Equal(t, "starting", fx.AppStarting.String())
+		assert.Equal(t, "started", fx.AppStarted.String())
+		assert.Equal(t, "stopping", fx.AppStopping.String())
+		assert.Equal(t, "stopped", fx.AppStopped.String())
+		assert.Equal(t, "paused", fx.AppPaused.String())
+		assert.Equal(t, "AppState(42)", fx.AppState(42).String())
+	})
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"reflect"
+)
+
+// Strict enables checks that fail the application when constructors
+// passed to [Provide] do not follow conventions
+// that keep large Fx applications maintainable.
+// Unlike [Lint], which only reports suspicious patterns,
+// violations found by Strict are errors.
+//
+// The following are rejected:
+//
+//   - variadic constructors,
+//     whose variadic parameters are never filled by Fx
+//   - constructors that return values of unexported types,
+//     unless they are provided as an exported interface with [As]
+//   - constructors that accept a context.Context;
+//     constructors run while the application is built,
+//     so use lifecycle hooks for work that needs a context
+//   - [In] structs with more than one field of the same type
+//     without a name or group tag
+//
+// Strict may only be passed to [New].
+func Strict() Option {
+	return strictOption{}
+}
+
+type strictOption struct{}
+
+func (o strictOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.Strict Option should be passed to top-level " +
+			"App, not to fx.Module")
+	} else {
+		m.app.strict = true
+	}
+}
+
+func (o strictOption) String() string {
+	return "fx.Strict()"
+}
+
+// checkStrict checks a constructor against the rules enabled by fx.Strict.
+func checkStrict(target interface{}) error {
+	t, ok := lintFuncType(target)
+	if !ok {
+		return nil
+	}
+
+	if t.IsVariadic() {
+		return errors.New("fx.Strict: constructors must not be variadic")
+	}
+
+	for i := 0; i < t.NumIn(); i++ {
+		in := t.In(i)
+		if in == _typeOfContext {
+			return errors.New("fx.Strict: constructors must not accept a context.Context; " +
+				"use lifecycle hooks for work that needs a context")
+		}
+		if isIn(in) {
+			if err := checkStrictParams(in, make(map[reflect.Type]string)); err != nil {
+				return err
+			}
+		}
+	}
+
+	var as [][]asType
+	if ann, ok := target.(annotated); ok {
+		as = ann.As
+	}
+	for i := 0; i < t.NumOut(); i++ {
+		out := t.Out(i)
+		if out == _typeOfError || !providedAsSelf(as, i) {
+			continue
+		}
+		if isOut(out) {
+			for j := 0; j < out.NumField(); j++ {
+				f := out.Field(j)
+				if f.Type == _outAnnotationField.Type {
+					continue
+				}
+				if unexportedType(f.Type) {
+					return fmt.Errorf("fx.Strict: field %v of %v has unexported type %v", f.Name, out, f.Type)
+				}
+			}
+			continue
+		}
+		if unexportedType(out) {
+			return fmt.Errorf("fx.Strict: constructors must not return unexported type %v", out)
+		}
+	}
+
+	return nil
+}
+
+// checkStrictParams reports fields of the fx.In struct t that have the same
+// type as another field, and neither has a name or group tag.
+// seen maps the types of untagged fields to their names.
+func checkStrictParams(t reflect.Type, seen map[reflect.Type]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		switch {
+		case f.Type == _inAnnotationField.Type:
+			continue
+		case f.Type == _typeOfContext:
+			return fmt.Errorf("fx.Strict: field %v of %v is a context.Context; "+
+				"use lifecycle hooks for work that needs a context", f.Name, t)
+		case isIn(f.Type), f.Anonymous && f.Tag.Get(_inlineTag) == "true":
+			if err := checkStrictParams(f.Type, seen); err != nil {
+				return err
+			}
+			continue
+		case f.Tag.Get("name") != "" || f.Tag.Get("group") != "":
+			continue
+		}
+
+		if prev, ok := seen[f.Type]; ok {
+			return fmt.Errorf("fx.Strict: fields %v and %v of %v have the same type %v; "+
+				"tag them with different names", prev, f.Name, t, f.Type)
+		}
+		seen[f.Type] = f.Name
+	}
+	return nil
+}
+
+// providedAsSelf reports whether the i-th result of a constructor annotated
+// with the given fx.As annotations is provided as its own type.
+func providedAsSelf(as [][]asType, i int) bool {
+	if len(as) == 0 {
+		return true
+	}
+	for _, types := range as {
+		if i >= len(types) || types[i].self {
+			return true
+		}
+	}
+	return false
+}
+
+// unexportedType reports whether t, or the type of the elements of t,
+// is a named type that is not exported.
+func unexportedType(t reflect.Type) bool {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+			t = t.Elem()
+			continue
+		}
+		break
+	}
+	return t.PkgPath() != "" && !token.IsExported(t.Name())
+}
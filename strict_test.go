@@ -0,0 +1,174 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type strictReader struct{}
+
+func (strictReader) Read([]byte) (int, error) { return 0, io.EOF }
+
+func TestStrict(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+
+	tests := []struct {
+		desc    string
+		give    interface{}
+		wantErr string // empty if the constructor is allowed
+	}{
+		{
+			desc: "Allowed",
+			give: func(*A) *B { return &B{} },
+		},
+		{
+			desc:    "Variadic",
+			give:    func(...*A) *B { return &B{} },
+			wantErr: "constructors must not be variadic",
+		},
+		{
+			desc:    "Context",
+			give:    func(context.Context) *B { return &B{} },
+			wantErr: "constructors must not accept a context.Context",
+		},
+		{
+			desc: "ContextInParams",
+			give: func(struct {
+				fx.In
+
+				Ctx context.Context
+			}) *B {
+				return &B{}
+			},
+			wantErr: "field Ctx of struct { dig.In; Ctx context.Context } is a context.Context",
+		},
+		{
+			desc:    "UnexportedResult",
+			give:    func() *strictReader { return &strictReader{} },
+			wantErr: "constructors must not return unexported type *fx_test.strictReader",
+		},
+		{
+			desc: "UnexportedResultField",
+			give: func() struct {
+				fx.Out
+
+				Readers []strictReader `group:"readers,flatten"`
+			} {
+				return struct {
+					fx.Out
+
+					Readers []strictReader `group:"readers,flatten"`
+				}{}
+			},
+			wantErr: "field Readers of",
+		},
+		{
+			desc: "UnexportedResultAs",
+			give: fx.Annotate(
+				func() *strictReader { return &strictReader{} },
+				fx.As(new(io.Reader)),
+			),
+		},
+		{
+			desc: "UnexportedResultAsSelf",
+			give: fx.Annotate(
+				func() *strictReader { return &strictReader{} },
+				fx.As(new(io.Reader)),
+				fx.As(fx.Self()),
+			),
+			wantErr: "constructors must not return unexported type *fx_test.strictReader",
+		},
+		{
+			desc: "DuplicateParams",
+			give: func(struct {
+				fx.In
+
+				Primary   *A
+				Secondary *A
+			}) *B {
+				return &B{}
+			},
+			wantErr: "fields Primary and Secondary of",
+		},
+		{
+			desc: "DuplicateNamedParams",
+			give: func(struct {
+				fx.In
+
+				Primary   *A `name:"primary"`
+				Secondary *A `name:"secondary"`
+			}) *B {
+				return &B{}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			app := fx.New(
+				fx.NopLogger,
+				fx.Strict(),
+				fx.Supply(&A{}),
+				fx.Provide(tt.give),
+			)
+			err := app.Err()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "fx.Strict: ")
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(func(...string) *strictReader { return &strictReader{} }),
+		)
+		assert.NoError(t, app.Err())
+	})
+
+	t.Run("InModule", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(fx.NopLogger, fx.Module("foo", fx.Strict()))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.Strict Option should be passed to top-level App")
+	})
+}
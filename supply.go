@@ -23,6 +23,7 @@ package fx
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"go.uber.org/fx/internal/fxreflect"
@@ -82,7 +83,10 @@ import (
 func Supply(values ...interface{}) Option {
 	constructors := make([]interface{}, 0, len(values))
 	types := make([]reflect.Type, 0, len(values))
-	var private bool
+	var (
+		private bool
+		meta    map[string]string
+	)
 	for _, value := range values {
 		var (
 			typ  reflect.Type
@@ -92,6 +96,9 @@ func Supply(values ...interface{}) Option {
 		case privateOption:
 			private = true
 			continue
+		case metaOption:
+			meta = value.addMeta(meta)
+			continue
 		case annotated:
 			value.Target, typ = newSupplyConstructor(value.Target)
 			ctor = value
@@ -110,14 +117,86 @@ func Supply(values ...interface{}) Option {
 		Types:   types,
 		Stack:   fxreflect.CallerStack(1, 0),
 		Private: private,
+		Meta:    meta,
+	}
+}
+
+// SupplyNamed supplies the given values under the names they're mapped to,
+// as if each was passed to [Supply] annotated with that name.
+// As with Supply, the most specific type of each value is used.
+//
+// For example, the following two forms are equivalent:
+//
+//	fx.SupplyNamed(map[string]any{"rw": rwDB, "ro": roDB})
+//
+//	fx.Supply(
+//		fx.Annotated{Name: "rw", Target: rwDB},
+//		fx.Annotated{Name: "ro", Target: roDB},
+//	)
+//
+// Values are supplied in the order of their names.
+// Use [SupplyNamedAs] to supply them as an interface they implement.
+//
+// SupplyNamed panics if a value is an untyped nil or an error.
+func SupplyNamed(values map[string]interface{}) Option {
+	o := supplyOption{Stack: fxreflect.CallerStack(1, 0)}
+	for _, name := range sortedNames(values) {
+		ctor, typ := newSupplyConstructor(values[name])
+		o.add(name, ctor, typ)
 	}
+	return o
+}
+
+// SupplyNamedAs supplies the given values as type T
+// under the names they're mapped to.
+// It's like [SupplyNamed], but T may be an interface the values implement:
+//
+//	fx.SupplyNamedAs[http.Handler](map[string]http.Handler{
+//		"public":  publicMux,
+//		"private": privateMux,
+//	})
+//
+// SupplyNamedAs panics if a value is a nil interface or an error.
+func SupplyNamedAs[T any](values map[string]T) Option {
+	o := supplyOption{Stack: fxreflect.CallerStack(1, 0)}
+	for _, name := range sortedNames(values) {
+		value := values[name]
+		switch any(value).(type) {
+		case nil:
+			panic(fmt.Sprintf("nil interface passed to fx.SupplyNamedAs for %q", name))
+		case error:
+			panic(fmt.Sprintf("error value passed to fx.SupplyNamedAs for %q", name))
+		}
+		ctor, typ := newTypedSupplyConstructor(reflect.ValueOf(&value).Elem())
+		o.add(name, ctor, typ)
+	}
+	return o
+}
+
+func sortedNames[T any](values map[string]T) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 type supplyOption struct {
 	Targets []interface{}
 	Types   []reflect.Type // type of value produced by constructor[i]
+	Names   []string       // name of value i, if supplied with fx.SupplyNamed
 	Stack   fxreflect.Stack
 	Private bool
+	Meta    map[string]string // set with fx.Meta
+	Default bool              // whether this is an fx.Default
+}
+
+// add adds a value supplied under the given name.
+func (o *supplyOption) add(name string, ctor interface{}, typ reflect.Type) {
+	o.Targets = append(o.Targets, Annotated{Name: name, Target: ctor})
+	o.Types = append(o.Types, typ)
+	o.Names = append(o.Names, name)
 }
 
 func (o supplyOption) apply(m *module) {
@@ -128,16 +207,32 @@ func (o supplyOption) apply(m *module) {
 			IsSupply:   true,
 			SupplyType: o.Types[i],
 			Private:    o.Private,
+			Meta:       o.Meta,
+			IsDefault:  o.Default,
 		})
 	}
+	if o.Default {
+		m.app.hasDuplicatePolicy = true
+	}
 }
 
 func (o supplyOption) String() string {
 	items := make([]string, 0, len(o.Targets))
-	for _, typ := range o.Types {
-		items = append(items, typ.String())
+	for i, typ := range o.Types {
+		if o.Names != nil {
+			items = append(items, fmt.Sprintf("%q: %v", o.Names[i], typ))
+		} else {
+			items = append(items, typ.String())
+		}
 	}
-	return fmt.Sprintf("fx.Supply(%s)", strings.Join(items, ", "))
+	name := "fx.Supply"
+	switch {
+	case o.Default:
+		name = "fx.Default"
+	case o.Names != nil:
+		name = "fx.SupplyNamed"
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(items, ", "))
 }
 
 // Returns a function that takes no parameters, and returns the given value.
@@ -149,9 +244,15 @@ func newSupplyConstructor(value interface{}) (interface{}, reflect.Type) {
 		panic("error value passed to fx.Supply")
 	}
 
-	typ := reflect.TypeOf(value)
+	return newTypedSupplyConstructor(reflect.ValueOf(value))
+}
+
+// Returns a function that takes no parameters, and returns the given value
+// as its type, which may be an interface.
+func newTypedSupplyConstructor(value reflect.Value) (interface{}, reflect.Type) {
+	typ := value.Type()
 	returnTypes := []reflect.Type{typ}
-	returnValues := []reflect.Value{reflect.ValueOf(value)}
+	returnValues := []reflect.Value{value}
 
 	ft := reflect.FuncOf([]reflect.Type{}, returnTypes, false)
 	fv := reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
@@ -23,6 +23,7 @@ package fx_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"testing"
 
@@ -216,3 +217,87 @@ func TestSupply(t *testing.T) {
 		defer app.RequireStart().RequireStop()
 	})
 }
+
+func TestSupplyNamed(t *testing.T) {
+	t.Parallel()
+
+	type DB struct{ name string }
+
+	t.Run("ByType", func(t *testing.T) {
+		t.Parallel()
+
+		rw, ro := &DB{"rw"}, &DB{"ro"}
+		var got struct {
+			fx.In
+
+			RW *DB `name:"rw"`
+			RO *DB `name:"ro"`
+		}
+		app := fxtest.New(t,
+			fx.SupplyNamed(map[string]any{"rw": rw, "ro": ro}),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Same(t, rw, got.RW)
+		assert.Same(t, ro, got.RO)
+	})
+
+	t.Run("AsInterface", func(t *testing.T) {
+		t.Parallel()
+
+		src, dst := new(bytes.Buffer), new(bytes.Buffer)
+		var got struct {
+			fx.In
+
+			Src io.Reader `name:"src"`
+			Dst io.Reader `name:"dst"`
+		}
+		app := fxtest.New(t,
+			fx.SupplyNamedAs[io.Reader](map[string]io.Reader{"src": src, "dst": dst}),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Same(t, src, got.Src)
+		assert.Same(t, dst, got.Dst)
+	})
+
+	t.Run("SuppliedInOrder", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		app := fx.New(
+			fx.WithLogger(func() fxevent.Logger { return &spy }),
+			fx.SupplyNamed(map[string]any{"b": &DB{}, "a": 1, "c": "c"}),
+		)
+		require.NoError(t, app.Err())
+
+		var types []string
+		for _, e := range spy.Events().SelectByTypeName("Supplied") {
+			types = append(types, e.(*fxevent.Supplied).TypeName)
+		}
+		assert.Equal(t, []string{"int", "*fx_test.DB", "string"}, types)
+	})
+
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, `fx.SupplyNamed("a": int, "b": string)`,
+			fmt.Sprint(fx.SupplyNamed(map[string]any{"b": "b", "a": 1})))
+	})
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		t.Parallel()
+
+		assert.PanicsWithValue(t, "untyped nil passed to fx.Supply",
+			func() { fx.SupplyNamed(map[string]any{"a": nil}) })
+		assert.PanicsWithValue(t, `nil interface passed to fx.SupplyNamedAs for "a"`,
+			func() { fx.SupplyNamedAs[io.Reader](map[string]io.Reader{"a": nil}) })
+		assert.PanicsWithValue(t, `error value passed to fx.SupplyNamedAs for "a"`,
+			func() { fx.SupplyNamedAs[error](map[string]error{"a": errors.New("fail")}) })
+		assert.NotPanics(t,
+			func() { fx.SupplyNamedAs[*DB](map[string]*DB{"a": nil}) },
+			"a typed nil should not panic")
+	})
+}
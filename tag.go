@@ -0,0 +1,156 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TagBuilder builds tags for [ParamTags] and [ResultTags],
+// so that they don't need to be written by hand.
+// Start building a tag with [Tag].
+//
+//	fx.Annotate(NewHandler,
+//		fx.ParamTags(fx.Skip(), fx.Tag().Name("ro").Optional().Build()),
+//		fx.ResultTags(fx.GroupTag("routes", fx.Flatten)),
+//	)
+//
+// TagBuilder values are immutable:
+// each method returns a new builder.
+type TagBuilder struct {
+	name     string
+	group    string
+	groupKey string
+	optional bool
+	dflt     *string
+	custom   [][2]string // key, value
+}
+
+// Tag starts building a tag. See [TagBuilder].
+func Tag() TagBuilder {
+	return TagBuilder{}
+}
+
+// Name sets the name of the value, as with `name:"..."`.
+func (b TagBuilder) Name(name string) TagBuilder {
+	b.name = name
+	return b
+}
+
+// Group sets the value group of the value, as with `group:"..."`,
+// with the given options.
+func (b TagBuilder) Group(group string, opts ...GroupOption) TagBuilder {
+	b.group = groupTagValue(group, opts)
+	return b
+}
+
+// GroupKey sets the key of a value contributed to a value group,
+// as with `key:"..."`, so that it can be looked up by key
+// by parameters that consume the group as a map[string]T.
+func (b TagBuilder) GroupKey(key string) TagBuilder {
+	b.groupKey = key
+	return b
+}
+
+// Optional marks a parameter as optional, as with `optional:"true"`.
+func (b TagBuilder) Optional() TagBuilder {
+	b.optional = true
+	return b
+}
+
+// Default sets the default value of an optional parameter,
+// as with `default:"..."`.
+func (b TagBuilder) Default(value string) TagBuilder {
+	b.dflt = &value
+	return b
+}
+
+// Key attaches user-defined metadata under a namespaced key,
+// as with `acme.owner:"payments"`.
+// See [AnnotationTags] to read it back.
+func (b TagBuilder) Key(key, value string) TagBuilder {
+	b.custom = append(b.custom[:len(b.custom):len(b.custom)], [2]string{key, value})
+	return b
+}
+
+// Build returns the tag.
+func (b TagBuilder) Build() string {
+	var parts []string
+	add := func(key, value string) {
+		parts = append(parts, key+":"+strconv.Quote(value))
+	}
+	if b.name != "" {
+		add("name", b.name)
+	}
+	if b.group != "" {
+		add("group", b.group)
+	}
+	if b.groupKey != "" {
+		add(_keyTag, b.groupKey)
+	}
+	if b.optional {
+		add("optional", "true")
+	}
+	if b.dflt != nil {
+		add(_defaultTag, *b.dflt)
+	}
+	for _, kv := range b.custom {
+		add(kv[0], kv[1])
+	}
+	return strings.Join(parts, " ")
+}
+
+// GroupOption is an option of a value group tag.
+// See [GroupTag].
+type GroupOption string
+
+const (
+	// Flatten provides the elements of a slice result
+	// as individual values of the group.
+	// It only applies to results.
+	Flatten GroupOption = "flatten"
+
+	// Soft makes a group parameter only receive values from constructors
+	// that were already run for other reasons.
+	// It only applies to parameters.
+	Soft GroupOption = "soft"
+)
+
+// GroupTag returns a tag that places a value in, or consumes, the given
+// value group with the given options.
+//
+//	fx.ResultTags(fx.GroupTag("routes", fx.Flatten)) // `group:"routes,flatten"`
+//
+// It's equivalent to fx.Tag().Group(group, opts...).Build().
+func GroupTag(group string, opts ...GroupOption) string {
+	return Tag().Group(group, opts...).Build()
+}
+
+func groupTagValue(group string, opts []GroupOption) string {
+	var sb strings.Builder
+	sb.WriteString(group)
+	for _, opt := range opts {
+		sb.WriteByte(',')
+		sb.WriteString(string(opt))
+	}
+	return sb.String()
+}
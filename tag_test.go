@@ -0,0 +1,118 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestTagBuilder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give string
+		want string
+	}{
+		{desc: "empty", give: fx.Tag().Build(), want: ``},
+		{desc: "name", give: fx.Tag().Name("ro").Build(), want: `name:"ro"`},
+		{
+			desc: "optional name",
+			give: fx.Tag().Optional().Name("ro").Build(),
+			want: `name:"ro" optional:"true"`,
+		},
+		{
+			desc: "default",
+			give: fx.Tag().Optional().Default("8080").Build(),
+			want: `optional:"true" default:"8080"`,
+		},
+		{desc: "group", give: fx.Tag().Group("servers").Build(), want: `group:"servers"`},
+		{
+			desc: "group options",
+			give: fx.Tag().Group("servers", fx.Soft).Build(),
+			want: `group:"servers,soft"`,
+		},
+		{
+			desc: "group key",
+			give: fx.Tag().Group("codecs").GroupKey("json").Build(),
+			want: `group:"codecs" key:"json"`,
+		},
+		{
+			desc: "custom keys",
+			give: fx.Tag().Name("ro").Key("acme.owner", "payments").Key("acme.tier", "1").Build(),
+			want: `name:"ro" acme.owner:"payments" acme.tier:"1"`,
+		},
+		{desc: "quoted", give: fx.Tag().Name(`a"b`).Build(), want: `name:"a\"b"`},
+		{desc: "GroupTag", give: fx.GroupTag("routes", fx.Flatten), want: `group:"routes,flatten"`},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.give)
+		})
+	}
+
+	t.Run("immutable", func(t *testing.T) {
+		t.Parallel()
+
+		base := fx.Tag().Key("acme.owner", "payments")
+		a := base.Key("acme.tier", "1")
+		b := base.Key("acme.tier", "2")
+		assert.Equal(t, `acme.owner:"payments"`, base.Build())
+		assert.Equal(t, `acme.owner:"payments" acme.tier:"1"`, a.Build())
+		assert.Equal(t, `acme.owner:"payments" acme.tier:"2"`, b.Build())
+	})
+}
+
+func TestTagBuilderAnnotate(t *testing.T) {
+	t.Parallel()
+
+	type route string
+	type server struct{ routes []route }
+
+	var got *server
+	app := fxtest.New(t,
+		fx.Provide(
+			fx.Annotate(
+				func() []route { return []route{"/a", "/b"} },
+				fx.ResultTags(fx.GroupTag("routes", fx.Flatten)),
+			),
+			fx.Annotate(
+				func(name string, routes []route) *server { return &server{routes: routes} },
+				fx.ParamTags(
+					fx.Tag().Name("name").Optional().Default("srv").Build(),
+					fx.GroupTag("routes"),
+				),
+			),
+		),
+		fx.Populate(&got),
+	)
+	defer app.RequireStart().RequireStop()
+
+	assert.ElementsMatch(t, []route{"/a", "/b"}, got.routes)
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/multierr"
+)
+
+// TagError is a problem with one of the tags passed to [ParamTags] or
+// [ResultTags]. [Annotate] reports all the tag problems in its annotations
+// at once: use [go.uber.org/multierr.Errors] and [errors.As] to inspect them.
+type TagError struct {
+	// Annotation is the annotation that holds the tag,
+	// either "fx.ParamTags" or "fx.ResultTags".
+	Annotation string
+
+	// Index is the position of the tag in the annotation.
+	Index int
+
+	// Tag is the offending tag.
+	Tag string
+
+	// Err describes the problem.
+	Err error
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("%v tag %d (`%v`): %v", e.Annotation, e.Index, e.Tag, e.Err)
+}
+
+// Unwrap returns the problem with the tag.
+func (e *TagError) Unwrap() error {
+	return e.Err
+}
+
+// Tag keys interpreted by dig or Fx, in the order they are suggested.
+var _tagKeys = []string{"name", "group", "optional", _defaultTag, _keyTag}
+
+// unknownTagKeyError reports an invalid tag key,
+// suggesting the key that was most likely intended, if any.
+func unknownTagKeyError(key string) error {
+	if s := suggestTagKey(key); s != "" {
+		return fmt.Errorf("%w: did you mean `%v:`?", errTagKeySyntax, s)
+	}
+	return errTagKeySyntax
+}
+
+// suggestTagKey returns the known tag key closest to key,
+// or "" if none is close enough to be a likely typo.
+func suggestTagKey(key string) string {
+	key = strings.ToLower(key)
+	if key == "" {
+		return ""
+	}
+	switch key {
+	case "soft", "flatten":
+		// Options of value groups, as in group:"name,soft".
+		return "group"
+	}
+
+	var (
+		best     string
+		bestDist = 3 // only suggest keys at most 2 edits away
+	)
+	for _, k := range _tagKeys {
+		if d := editDistance(key, k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	if bestDist >= len(key) {
+		// Too short to tell what was intended.
+		return ""
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// checkTags verifies the tags of a ParamTags (params is true) or ResultTags
+// annotation, returning a TagError for each problem found.
+func checkTags(tags []string, params bool) error {
+	annotation := "fx.ResultTags"
+	if params {
+		annotation = "fx.ParamTags"
+	}
+
+	var errs error
+	for i, tag := range tags {
+		err := verifyAnnotateTag(tag)
+		if err == nil {
+			// Combinations are only meaningful for well-formed tags.
+			err = checkTagCombination(reflect.StructTag(tag), params)
+		}
+		for _, err := range multierr.Errors(err) {
+			errs = multierr.Append(errs, &TagError{
+				Annotation: annotation,
+				Index:      i,
+				Tag:        tag,
+				Err:        err,
+			})
+		}
+	}
+	return errs
+}
+
+// checkTagCombination verifies that the keys of a well-formed tag
+// make sense together, and for a parameter or result.
+func checkTagCombination(tag reflect.StructTag, params bool) error {
+	var errs error
+	name, hasName := tag.Lookup("name")
+	group, hasGroup := tag.Lookup("group")
+	if hasName && hasGroup {
+		errs = multierr.Append(errs, fmt.Errorf(
+			"cannot use named values with value groups: name:%q used with group:%q", name, group))
+	}
+
+	if hasGroup {
+		opts := strings.Split(group, ",")[1:]
+		for _, opt := range opts {
+			switch {
+			case opt == "soft" && !params:
+				errs = multierr.Append(errs, fmt.Errorf(
+					"cannot use soft with result value groups: soft was used with group:%q", group))
+			case opt == "flatten" && params:
+				errs = multierr.Append(errs, fmt.Errorf(
+					"cannot use flatten in parameter value groups: flatten was used with group:%q", group))
+			}
+		}
+	}
+
+	if _, ok := tag.Lookup(_defaultTag); ok && !params {
+		errs = multierr.Append(errs, errors.New(
+			"default tags can only be used with fx.ParamTags"))
+	}
+	return errs
+}
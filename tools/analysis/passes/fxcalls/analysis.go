@@ -0,0 +1,313 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fxcalls implements a Go analysis pass that reports errors in
+// calls to Fx functions that would otherwise only be found when the
+// application is built at runtime. It reports:
+//
+//   - arguments to fx.Provide and fx.Invoke that are not functions
+//   - malformed tags passed to fx.ParamTags and fx.ResultTags
+//   - fx.As targets that are not interfaces, or that the corresponding
+//     result of the annotated function doesn't implement
+package fxcalls
+
+import (
+	"errors"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Analyzer is a go/analysis compatible analyzer that verifies calls to
+// fx.Provide, fx.Invoke, and fx.Annotate.
+var Analyzer = &analysis.Analyzer{
+	Name: "fxcalls",
+	Doc:  "check calls to Fx functions for errors detectable at compile time",
+	Run:  run,
+	Requires: []*analysis.Analyzer{
+		inspect.Analyzer,
+	},
+}
+
+const _fxPath = "go.uber.org/fx"
+
+var _filter = []ast.Node{
+	&ast.CallExpr{},
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	if _, ok := findPackage(pass.Pkg, _fxPath); !ok {
+		// Nothing to check if the package doesn't use Fx.
+		return nil, nil
+	}
+
+	pass.ResultOf[inspect.Analyzer].(*inspector.Inspector).Preorder(_filter, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		switch fxFuncName(pass.TypesInfo, call) {
+		case "Provide":
+			checkFuncArgs(pass, "fx.Provide", call)
+		case "Invoke":
+			checkFuncArgs(pass, "fx.Invoke", call)
+		case "ParamTags", "ResultTags":
+			checkTags(pass, call)
+		case "Annotate":
+			checkAnnotate(pass, call)
+		}
+	})
+	return nil, nil
+}
+
+// fxFuncName returns the name of the top-level Fx function called by call,
+// or an empty string if call is not a call to one.
+func fxFuncName(info *types.Info, call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != _fxPath {
+		return ""
+	}
+	if fn.Type().(*types.Signature).Recv() != nil {
+		return ""
+	}
+	return fn.Name()
+}
+
+// checkFuncArgs reports arguments to fx.Provide or fx.Invoke that cannot
+// be functions.
+func checkFuncArgs(pass *analysis.Pass, fname string, call *ast.CallExpr) {
+	if call.Ellipsis.IsValid() {
+		// fx.Provide(ctors...)
+		return
+	}
+
+	for _, arg := range call.Args {
+		t := pass.TypesInfo.TypeOf(arg)
+		if t == nil {
+			continue
+		}
+		if implementsFxOption(t) {
+			pass.Reportf(arg.Pos(), "%v received %v, an fx.Option; "+
+				"pass it to fx.New or fx.Options directly", fname, types.ExprString(arg))
+			continue
+		}
+
+		switch t.Underlying().(type) {
+		case *types.Signature, *types.Interface:
+			// Functions, and values like the result of fx.Annotate
+			// that we can't see through.
+			continue
+		case *types.Struct:
+			if isFxType(t, "Annotated") || isFxType(t, "privateOption") {
+				continue
+			}
+		}
+
+		pass.Reportf(arg.Pos(), "%v expects a function, got %v of type %v",
+			fname, types.ExprString(arg), types.TypeString(t, types.RelativeTo(pass.Pkg)))
+	}
+}
+
+// checkTags reports malformed constant tags passed to fx.ParamTags or
+// fx.ResultTags.
+func checkTags(pass *analysis.Pass, call *ast.CallExpr) {
+	for _, arg := range call.Args {
+		tv, ok := pass.TypesInfo.Types[arg]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+			continue
+		}
+		if err := verifyTag(constant.StringVal(tv.Value)); err != nil {
+			pass.Reportf(arg.Pos(), "invalid tag %v: %v", types.ExprString(arg), err)
+		}
+	}
+}
+
+// checkAnnotate reports fx.As annotations passed to fx.Annotate that
+// cannot apply to the annotated function.
+func checkAnnotate(pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) == 0 {
+		return
+	}
+
+	sig, ok := pass.TypesInfo.TypeOf(call.Args[0]).Underlying().(*types.Signature)
+	if !ok {
+		return
+	}
+
+	// Results of the annotated function, excluding a trailing error.
+	var results []types.Type
+	for i := 0; i < sig.Results().Len(); i++ {
+		results = append(results, sig.Results().At(i).Type())
+	}
+	if n := len(results); n > 0 && types.Identical(results[n-1], types.Universe.Lookup("error").Type()) {
+		results = results[:n-1]
+	}
+
+	for _, arg := range call.Args[1:] {
+		as, ok := astutil.Unparen(arg).(*ast.CallExpr)
+		if !ok || fxFuncName(pass.TypesInfo, as) != "As" {
+			continue
+		}
+		for i, target := range as.Args {
+			if self, ok := astutil.Unparen(target).(*ast.CallExpr); ok &&
+				fxFuncName(pass.TypesInfo, self) == "Self" {
+				continue
+			}
+
+			ptr, ok := pass.TypesInfo.TypeOf(target).(*types.Pointer)
+			if !ok {
+				pass.Reportf(target.Pos(), "fx.As expects a pointer to an interface, e.g. new(io.Writer), got %v",
+					types.ExprString(target))
+				continue
+			}
+			iface, ok := ptr.Elem().Underlying().(*types.Interface)
+			if !ok {
+				pass.Reportf(target.Pos(), "fx.As expects a pointer to an interface, got a pointer to %v",
+					types.TypeString(ptr.Elem(), types.RelativeTo(pass.Pkg)))
+				continue
+			}
+			if i >= len(results) {
+				pass.Reportf(target.Pos(), "fx.As target %v has no corresponding result: "+
+					"the annotated function returns %d values", types.ExprString(target), len(results))
+				continue
+			}
+			if !types.Implements(results[i], iface) {
+				pass.Reportf(target.Pos(), "result %d of the annotated function (%v) does not implement %v",
+					i, types.TypeString(results[i], types.RelativeTo(pass.Pkg)),
+					types.TypeString(ptr.Elem(), types.RelativeTo(pass.Pkg)))
+			}
+		}
+	}
+}
+
+// isFxType reports whether t is the named type with the given name
+// declared in the fx package.
+func isFxType(t types.Type, name string) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == _fxPath && obj.Name() == name
+}
+
+// implementsFxOption reports whether t implements fx.Option.
+func implementsFxOption(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	fxPkg, ok := findPackage(named.Obj().Pkg(), _fxPath)
+	if !ok {
+		return false
+	}
+	opt := fxPkg.Scope().Lookup("Option")
+	if opt == nil {
+		return false
+	}
+	iface, ok := opt.Type().Underlying().(*types.Interface)
+	return ok && types.Implements(t, iface)
+}
+
+// Find the package with the given import path.
+func findPackage(pkg *types.Package, importPath string) (_ *types.Package, ok bool) {
+	if pkg.Path() == importPath {
+		return pkg, true
+	}
+
+	for _, imp := range pkg.Imports() {
+		if imp.Path() == importPath {
+			return imp, true
+		}
+	}
+
+	return nil, false
+}
+
+// The following mirrors the tag validation performed by fx.ParamTags and
+// fx.ResultTags at runtime.
+
+var (
+	errTagSyntaxSpace            = errors.New(`multiple tags are not separated by space`)
+	errTagKeySyntax              = errors.New("tag key is invalid, Use group, name, optional, default, key or a namespaced key (e.g. acme.owner) as tag keys")
+	errTagValueSyntaxQuote       = errors.New(`tag value should start with double quote. i.e. key:"value" `)
+	errTagValueSyntaxEndingQuote = errors.New(`tag value should end in double quote. i.e. key:"value" `)
+)
+
+var _validTagKeys = map[string]struct{}{"group": {}, "optional": {}, "name": {}, "default": {}, "key": {}}
+
+func verifyTag(tag string) error {
+	for tagIdx := 0; tag != ""; tagIdx++ {
+		if tagIdx > 0 && tag[0] != ' ' {
+			return errTagSyntaxSpace
+		}
+		if strings.TrimSpace(tag) == "" {
+			return nil
+		}
+
+		i := strings.IndexByte(tag, ':')
+		if i < 0 {
+			i = len(tag)
+		}
+		key := strings.TrimSpace(tag[:i])
+		if _, ok := _validTagKeys[key]; !ok && !isCustomTagKey(key) {
+			return errTagKeySyntax
+		}
+		if i == len(tag) {
+			return errTagValueSyntaxQuote
+		}
+
+		rest, err := verifyValueQuote(tag[i+1:])
+		if err != nil {
+			return err
+		}
+		tag = rest
+	}
+	return nil
+}
+
+func isCustomTagKey(key string) bool {
+	i := strings.IndexByte(key, '.')
+	if i <= 0 || i == len(key)-1 {
+		return false
+	}
+	return !strings.ContainsAny(key, " \t\"`:")
+}
+
+func verifyValueQuote(value string) (string, error) {
+	if value == "" || value[0] != '"' {
+		return "", errTagValueSyntaxQuote
+	}
+	i := 1
+	for i < len(value) && value[i] != '"' {
+		if value[i] == '\\' {
+			i++
+		}
+		i++
+	}
+	if i >= len(value) {
+		return "", errTagValueSyntaxEndingQuote
+	}
+	return value[i+1:], nil
+}
@@ -0,0 +1,40 @@
+package a
+
+import (
+	"bytes"
+	"io"
+
+	"go.uber.org/fx"
+)
+
+func newBuffer() (*bytes.Buffer, error) { return nil, nil }
+
+func newPair() (*bytes.Buffer, *config) { return nil, nil }
+
+func annotations() interface{} {
+	return fx.Options(
+		fx.Provide(
+			fx.Annotate(newBuffer, fx.As(new(io.Writer))),
+			fx.Annotate(newBuffer, fx.As(fx.Self(), new(io.Reader))),    // want `fx.As target new\(io.Reader\) has no corresponding result: the annotated function returns 1 values`
+			fx.Annotate(newBuffer, fx.As(new(io.Closer))),               // want `result 0 of the annotated function \(\*bytes.Buffer\) does not implement io.Closer`
+			fx.Annotate(newPair, fx.As(new(io.Reader), new(io.Writer))), // want `result 1 of the annotated function \(\*config\) does not implement io.Writer`
+			fx.Annotate(newBuffer, fx.As(new(bytes.Buffer))),            // want `fx.As expects a pointer to an interface, got a pointer to bytes.Buffer`
+			fx.Annotate(newBuffer, fx.As(io.Writer(nil))),               // want `fx.As expects a pointer to an interface, e.g. new\(io.Writer\), got io.Writer\(nil\)`
+		),
+	)
+}
+
+const _nameTag = `name:"foo"`
+
+func tags() interface{} {
+	return fx.Annotate(newPair,
+		fx.ParamTags(`name:"foo"`, `group:"bar" optional:"true"`, ``, _nameTag),
+		fx.ResultTags(
+			`name:"foo"group:"bar"`, // want `invalid tag .*: multiple tags are not separated by space`
+			`nmae:"foo"`,            // want `invalid tag .*: tag key is invalid, Use group, name, optional, default, key or a namespaced key \(e.g. acme.owner\) as tag keys`
+			`name:foo`,              // want `invalid tag .*: tag value should start with double quote`
+			`name:"foo`,             // want `invalid tag .*: tag value should end in double quote`
+			`name:`,                 // want `invalid tag .*: tag value should start with double quote`
+		),
+	)
+}
@@ -0,0 +1,29 @@
+package a
+
+import (
+	"bytes"
+
+	"go.uber.org/fx"
+)
+
+type config struct{}
+
+func newConfig() *config { return &config{} }
+
+func provides(ctors []interface{}, ann interface{}) fx.Option {
+	return fx.Options(
+		fx.Provide(
+			newConfig,
+			func() *bytes.Buffer { return nil },
+			fx.Annotated{Name: "cfg", Target: newConfig},
+			fx.Private,
+			ann,
+		),
+		fx.Provide(ctors...),
+		fx.Provide(config{}),     // want `fx.Provide expects a function, got config\{\} of type config`
+		fx.Provide(newConfig()),  // want `fx.Provide expects a function, got newConfig\(\) of type \*config`
+		fx.Provide(fx.Supply(1)), // want `fx.Provide received fx.Supply\(1\), an fx.Option; pass it to fx.New or fx.Options directly`
+		fx.Invoke(func(*config) {}),
+		fx.Invoke("run"), // want `fx.Invoke expects a function, got "run" of type string`
+	)
+}
@@ -0,0 +1,43 @@
+package fx
+
+// This is a partial fx package mirroring the signatures of the real fx
+// package that the fxcalls analyzer inspects.
+
+type Option interface{ apply() }
+
+type Annotation interface{ annotation() }
+
+type Annotated struct {
+	Name   string
+	Target interface{}
+}
+
+type privateOption struct{}
+
+var Private = privateOption{}
+
+type option struct{}
+
+func (option) apply() {}
+
+type annotation struct{}
+
+func (annotation) annotation() {}
+
+func Provide(constructors ...interface{}) Option { return option{} }
+
+func Invoke(funcs ...interface{}) Option { return option{} }
+
+func Supply(values ...interface{}) Option { return option{} }
+
+func Options(opts ...Option) Option { return option{} }
+
+func Annotate(t interface{}, anns ...Annotation) interface{} { return t }
+
+func ParamTags(tags ...string) Annotation { return annotation{} }
+
+func ResultTags(tags ...string) Annotation { return annotation{} }
+
+func As(interfaces ...interface{}) Annotation { return annotation{} }
+
+func Self() any { return nil }
@@ -23,15 +23,19 @@
 // Currently, the following passes are provided:
 //
 // - allfxevents: Verifies that all Fx events are handled by an fxevent.Logger.
+// - fxcalls: Reports errors in calls to fx.Provide, fx.Invoke, and
+// fx.Annotate that would otherwise only be found at runtime.
 package main
 
 import (
 	"go.uber.org/fx/tools/analysis/passes/allfxevents"
+	"go.uber.org/fx/tools/analysis/passes/fxcalls"
 	"golang.org/x/tools/go/analysis/multichecker"
 )
 
 func main() {
 	multichecker.Main(
 		allfxevents.Analyzer,
+		fxcalls.Analyzer,
 	)
 }
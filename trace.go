@@ -0,0 +1,190 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// Tracer starts spans for the work Fx performs on behalf of an application.
+// See [WithTracer].
+//
+// Fx does not depend on a tracing library.
+// Tracer is small enough to implement on top of an OpenTelemetry
+// trace.Tracer, for example:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string, start time.Time, attrs ...fx.SpanAttribute) (context.Context, fx.Span) {
+//		kvs := make([]attribute.KeyValue, len(attrs))
+//		for i, a := range attrs {
+//			kvs[i] = attribute.String(a.Key, a.Value)
+//		}
+//		ctx, span := t.Tracer.Start(ctx, name, trace.WithTimestamp(start), trace.WithAttributes(kvs...))
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) End(end time.Time, err error) {
+//		if err != nil {
+//			s.RecordError(err)
+//			s.SetStatus(codes.Error, err.Error())
+//		}
+//		s.Span.End(trace.WithTimestamp(end))
+//	}
+type Tracer interface {
+	// Start starts a span with the given name and start time
+	// as a child of the span in ctx, if any.
+	// It returns a context holding the new span.
+	Start(ctx context.Context, name string, start time.Time, attrs ...SpanAttribute) (context.Context, Span)
+}
+
+// Span is a span started by a [Tracer].
+type Span interface {
+	// End ends the span at the given time.
+	// err is the error the traced operation failed with, if any.
+	End(end time.Time, err error)
+}
+
+// SpanAttribute is a key-value pair attached to spans started by Fx.
+type SpanAttribute struct {
+	Key   string
+	Value string
+}
+
+// WithTracer traces the application with the given [Tracer].
+// This is independent of the [fxevent.Logger] in use,
+// for teams that trace their application's boot rather than log it.
+//
+// Fx starts the following root spans:
+//
+//   - "fx.New" while [New] runs constructors, decorators, and invokes
+//   - "fx.Extend" while [App.Extend] does the same
+//   - "fx.Start" while [App.Start] runs OnStart hooks
+//   - "fx.Stop" while [App.Stop] runs OnStop hooks
+//
+// Each constructor, decorator, invoke, and hook runs in a child span named
+// "fx.Provide", "fx.Decorate", "fx.Invoke", "fx.OnStart", or "fx.OnStop",
+// with the function name in the "fx.function" attribute
+// and the name of its [Module], if any, in "fx.module".
+// Constructors report a span under the invoke that requested them.
+// Hooks receive a context holding their span,
+// so spans they start are nested under it.
+//
+// To view the spans as a timeline, use the Recorder of the fxtrace package.
+func WithTracer(t Tracer) Option {
+	return withTracerOption{t}
+}
+
+type withTracerOption struct{ tracer Tracer }
+
+func (o withTracerOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.WithTracer Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.tracer = o.tracer
+	}
+}
+
+func (o withTracerOption) String() string {
+	return fmt.Sprintf("fx.WithTracer(%T)", o.tracer)
+}
+
+// startSpan starts a span if the application has a Tracer.
+// The returned function ends the span.
+func (app *App) startSpan(ctx context.Context, name string, attrs ...SpanAttribute) (context.Context, func(error)) {
+	if app.tracer == nil {
+		return ctx, func(error) {}
+	}
+	ctx, span := app.tracer.Start(ctx, name, app.clock.Now(), attrs...)
+	return ctx, func(err error) {
+		span.End(app.clock.Now(), err)
+	}
+}
+
+// spanParent returns the context that spans for constructors,
+// decorators, and invokes are started in.
+func (app *App) spanParent() context.Context {
+	if app.traceCtx == nil {
+		return context.Background()
+	}
+	return app.traceCtx
+}
+
+// traceRun records a span for a function that just returned
+// after running for the given duration.
+func (app *App) traceRun(name string, runtime time.Duration, err error, attrs ...SpanAttribute) {
+	if app.tracer == nil {
+		return
+	}
+	end := app.clock.Now()
+	_, span := app.tracer.Start(app.spanParent(), name, end.Add(-runtime), attrs...)
+	span.End(end, err)
+}
+
+// traceHook wraps the functions of a hook to run in spans.
+// It's called as the hook is appended, to attribute it to the running module.
+func (app *App) traceHook(h Hook) Hook {
+	var module string
+	if m := app.lifecycle.modules.current(); m != nil {
+		module = m.Name
+	}
+	if h.OnStart != nil {
+		if h.onStartName == "" {
+			h.onStartName = fxreflect.FuncName(h.OnStart)
+		}
+		h.OnStart = app.traceHookFunc("fx.OnStart", h.onStartName, module, h.OnStart)
+	}
+	if h.OnStop != nil {
+		if h.onStopName == "" {
+			h.onStopName = fxreflect.FuncName(h.OnStop)
+		}
+		h.OnStop = app.traceHookFunc("fx.OnStop", h.onStopName, module, h.OnStop)
+	}
+	return h
+}
+
+func (app *App) traceHookFunc(span, name, module string, f func(context.Context) error) func(context.Context) error {
+	attrs := []SpanAttribute{{Key: "fx.function", Value: name}}
+	if module != "" {
+		attrs = append(attrs, SpanAttribute{Key: "fx.module", Value: module})
+	}
+	return func(ctx context.Context) (err error) {
+		ctx, end := app.startSpan(ctx, span, attrs...)
+		defer func() { end(err) }()
+		return f(ctx)
+	}
+}
+
+// spanAttributes returns the attributes of spans for functions of m.
+func (m *module) spanAttributes(funcName string) []SpanAttribute {
+	attrs := []SpanAttribute{{Key: "fx.function", Value: funcName}}
+	if m.name != "" {
+		attrs = append(attrs, SpanAttribute{Key: "fx.module", Value: m.name})
+	}
+	return attrs
+}
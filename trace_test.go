@@ -0,0 +1,194 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type spanKey struct{}
+
+type recordedSpan struct {
+	Name   string
+	Parent string
+	Attrs  map[string]string
+	Err    error
+	Ended  bool
+}
+
+// recordingTracer records the spans started by Fx.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, _ time.Time, attrs ...fx.SpanAttribute) (context.Context, fx.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	span := &recordedSpan{Name: name, Attrs: make(map[string]string)}
+	if parent, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+		span.Parent = parent.Name
+	}
+	for _, a := range attrs {
+		span.Attrs[a.Key] = a.Value
+	}
+	r.spans = append(r.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), recordedSpanEnder{r, span}
+}
+
+type recordedSpanEnder struct {
+	r    *recordingTracer
+	span *recordedSpan
+}
+
+func (e recordedSpanEnder) End(_ time.Time, err error) {
+	e.r.mu.Lock()
+	defer e.r.mu.Unlock()
+	e.span.Ended = true
+	e.span.Err = err
+}
+
+// find returns the first span with the given name and fx.function.
+func (r *recordingTracer) find(t *testing.T, name, function string) *recordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.spans {
+		if s.Name == name && (function == "" || s.Attrs["fx.function"] == function) {
+			return s
+		}
+	}
+	require.Failf(t, "span not found", "%v %q", name, function)
+	return nil
+}
+
+type tracedA struct{}
+
+func newTracedA() *tracedA { return &tracedA{} }
+
+func TestWithTracer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("spans", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := new(recordingTracer)
+		var hookSpan string
+		app := fxtest.New(t,
+			fx.WithTracer(tracer),
+			fx.Module("child", fx.Provide(newTracedA)),
+			fx.Decorate(func(a *tracedA) *tracedA { return a }),
+			fx.Invoke(func(lc fx.Lifecycle, _ *tracedA) {
+				lc.Append(fx.Hook{
+					OnStart: func(ctx context.Context) error {
+						hookSpan = ctx.Value(spanKey{}).(*recordedSpan).Name
+						return nil
+					},
+					OnStop: func(context.Context) error { return nil },
+				})
+			}),
+		)
+		app.RequireStart().RequireStop()
+
+		newSpan := tracer.find(t, "fx.New", "")
+		assert.Empty(t, newSpan.Parent)
+		assert.True(t, newSpan.Ended)
+
+		invoke := tracer.find(t, "fx.Invoke", "")
+		assert.Equal(t, "fx.New", invoke.Parent)
+
+		provide := tracer.find(t, "fx.Provide", "go.uber.org/fx_test.newTracedA()")
+		assert.Equal(t, "fx.Invoke", provide.Parent)
+		assert.Equal(t, "child", provide.Attrs["fx.module"])
+		assert.True(t, provide.Ended)
+
+		decorate := tracer.find(t, "fx.Decorate", "")
+		assert.Equal(t, "fx.Invoke", decorate.Parent)
+
+		assert.Equal(t, "fx.Start", tracer.find(t, "fx.OnStart", "").Parent)
+		assert.Equal(t, "fx.Stop", tracer.find(t, "fx.OnStop", "").Parent)
+		assert.Equal(t, "fx.OnStart", hookSpan, "hooks must receive their span")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := new(recordingTracer)
+		app := NewForTest(t,
+			fx.WithTracer(tracer),
+			fx.Provide(func() (*tracedA, error) { return nil, errors.New("great sadness") }),
+			fx.Invoke(func(*tracedA) {}),
+		)
+		require.Error(t, app.Err())
+
+		assert.Error(t, tracer.find(t, "fx.Invoke", "").Err)
+		assert.Error(t, tracer.find(t, "fx.New", "").Err)
+	})
+
+	t.Run("hook errors", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := new(recordingTracer)
+		app := NewForTest(t,
+			fx.WithTracer(tracer),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() error { return errors.New("great sadness") }))
+			}),
+		)
+		require.Error(t, app.Start(context.Background()))
+
+		assert.EqualError(t, tracer.find(t, "fx.OnStart", "").Err, "great sadness")
+		assert.EqualError(t, tracer.find(t, "fx.Start", "").Err, "great sadness")
+	})
+
+	t.Run("hooks of modules", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := new(recordingTracer)
+		app := fxtest.New(t,
+			fx.WithTracer(tracer),
+			fx.Module("child",
+				fx.Invoke(func(lc fx.Lifecycle) {
+					lc.Append(fx.StartHook(func() {}))
+				}),
+			),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.Equal(t, "child", tracer.find(t, "fx.OnStart", "").Attrs["fx.module"])
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, fx.Module("child", fx.WithTracer(new(recordingTracer))))
+		assert.ErrorContains(t, app.Err(), "fx.WithTracer Option should be passed to top-level App")
+	})
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+var _typeOfCleanup = reflect.TypeOf(func() {})
+
+// ProvideWire registers providers written for google/wire,
+// easing the migration of applications from Wire to Fx
+// without rewriting every provider.
+// It accepts the same elements as wire.NewSet:
+//
+//	var Set = fx.ProvideWire(
+//		NewDB, // func(Config) (*DB, func(), error)
+//		NewUserStore,
+//		fx.WireBind(new(Store), new(*UserStore)),
+//		fx.Supply(Config{}),
+//	)
+//
+// Providers may return a value, and optionally a cleanup function
+// and an error, in that order, as with Wire.
+// Cleanup functions run when the application stops,
+// as OnStop hooks in the application's [Lifecycle].
+// If [New] fails, as a Wire injector would,
+// the cleanup functions of the providers that already ran
+// are called before it returns, in reverse order.
+//
+// Other elements of a Wire provider set are translated as follows:
+//
+//   - wire.NewSet: nested ProvideWire calls,
+//     or any other [Option], are applied as-is
+//   - wire.Bind: [WireBind]
+//   - wire.Value: [Supply]
+//   - wire.InterfaceValue: [Supply] with [Annotate] and [As]
+//
+// wire.Struct and wire.FieldsOf have no equivalent.
+// Use [In] and [Out] structs instead.
+func ProvideWire(providers ...interface{}) Option {
+	return provideWireOption{
+		Targets: providers,
+		Stack:   fxreflect.CallerStack(1, 0),
+	}
+}
+
+type provideWireOption struct {
+	Targets []interface{}
+	Stack   fxreflect.Stack
+}
+
+func (o provideWireOption) apply(m *module) {
+	for _, target := range o.Targets {
+		if opt, ok := target.(Option); ok {
+			opt.apply(m)
+			continue
+		}
+
+		p, err := m.wireProvide(target, o.Stack)
+		if err != nil {
+			m.app.err = fmt.Errorf("fx.ProvideWire(%v) from:\n%+vFailed: %w",
+				fxreflect.FuncName(target), o.Stack, err)
+			return
+		}
+		m.provides = append(m.provides, p)
+	}
+}
+
+func (o provideWireOption) String() string {
+	items := make([]string, len(o.Targets))
+	for i, t := range o.Targets {
+		items[i] = fxreflect.FuncName(t)
+	}
+	return fmt.Sprintf("fx.ProvideWire(%s)", strings.Join(items, ", "))
+}
+
+// wireProvide returns a provide for a Wire provider.
+// Providers that return a cleanup function are wrapped
+// to register it as an OnStop hook instead.
+func (m *module) wireProvide(target interface{}, stack fxreflect.Stack) (provide, error) {
+	p := provide{Target: target, Stack: stack}
+
+	fn := reflect.ValueOf(target)
+	if fn.Kind() != reflect.Func {
+		return p, fmt.Errorf("must provide constructor function, got %v (%T)", target, target)
+	}
+	ft := fn.Type()
+
+	// Wire providers return (T), (T, error), (T, func()), or (T, func(), error).
+	outs := make([]reflect.Type, 0, ft.NumOut())
+	cleanupIdx := -1
+	for i := 0; i < ft.NumOut(); i++ {
+		if ft.Out(i) == _typeOfCleanup && i == 1 {
+			cleanupIdx = i
+			continue
+		}
+		outs = append(outs, ft.Out(i))
+	}
+	if cleanupIdx < 0 {
+		return p, nil
+	}
+
+	ins := make([]reflect.Type, ft.NumIn())
+	for i := range ins {
+		ins[i] = ft.In(i)
+	}
+
+	name := fxreflect.FuncName(target)
+	p.Name = name
+	p.FuncPtr = fn.Pointer()
+	p.Target = reflect.MakeFunc(
+		reflect.FuncOf(ins, outs, ft.IsVariadic()),
+		func(args []reflect.Value) []reflect.Value {
+			var results []reflect.Value
+			if ft.IsVariadic() {
+				results = fn.CallSlice(args)
+			} else {
+				results = fn.Call(args)
+			}
+
+			failed := false
+			if last := results[len(results)-1]; last.Type() == _typeOfError {
+				failed = !last.IsNil()
+			}
+			if cleanup, _ := results[cleanupIdx].Interface().(func()); cleanup != nil && !failed {
+				m.app.wireCleanups = append(m.app.wireCleanups, cleanup)
+				m.app.lifecycle.Append(Hook{
+					OnStop: func(context.Context) error {
+						cleanup()
+						return nil
+					},
+					onStopName: name,
+				})
+			}
+			return append(results[:cleanupIdx:cleanupIdx], results[cleanupIdx+1:]...)
+		},
+	).Interface()
+	return p, nil
+}
+
+// WireBind binds an interface to a type that implements it,
+// like wire.Bind in a provider set.
+// Both are given as pointers:
+//
+//	fx.WireBind(new(Store), new(*UserStore))
+//
+// This provides Store using the *UserStore in the application.
+// To bind the result of a single constructor, prefer [Annotate] with [As].
+func WireBind(iface, to interface{}) Option {
+	return wireBindOption{
+		Iface: reflect.TypeOf(iface),
+		To:    reflect.TypeOf(to),
+		Stack: fxreflect.CallerStack(1, 0),
+	}
+}
+
+type wireBindOption struct {
+	Iface reflect.Type
+	To    reflect.Type
+	Stack fxreflect.Stack
+}
+
+func (o wireBindOption) apply(m *module) {
+	if err := o.validate(); err != nil {
+		m.app.err = fmt.Errorf("%v from:\n%+vFailed: %w", o, o.Stack, err)
+		return
+	}
+
+	iface, to := o.Iface.Elem(), o.To.Elem()
+	m.provides = append(m.provides, provide{
+		Target: reflect.MakeFunc(
+			reflect.FuncOf([]reflect.Type{to}, []reflect.Type{iface}, false),
+			func(args []reflect.Value) []reflect.Value {
+				out := reflect.New(iface).Elem()
+				out.Set(args[0])
+				return []reflect.Value{out}
+			},
+		).Interface(),
+		Stack: o.Stack,
+		Name:  o.String(),
+	})
+}
+
+func (o wireBindOption) validate() error {
+	if o.Iface == nil || o.Iface.Kind() != reflect.Ptr || o.Iface.Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("first argument must be a pointer to an interface, got %v", o.Iface)
+	}
+	if o.To == nil || o.To.Kind() != reflect.Ptr {
+		return fmt.Errorf("second argument must be a pointer to a type, got %v", o.To)
+	}
+	if !o.To.Elem().Implements(o.Iface.Elem()) {
+		return fmt.Errorf("%v does not implement %v", o.To.Elem(), o.Iface.Elem())
+	}
+	return nil
+}
+
+func (o wireBindOption) String() string {
+	return fmt.Sprintf("fx.WireBind(new(%v), new(%v))", typeElem(o.Iface), typeElem(o.To))
+}
+
+// typeElem returns the type pointed to by t, or t if it's not a pointer.
+func typeElem(t reflect.Type) interface{} {
+	if t == nil || t.Kind() != reflect.Ptr {
+		return t
+	}
+	return t.Elem()
+}
+
+// runWireCleanups calls the cleanup functions of the Wire providers
+// that ran while building an application that failed,
+// since the OnStop hooks that would call them never run.
+func (app *App) runWireCleanups() {
+	for i := len(app.wireCleanups) - 1; i >= 0; i-- {
+		app.wireCleanups[i]()
+	}
+	app.wireCleanups = nil
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+)
+
+type wireDB struct{ name string }
+
+func newWireDB(name string) (*wireDB, func(), error) {
+	return &wireDB{name: name}, nil, nil
+}
+
+func TestProvideWire(t *testing.T) {
+	t.Parallel()
+
+	type Config struct{ Name string }
+	type DB struct{ Name string }
+	type Cache struct{ DB *DB }
+
+	t.Run("Cleanup", func(t *testing.T) {
+		t.Parallel()
+
+		var cleaned []string
+		app := fxtest.New(t,
+			fx.ProvideWire(
+				func(cfg Config) (*DB, func(), error) {
+					return &DB{Name: cfg.Name}, func() { cleaned = append(cleaned, "db") }, nil
+				},
+				func(db *DB) (*Cache, func()) {
+					return &Cache{DB: db}, func() { cleaned = append(cleaned, "cache") }
+				},
+				fx.Supply(Config{Name: "users"}),
+			),
+			fx.Invoke(func(c *Cache) {
+				assert.Equal(t, "users", c.DB.Name)
+			}),
+		)
+
+		app.RequireStart()
+		assert.Empty(t, cleaned)
+		app.RequireStop()
+		assert.Equal(t, []string{"cache", "db"}, cleaned)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.ProvideWire(func() (*DB, func(), error) {
+				return nil, nil, errors.New("great sadness")
+			}),
+			fx.Invoke(func(*DB) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+	})
+
+	t.Run("CleanupWhenNewFails", func(t *testing.T) {
+		t.Parallel()
+
+		var cleaned []string
+		app := fx.New(
+			fx.NopLogger,
+			fx.ProvideWire(
+				func() (*DB, func(), error) {
+					return &DB{}, func() { cleaned = append(cleaned, "db") }, nil
+				},
+				func(*DB) (*Cache, func(), error) {
+					return nil, func() { cleaned = append(cleaned, "cache") }, errors.New("great sadness")
+				},
+			),
+			fx.Invoke(func(*DB) {}),
+			fx.Invoke(func(*Cache) {}),
+		)
+		require.Error(t, app.Err())
+		assert.Equal(t, []string{"db"}, cleaned,
+			"cleanups of providers that succeeded must run once New fails")
+	})
+
+	t.Run("PlainConstructor", func(t *testing.T) {
+		t.Parallel()
+
+		var db *DB
+		fxtest.New(t,
+			fx.ProvideWire(func() *DB { return &DB{Name: "plain"} }),
+			fx.Populate(&db),
+		)
+		assert.Equal(t, "plain", db.Name)
+	})
+
+	t.Run("ConstructorName", func(t *testing.T) {
+		t.Parallel()
+
+		app, spy := NewSpied(
+			fx.Supply("users"),
+			fx.ProvideWire(newWireDB),
+			fx.Invoke(func(*wireDB) {}),
+		)
+		require.NoError(t, app.Err())
+
+		var names []string
+		for _, e := range spy.Events().SelectByTypeName("Provided") {
+			names = append(names, e.(*fxevent.Provided).ConstructorName)
+		}
+		assert.Contains(t, names, "go.uber.org/fx_test.newWireDB()")
+	})
+
+	t.Run("NotFunction", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(fx.NopLogger, fx.ProvideWire(&DB{}))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.ProvideWire(")
+		assert.Contains(t, err.Error(), "must provide constructor function")
+	})
+}
+
+func TestWireBind(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Binds", func(t *testing.T) {
+		t.Parallel()
+
+		var r io.Reader
+		fxtest.New(t,
+			fx.ProvideWire(
+				func() *strings.Reader { return strings.NewReader("hello") },
+				fx.WireBind(new(io.Reader), new(*strings.Reader)),
+			),
+			fx.Populate(&r),
+		)
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(b))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    fx.Option
+			wantErr string
+		}{
+			{
+				desc:    "NotInterface",
+				give:    fx.WireBind(new(*strings.Reader), new(*strings.Reader)),
+				wantErr: "first argument must be a pointer to an interface, got **strings.Reader",
+			},
+			{
+				desc:    "NotPointer",
+				give:    fx.WireBind(new(io.Reader), strings.Reader{}),
+				wantErr: "second argument must be a pointer to a type, got strings.Reader",
+			},
+			{
+				desc:    "NotImplemented",
+				give:    fx.WireBind(new(io.Writer), new(*strings.Reader)),
+				wantErr: "*strings.Reader does not implement io.Writer",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				err := fx.New(fx.NopLogger, tt.give).Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}